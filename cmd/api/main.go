@@ -7,15 +7,36 @@ import (
 	"os/signal"
 	"syscall"
 
+	"basic-gin/internal/cli"
 	"basic-gin/internal/server"
 )
 
 func main() {
-	log.Println("Starting application...")
-
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "export":
+			if err := cli.Export(ctx, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "renumber-accounts":
+			if err := cli.RenumberAccounts(ctx, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		case "replay-monitoring":
+			if err := cli.ReplayMonitoring(ctx, os.Args[2:]); err != nil {
+				log.Fatal(err)
+			}
+			return
+		}
+	}
+
+	log.Println("Starting application...")
+
 	if err := server.Run(ctx); err != nil {
 		log.Fatalf("server stopped with error: %v", err)
 	}
@@ -0,0 +1,314 @@
+// Package accountnumber generates and validates account numbers. A Scheme
+// describes how the bank numbers its accounts: a national number made of a
+// bank code, an account part and check digits (ISO 7064 mod 97-10, as in the
+// Serbian 3-13-2 format, or Luhn), optionally wrapped in an IBAN.
+package accountnumber
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+const (
+	// KindIBAN stores numbers as IBANs around the national number.
+	KindIBAN = "iban"
+	// KindNational stores the national number alone.
+	KindNational = "national"
+	// KindLegacy is the original format: random digits, no check digit.
+	KindLegacy = "legacy"
+
+	CheckMod97 = "mod97"
+	CheckLuhn  = "luhn"
+
+	// LegacyLength is the length of numbers issued before schemes existed.
+	LegacyLength = 16
+)
+
+// Scheme is a bank's account numbering. The zero value is not usable; build
+// one with the fields below and call Validate.
+type Scheme struct {
+	Kind string
+	// Check is the national check digit method, CheckMod97 or CheckLuhn.
+	Check string
+	// Country is the IBAN country code.
+	Country string
+	// BankCode prefixes every national number.
+	BankCode string
+	// AccountDigits is the length of the account part.
+	AccountDigits int
+	// AcceptLegacy lets Canonical pass LegacyLength-digit numbers through,
+	// so accounts not yet renumbered can still be addressed.
+	AcceptLegacy bool
+}
+
+var ErrInvalid = errors.New("invalid account number")
+
+// Validate checks the scheme's own settings.
+func (s Scheme) Validate() error {
+	switch s.Kind {
+	case KindLegacy:
+		return nil
+	case KindIBAN, KindNational:
+	default:
+		return fmt.Errorf("unknown account number scheme %q", s.Kind)
+	}
+	if s.Check != CheckMod97 && s.Check != CheckLuhn {
+		return fmt.Errorf("unknown account number check %q", s.Check)
+	}
+	if s.BankCode == "" || !digits(s.BankCode) {
+		return fmt.Errorf("bank code %q must be digits", s.BankCode)
+	}
+	if s.AccountDigits < 6 || s.AccountDigits > 20 {
+		return fmt.Errorf("account part must have 6 to 20 digits, not %d", s.AccountDigits)
+	}
+	if s.Kind == KindIBAN {
+		if len(s.Country) != 2 || !letters(s.Country) {
+			return fmt.Errorf("country code %q must be two letters", s.Country)
+		}
+		if n, ok := ibanLengths[s.Country]; ok && n != 4+s.nationalLength() {
+			return fmt.Errorf("%s IBANs have %d characters, the scheme gives %d", s.Country, n, 4+s.nationalLength())
+		}
+	}
+	return nil
+}
+
+func (s Scheme) checkLength() int {
+	if s.Check == CheckLuhn {
+		return 1
+	}
+	return 2
+}
+
+func (s Scheme) nationalLength() int {
+	return len(s.BankCode) + s.AccountDigits + s.checkLength()
+}
+
+// Generate returns a new random account number in the scheme's format.
+func (s Scheme) Generate() (string, error) {
+	if s.Kind == KindLegacy {
+		return randomDigits(LegacyLength)
+	}
+	part, err := randomDigits(s.AccountDigits)
+	if err != nil {
+		return "", err
+	}
+	return s.Number(part), nil
+}
+
+// Number builds the full account number for an account part of
+// AccountDigits digits.
+func (s Scheme) Number(part string) string {
+	national := s.BankCode + part + s.checkDigits(s.BankCode+part)
+	if s.Kind == KindIBAN {
+		return IBAN(s.Country, national)
+	}
+	return national
+}
+
+func (s Scheme) checkDigits(payload string) string {
+	if s.Check == CheckLuhn {
+		return string(LuhnDigit(payload))
+	}
+	return Mod97Check(payload)
+}
+
+// Canonical validates input and returns it in the form accounts of this
+// bank are stored in. Spaces and dashes are ignored, and the shortened
+// national notation "bank-account-check" has its account part zero-padded
+// (e.g. "160-5100-56"). An IBAN of the bank's country is converted to or
+// from the national number as the scheme requires. Foreign IBANs are
+// returned cleaned if their check digits hold.
+func (s Scheme) Canonical(input string) (string, error) {
+	in := strings.ToUpper(strings.TrimSpace(input))
+	if in == "" {
+		return "", fmt.Errorf("%w: empty", ErrInvalid)
+	}
+	if parts := strings.Split(in, "-"); len(parts) == 3 && s.Kind != KindLegacy {
+		if len(parts[1]) < s.AccountDigits {
+			parts[1] = strings.Repeat("0", s.AccountDigits-len(parts[1])) + parts[1]
+		}
+		in = strings.Join(parts, "")
+	}
+	clean := strings.NewReplacer(" ", "", "-", "").Replace(in)
+
+	if len(clean) >= 2 && letters(clean[:2]) {
+		if err := ValidateIBAN(clean); err != nil {
+			return "", err
+		}
+		if s.Kind == KindLegacy || clean[:2] != s.Country {
+			return clean, nil
+		}
+		national := clean[4:]
+		if err := s.validateNational(national); err != nil {
+			return "", err
+		}
+		return s.store(national), nil
+	}
+
+	if !digits(clean) {
+		return "", fmt.Errorf("%w %q: only digits, spaces and dashes are allowed", ErrInvalid, input)
+	}
+	if s.Kind == KindLegacy {
+		return clean, nil
+	}
+	if len(clean) == LegacyLength && s.AcceptLegacy && len(clean) != s.nationalLength() {
+		return clean, nil
+	}
+	if err := s.validateNational(clean); err != nil {
+		return "", err
+	}
+	return s.store(clean), nil
+}
+
+func (s Scheme) store(national string) string {
+	if s.Kind == KindIBAN {
+		return IBAN(s.Country, national)
+	}
+	return national
+}
+
+func (s Scheme) validateNational(n string) error {
+	if len(n) != s.nationalLength() || !digits(n) {
+		return fmt.Errorf("%w %q: expected %d digits", ErrInvalid, n, s.nationalLength())
+	}
+	if !strings.HasPrefix(n, s.BankCode) {
+		return fmt.Errorf("%w %q: bank code is not %s", ErrInvalid, n, s.BankCode)
+	}
+	cut := len(n) - s.checkLength()
+	if s.checkDigits(n[:cut]) != n[cut:] {
+		return fmt.Errorf("%w %q: check digits do not match", ErrInvalid, n)
+	}
+	return nil
+}
+
+// IsCanonical reports whether number is already in the scheme's stored
+// format, i.e. it needs no renumbering.
+func (s Scheme) IsCanonical(number string) bool {
+	if s.Kind == KindLegacy {
+		return true
+	}
+	if len(number) == LegacyLength && digits(number) && s.nationalLength() != LegacyLength {
+		return false
+	}
+	c, err := s.Canonical(number)
+	return err == nil && c == number
+}
+
+// Mod97Check returns the two ISO 7064 mod 97-10 check digits appended to
+// payload, as used by Serbian and several other national account numbers.
+func Mod97Check(payload string) string {
+	return fmt.Sprintf("%02d", 98-mod97(payload+"00"))
+}
+
+// LuhnDigit returns the Luhn check digit for payload.
+func LuhnDigit(payload string) byte {
+	sum := 0
+	double := true
+	for i := len(payload) - 1; i >= 0; i-- {
+		d := int(payload[i] - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// IBAN assembles an IBAN from a country code and BBAN.
+func IBAN(country, bban string) string {
+	country = strings.ToUpper(country)
+	check := 98 - mod97(expand(bban+country+"00"))
+	return fmt.Sprintf("%s%02d%s", country, check, bban)
+}
+
+// ValidateIBAN checks an IBAN without spaces: country code, length for
+// known countries and the mod 97 check.
+func ValidateIBAN(iban string) error {
+	if len(iban) < 15 || len(iban) > 34 {
+		return fmt.Errorf("%w %q: an IBAN has 15 to 34 characters", ErrInvalid, iban)
+	}
+	if !letters(iban[:2]) || !digits(iban[2:4]) {
+		return fmt.Errorf("%w %q: an IBAN starts with a country code and two check digits", ErrInvalid, iban)
+	}
+	if n, ok := ibanLengths[iban[:2]]; ok && len(iban) != n {
+		return fmt.Errorf("%w %q: %s IBANs have %d characters", ErrInvalid, iban, iban[:2], n)
+	}
+	for _, r := range iban[4:] {
+		if !(r >= '0' && r <= '9' || r >= 'A' && r <= 'Z') {
+			return fmt.Errorf("%w %q: unexpected character %q", ErrInvalid, iban, r)
+		}
+	}
+	if mod97(expand(iban[4:]+iban[:4])) != 1 {
+		return fmt.Errorf("%w %q: IBAN check digits do not match", ErrInvalid, iban)
+	}
+	return nil
+}
+
+// expand replaces letters with their two-digit values (A=10 ... Z=35).
+func expand(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			fmt.Fprintf(&b, "%d", r-'A'+10)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// mod97 computes a decimal string modulo 97 piecewise, so any length works.
+func mod97(s string) int {
+	r := 0
+	for i := 0; i < len(s); i++ {
+		r = (r*10 + int(s[i]-'0')) % 97
+	}
+	return r
+}
+
+func randomDigits(n int) (string, error) {
+	b := make([]byte, n)
+	for i := range b {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b[i] = byte('0' + d.Int64())
+	}
+	return string(b), nil
+}
+
+func digits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func letters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'A' || s[i] > 'Z' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// ibanLengths lists IBAN lengths for the countries payments most often go
+// to; IBANs of other countries are checked by mod 97 only.
+var ibanLengths = map[string]int{
+	"AT": 20, "BA": 20, "BE": 16, "BG": 22, "CH": 21, "CY": 28, "CZ": 24,
+	"DE": 22, "DK": 18, "EE": 20, "ES": 24, "FI": 18, "FR": 27, "GB": 22,
+	"GR": 27, "HR": 21, "HU": 28, "IE": 22, "IT": 27, "LT": 20, "LU": 20,
+	"LV": 21, "ME": 22, "MK": 19, "MT": 31, "NL": 18, "NO": 15, "PL": 28,
+	"PT": 25, "RO": 24, "RS": 22, "SE": 24, "SI": 19, "SK": 24, "TR": 26,
+}
@@ -0,0 +1,102 @@
+package accountnumber
+
+import (
+	"errors"
+	"testing"
+)
+
+var serbian = Scheme{Kind: KindIBAN, Check: CheckMod97, Country: "RS", BankCode: "260", AccountDigits: 13}
+
+func TestCheckDigits(t *testing.T) {
+	if got := Mod97Check("2600056010016113"); got != "79" {
+		t.Errorf("Mod97Check = %s, want 79", got)
+	}
+	if got := LuhnDigit("7992739871"); got != '3' {
+		t.Errorf("LuhnDigit = %c, want 3", got)
+	}
+	if got := IBAN("RS", "260005601001611379"); got != "RS35260005601001611379" {
+		t.Errorf("IBAN = %s, want RS35260005601001611379", got)
+	}
+}
+
+func TestValidateIBAN(t *testing.T) {
+	tests := []struct {
+		iban string
+		ok   bool
+	}{
+		{"RS35260005601001611379", true},
+		{"DE89370400440532013000", true},
+		{"GB82WEST12345698765432", true},
+		{"RS35260005601001611378", false},
+		{"GB82WEST12345698765431", false},
+		{"RS352600056010016113", false},
+		{"3535260005601001611379", false},
+	}
+	for _, tt := range tests {
+		err := ValidateIBAN(tt.iban)
+		if tt.ok && err != nil {
+			t.Errorf("ValidateIBAN(%s): %v", tt.iban, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalid) {
+			t.Errorf("ValidateIBAN(%s) = %v, want ErrInvalid", tt.iban, err)
+		}
+	}
+}
+
+func TestCanonical(t *testing.T) {
+	national := serbian
+	national.Kind = KindNational
+	luhn := Scheme{Kind: KindNational, Check: CheckLuhn, BankCode: "799", AccountDigits: 7}
+
+	tests := []struct {
+		name   string
+		scheme Scheme
+		input  string
+		want   string
+	}{
+		{"short national notation", serbian, "260-56010016113-79", "RS35260005601001611379"},
+		{"spaced iban", serbian, " rs35 2600 0560 1001 6113 79", "RS35260005601001611379"},
+		{"foreign iban", serbian, "DE89 3704 0044 0532 0130 00", "DE89370400440532013000"},
+		{"iban to national", national, "RS35260005601001611379", "260005601001611379"},
+		{"luhn", luhn, "799-2739871-3", "79927398713"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.scheme.Canonical(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Canonical(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "260005601001611378", "265005601001611379", "26000560100161137", "RS35 2600 0560 1001 6113 78", "7992739870"} {
+		if _, err := serbian.Canonical(input); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Canonical(%q) = %v, want ErrInvalid", input, err)
+		}
+	}
+}
+
+func TestSchemeNumber(t *testing.T) {
+	if got := serbian.Number("0056010016113"); got != "RS35260005601001611379" {
+		t.Errorf("Number = %s, want RS35260005601001611379", got)
+	}
+	n, err := serbian.Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !serbian.IsCanonical(n) {
+		t.Errorf("generated %s is not canonical", n)
+	}
+
+	bad := serbian
+	bad.Check = CheckLuhn
+	if err := bad.Validate(); err == nil {
+		t.Error("Validate accepted a Luhn scheme that does not fit RS IBANs")
+	}
+	if err := serbian.Validate(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,91 @@
+// Package cli holds the application's command-line subcommands.
+package cli
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/db"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/service"
+	"basic-gin/internal/statement"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+// Export writes an account statement file, by default camt.053:
+//
+//	app export -account 12 -from 2026-09-01 -to 2026-09-30 -format mt940 -verify
+func Export(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	accountID := fs.Int("account", 0, "account id")
+	from := fs.String("from", "", "first day, YYYY-MM-DD (default: start of last month)")
+	to := fs.String("to", "", "last day, YYYY-MM-DD (default: end of last month)")
+	format := fs.String("format", statement.FormatCamt053, "one of "+strings.Join(statement.Formats(), ", "))
+	out := fs.String("out", "", `output file (default: generated name, "-" for stdout)`)
+	verify := fs.Bool("verify", false, "re-read a camt053 or mt940 file and check its balances")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *accountID <= 0 {
+		return errors.New("export: -account is required")
+	}
+	if *verify && *out == "-" {
+		return errors.New("export: -verify needs a file, not stdout")
+	}
+
+	config.Load()
+	pool, err := db.Connect(ctx, config.App.PostgresDSN)
+	if err != nil {
+		return fmt.Errorf("db connect: %w", err)
+	}
+	defer pool.Close()
+
+	svc := service.NewStatementService(repository.NewStatementRepository(pool), repository.NewAccountRepository(pool))
+	job, err := svc.Prepare(ctx, *accountID, dto.StatementQuery{From: *from, To: *to, Format: *format})
+	if err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		path = job.Filename()
+	}
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if err := svc.Write(ctx, job, w); err != nil {
+		return err
+	}
+	if f, ok := w.(*os.File); ok && f != os.Stdout {
+		if err := f.Close(); err != nil {
+			return err
+		}
+		log.Printf("export: wrote %s", path)
+	}
+
+	if *verify {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := statement.Verify(job.Format, f); err != nil {
+			return fmt.Errorf("export: verification failed: %w", err)
+		}
+		log.Printf("export: %s verified", path)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/db"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mail"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/sanctions"
+	"basic-gin/internal/service"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+)
+
+// RenumberAccounts moves accounts still carrying numbers from before the
+// configured scheme (ACCOUNT_NUMBER_SCHEME) to new numbers. Old numbers stay
+// valid as legacy numbers:
+//
+//	app renumber-accounts -dry-run
+//	app renumber-accounts -actor ops@bank
+func RenumberAccounts(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("renumber-accounts", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "list the accounts that would be renumbered without changing them")
+	actor := fs.String("actor", "", "who is running the migration, recorded in the audit log")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	config.Load()
+	pool, err := db.Connect(ctx, config.App.PostgresDSN)
+	if err != nil {
+		return fmt.Errorf("db connect: %w", err)
+	}
+	defer pool.Close()
+
+	if *actor != "" {
+		ctx = context.WithValue(ctx, middleware.ActorKey, *actor)
+	}
+
+	account_repo := repository.NewAccountRepository(pool)
+	transaction_repo := repository.NewTransactionRepository(pool)
+	audit_repo := repository.NewAuditRepository(pool)
+	client_repo := repository.NewClientRepository(pool)
+	// renumbering opens no accounts, so no sanctions list is loaded
+	screening_repo := repository.NewScreeningRepository(pool)
+	screening_service := service.NewScreeningService(sanctions.NewScreener(), screening_repo, client_repo, audit_repo)
+	// renumbering sends no mail, so any that would go out stays in memory
+	email_verification_service := service.NewEmailVerificationService(repository.NewEmailVerificationRepository(pool), client_repo,
+		repository.NewClientContactRepository(pool), audit_repo, mail.NewMemoryOutbox(config.App.MailFrom))
+	client_service := service.NewClientService(*client_repo, screening_service, email_verification_service, nil)
+	fee_service := service.NewFeeService(repository.NewFeeRepository(pool), account_repo, transaction_repo, audit_repo)
+	approval_repo := repository.NewApprovalRepository(pool)
+	account_holder_repo := repository.NewAccountHolderRepository(pool)
+	limit_service := service.NewLimitService(repository.NewLimitRepository(pool), account_repo, transaction_repo, audit_repo, approval_repo, account_holder_repo)
+	// renumbering moves no money, so no monitoring rules are loaded
+	monitoring_service := service.NewMonitoringService(monitoring.NewEngine(), repository.NewMonitoringRepository(pool),
+		repository.NewCaseRepository(pool), transaction_repo, audit_repo)
+	business_service := service.NewBusinessService(repository.NewBusinessPartyRepository(pool), client_repo, screening_repo, audit_repo)
+	svc := service.NewAccountService(account_repo, transaction_repo, audit_repo, approval_repo, account_holder_repo, client_service, fee_service, limit_service, monitoring_service, screening_service, business_service, nil)
+
+	n, err := svc.Renumber(ctx, *dryRun, func(r dto.AccountRenumbered) {
+		fmt.Printf("%d\t%s\t%s\n", r.ID, r.OldNumber, r.NewNumber)
+	})
+	if err != nil {
+		return fmt.Errorf("renumber-accounts: %w", err)
+	}
+	if *dryRun {
+		log.Printf("renumber-accounts: %d accounts would be renumbered", n)
+	} else {
+		log.Printf("renumber-accounts: %d accounts renumbered", n)
+	}
+	return nil
+}
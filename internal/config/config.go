@@ -1,11 +1,14 @@
 package config
 
 import (
+	"basic-gin/internal/accountnumber"
 	"log"
 	"net"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -25,6 +28,89 @@ type Config struct {
 	RedisAddr  string
 	RedisPass  string
 	HMACSecret string
+
+	DefaultCurrency string
+
+	// AccountNumbers is the scheme new account numbers are issued in and
+	// incoming numbers are validated against.
+	AccountNumbers accountnumber.Scheme
+
+	FXRatesFile string
+	FXSpreadBps int
+	FXQuoteTTL  time.Duration
+
+	HoldDefaultTTL     time.Duration
+	HoldMaxTTL         time.Duration
+	HoldExpiryInterval time.Duration
+
+	StandingOrderInterval      time.Duration
+	StandingOrderMaxRetries    int
+	StandingOrderRetryInterval time.Duration
+
+	InterestAccrualInterval time.Duration
+
+	// MonitoringRulesFile is the .yaml or .json rule set transfers, deposits
+	// and withdrawals are screened with; it is reloaded when it changes,
+	// checked every MonitoringReloadInterval.
+	MonitoringRulesFile      string
+	MonitoringReloadInterval time.Duration
+
+	// SanctionsListFile is the sanctions list clients are screened against
+	// (.csv or .xml). Names scoring at least SanctionsMatchThreshold (0 to 1)
+	// are hits. The file is checked for changes every
+	// SanctionsReloadInterval; a changed list is screened against all
+	// clients.
+	SanctionsListFile       string
+	SanctionsMatchThreshold float64
+	SanctionsReloadInterval time.Duration
+
+	// ClientDuplicateThreshold is the similarity (0 to 1) from which a
+	// client born on the same day is reported as a possible duplicate.
+	ClientDuplicateThreshold float64
+
+	// Mailer picks how mail to clients goes out: smtp through SMTPAddr,
+	// file writes one .eml per message to MailOutboxDir and memory only
+	// logs it. EmailVerificationTTL is how long an email confirmation
+	// token is valid.
+	Mailer               string
+	MailFrom             string
+	MailOutboxDir        string
+	SMTPAddr             string
+	SMTPUsername         string
+	SMTPPassword         string
+	EmailVerificationTTL time.Duration
+
+	// BeneficiaryCoolingOff is how long a newly saved beneficiary may not
+	// receive large transfers; BeneficiaryLargeAmounts sets what is large
+	// per currency; currencies not listed have no limit.
+	BeneficiaryCoolingOff   time.Duration
+	BeneficiaryLargeAmounts map[string]int
+
+	// ApprovalOperations are the operations that wait for a second person:
+	// withdrawal (operator-initiated only), transfer (from
+	// ApprovalTransferThresholds up, per currency), overdraft_limit and
+	// transfer_limit. ApprovalApprovers, when not empty, restricts who may
+	// decide; the maker never may.
+	ApprovalOperations         map[string]bool
+	ApprovalTransferThresholds map[string]int
+	ApprovalApprovers          map[string]bool
+	ApprovalTTL                time.Duration
+	ApprovalExpiryInterval     time.Duration
+
+	// CaseAnalysts, when not empty, restricts who monitoring cases may be
+	// assigned to.
+	CaseAnalysts map[string]bool
+
+	PaymentBatchInterval time.Duration
+	// PaymentBatchLease is how long a worker may go without progress on a
+	// batch before another worker takes it over.
+	PaymentBatchLease    time.Duration
+	PaymentBatchMaxItems int
+
+	// FeeRevenueAccounts maps a currency code to the account fees in that
+	// currency are credited to. No fees are charged in a currency missing
+	// from it.
+	FeeRevenueAccounts map[string]int
 }
 
 var App Config
@@ -36,6 +122,81 @@ func getenv(k, def string) string {
 	return def
 }
 
+func getenvInt(k string, def int) int {
+	if v := os.Getenv(k); v != "" {
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+		log.Printf("config: invalid int for %s=%q, using %d", k, v, def)
+	}
+	return def
+}
+
+func getenvDuration(k string, def time.Duration) time.Duration {
+	if v := os.Getenv(k); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+		log.Printf("config: invalid duration for %s=%q, using %s", k, v, def)
+	}
+	return def
+}
+
+func getenvFloat(k string, def float64) float64 {
+	if v := os.Getenv(k); v != "" {
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+		log.Printf("config: invalid float for %s=%q, using %g", k, v, def)
+	}
+	return def
+}
+
+func getenvBool(k string, def bool) bool {
+	if v := os.Getenv(k); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+		log.Printf("config: invalid bool for %s=%q, using %t", k, v, def)
+	}
+	return def
+}
+
+// getenvIntMap parses "KEY=1,OTHER=2"; keys are upper-cased.
+func getenvIntMap(k, def string) map[string]int {
+	out := map[string]int{}
+	v := getenv(k, def)
+	if v == "" {
+		return out
+	}
+	for _, part := range strings.Split(v, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			log.Printf("config: ignoring malformed entry %q in %s", part, k)
+			continue
+		}
+		i, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil {
+			log.Printf("config: ignoring malformed entry %q in %s", part, k)
+			continue
+		}
+		out[strings.ToUpper(strings.TrimSpace(key))] = i
+	}
+	return out
+}
+
+// getenvSet parses "a,b,c" into a set; entries are trimmed and empty ones
+// dropped.
+func getenvSet(k, def string) map[string]bool {
+	out := map[string]bool{}
+	for _, part := range strings.Split(getenv(k, def), ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out[part] = true
+		}
+	}
+	return out
+}
+
 func Load() {
 	_ = godotenv.Load()
 
@@ -53,6 +214,65 @@ func Load() {
 		RedisPass:  getenv("REDIS_PASS", ""),
 		HMACSecret: getenv("HMAC_SECRET", "dev-secret"),
 
+		DefaultCurrency: getenv("DEFAULT_CURRENCY", "RSD"),
+
+		AccountNumbers: accountnumber.Scheme{
+			Kind:          getenv("ACCOUNT_NUMBER_SCHEME", accountnumber.KindIBAN),
+			Check:         getenv("ACCOUNT_NUMBER_CHECK", accountnumber.CheckMod97),
+			Country:       strings.ToUpper(getenv("ACCOUNT_NUMBER_COUNTRY", "RS")),
+			BankCode:      getenv("ACCOUNT_NUMBER_BANK_CODE", "123"),
+			AccountDigits: getenvInt("ACCOUNT_NUMBER_DIGITS", 13),
+			AcceptLegacy:  getenvBool("ACCOUNT_NUMBER_ACCEPT_LEGACY", true),
+		},
+
+		FXRatesFile: getenv("FX_RATES_FILE", "data/fx_rates.csv"),
+		FXSpreadBps: getenvInt("FX_SPREAD_BPS", 50),
+		FXQuoteTTL:  getenvDuration("FX_QUOTE_TTL", 60*time.Second),
+
+		HoldDefaultTTL:     getenvDuration("HOLD_DEFAULT_TTL", 7*24*time.Hour),
+		HoldMaxTTL:         getenvDuration("HOLD_MAX_TTL", 30*24*time.Hour),
+		HoldExpiryInterval: getenvDuration("HOLD_EXPIRY_INTERVAL", time.Minute),
+
+		StandingOrderInterval:      getenvDuration("STANDING_ORDER_INTERVAL", time.Minute),
+		StandingOrderMaxRetries:    getenvInt("STANDING_ORDER_MAX_RETRIES", 3),
+		StandingOrderRetryInterval: getenvDuration("STANDING_ORDER_RETRY_INTERVAL", 6*time.Hour),
+
+		InterestAccrualInterval: getenvDuration("INTEREST_ACCRUAL_INTERVAL", time.Hour),
+
+		MonitoringRulesFile:      getenv("MONITORING_RULES_FILE", "data/monitoring_rules.yaml"),
+		MonitoringReloadInterval: getenvDuration("MONITORING_RELOAD_INTERVAL", 30*time.Second),
+
+		SanctionsListFile:       getenv("SANCTIONS_LIST_FILE", "data/sanctions.csv"),
+		SanctionsMatchThreshold: getenvFloat("SANCTIONS_MATCH_THRESHOLD", 0.9),
+		SanctionsReloadInterval: getenvDuration("SANCTIONS_RELOAD_INTERVAL", time.Minute),
+
+		ClientDuplicateThreshold: getenvFloat("CLIENT_DUPLICATE_THRESHOLD", 0.9),
+
+		Mailer:               strings.ToLower(getenv("MAILER", "file")),
+		MailFrom:             getenv("MAIL_FROM", "no-reply@basic-gin.local"),
+		MailOutboxDir:        getenv("MAIL_OUTBOX_DIR", "data/outbox"),
+		SMTPAddr:             getenv("SMTP_ADDR", "localhost:25"),
+		SMTPUsername:         getenv("SMTP_USERNAME", ""),
+		SMTPPassword:         getenv("SMTP_PASSWORD", ""),
+		EmailVerificationTTL: getenvDuration("EMAIL_VERIFICATION_TTL", 24*time.Hour),
+
+		BeneficiaryCoolingOff:   getenvDuration("BENEFICIARY_COOLING_OFF", 24*time.Hour),
+		BeneficiaryLargeAmounts: getenvIntMap("BENEFICIARY_LARGE_AMOUNTS", "RSD=100000,EUR=1000,USD=1000,CHF=1000,GBP=1000"),
+
+		ApprovalOperations:         getenvSet("APPROVAL_OPERATIONS", "withdrawal,transfer,overdraft_limit,transfer_limit"),
+		ApprovalTransferThresholds: getenvIntMap("APPROVAL_TRANSFER_THRESHOLDS", "RSD=1000000,EUR=10000,USD=10000,CHF=10000,GBP=10000"),
+		ApprovalApprovers:          getenvSet("APPROVAL_APPROVERS", ""),
+		ApprovalTTL:                getenvDuration("APPROVAL_TTL", 24*time.Hour),
+		ApprovalExpiryInterval:     getenvDuration("APPROVAL_EXPIRY_INTERVAL", time.Minute),
+
+		CaseAnalysts: getenvSet("CASE_ANALYSTS", ""),
+
+		PaymentBatchInterval: getenvDuration("PAYMENT_BATCH_INTERVAL", 5*time.Second),
+		PaymentBatchLease:    getenvDuration("PAYMENT_BATCH_LEASE", 10*time.Minute),
+		PaymentBatchMaxItems: getenvInt("PAYMENT_BATCH_MAX_ITEMS", 5000),
+
+		FeeRevenueAccounts: getenvIntMap("FEE_REVENUE_ACCOUNTS", ""),
+
 		PostgresDSN: getenv("POSTGRES_DSN", ""),
 	}
 
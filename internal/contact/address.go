@@ -0,0 +1,19 @@
+package contact
+
+import "strings"
+
+// FormatAddress renders an address on one line as "Street Number, Postal
+// City, CC", leaving out empty parts.
+func FormatAddress(street, number, postalCode, city, country string) string {
+	var parts []string
+	if line := strings.TrimSpace(street + " " + number); line != "" {
+		parts = append(parts, line)
+	}
+	if line := strings.TrimSpace(postalCode + " " + city); line != "" {
+		parts = append(parts, line)
+	}
+	if country != "" {
+		parts = append(parts, country)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,24 @@
+package contact
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// NormalizeEmail checks that email is a bare RFC 5322 address, without a
+// display name, angle brackets or quoted local part, whose domain has at
+// least two labels. It returns it trimmed with the domain in lower case; the
+// local part is kept as given since it may be case sensitive.
+func NormalizeEmail(email string) (string, error) {
+	email = strings.TrimSpace(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", fmt.Errorf("invalid email address %q", email)
+	}
+	at := strings.LastIndex(email, "@")
+	if at <= 0 || !strings.Contains(email[at+1:], ".") {
+		return "", fmt.Errorf("invalid email address %q", email)
+	}
+	return email[:at] + "@" + strings.ToLower(email[at+1:]), nil
+}
@@ -0,0 +1,34 @@
+package contact
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalizePhone returns number in E.164 form. Spaces, dashes, dots and
+// parentheses are dropped and a leading international prefix "00" is read
+// as "+"; the number must carry its country code.
+func NormalizePhone(number string) (string, error) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(number) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		default:
+			return "", fmt.Errorf("invalid phone number %q", number)
+		}
+	}
+	n := b.String()
+	if strings.HasPrefix(n, "00") {
+		n = "+" + n[2:]
+	}
+	// E.164 allows at most 15 digits; anything under 7 cannot be a full
+	// international number.
+	if !strings.HasPrefix(n, "+") || len(n) < 8 || len(n) > 16 || n[1] == '0' {
+		return "", fmt.Errorf("phone number %q is not in E.164 format (e.g. +381641234567)", number)
+	}
+	return n, nil
+}
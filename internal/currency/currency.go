@@ -0,0 +1,87 @@
+package currency
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// minorUnits holds the ISO 4217 minor unit exponent for every currency the
+// service accepts. Balances are stored as NUMERIC(18,3) so nothing above 3
+// decimal places can be supported.
+var minorUnits = map[string]int{
+	"RSD": 2,
+	"EUR": 2,
+	"USD": 2,
+	"GBP": 2,
+	"CHF": 2,
+	"BAM": 2,
+	"HUF": 2,
+	"CZK": 2,
+	"PLN": 2,
+	"SEK": 2,
+	"NOK": 2,
+	"DKK": 2,
+	"CAD": 2,
+	"AUD": 2,
+	"CNY": 2,
+	"JPY": 0,
+	"KRW": 0,
+	"ISK": 0,
+	"KWD": 3,
+	"BHD": 3,
+	"JOD": 3,
+	"OMR": 3,
+	"TND": 3,
+}
+
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func IsSupported(code string) bool {
+	_, ok := minorUnits[Normalize(code)]
+	return ok
+}
+
+func MinorUnits(code string) (int, error) {
+	n, ok := minorUnits[Normalize(code)]
+	if !ok {
+		return 0, fmt.Errorf("unsupported currency: %q", code)
+	}
+	return n, nil
+}
+
+// ValidateAmount checks that amount has no more decimal places than the
+// currency's minor unit allows (e.g. 10.5 JPY or 1.005 EUR are rejected).
+func ValidateAmount(code string, amount float64) error {
+	n, err := MinorUnits(code)
+	if err != nil {
+		return err
+	}
+	scaled := amount * math.Pow10(n)
+	if math.Abs(scaled-math.Round(scaled)) > 1e-6 {
+		return fmt.Errorf("amount %v has more than %d decimal places allowed for %s", amount, n, Normalize(code))
+	}
+	return nil
+}
+
+// Round rounds amount half away from zero to the currency's minor unit.
+func Round(code string, amount float64) float64 {
+	n, err := MinorUnits(code)
+	if err != nil {
+		n = 2
+	}
+	p := math.Pow10(n)
+	return math.Round(amount*p) / p
+}
+
+// Floor rounds amount down to the currency's minor unit.
+func Floor(code string, amount float64) float64 {
+	n, err := MinorUnits(code)
+	if err != nil {
+		n = 2
+	}
+	p := math.Pow10(n)
+	return math.Floor(amount*p+1e-9) / p
+}
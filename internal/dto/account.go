@@ -1,6 +1,16 @@
 package dto
 
-import "time"
+import (
+	"basic-gin/internal/model"
+	"time"
+)
+
+type AccountCreate struct {
+	ClientID int    `json:"client_id" binding:"required,min=1"`
+	Currency string `json:"currency"`
+	// AccountType is an account product code; defaults to "current".
+	AccountType string `json:"account_type" binding:"max=20"`
+}
 
 type AccountUpdate struct {
 	ID            int      `json:"id" binding:"required"`
@@ -10,9 +20,57 @@ type AccountUpdate struct {
 }
 
 type AccountResponse struct {
-	ID            int       `json:"id"`
-	ClientID      int       `json:"client_id"`
-	AccountNumber string    `json:"account_number"`
-	Balance       float64   `json:"balance"`
-	CreatedAt     time.Time `json:"created_at"`
+	ID            int    `json:"id"`
+	ClientID      int    `json:"client_id"`
+	AccountNumber string `json:"account_number"`
+	// LegacyAccountNumber is the number the account had before it was
+	// renumbered; it still resolves to this account.
+	LegacyAccountNumber string  `json:"legacy_account_number,omitempty"`
+	Balance             float64 `json:"balance"`
+	// AvailableBalance is Balance minus funds reserved by active holds.
+	AvailableBalance float64 `json:"available_balance"`
+	OverdraftLimit   float64 `json:"overdraft_limit"`
+	AccountType      string  `json:"account_type"`
+	Currency         string  `json:"currency"`
+	// Status is "active" or "frozen"; frozen accounts cannot be debited.
+	Status string `json:"status"`
+	// Role is the listing client's role on the account when accounts are
+	// listed by client: owner, joint_owner, authorized_user or view_only.
+	Role      string    `json:"role,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OverdraftLimitUpdate struct {
+	Limit  float64 `json:"limit" binding:"gte=0"`
+	Reason string  `json:"reason" binding:"required,max=500"`
+}
+
+// AccountRenumbered reports one account moved to the current numbering scheme.
+type AccountRenumbered struct {
+	ID        int    `json:"id"`
+	OldNumber string `json:"old_number"`
+	NewNumber string `json:"new_number"`
+}
+
+type AccountHolderCreate struct {
+	ClientID int    `json:"client_id" binding:"required,min=1"`
+	Role     string `json:"role" binding:"required"`
+	// SpendLimit caps what an authorized user may move out of the account
+	// a day; only authorized users have one.
+	SpendLimit float64 `json:"spend_limit" binding:"gte=0"`
+}
+
+type AccountHolderResponse struct {
+	ID         int     `json:"id"`
+	AccountID  int     `json:"account_id"`
+	ClientID   int     `json:"client_id"`
+	Role       string  `json:"role"`
+	SpendLimit float64 `json:"spend_limit,omitempty"`
+	// Status is pending until every consent is given, then active;
+	// declined and removed holders have no access.
+	Status    string                `json:"status"`
+	AddedBy   int                   `json:"added_by,omitempty"`
+	Consents  []model.HolderConsent `json:"consents"`
+	CreatedAt time.Time             `json:"created_at"`
+	UpdatedAt time.Time             `json:"updated_at"`
 }
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type ApprovalReject struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type ApprovalResponse struct {
+	ID         int             `json:"id"`
+	Operation  string          `json:"operation"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Payload    json.RawMessage `json:"payload"`
+	Status     string          `json:"status"`
+	Maker      string          `json:"maker"`
+	Checker    string          `json:"checker,omitempty"`
+	Reason     string          `json:"reason,omitempty"`
+	// Result is what the operation returned when it was approved.
+	Result    json.RawMessage `json:"result,omitempty"`
+	RequestID string          `json:"request_id,omitempty"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	DecidedAt *time.Time      `json:"decided_at,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+}
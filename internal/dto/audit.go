@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type AuditEntryResponse struct {
+	ID         int64          `json:"id"`
+	Actor      string         `json:"actor"`
+	Action     string         `json:"action"`
+	EntityType string         `json:"entity_type"`
+	EntityID   string         `json:"entity_id"`
+	Details    map[string]any `json:"details"`
+	RequestID  string         `json:"request_id,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+}
@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+type BeneficiaryCreate struct {
+	Nickname string `json:"nickname" binding:"required,max=70"`
+	// Account is the beneficiary's account number or IBAN.
+	Account string `json:"account" binding:"required,max=42"`
+	// Name is checked against the account holder like a transfer's
+	// beneficiary_name; a close match needs AcceptCloseMatch.
+	Name             string `json:"name" binding:"required,max=140"`
+	Currency         string `json:"currency" binding:"omitempty,len=3"`
+	AcceptCloseMatch bool   `json:"accept_close_match"`
+}
+
+type BeneficiaryUpdate struct {
+	Nickname string `json:"nickname" binding:"required,max=70"`
+}
+
+type BeneficiaryResponse struct {
+	ID            int    `json:"id"`
+	ClientID      int    `json:"client_id"`
+	Nickname      string `json:"nickname"`
+	AccountNumber string `json:"account_number"`
+	Name          string `json:"name"`
+	Currency      string `json:"currency"`
+	PayeeMatch    string `json:"payee_match"`
+	// Trusted is false during the cooling-off period, until TrustedAt.
+	Trusted   bool      `json:"trusted"`
+	TrustedAt time.Time `json:"trusted_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,80 @@
+package dto
+
+import "time"
+
+type CaseAssign struct {
+	// Assignee is the analyst taking the case; empty unassigns it.
+	Assignee string `json:"assignee" binding:"max=100"`
+}
+
+type CaseStatusUpdate struct {
+	Status string `json:"status" binding:"required"`
+	// Note is required when closing the case.
+	Note string `json:"note" binding:"max=5000"`
+}
+
+type CaseNoteCreate struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+// CaseAttachmentCreate registers a file already uploaded to document
+// storage.
+type CaseAttachmentCreate struct {
+	FileName    string `json:"file_name" binding:"required,max=255"`
+	ContentType string `json:"content_type" binding:"required,max=100"`
+	SizeBytes   int64  `json:"size_bytes" binding:"gte=0"`
+	SHA256      string `json:"sha256" binding:"omitempty,len=64,hexadecimal"`
+	StorageURL  string `json:"storage_url" binding:"required,url"`
+}
+
+type CaseAccountAction struct {
+	AccountID int    `json:"account_id" binding:"required,min=1"`
+	Reason    string `json:"reason" binding:"required,max=500"`
+}
+
+type CaseRelease struct {
+	ApprovalID int `json:"approval_id" binding:"required,min=1"`
+}
+
+type CaseResponse struct {
+	ID        int        `json:"id"`
+	ClientID  int        `json:"client_id"`
+	Status    string     `json:"status"`
+	Assignee  string     `json:"assignee,omitempty"`
+	OpenedBy  string     `json:"opened_by"`
+	ClosedBy  string     `json:"closed_by,omitempty"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type CaseNoteResponse struct {
+	ID        int       `json:"id"`
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CaseAttachmentResponse struct {
+	ID          int       `json:"id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256,omitempty"`
+	StorageURL  string    `json:"storage_url"`
+	UploadedBy  string    `json:"uploaded_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CaseDetailResponse is a case with everything an analyst works from: the
+// client and their accounts, the alerts and the transactions they were
+// raised on.
+type CaseDetailResponse struct {
+	CaseResponse
+	Client       ClientResponse            `json:"client"`
+	Accounts     []*AccountResponse        `json:"accounts"`
+	Alerts       []*AlertResponse          `json:"alerts"`
+	Transactions []*TransactionResponse    `json:"transactions"`
+	Notes        []*CaseNoteResponse       `json:"notes"`
+	Attachments  []*CaseAttachmentResponse `json:"attachments"`
+}
@@ -1,13 +1,30 @@
 package dto
 
+import (
+	"basic-gin/internal/model"
+	"time"
+)
+
+// ClientCreate describes an individual (the default type) by first_name,
+// last_name and birth_date, a business by registered_name,
+// registration_number, tax_id, incorporation_date and
+// incorporation_country.
 type ClientCreate struct {
+	Type             string `json:"type"`
 	FirstName        string `json:"first_name"`
 	LastName         string `json:"last_name"`
 	Email            string `json:"email"`
 	ResidenceAddress string `json:"residence_address"`
 	BirthDate        string `json:"birth_date"`
+
+	RegisteredName       string `json:"registered_name"`
+	RegistrationNumber   string `json:"registration_number"`
+	TaxID                string `json:"tax_id"`
+	IncorporationDate    string `json:"incorporation_date"`
+	IncorporationCountry string `json:"incorporation_country"`
 }
 
+// ClientUpdate takes the fields of the client's type, which cannot change.
 type ClientUpdate struct {
 	ID               int64  `json:"id"`
 	FirstName        string `json:"first_name"`
@@ -15,14 +32,144 @@ type ClientUpdate struct {
 	Email            string `json:"email"`
 	ResidenceAddress string `json:"residence_address"`
 	BirthDate        string `json:"birth_date"`
+
+	RegisteredName       string `json:"registered_name"`
+	RegistrationNumber   string `json:"registration_number"`
+	TaxID                string `json:"tax_id"`
+	IncorporationDate    string `json:"incorporation_date"`
+	IncorporationCountry string `json:"incorporation_country"`
 }
 
 type ClientResponse struct {
 	ID               int64  `json:"id"`
-	FirstName        string `json:"first_name"`
-	LastName         string `json:"last_name"`
+	Type             string `json:"type"`
+	FirstName        string `json:"first_name,omitempty"`
+	LastName         string `json:"last_name,omitempty"`
 	Email            string `json:"email"`
+	EmailVerified    bool   `json:"email_verified"`
 	ResidenceAddress string `json:"residence_address"`
-	BirthDate        string `json:"birth_date"`
-	CreatedAt        string `json:"created_at"`
+	BirthDate        string `json:"birth_date,omitempty"`
+
+	RegisteredName       string `json:"registered_name,omitempty"`
+	RegistrationNumber   string `json:"registration_number,omitempty"`
+	TaxID                string `json:"tax_id,omitempty"`
+	IncorporationDate    string `json:"incorporation_date,omitempty"`
+	IncorporationCountry string `json:"incorporation_country,omitempty"`
+
+	// PendingEmail is the address the client is moving to once it confirms
+	// it; it is only filled in on update.
+	PendingEmail string `json:"pending_email,omitempty"`
+	// MergedInto is the client this one was merged into.
+	MergedInto int64 `json:"merged_into,omitempty"`
+	// ErasedAt is when the client's personal data was erased.
+	ErasedAt  string `json:"erased_at,omitempty"`
+	CreatedAt string `json:"created_at"`
+	// PossibleDuplicates lists existing clients resembling the one just
+	// saved; it is only filled in on create and update.
+	PossibleDuplicates []ClientMatch `json:"possible_duplicates,omitempty"`
+}
+
+// ClientMatch is an existing client born on the same day whose name, and
+// address when both are known, resemble another client's. Scores run from
+// 0 to 1.
+type ClientMatch struct {
+	ClientID     int64   `json:"client_id"`
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	Email        string  `json:"email"`
+	BirthDate    string  `json:"birth_date"`
+	NameScore    float64 `json:"name_score"`
+	AddressScore float64 `json:"address_score,omitempty"`
+	Score        float64 `json:"score"`
+}
+
+type EmailVerifyRequest struct {
+	Token string `json:"token" binding:"required,max=100"`
+}
+
+// ClientEmailStatus tells whether the client confirmed its email and which
+// address, if any, it is moving to.
+type ClientEmailStatus struct {
+	ClientID         int64      `json:"client_id"`
+	Email            string     `json:"email"`
+	Verified         bool       `json:"verified"`
+	VerifiedAt       *time.Time `json:"verified_at,omitempty"`
+	PendingEmail     string     `json:"pending_email,omitempty"`
+	PendingExpiresAt *time.Time `json:"pending_expires_at,omitempty"`
+}
+
+type ClientMergeRequest struct {
+	SurvivorID  int64  `json:"survivor_id" binding:"required"`
+	DuplicateID int64  `json:"duplicate_id" binding:"required"`
+	Reason      string `json:"reason" binding:"required,max=1000"`
+}
+
+type ClientMergeResponse struct {
+	ID          int                    `json:"id"`
+	SurvivorID  int                    `json:"survivor_id"`
+	DuplicateID int                    `json:"duplicate_id"`
+	Reason      string                 `json:"reason"`
+	MergedBy    string                 `json:"merged_by"`
+	Moved       model.ClientMergeMoved `json:"moved"`
+	CreatedAt   time.Time              `json:"created_at"`
+}
+
+type ClientEraseRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+type ClientErasureResponse struct {
+	ID        int                `json:"id"`
+	ClientID  int                `json:"client_id"`
+	Reason    string             `json:"reason"`
+	ErasedBy  string             `json:"erased_by"`
+	Erased    model.ClientErased `json:"erased"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+// ClientExport is the personal data held about a client, as handed out on
+// a data access request: its record and contact details, the accounts it
+// holds a role on, the transactions on them and the audit entries about
+// it. Erasure is set once the client was erased.
+type ClientExport struct {
+	ExportedAt   time.Time                `json:"exported_at"`
+	Client       ClientResponse           `json:"client"`
+	Addresses    []*ClientAddressResponse `json:"addresses"`
+	Phones       []*ClientPhoneResponse   `json:"phones"`
+	Emails       []*ClientEmailResponse   `json:"emails"`
+	Accounts     []*AccountResponse       `json:"accounts"`
+	AccountRoles []*AccountHolderResponse `json:"account_roles"`
+	Transactions []*TransactionResponse   `json:"transactions"`
+	AuditEntries []*AuditEntryResponse    `json:"audit_entries"`
+	Erasure      *ClientErasureResponse   `json:"erasure,omitempty"`
+}
+
+// BusinessPartyCreate links individual client_id to a business as a
+// beneficial_owner, with its ownership_percent, or as a signatory.
+type BusinessPartyCreate struct {
+	ClientID         int64   `json:"client_id" binding:"required"`
+	Role             string  `json:"role" binding:"required"`
+	OwnershipPercent float64 `json:"ownership_percent"`
+	Title            string  `json:"title" binding:"max=100"`
+}
+
+type BusinessPartyResponse struct {
+	ID               int       `json:"id"`
+	BusinessID       int64     `json:"business_id"`
+	ClientID         int64     `json:"client_id"`
+	Name             string    `json:"name"`
+	Role             string    `json:"role"`
+	OwnershipPercent float64   `json:"ownership_percent,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// KYBResponse is the outcome of the know-your-business checks on a business
+// client; accounts open only while Passed.
+type KYBResponse struct {
+	ClientID         int64                    `json:"client_id"`
+	Passed           bool                     `json:"passed"`
+	Problems         []string                 `json:"problems"`
+	OwnershipPercent float64                  `json:"ownership_percent"`
+	Parties          []*BusinessPartyResponse `json:"parties"`
 }
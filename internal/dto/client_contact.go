@@ -0,0 +1,64 @@
+package dto
+
+import "time"
+
+type ClientAddressRequest struct {
+	Street      string `json:"street" binding:"required,max=200"`
+	HouseNumber string `json:"house_number" binding:"max=20"`
+	PostalCode  string `json:"postal_code" binding:"max=20"`
+	City        string `json:"city" binding:"required,max=100"`
+	// Country is an ISO 3166-1 alpha-2 code.
+	Country string `json:"country" binding:"required,len=2"`
+}
+
+type ClientPhoneCreate struct {
+	// Number is converted to E.164; it must include the country code.
+	Number  string `json:"number" binding:"required,max=32"`
+	Type    string `json:"type"`
+	Primary bool   `json:"primary"`
+}
+
+type ClientEmailCreate struct {
+	Email   string `json:"email" binding:"required,email,max=150"`
+	Primary bool   `json:"primary"`
+}
+
+type ClientAddressResponse struct {
+	ID          int        `json:"id"`
+	Type        string     `json:"type"`
+	Street      string     `json:"street,omitempty"`
+	HouseNumber string     `json:"house_number,omitempty"`
+	PostalCode  string     `json:"postal_code,omitempty"`
+	City        string     `json:"city,omitempty"`
+	Country     string     `json:"country,omitempty"`
+	Raw         string     `json:"raw,omitempty"`
+	ValidFrom   time.Time  `json:"valid_from"`
+	ValidTo     *time.Time `json:"valid_to,omitempty"`
+}
+
+type ClientPhoneResponse struct {
+	ID        int       `json:"id"`
+	Number    string    `json:"number"`
+	Type      string    `json:"type"`
+	Primary   bool      `json:"primary"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ClientEmailResponse is one of a client's email addresses. The primary one
+// is the client's email and has no id; Pending marks the address the client
+// is moving to once it confirms it.
+type ClientEmailResponse struct {
+	ID      int    `json:"id,omitempty"`
+	Email   string `json:"email"`
+	Primary bool   `json:"primary"`
+	Pending bool   `json:"pending,omitempty"`
+}
+
+// ClientContactsResponse holds a client's current addresses, phone numbers
+// and email addresses, primary ones first.
+type ClientContactsResponse struct {
+	ClientID  int64                    `json:"client_id"`
+	Addresses []*ClientAddressResponse `json:"addresses"`
+	Phones    []*ClientPhoneResponse   `json:"phones"`
+	Emails    []*ClientEmailResponse   `json:"emails"`
+}
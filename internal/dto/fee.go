@@ -0,0 +1,48 @@
+package dto
+
+import (
+	"basic-gin/internal/model"
+	"time"
+)
+
+type FeeQuoteResponse struct {
+	AccountID int     `json:"account_id"`
+	Operation string  `json:"operation"`
+	Amount    float64 `json:"amount"`
+	Fee       float64 `json:"fee"`
+	Total     float64 `json:"total"`
+	Currency  string  `json:"currency"`
+	// Kind is the schedule type applied, empty when the operation is free.
+	Kind string `json:"kind,omitempty"`
+	// Waived is set when the fee was covered by the free monthly quota.
+	Waived        bool `json:"waived"`
+	FreeRemaining int  `json:"free_remaining"`
+}
+
+type FeeScheduleUpsert struct {
+	AccountType  string          `json:"account_type" binding:"required,max=20"`
+	Operation    string          `json:"operation" binding:"required,oneof=transfer withdrawal"`
+	Currency     string          `json:"currency" binding:"required,len=3"`
+	Kind         string          `json:"kind" binding:"required,oneof=flat percentage tiered"`
+	FlatAmount   float64         `json:"flat_amount" binding:"gte=0"`
+	Percent      float64         `json:"percent" binding:"gte=0,lte=100"`
+	MinFee       float64         `json:"min_fee" binding:"gte=0"`
+	MaxFee       *float64        `json:"max_fee" binding:"omitempty,gte=0"`
+	Tiers        []model.FeeTier `json:"tiers"`
+	FreePerMonth int             `json:"free_per_month" binding:"gte=0"`
+}
+
+type FeeScheduleResponse struct {
+	ID           int             `json:"id"`
+	AccountType  string          `json:"account_type"`
+	Operation    string          `json:"operation"`
+	Currency     string          `json:"currency"`
+	Kind         string          `json:"kind"`
+	FlatAmount   float64         `json:"flat_amount"`
+	Percent      float64         `json:"percent"`
+	MinFee       float64         `json:"min_fee"`
+	MaxFee       *float64        `json:"max_fee,omitempty"`
+	Tiers        []model.FeeTier `json:"tiers"`
+	FreePerMonth int             `json:"free_per_month"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
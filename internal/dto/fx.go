@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type FXQuoteResponse struct {
+	ID           string    `json:"quote_id"`
+	FromCurrency string    `json:"from_currency"`
+	ToCurrency   string    `json:"to_currency"`
+	MidRate      float64   `json:"mid_rate"`
+	Rate         float64   `json:"rate"`
+	SpreadBps    int       `json:"spread_bps"`
+	SellAmount   float64   `json:"sell_amount"`
+	BuyAmount    float64   `json:"buy_amount"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type FXRateResponse struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Rate  float64 `json:"rate"`
+}
+
+type FXRatesResponse struct {
+	Source   string           `json:"source"`
+	LoadedAt time.Time        `json:"loaded_at"`
+	Rates    []FXRateResponse `json:"rates"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type HoldCreate struct {
+	Amount float64 `json:"amount" binding:"required,gt=0"`
+	// ExpiresInSeconds defaults to config.App.HoldDefaultTTL when zero.
+	ExpiresInSeconds int    `json:"expires_in_seconds" binding:"gte=0"`
+	Reference        string `json:"reference" binding:"max=100"`
+}
+
+type HoldCapture struct {
+	// Amount defaults to the full hold amount; any remainder is released.
+	Amount float64 `json:"amount" binding:"gte=0"`
+	// ToAccount, an account number or IBAN, settles the capture as a
+	// transfer; otherwise it is a withdrawal.
+	ToAccount string `json:"to_account,omitempty" binding:"max=42"`
+}
+
+type HoldResponse struct {
+	ID             int       `json:"id"`
+	AccountID      int       `json:"account_id"`
+	Amount         float64   `json:"amount"`
+	CapturedAmount float64   `json:"captured_amount"`
+	Currency       string    `json:"currency"`
+	Status         string    `json:"status"`
+	Reference      string    `json:"reference,omitempty"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	TransactionID  *int      `json:"transaction_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
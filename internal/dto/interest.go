@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+type AccountProductUpsert struct {
+	Name string `json:"name" binding:"required,max=100"`
+	// AnnualRate is a percentage, e.g. 2.5 for 2.5% a year.
+	AnnualRate float64 `json:"annual_rate" binding:"gte=0,lte=100"`
+}
+
+type AccountProductResponse struct {
+	Code       string    `json:"code"`
+	Name       string    `json:"name"`
+	AnnualRate float64   `json:"annual_rate"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Interest amounts below are exact decimals encoded as strings.
+
+type InterestAccrualResponse struct {
+	ID               int    `json:"id"`
+	AccountID        int    `json:"account_id"`
+	Date             string `json:"date"`
+	Balance          string `json:"balance"`
+	AnnualRate       string `json:"annual_rate"`
+	Amount           string `json:"amount"`
+	CapitalizationID int    `json:"capitalization_id,omitempty"`
+}
+
+type InterestCapitalizationResponse struct {
+	ID            int        `json:"id,omitempty"`
+	Period        string     `json:"period"`
+	Accrued       string     `json:"accrued"`
+	CarriedIn     string     `json:"carried_in"`
+	Posted        string     `json:"posted"`
+	CarryOut      string     `json:"carry_out"`
+	TransactionID int        `json:"transaction_id,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+}
+
+type InterestRunRequest struct {
+	// AsOf defaults to today (UTC): days before it are accrued and months
+	// ending before its month are capitalized.
+	AsOf      string `json:"as_of"`
+	DryRun    bool   `json:"dry_run"`
+	AccountID int    `json:"account_id" binding:"gte=0"`
+}
+
+type InterestAccountReport struct {
+	AccountID       int                               `json:"account_id"`
+	Currency        string                            `json:"currency,omitempty"`
+	AnnualRate      string                            `json:"annual_rate,omitempty"`
+	DaysAccrued     int                               `json:"days_accrued"`
+	Accrued         string                            `json:"accrued"`
+	Capitalizations []*InterestCapitalizationResponse `json:"capitalizations"`
+	Error           string                            `json:"error,omitempty"`
+}
+
+type InterestRunReport struct {
+	AsOf     string                   `json:"as_of"`
+	DryRun   bool                     `json:"dry_run"`
+	Accounts []*InterestAccountReport `json:"accounts"`
+}
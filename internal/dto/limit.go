@@ -0,0 +1,63 @@
+package dto
+
+import "time"
+
+type TransferLimitUpsert struct {
+	Scope string `json:"scope" binding:"required,oneof=product client account"`
+	// Exactly the subject matching Scope must be set.
+	AccountType string `json:"account_type" binding:"max=20"`
+	ClientID    int    `json:"client_id" binding:"gte=0"`
+	AccountID   int    `json:"account_id" binding:"gte=0"`
+	Operation   string `json:"operation" binding:"required,oneof=transfer withdrawal"`
+	// Currency is required for product and client limits; account limits
+	// use the account's currency.
+	Currency string `json:"currency" binding:"omitempty,len=3"`
+	// A cap left out is inherited from the less specific scopes.
+	PerTransaction *float64 `json:"per_transaction" binding:"omitempty,gt=0"`
+	Daily          *float64 `json:"daily" binding:"omitempty,gt=0"`
+	Monthly        *float64 `json:"monthly" binding:"omitempty,gt=0"`
+}
+
+type TransferLimitResponse struct {
+	ID             int       `json:"id"`
+	Scope          string    `json:"scope"`
+	AccountType    string    `json:"account_type,omitempty"`
+	ClientID       int       `json:"client_id,omitempty"`
+	AccountID      int       `json:"account_id,omitempty"`
+	Operation      string    `json:"operation"`
+	Currency       string    `json:"currency"`
+	PerTransaction *float64  `json:"per_transaction,omitempty"`
+	Daily          *float64  `json:"daily,omitempty"`
+	Monthly        *float64  `json:"monthly,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AccountLimitsResponse struct {
+	AccountID int                        `json:"account_id"`
+	Currency  string                     `json:"currency"`
+	Limits    []*OperationLimitsResponse `json:"limits"`
+}
+
+// OperationLimitsResponse shows the caps on one operation; a missing cap
+// means the operation is not limited that way.
+type OperationLimitsResponse struct {
+	Operation      string              `json:"operation"`
+	PerTransaction *LimitCapResponse   `json:"per_transaction,omitempty"`
+	Daily          *LimitUsageResponse `json:"daily,omitempty"`
+	Monthly        *LimitUsageResponse `json:"monthly,omitempty"`
+}
+
+type LimitCapResponse struct {
+	Limit float64 `json:"limit"`
+	// Source is the scope the cap comes from: product, client or account.
+	Source string `json:"source"`
+}
+
+type LimitUsageResponse struct {
+	Limit     float64   `json:"limit"`
+	Source    string    `json:"source"`
+	Used      float64   `json:"used"`
+	Remaining float64   `json:"remaining"`
+	ResetsAt  time.Time `json:"resets_at"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"basic-gin/internal/monitoring"
+	"time"
+)
+
+type MonitoringRulesResponse struct {
+	Source   string            `json:"source"`
+	LoadedAt time.Time         `json:"loaded_at"`
+	Review   int               `json:"review"`
+	Block    int               `json:"block"`
+	Rules    []monitoring.Rule `json:"rules"`
+}
+
+type AlertClose struct {
+	Note string `json:"note" binding:"required,max=1000"`
+}
+
+type AlertHitResponse struct {
+	Rule   string `json:"rule"`
+	Kind   string `json:"kind"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+type AlertResponse struct {
+	ID                    int                `json:"id"`
+	Operation             string             `json:"operation"`
+	AccountID             int                `json:"account_id"`
+	CounterpartyAccountID int                `json:"counterparty_account_id,omitempty"`
+	Amount                float64            `json:"amount"`
+	Currency              string             `json:"currency"`
+	Score                 int                `json:"score"`
+	Action                string             `json:"action"`
+	Hits                  []AlertHitResponse `json:"hits"`
+	TransactionID         string             `json:"transaction_id,omitempty"`
+	ApprovalID            int                `json:"approval_id,omitempty"`
+	CaseID                int                `json:"case_id,omitempty"`
+	Status                string             `json:"status"`
+	ClosedBy              string             `json:"closed_by,omitempty"`
+	Note                  string             `json:"note,omitempty"`
+	ClosedAt              *time.Time         `json:"closed_at,omitempty"`
+	CreatedAt             time.Time          `json:"created_at"`
+}
+
+// MonitoringReplayed is one historical transaction on which rules fired
+// during a replay.
+type MonitoringReplayed struct {
+	TransactionID string             `json:"transaction_id"`
+	Operation     string             `json:"operation"`
+	AccountID     int                `json:"account_id"`
+	Amount        float64            `json:"amount"`
+	Currency      string             `json:"currency"`
+	At            time.Time          `json:"at"`
+	Score         int                `json:"score"`
+	Action        string             `json:"action"`
+	Hits          []AlertHitResponse `json:"hits"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+type PayeeVerify struct {
+	// Account is an account number or IBAN.
+	Account string `json:"account" binding:"required,max=42"`
+	Name    string `json:"name" binding:"required,max=140"`
+}
+
+type PayeeVerifyResponse struct {
+	// Result is "match", "close_match" or "no_match".
+	Result string `json:"result"`
+	// AccountName is the holder's name, disclosed on a close match only so
+	// the payer can correct it.
+	AccountName string `json:"account_name,omitempty"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type PaymentBatchSubmit struct {
+	// Format is "pain001" or "csv"; when empty it is taken from the
+	// request's Content-Type.
+	Format string `form:"format" binding:"omitempty,oneof=pain001 csv"`
+	// Mode is "all_or_nothing" (the default) or "best_effort".
+	Mode string `form:"mode" binding:"omitempty,oneof=all_or_nothing best_effort"`
+}
+
+type PaymentBatchResponse struct {
+	ID             int        `json:"id"`
+	Format         string     `json:"format"`
+	Mode           string     `json:"mode"`
+	Status         string     `json:"status"`
+	MessageID      string     `json:"message_id,omitempty"`
+	ControlSum     float64    `json:"control_sum"`
+	CreatedBy      string     `json:"created_by,omitempty"`
+	Error          string     `json:"error,omitempty"`
+	ItemCount      int        `json:"item_count"`
+	PendingCount   int        `json:"pending_count"`
+	InvalidCount   int        `json:"invalid_count"`
+	SucceededCount int        `json:"succeeded_count"`
+	FailedCount    int        `json:"failed_count"`
+	SkippedCount   int        `json:"skipped_count"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	// Errors lists the lines that failed validation; it is only filled in
+	// on upload.
+	Errors []*PaymentBatchItemResponse `json:"errors,omitempty"`
+}
+
+type PaymentBatchItemResponse struct {
+	ID                int       `json:"id"`
+	LineNo            int       `json:"line_no"`
+	FromAccountNumber string    `json:"from_account_number"`
+	ToAccountNumber   string    `json:"to_account_number"`
+	FromAccountID     int       `json:"from_account_id,omitempty"`
+	ToAccountID       int       `json:"to_account_id,omitempty"`
+	Amount            float64   `json:"amount"`
+	Currency          string    `json:"currency,omitempty"`
+	EndToEndID        string    `json:"end_to_end_id,omitempty"`
+	Description       string    `json:"description,omitempty"`
+	Status            string    `json:"status"`
+	Error             string    `json:"error,omitempty"`
+	TransactionID     int       `json:"transaction_id,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
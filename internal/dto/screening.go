@@ -0,0 +1,45 @@
+package dto
+
+import "time"
+
+type ScreeningHitReview struct {
+	Note string `json:"note" binding:"required,max=1000"`
+}
+
+type ScreeningHitResponse struct {
+	ID          int    `json:"id"`
+	ClientID    int    `json:"client_id"`
+	ListVersion string `json:"list_version"`
+	EntryID     string `json:"entry_id"`
+	EntryName   string `json:"entry_name"`
+	Program     string `json:"program,omitempty"`
+	// MatchedName is the entry name or alias closest to the client's.
+	MatchedName    string     `json:"matched_name"`
+	ScreenedName   string     `json:"screened_name"`
+	Score          float64    `json:"score"`
+	BirthDateMatch bool       `json:"birth_date_match"`
+	Status         string     `json:"status"`
+	ReviewedBy     string     `json:"reviewed_by,omitempty"`
+	Note           string     `json:"note,omitempty"`
+	ReviewedAt     *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type ScreeningRunResponse struct {
+	ListVersion string    `json:"list_version"`
+	Clients     int       `json:"clients"`
+	Hits        int       `json:"hits"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// ScreeningListResponse describes the sanctions list in force and the last
+// rescreening of all clients.
+type ScreeningListResponse struct {
+	Source    string                `json:"source"`
+	Version   string                `json:"version"`
+	Entries   int                   `json:"entries"`
+	Threshold float64               `json:"threshold"`
+	LoadedAt  time.Time             `json:"loaded_at"`
+	LastRun   *ScreeningRunResponse `json:"last_run,omitempty"`
+}
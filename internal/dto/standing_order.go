@@ -0,0 +1,50 @@
+package dto
+
+import "time"
+
+type StandingOrderCreate struct {
+	// FromAccount and ToAccount take an account number or IBAN.
+	FromAccount string  `json:"from_account" binding:"required,max=42"`
+	ToAccount   string  `json:"to_account" binding:"required,max=42"`
+	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	// Schedule is a five-field cron expression ("0 9 1 * *") or an
+	// RRULE-like interval ("FREQ=MONTHLY;BYMONTHDAY=1").
+	Schedule  string     `json:"schedule" binding:"required,max=200"`
+	Reference string     `json:"reference" binding:"max=140"`
+	StartAt   *time.Time `json:"start_at"`
+	EndAt     *time.Time `json:"end_at"`
+	// MaxRetries and RetryIntervalSeconds override the configured retry
+	// policy for insufficient funds when set.
+	MaxRetries           *int `json:"max_retries" binding:"omitempty,gte=0,lte=50"`
+	RetryIntervalSeconds *int `json:"retry_interval_seconds" binding:"omitempty,gte=60"`
+}
+
+type StandingOrderResponse struct {
+	ID                   int        `json:"id"`
+	FromAccountID        int        `json:"from_account_id"`
+	ToAccountID          int        `json:"to_account_id"`
+	Amount               float64    `json:"amount"`
+	Currency             string     `json:"currency"`
+	Schedule             string     `json:"schedule"`
+	Reference            string     `json:"reference,omitempty"`
+	StartAt              time.Time  `json:"start_at"`
+	EndAt                *time.Time `json:"end_at,omitempty"`
+	Status               string     `json:"status"`
+	MaxRetries           int        `json:"max_retries"`
+	RetryIntervalSeconds int        `json:"retry_interval_seconds"`
+	NextOccurrence       *time.Time `json:"next_occurrence,omitempty"`
+	NextRunAt            *time.Time `json:"next_run_at,omitempty"`
+	Attempts             int        `json:"attempts"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+type StandingOrderExecutionResponse struct {
+	ID            int       `json:"id"`
+	ScheduledFor  time.Time `json:"scheduled_for"`
+	Attempt       int       `json:"attempt"`
+	Status        string    `json:"status"`
+	TransactionID *int      `json:"transaction_id,omitempty"`
+	Error         string    `json:"error,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
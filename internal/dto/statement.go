@@ -0,0 +1,9 @@
+package dto
+
+type StatementQuery struct {
+	// From and To are YYYY-MM-DD days, inclusive; both default to the
+	// previous calendar month.
+	From   string `form:"from"`
+	To     string `form:"to"`
+	Format string `form:"format"`
+}
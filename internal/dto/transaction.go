@@ -1,15 +1,75 @@
 package dto
 
 type TransactionCreate struct {
-	FromAccountID int     `json:"from_account_id" binding:"required"`
-	ToAccountID   int     `json:"to_account_id" binding:"required"`
+	// FromAccount and ToAccount take an account number or IBAN.
+	FromAccount string `json:"from_account,omitempty" binding:"max=42"`
+	ToAccount   string `json:"to_account,omitempty" binding:"max=42"`
+	// FromAccountID and ToAccountID are resolved from FromAccount, ToAccount
+	// or BeneficiaryID; whatever the body says is overwritten. They are kept
+	// in the payload of a transfer parked for approval.
+	FromAccountID int `json:"from_account_id,omitempty"`
+	ToAccountID   int `json:"to_account_id,omitempty"`
+	// BeneficiaryID sends to one of the payer's saved beneficiaries instead
+	// of ToAccount.
+	BeneficiaryID int     `json:"beneficiary_id,omitempty" binding:"omitempty,min=1"`
 	Amount        float64 `json:"amount" binding:"required,gt=0"`
+	// BeneficiaryName, when set, is checked against the recipient account's
+	// holder before any funds move. A close match is refused unless
+	// AcceptCloseMatch confirms the payer has seen the holder's name.
+	BeneficiaryName  string `json:"beneficiary_name,omitempty" binding:"max=140"`
+	AcceptCloseMatch bool   `json:"accept_close_match,omitempty"`
+	// QuoteID references a locked FX quote from GET /fx/quote and is required
+	// when the two accounts are held in different currencies.
+	QuoteID string `json:"quote_id,omitempty"`
+	// IdempotencyKey may also be sent as the Idempotency-Key header.
+	IdempotencyKey string `json:"idempotency_key,omitempty" binding:"max=120"`
+	Description    string `json:"description,omitempty" binding:"max=140"`
+	// InitiatedBy is the client making the transfer, taken from the
+	// X-Client-ID header; whatever the body says is overwritten.
+	InitiatedBy int `json:"initiated_by,omitempty"`
 }
 
 type TransactionResponse struct {
-	ID            string  `json:"id"`
-	FromAccountID int     `json:"from_account_id"`
-	ToAccountID   int     `json:"to_account_id"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// FromAccount and ToAccount are account numbers. The internal
+	// FromAccountID and ToAccountID are only shown to operators.
+	FromAccount   string  `json:"from_account,omitempty"`
+	ToAccount     string  `json:"to_account,omitempty"`
+	FromAccountID int     `json:"from_account_id,omitempty"`
+	ToAccountID   int     `json:"to_account_id,omitempty"`
 	Amount        float64 `json:"amount"`
-	CreatedAt     string  `json:"created_at"`
+	Currency      string  `json:"currency"`
+	ToAmount      float64 `json:"to_amount,omitempty"`
+	ToCurrency    string  `json:"to_currency,omitempty"`
+	FXRate        float64 `json:"fx_rate,omitempty"`
+	FXMidRate     float64 `json:"fx_mid_rate,omitempty"`
+	FXSpreadBps   int     `json:"fx_spread_bps,omitempty"`
+	FXQuoteID     string  `json:"fx_quote_id,omitempty"`
+	Description   string  `json:"description,omitempty"`
+	// ReversalOf is set on reversals; Reversals and ReversedAmount on the
+	// transfers they compensate.
+	ReversalOf     int     `json:"reversal_of,omitempty"`
+	Reversals      []int   `json:"reversals,omitempty"`
+	ReversedAmount float64 `json:"reversed_amount,omitempty"`
+	// FeeFor is set on fee transactions; Fee on the transaction charged.
+	FeeFor int     `json:"fee_for,omitempty"`
+	Fee    float64 `json:"fee,omitempty"`
+	// InitiatedBy is the account holder that made the transaction, when it
+	// was made with X-Client-ID.
+	InitiatedBy int `json:"initiated_by,omitempty"`
+	// PayeeMatch is the outcome of the beneficiary name check, if one was
+	// requested.
+	PayeeMatch string `json:"payee_match,omitempty"`
+	CreatedAt  string `json:"created_at"`
+}
+
+type TransactionReverse struct {
+	// Amount defaults to everything not yet reversed; it is expressed in the
+	// original transfer's (debited) currency.
+	Amount float64 `json:"amount" binding:"gte=0"`
+	Reason string  `json:"reason" binding:"required,max=255"`
+	// AllowPartial reverses only what the recipient can still cover instead
+	// of failing when their account lacks the funds.
+	AllowPartial bool `json:"allow_partial"`
 }
@@ -0,0 +1,171 @@
+package fx
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"basic-gin/internal/currency"
+)
+
+// Rate is a mid-market rate: 1 unit of Base buys Rate units of Quote.
+type Rate struct {
+	Base  string  `json:"base"`
+	Quote string  `json:"quote"`
+	Rate  float64 `json:"rate"`
+}
+
+// RateTable is an in-memory set of mid rates loaded from a local feed file.
+// It is safe for concurrent use and can be reloaded while serving.
+type RateTable struct {
+	mu       sync.RWMutex
+	rates    map[string]float64
+	source   string
+	loadedAt time.Time
+}
+
+func NewRateTable() *RateTable {
+	return &RateTable{rates: map[string]float64{}}
+}
+
+func pairKey(base, quote string) string { return base + "/" + quote }
+
+// LoadFile replaces the table with rates read from a .csv or .json file.
+//
+// CSV rows are "base,quote,rate" (a header row is allowed); JSON is an array
+// of {"base","quote","rate"} objects.
+func (t *RateTable) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open rates file: %w", err)
+	}
+	defer f.Close()
+
+	var rates []Rate
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		rates, err = parseCSV(f)
+	case ".json":
+		rates, err = parseJSON(f)
+	default:
+		return fmt.Errorf("unsupported rates file format: %s", path)
+	}
+	if err != nil {
+		return err
+	}
+
+	next := make(map[string]float64, len(rates))
+	for _, r := range rates {
+		base, quote := currency.Normalize(r.Base), currency.Normalize(r.Quote)
+		if !currency.IsSupported(base) || !currency.IsSupported(quote) {
+			return fmt.Errorf("rates file: unsupported pair %s/%s", r.Base, r.Quote)
+		}
+		if r.Rate <= 0 {
+			return fmt.Errorf("rates file: rate for %s/%s must be positive", base, quote)
+		}
+		next[pairKey(base, quote)] = r.Rate
+	}
+
+	t.mu.Lock()
+	t.rates = next
+	t.source = path
+	t.loadedAt = time.Now().UTC()
+	t.mu.Unlock()
+	return nil
+}
+
+func parseCSV(r io.Reader) ([]Rate, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 3
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse rates csv: %w", err)
+	}
+	out := make([]Rate, 0, len(records))
+	for i, rec := range records {
+		if i == 0 && strings.EqualFold(rec[0], "base") {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(rec[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse rates csv line %d: %w", i+1, err)
+		}
+		out = append(out, Rate{Base: rec[0], Quote: rec[1], Rate: v})
+	}
+	return out, nil
+}
+
+func parseJSON(r io.Reader) ([]Rate, error) {
+	var out []Rate
+	if err := json.NewDecoder(r).Decode(&out); err != nil {
+		return nil, fmt.Errorf("parse rates json: %w", err)
+	}
+	return out, nil
+}
+
+// Mid returns the mid rate for converting from -> to. Inverse pairs are
+// derived automatically and, failing that, a cross rate through any common
+// currency is used.
+func (t *RateTable) Mid(from, to string) (float64, error) {
+	from, to = currency.Normalize(from), currency.Normalize(to)
+	if from == to {
+		return 1, nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if r, ok := t.direct(from, to); ok {
+		return r, nil
+	}
+	for key := range t.rates {
+		via := strings.SplitN(key, "/", 2)
+		for _, c := range via {
+			if c == from || c == to {
+				continue
+			}
+			a, ok1 := t.direct(from, c)
+			b, ok2 := t.direct(c, to)
+			if ok1 && ok2 {
+				return a * b, nil
+			}
+		}
+	}
+	return 0, errors.New("no exchange rate for " + from + "/" + to)
+}
+
+func (t *RateTable) direct(from, to string) (float64, bool) {
+	if r, ok := t.rates[pairKey(from, to)]; ok {
+		return r, true
+	}
+	if r, ok := t.rates[pairKey(to, from)]; ok {
+		return 1 / r, true
+	}
+	return 0, false
+}
+
+// Snapshot returns a copy of the loaded rates together with load metadata.
+func (t *RateTable) Snapshot() (rates []Rate, source string, loadedAt time.Time) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	rates = make([]Rate, 0, len(t.rates))
+	for key, v := range t.rates {
+		p := strings.SplitN(key, "/", 2)
+		rates = append(rates, Rate{Base: p[0], Quote: p[1], Rate: v})
+	}
+	sort.Slice(rates, func(i, j int) bool {
+		return pairKey(rates[i].Base, rates[i].Quote) < pairKey(rates[j].Base, rates[j].Quote)
+	})
+	return rates, t.source, t.loadedAt
+}
@@ -1,7 +1,10 @@
 package handler
 
 import (
+	"basic-gin/internal/dto"
 	"basic-gin/internal/service"
+	"context"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -23,10 +26,14 @@ func (h *AccountHandler) Register(rg *gin.RouterGroup) {
 	rg.POST("", h.Create)                // POST   /accounts
 	rg.POST("/:id/deposit", h.Deposit)   // POST   /accounts/:id/deposit
 	rg.POST("/:id/withdraw", h.Withdraw) // POST  /accounts/:id/withdraw
+
+	rg.POST("/by-number/:number/deposit", h.byNumber(h.svc.DepositByNumber))   // POST   /accounts/by-number/:number/deposit
+	rg.POST("/by-number/:number/withdraw", h.byNumber(h.svc.WithdrawByNumber)) // POST   /accounts/by-number/:number/withdraw
 }
 
-type accountCreateReq struct {
-	ClientID int `json:"client_id" binding:"required,min=1"`
+// RegisterAdmin mounts operator-only account routes on the admin group.
+func (h *AccountHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.PUT("/accounts/:id/overdraft-limit", h.SetOverdraftLimit) // PUT    /admin/accounts/:id/overdraft-limit
 }
 
 type amountReq struct {
@@ -42,11 +49,7 @@ func (h *AccountHandler) GetByID(c *gin.Context) {
 	ctx := c.Request.Context()
 	res, err := h.svc.GetById(ctx, id)
 	if err != nil {
-		code := http.StatusBadRequest
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			code = http.StatusNotFound
-		}
-		h.respondError(c, code, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -66,30 +69,22 @@ func (h *AccountHandler) ListByClient(c *gin.Context) {
 	ctx := c.Request.Context()
 	res, err := h.svc.GetByClientId(ctx, clientID)
 	if err != nil {
-		code := http.StatusBadRequest
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			code = http.StatusNotFound
-		}
-		h.respondError(c, code, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
 }
 
 func (h *AccountHandler) Create(c *gin.Context) {
-	var in accountCreateReq
+	var in dto.AccountCreate
 	if err := c.ShouldBindJSON(&in); err != nil {
 		h.respondError(c, http.StatusBadRequest, err)
 		return
 	}
 	ctx := c.Request.Context()
-	out, err := h.svc.Save(ctx, in.ClientID)
+	out, err := h.svc.Save(ctx, in)
 	if err != nil {
-		code := http.StatusBadRequest
-		if strings.Contains(strings.ToLower(err.Error()), "not found") {
-			code = http.StatusNotFound
-		}
-		h.respondError(c, code, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusCreated, out)
@@ -109,8 +104,11 @@ func (h *AccountHandler) Deposit(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	out, err := h.svc.Deposit(ctx, id, in.Amount)
+	if respondPending(c, err) {
+		return
+	}
 	if err != nil {
-		h.respondError(c, http.StatusBadRequest, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusOK, out)
@@ -130,10 +128,62 @@ func (h *AccountHandler) Withdraw(c *gin.Context) {
 
 	ctx := c.Request.Context()
 	out, err := h.svc.Withdraw(ctx, id, in.Amount)
+	if respondPending(c, err) {
+		return
+	}
 	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// byNumber adapts a deposit or withdrawal addressed by account number or IBAN.
+func (h *AccountHandler) byNumber(fn func(context.Context, string, float64) (*dto.AccountResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		number := strings.TrimSpace(c.Param("number"))
+		if number == "" {
+			h.respondError(c, http.StatusBadRequest, errStr("missing account number"))
+			return
+		}
+		var in amountReq
+		if err := c.ShouldBindJSON(&in); err != nil {
+			h.respondError(c, http.StatusBadRequest, err)
+			return
+		}
+		out, err := fn(c.Request.Context(), number, in.Amount)
+		if respondPending(c, err) {
+			return
+		}
+		if err != nil {
+			h.respondError(c, statusFor(err), err)
+			return
+		}
+		c.JSON(http.StatusOK, out)
+	}
+}
+
+func (h *AccountHandler) SetOverdraftLimit(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.OverdraftLimitUpdate
+	if err := c.ShouldBindJSON(&in); err != nil {
 		h.respondError(c, http.StatusBadRequest, err)
 		return
 	}
+
+	ctx := c.Request.Context()
+	out, err := h.svc.SetOverdraftLimit(ctx, id, in)
+	if respondPending(c, err) {
+		return
+	}
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
 	c.JSON(http.StatusOK, out)
 }
 
@@ -146,6 +196,48 @@ func (h *AccountHandler) respondError(c *gin.Context, code int, err error) {
 	})
 }
 
+// statusFor maps service errors to HTTP codes: a missing record is 404, a
+// request the current state refuses is 422, anything else a client error.
+func statusFor(err error) int {
+	switch {
+	case errors.Is(err, service.ErrNotAuthorized):
+		return http.StatusForbidden
+	case errors.Is(err, service.ErrNotFound):
+		return http.StatusNotFound
+	case unprocessable(err):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusBadRequest
+}
+
+// unprocessableErrors are the service errors for well-formed requests the
+// current state of the accounts or clients does not allow.
+var unprocessableErrors = []error{
+	service.ErrInsufficientFunds,
+	service.ErrAccountFrozen,
+	service.ErrLimitExceeded,
+	service.ErrMonitoringBlocked,
+	service.ErrSanctionsHit,
+	service.ErrVerificationExpired,
+	service.ErrAlreadyVerified,
+	service.ErrAlreadyUsed,
+	service.ErrDuplicate,
+	service.ErrClientMerged,
+	service.ErrClientErased,
+	service.ErrOpenCase,
+	service.ErrFundsRemaining,
+	service.ErrKYBFailed,
+}
+
+func unprocessable(err error) bool {
+	for _, target := range unprocessableErrors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
 func parseInt(s string) (int, error) {
 	i64, err := strconv.ParseInt(s, 10, 64)
 	return int(i64), err
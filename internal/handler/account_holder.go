@@ -0,0 +1,129 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AccountHolderHandler struct {
+	svc *service.AccountHolderService
+}
+
+func NewAccountHolderHandler(svc *service.AccountHolderService) *AccountHolderHandler {
+	return &AccountHolderHandler{svc: svc}
+}
+
+// RegisterAccounts mounts the joint holder and authorized user routes on the
+// accounts group.
+func (h *AccountHolderHandler) RegisterAccounts(rg *gin.RouterGroup) {
+	rg.GET("/:id/holders", h.List)                       // GET    /accounts/:id/holders?all=true
+	rg.POST("/:id/holders", h.Add)                       // POST   /accounts/:id/holders
+	rg.POST("/:id/holders/:holderID/consent", h.Consent) // POST   /accounts/:id/holders/:holderID/consent
+	rg.POST("/:id/holders/:holderID/decline", h.Decline) // POST   /accounts/:id/holders/:holderID/decline
+	rg.DELETE("/:id/holders/:holderID", h.Remove)        // DELETE /accounts/:id/holders/:holderID
+}
+
+func (h *AccountHolderHandler) List(c *gin.Context) {
+	id, ok := h.accountID(c)
+	if !ok {
+		return
+	}
+	all, _ := strconv.ParseBool(c.DefaultQuery("all", "false"))
+	out, err := h.svc.List(c.Request.Context(), id, all)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *AccountHolderHandler) Add(c *gin.Context) {
+	id, ok := h.accountID(c)
+	if !ok {
+		return
+	}
+	var in dto.AccountHolderCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Add(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *AccountHolderHandler) Consent(c *gin.Context) {
+	id, holderID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.Consent(c.Request.Context(), id, holderID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *AccountHolderHandler) Decline(c *gin.Context) {
+	id, holderID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.Decline(c.Request.Context(), id, holderID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *AccountHolderHandler) Remove(c *gin.Context) {
+	id, holderID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Remove(c.Request.Context(), id, holderID); err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *AccountHolderHandler) accountID(c *gin.Context) (int, bool) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return 0, false
+	}
+	return id, true
+}
+
+func (h *AccountHolderHandler) ids(c *gin.Context) (id, holderID int, ok bool) {
+	id, ok = h.accountID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	holderID, err := parseInt(c.Param("holderID"))
+	if err != nil || holderID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid holder id", err))
+		return 0, 0, false
+	}
+	return id, holderID, true
+}
+
+func (h *AccountHolderHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApprovalHandler struct {
+	svc *service.ApprovalService
+}
+
+func NewApprovalHandler(svc *service.ApprovalService) *ApprovalHandler {
+	return &ApprovalHandler{svc: svc}
+}
+
+// Register mounts approval routes on the admin group.
+func (h *ApprovalHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/approvals", h.List)                 // GET    /admin/approvals?status=pending
+	rg.GET("/approvals/:id", h.GetByID)          // GET    /admin/approvals/:id
+	rg.POST("/approvals/:id/approve", h.Approve) // POST   /admin/approvals/:id/approve
+	rg.POST("/approvals/:id/reject", h.Reject)   // POST   /admin/approvals/:id/reject
+}
+
+func (h *ApprovalHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	out, err := h.svc.List(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ApprovalHandler) GetByID(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ApprovalHandler) Approve(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.Approve(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, approvalStatusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ApprovalHandler) Reject(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.ApprovalReject
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.Reject(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, approvalStatusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func approvalStatusFor(err error) int {
+	if errors.Is(err, service.ErrApprovalForbidden) {
+		return http.StatusForbidden
+	}
+	return statusFor(err)
+}
+
+// respondPending answers 202 with the approval request when err reports that
+// the operation is waiting for a second person, and says whether it did.
+func respondPending(c *gin.Context, err error) bool {
+	var pending *service.ApprovalRequiredError
+	if !errors.As(err, &pending) {
+		return false
+	}
+	c.JSON(http.StatusAccepted, pending.Approval)
+	return true
+}
+
+func (h *ApprovalHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
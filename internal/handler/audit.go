@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	svc *service.AuditService
+}
+
+func NewAuditHandler(svc *service.AuditService) *AuditHandler {
+	return &AuditHandler{svc: svc}
+}
+
+// Register mounts audit routes on the admin group.
+func (h *AuditHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/audit", h.List) // GET    /admin/audit?entity_type=account&entity_id=1
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	out, err := h.svc.List(c.Request.Context(), c.Query("entity_type"), c.Query("entity_id"), limit, offset)
+	if err != nil {
+		rid := c.Writer.Header().Get("X-Request-ID")
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":       err.Error(),
+			"status_code": http.StatusInternalServerError,
+			"request_id":  rid,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
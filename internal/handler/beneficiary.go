@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BeneficiaryHandler struct {
+	svc *service.BeneficiaryService
+}
+
+func NewBeneficiaryHandler(svc *service.BeneficiaryService) *BeneficiaryHandler {
+	return &BeneficiaryHandler{svc: svc}
+}
+
+// RegisterClients mounts beneficiary routes on the clients group.
+func (h *BeneficiaryHandler) RegisterClients(rg *gin.RouterGroup) {
+	rg.GET("/:id/beneficiaries", h.List)                     // GET    /clients/:id/beneficiaries
+	rg.POST("/:id/beneficiaries", h.Create)                  // POST   /clients/:id/beneficiaries
+	rg.GET("/:id/beneficiaries/:beneficiaryID", h.GetByID)   // GET    /clients/:id/beneficiaries/:beneficiaryID
+	rg.PATCH("/:id/beneficiaries/:beneficiaryID", h.Rename)  // PATCH  /clients/:id/beneficiaries/:beneficiaryID
+	rg.DELETE("/:id/beneficiaries/:beneficiaryID", h.Delete) // DELETE /clients/:id/beneficiaries/:beneficiaryID
+}
+
+func (h *BeneficiaryHandler) List(c *gin.Context) {
+	clientID, err := parseInt(c.Param("id"))
+	if err != nil || clientID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return
+	}
+	out, err := h.svc.List(c.Request.Context(), clientID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *BeneficiaryHandler) Create(c *gin.Context) {
+	clientID, err := parseInt(c.Param("id"))
+	if err != nil || clientID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return
+	}
+	var in dto.BeneficiaryCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Create(c.Request.Context(), clientID, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *BeneficiaryHandler) GetByID(c *gin.Context) {
+	clientID, id, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.GetById(c.Request.Context(), clientID, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *BeneficiaryHandler) Rename(c *gin.Context) {
+	clientID, id, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	var in dto.BeneficiaryUpdate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Rename(c.Request.Context(), clientID, id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *BeneficiaryHandler) Delete(c *gin.Context) {
+	clientID, id, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	if err := h.svc.Delete(c.Request.Context(), clientID, id); err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BeneficiaryHandler) ids(c *gin.Context) (clientID, id int, ok bool) {
+	clientID, err := parseInt(c.Param("id"))
+	if err != nil || clientID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return 0, 0, false
+	}
+	id, err = parseInt(c.Param("beneficiaryID"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid beneficiary id", err))
+		return 0, 0, false
+	}
+	return clientID, id, true
+}
+
+func (h *BeneficiaryHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
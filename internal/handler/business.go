@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BusinessHandler struct {
+	svc *service.BusinessService
+}
+
+func NewBusinessHandler(svc *service.BusinessService) *BusinessHandler {
+	return &BusinessHandler{svc: svc}
+}
+
+// RegisterClients mounts the beneficial owner, signatory and KYB routes of
+// business clients on the clients group.
+func (h *BusinessHandler) RegisterClients(rg *gin.RouterGroup) {
+	rg.GET("/:id/parties", h.Parties)                 // GET    /clients/:id/parties
+	rg.POST("/:id/parties", h.AddParty)               // POST   /clients/:id/parties
+	rg.DELETE("/:id/parties/:partyID", h.RemoveParty) // DELETE /clients/:id/parties/:partyID
+	rg.GET("/:id/kyb", h.KYB)                         // GET    /clients/:id/kyb
+}
+
+func (h *BusinessHandler) Parties(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Parties(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *BusinessHandler) AddParty(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	var in dto.BusinessPartyCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.AddParty(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *BusinessHandler) RemoveParty(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	partyID, err := parseInt(c.Param("partyID"))
+	if err != nil || partyID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid party id", err))
+		return
+	}
+	if err := h.svc.RemoveParty(c.Request.Context(), id, partyID); err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *BusinessHandler) KYB(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.KYB(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *BusinessHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
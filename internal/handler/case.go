@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CaseHandler struct {
+	svc *service.CaseService
+}
+
+func NewCaseHandler(svc *service.CaseService) *CaseHandler {
+	return &CaseHandler{svc: svc}
+}
+
+// Register mounts case management routes on the admin group.
+func (h *CaseHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/cases", h.List)                           // GET    /admin/cases?status=open&assignee=ana&client_id=3
+	rg.GET("/cases/:id", h.Get)                        // GET    /admin/cases/:id
+	rg.POST("/cases/:id/assign", h.Assign)             // POST   /admin/cases/:id/assign
+	rg.POST("/cases/:id/status", h.UpdateStatus)       // POST   /admin/cases/:id/status
+	rg.POST("/cases/:id/notes", h.AddNote)             // POST   /admin/cases/:id/notes
+	rg.POST("/cases/:id/attachments", h.AddAttachment) // POST   /admin/cases/:id/attachments
+	rg.POST("/cases/:id/freeze", h.Freeze)             // POST   /admin/cases/:id/freeze
+	rg.POST("/cases/:id/unfreeze", h.Unfreeze)         // POST   /admin/cases/:id/unfreeze
+	rg.POST("/cases/:id/release", h.Release)           // POST   /admin/cases/:id/release
+}
+
+func (h *CaseHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	clientID := 0
+	if v := c.Query("client_id"); v != "" {
+		id, err := parseInt(v)
+		if err != nil || id <= 0 {
+			h.respondError(c, http.StatusBadRequest, errOr("invalid client_id", err))
+			return
+		}
+		clientID = id
+	}
+
+	out, err := h.svc.List(c.Request.Context(), c.Query("status"), c.Query("assignee"), clientID, limit, offset)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) Get(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) Assign(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseAssign
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.Assign(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) UpdateStatus(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseStatusUpdate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.UpdateStatus(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) AddNote(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseNoteCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.AddNote(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *CaseHandler) AddAttachment(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseAttachmentCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.AddAttachment(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *CaseHandler) Freeze(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseAccountAction
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.FreezeAccount(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) Unfreeze(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseAccountAction
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.UnfreezeAccount(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) Release(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.CaseRelease
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.Release(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, approvalStatusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *CaseHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -22,6 +22,15 @@ func (h *ClientHandler) Register(rg *gin.RouterGroup) {
 	rg.GET("/:id", h.GetByID) // GET    /clients/:id
 	rg.POST("", h.Create)     // POST   /clients
 	rg.PUT("/:id", h.Update)  // PUT    /clients/:id
+
+	rg.GET("/:id/email", h.EmailStatus)                // GET    /clients/:id/email
+	rg.POST("/:id/email/verify", h.VerifyEmail)        // POST   /clients/:id/email/verify
+	rg.POST("/:id/email/resend", h.ResendVerification) // POST   /clients/:id/email/resend
+}
+
+// RegisterAdmin mounts operator-only client routes on the admin group.
+func (h *ClientHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.GET("/clients/:id/duplicates", h.Duplicates) // GET    /admin/clients/:id/duplicates
 }
 
 func (h *ClientHandler) List(c *gin.Context) {
@@ -43,7 +52,7 @@ func (h *ClientHandler) GetByID(c *gin.Context) {
 	ctx := c.Request.Context()
 	res, err := h.svc.GetById(ctx, id)
 	if err != nil {
-		h.respondError(c, http.StatusNotFound, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -58,7 +67,7 @@ func (h *ClientHandler) Create(c *gin.Context) {
 	ctx := c.Request.Context()
 	out, err := h.svc.Save(ctx, in)
 	if err != nil {
-		h.respondError(c, http.StatusBadRequest, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusCreated, out)
@@ -80,12 +89,77 @@ func (h *ClientHandler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
 	out, err := h.svc.Update(ctx, in)
 	if err != nil {
-		h.respondError(c, http.StatusBadRequest, err)
+		h.respondError(c, statusFor(err), err)
 		return
 	}
 	c.JSON(http.StatusOK, out)
 }
 
+func (h *ClientHandler) EmailStatus(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	ctx := c.Request.Context()
+	res, err := h.svc.EmailStatus(ctx, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ClientHandler) VerifyEmail(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	var in dto.EmailVerifyRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	ctx := c.Request.Context()
+	res, err := h.svc.VerifyEmail(ctx, id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *ClientHandler) ResendVerification(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	ctx := c.Request.Context()
+	res, err := h.svc.ResendVerification(ctx, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusAccepted, res)
+}
+
+func (h *ClientHandler) Duplicates(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	ctx := c.Request.Context()
+	res, err := h.svc.Duplicates(ctx, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
 func (h *ClientHandler) respondError(c *gin.Context, code int, err error) {
 	rid := c.Writer.Header().Get("X-Request-ID")
 	c.JSON(code, gin.H{
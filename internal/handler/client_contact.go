@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientContactHandler struct {
+	svc *service.ClientContactService
+}
+
+func NewClientContactHandler(svc *service.ClientContactService) *ClientContactHandler {
+	return &ClientContactHandler{svc: svc}
+}
+
+// RegisterClients mounts address, phone and email routes on the clients
+// group.
+func (h *ClientContactHandler) RegisterClients(rg *gin.RouterGroup) {
+	rg.GET("/:id/contacts", h.Contacts)                        // GET    /clients/:id/contacts
+	rg.GET("/:id/addresses", h.Addresses)                      // GET    /clients/:id/addresses?history=true
+	rg.PUT("/:id/addresses/:type", h.SetAddress)               // PUT    /clients/:id/addresses/residence
+	rg.POST("/:id/phones", h.AddPhone)                         // POST   /clients/:id/phones
+	rg.POST("/:id/phones/:phoneID/primary", h.SetPrimaryPhone) // POST   /clients/:id/phones/:phoneID/primary
+	rg.DELETE("/:id/phones/:phoneID", h.DeletePhone)           // DELETE /clients/:id/phones/:phoneID
+	rg.POST("/:id/emails", h.AddEmail)                         // POST   /clients/:id/emails
+	rg.POST("/:id/emails/:emailID/primary", h.SetPrimaryEmail) // POST   /clients/:id/emails/:emailID/primary
+	rg.DELETE("/:id/emails/:emailID", h.DeleteEmail)           // DELETE /clients/:id/emails/:emailID
+}
+
+func (h *ClientContactHandler) Contacts(c *gin.Context) {
+	clientID, ok := h.clientID(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.Contacts(c.Request.Context(), clientID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientContactHandler) Addresses(c *gin.Context) {
+	clientID, ok := h.clientID(c)
+	if !ok {
+		return
+	}
+	history, _ := strconv.ParseBool(c.DefaultQuery("history", "false"))
+	out, err := h.svc.Addresses(c.Request.Context(), clientID, history)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientContactHandler) SetAddress(c *gin.Context) {
+	clientID, ok := h.clientID(c)
+	if !ok {
+		return
+	}
+	var in dto.ClientAddressRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.SetAddress(c.Request.Context(), clientID, c.Param("type"), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientContactHandler) AddPhone(c *gin.Context) {
+	clientID, ok := h.clientID(c)
+	if !ok {
+		return
+	}
+	var in dto.ClientPhoneCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.AddPhone(c.Request.Context(), clientID, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *ClientContactHandler) SetPrimaryPhone(c *gin.Context) {
+	clientID, id, ok := h.ids(c, "phoneID")
+	if !ok {
+		return
+	}
+	out, err := h.svc.SetPrimaryPhone(c.Request.Context(), clientID, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientContactHandler) DeletePhone(c *gin.Context) {
+	clientID, id, ok := h.ids(c, "phoneID")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeletePhone(c.Request.Context(), clientID, id); err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClientContactHandler) AddEmail(c *gin.Context) {
+	clientID, ok := h.clientID(c)
+	if !ok {
+		return
+	}
+	var in dto.ClientEmailCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.AddEmail(c.Request.Context(), clientID, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *ClientContactHandler) SetPrimaryEmail(c *gin.Context) {
+	clientID, id, ok := h.ids(c, "emailID")
+	if !ok {
+		return
+	}
+	out, err := h.svc.SetPrimaryEmail(c.Request.Context(), clientID, id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientContactHandler) DeleteEmail(c *gin.Context) {
+	clientID, id, ok := h.ids(c, "emailID")
+	if !ok {
+		return
+	}
+	if err := h.svc.DeleteEmail(c.Request.Context(), clientID, id); err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ClientContactHandler) clientID(c *gin.Context) (int64, bool) {
+	clientID, err := parseID(c.Param("id"))
+	if err != nil || clientID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return 0, false
+	}
+	return clientID, true
+}
+
+func (h *ClientContactHandler) ids(c *gin.Context, param string) (clientID int64, id int, ok bool) {
+	clientID, ok = h.clientID(c)
+	if !ok {
+		return 0, 0, false
+	}
+	id, err := parseInt(c.Param(param))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid "+strings.TrimSuffix(param, "ID")+" id", err))
+		return 0, 0, false
+	}
+	return clientID, id, true
+}
+
+func (h *ClientContactHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
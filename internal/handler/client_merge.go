@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientMergeHandler struct {
+	svc *service.ClientMergeService
+}
+
+func NewClientMergeHandler(svc *service.ClientMergeService) *ClientMergeHandler {
+	return &ClientMergeHandler{svc: svc}
+}
+
+// Register mounts client merge routes on the admin group.
+func (h *ClientMergeHandler) Register(rg *gin.RouterGroup) {
+	rg.POST("/clients/merge", h.Merge)      // POST   /admin/clients/merge
+	rg.GET("/client-merges", h.List)        // GET    /admin/client-merges?client_id=3
+	rg.GET("/client-merges/:id", h.GetByID) // GET    /admin/client-merges/:id
+}
+
+func (h *ClientMergeHandler) Merge(c *gin.Context) {
+	var in dto.ClientMergeRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.Merge(c.Request.Context(), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *ClientMergeHandler) List(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	clientID := 0
+	if v := c.Query("client_id"); v != "" {
+		id, err := parseInt(v)
+		if err != nil || id <= 0 {
+			h.respondError(c, http.StatusBadRequest, errOr("invalid client_id", err))
+			return
+		}
+		clientID = id
+	}
+
+	out, err := h.svc.List(c.Request.Context(), clientID, limit, offset)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientMergeHandler) GetByID(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientMergeHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
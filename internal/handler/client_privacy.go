@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/service"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ClientPrivacyHandler struct {
+	svc *service.ClientPrivacyService
+}
+
+func NewClientPrivacyHandler(svc *service.ClientPrivacyService) *ClientPrivacyHandler {
+	return &ClientPrivacyHandler{svc: svc}
+}
+
+// RegisterClients mounts the data export and erasure routes on the clients
+// group. Both hand out or destroy personal data, so the operator must be
+// named in X-Actor.
+func (h *ClientPrivacyHandler) RegisterClients(rg *gin.RouterGroup) {
+	rg.GET("/:id/export", middleware.RequireActor(), h.Export) // GET    /clients/:id/export
+	rg.POST("/:id/erase", middleware.RequireActor(), h.Erase)  // POST   /clients/:id/erase
+}
+
+func (h *ClientPrivacyHandler) Export(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return
+	}
+	out, err := h.svc.Export(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="client-%d-export.json"`, id))
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientPrivacyHandler) Erase(c *gin.Context) {
+	id, err := parseID(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid client id", err))
+		return
+	}
+	var in dto.ClientEraseRequest
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Erase(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ClientPrivacyHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -5,27 +5,175 @@ import (
 )
 
 type Dependencies struct {
-	AccountHandler     *AccountHandler
-	ClientHandler      *ClientHandler
-	TransactionHandler *TransactionHandler
+	AccountHandler       *AccountHandler
+	ClientHandler        *ClientHandler
+	TransactionHandler   *TransactionHandler
+	FXHandler            *FXHandler
+	HoldHandler          *HoldHandler
+	AuditHandler         *AuditHandler
+	StandingOrderHandler *StandingOrderHandler
+	FeeHandler           *FeeHandler
+	InterestHandler      *InterestHandler
+	StatementHandler     *StatementHandler
+	PaymentBatchHandler  *PaymentBatchHandler
+	PayeeHandler         *PayeeHandler
+	BeneficiaryHandler   *BeneficiaryHandler
+	LimitHandler         *LimitHandler
+	ApprovalHandler      *ApprovalHandler
+	MonitoringHandler    *MonitoringHandler
+	CaseHandler          *CaseHandler
+	ScreeningHandler     *ScreeningHandler
+	ClientMergeHandler   *ClientMergeHandler
+	ClientContactHandler *ClientContactHandler
+	BusinessHandler      *BusinessHandler
+	AccountHolderHandler *AccountHolderHandler
+	ClientPrivacyHandler *ClientPrivacyHandler
 }
 
-func NewDependencies(cs *service.ClientService, as *service.AccountService, ts *service.TransactionService) *Dependencies {
+// Services lists the services handlers are built from. A nil service leaves
+// its handler nil and the router skips its routes.
+type Services struct {
+	Client        *service.ClientService
+	Account       *service.AccountService
+	Transaction   *service.TransactionService
+	FX            *service.FXService
+	Hold          *service.HoldService
+	Audit         *service.AuditService
+	StandingOrder *service.StandingOrderService
+	Fee           *service.FeeService
+	Interest      *service.InterestService
+	Statement     *service.StatementService
+	PaymentBatch  *service.PaymentBatchService
+	Payee         *service.PayeeService
+	Beneficiary   *service.BeneficiaryService
+	Limit         *service.LimitService
+	Approval      *service.ApprovalService
+	Monitoring    *service.MonitoringService
+	Case          *service.CaseService
+	Screening     *service.ScreeningService
+	ClientMerge   *service.ClientMergeService
+	ClientContact *service.ClientContactService
+	Business      *service.BusinessService
+	AccountHolder *service.AccountHolderService
+	ClientPrivacy *service.ClientPrivacyService
+}
+
+func NewDependencies(s Services) *Dependencies {
 	var ch *ClientHandler
-	if cs != nil {
-		ch = NewClientHandler(cs)
+	if s.Client != nil {
+		ch = NewClientHandler(s.Client)
 	}
 	var ah *AccountHandler
-	if as != nil {
-		ah = NewAccountHandler(as)
+	if s.Account != nil {
+		ah = NewAccountHandler(s.Account)
 	}
 	var th *TransactionHandler
-	if ts != nil {
-		th = NewTransactionHandler(ts)
+	if s.Transaction != nil {
+		th = NewTransactionHandler(s.Transaction)
+	}
+	var fh *FXHandler
+	if s.FX != nil {
+		fh = NewFXHandler(s.FX)
+	}
+	var hh *HoldHandler
+	if s.Hold != nil {
+		hh = NewHoldHandler(s.Hold)
+	}
+	var auh *AuditHandler
+	if s.Audit != nil {
+		auh = NewAuditHandler(s.Audit)
+	}
+	var soh *StandingOrderHandler
+	if s.StandingOrder != nil {
+		soh = NewStandingOrderHandler(s.StandingOrder)
+	}
+	var feh *FeeHandler
+	if s.Fee != nil {
+		feh = NewFeeHandler(s.Fee)
+	}
+	var ih *InterestHandler
+	if s.Interest != nil {
+		ih = NewInterestHandler(s.Interest)
+	}
+	var sth *StatementHandler
+	if s.Statement != nil {
+		sth = NewStatementHandler(s.Statement)
+	}
+	var pbh *PaymentBatchHandler
+	if s.PaymentBatch != nil {
+		pbh = NewPaymentBatchHandler(s.PaymentBatch)
+	}
+	var pyh *PayeeHandler
+	if s.Payee != nil {
+		pyh = NewPayeeHandler(s.Payee)
+	}
+	var bh *BeneficiaryHandler
+	if s.Beneficiary != nil {
+		bh = NewBeneficiaryHandler(s.Beneficiary)
+	}
+	var lh *LimitHandler
+	if s.Limit != nil {
+		lh = NewLimitHandler(s.Limit)
+	}
+	var aph *ApprovalHandler
+	if s.Approval != nil {
+		aph = NewApprovalHandler(s.Approval)
+	}
+	var mh *MonitoringHandler
+	if s.Monitoring != nil {
+		mh = NewMonitoringHandler(s.Monitoring)
+	}
+	var csh *CaseHandler
+	if s.Case != nil {
+		csh = NewCaseHandler(s.Case)
+	}
+	var sch *ScreeningHandler
+	if s.Screening != nil {
+		sch = NewScreeningHandler(s.Screening)
+	}
+	var cmh *ClientMergeHandler
+	if s.ClientMerge != nil {
+		cmh = NewClientMergeHandler(s.ClientMerge)
+	}
+	var cch *ClientContactHandler
+	if s.ClientContact != nil {
+		cch = NewClientContactHandler(s.ClientContact)
+	}
+	var buh *BusinessHandler
+	if s.Business != nil {
+		buh = NewBusinessHandler(s.Business)
+	}
+	var ahh *AccountHolderHandler
+	if s.AccountHolder != nil {
+		ahh = NewAccountHolderHandler(s.AccountHolder)
+	}
+	var cph *ClientPrivacyHandler
+	if s.ClientPrivacy != nil {
+		cph = NewClientPrivacyHandler(s.ClientPrivacy)
 	}
 	return &Dependencies{
-		ClientHandler:      ch,
-		AccountHandler:     ah,
-		TransactionHandler: th,
+		ClientHandler:        ch,
+		AccountHandler:       ah,
+		TransactionHandler:   th,
+		FXHandler:            fh,
+		HoldHandler:          hh,
+		AuditHandler:         auh,
+		StandingOrderHandler: soh,
+		FeeHandler:           feh,
+		InterestHandler:      ih,
+		StatementHandler:     sth,
+		PaymentBatchHandler:  pbh,
+		PayeeHandler:         pyh,
+		BeneficiaryHandler:   bh,
+		LimitHandler:         lh,
+		ApprovalHandler:      aph,
+		MonitoringHandler:    mh,
+		CaseHandler:          csh,
+		ScreeningHandler:     sch,
+		ClientMergeHandler:   cmh,
+		ClientContactHandler: cch,
+		BusinessHandler:      buh,
+		AccountHolderHandler: ahh,
+		ClientPrivacyHandler: cph,
 	}
 }
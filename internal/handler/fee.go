@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeeHandler struct {
+	svc *service.FeeService
+}
+
+func NewFeeHandler(svc *service.FeeService) *FeeHandler {
+	return &FeeHandler{svc: svc}
+}
+
+func (h *FeeHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/quote", h.Quote)         // GET    /fees/quote?account_id=1&operation=transfer&amount=100
+	rg.GET("/schedules", h.Schedules) // GET    /fees/schedules
+}
+
+// RegisterAdmin mounts fee schedule maintenance on the admin group.
+func (h *FeeHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.PUT("/fee-schedules", h.UpsertSchedule) // PUT    /admin/fee-schedules
+}
+
+func (h *FeeHandler) Quote(c *gin.Context) {
+	accountID, err := strconv.Atoi(c.Query("account_id"))
+	if err != nil || accountID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errStr("invalid account_id"))
+		return
+	}
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil || amount <= 0 {
+		h.respondError(c, http.StatusBadRequest, errStr("invalid amount"))
+		return
+	}
+
+	out, err := h.svc.Quote(c.Request.Context(), accountID, c.DefaultQuery("operation", "transfer"), amount)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *FeeHandler) Schedules(c *gin.Context) {
+	out, err := h.svc.ListSchedules(c.Request.Context())
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *FeeHandler) UpsertSchedule(c *gin.Context) {
+	var in dto.FeeScheduleUpsert
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.UpsertSchedule(c.Request.Context(), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *FeeHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FXHandler struct {
+	svc *service.FXService
+}
+
+func NewFXHandler(svc *service.FXService) *FXHandler {
+	return &FXHandler{svc: svc}
+}
+
+func (h *FXHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/quote", h.Quote) // GET    /fx/quote?from=EUR&to=RSD&amount=100
+	rg.GET("/rates", h.Rates) // GET    /fx/rates
+}
+
+// RegisterAdmin mounts rate maintenance on the admin group.
+func (h *FXHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.POST("/fx/rates/reload", h.ReloadRates) // POST   /admin/fx/rates/reload
+}
+
+func (h *FXHandler) Quote(c *gin.Context) {
+	from, to := c.Query("from"), c.Query("to")
+	if from == "" || to == "" {
+		h.respondError(c, http.StatusBadRequest, errStr("missing query params: from, to"))
+		return
+	}
+	amount, err := strconv.ParseFloat(c.Query("amount"), 64)
+	if err != nil || amount <= 0 {
+		h.respondError(c, http.StatusBadRequest, errStr("invalid amount"))
+		return
+	}
+
+	out, err := h.svc.Quote(c.Request.Context(), from, to, amount)
+	if err != nil {
+		h.respondError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *FXHandler) Rates(c *gin.Context) {
+	c.JSON(http.StatusOK, h.svc.Rates())
+}
+
+func (h *FXHandler) ReloadRates(c *gin.Context) {
+	out, err := h.svc.ReloadRates()
+	if err != nil {
+		h.respondError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *FXHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HoldHandler struct {
+	svc *service.HoldService
+}
+
+func NewHoldHandler(svc *service.HoldService) *HoldHandler {
+	return &HoldHandler{svc: svc}
+}
+
+// Register mounts hold routes on the accounts group.
+func (h *HoldHandler) Register(rg *gin.RouterGroup) {
+	rg.POST("/:id/holds", h.Create)                  // POST   /accounts/:id/holds
+	rg.GET("/:id/holds", h.List)                     // GET    /accounts/:id/holds?status=active
+	rg.GET("/:id/holds/:holdID", h.GetByID)          // GET    /accounts/:id/holds/:holdID
+	rg.POST("/:id/holds/:holdID/capture", h.Capture) // POST   /accounts/:id/holds/:holdID/capture
+	rg.POST("/:id/holds/:holdID/release", h.Release) // POST   /accounts/:id/holds/:holdID/release
+}
+
+func (h *HoldHandler) Create(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.HoldCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Create(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *HoldHandler) List(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.ListByAccountID(c.Request.Context(), id, c.Query("status"))
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *HoldHandler) GetByID(c *gin.Context) {
+	id, holdID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.GetById(c.Request.Context(), id, holdID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *HoldHandler) Capture(c *gin.Context) {
+	id, holdID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	var in dto.HoldCapture
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			h.respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	out, err := h.svc.Capture(c.Request.Context(), id, holdID, in)
+	if respondPending(c, err) {
+		return
+	}
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *HoldHandler) Release(c *gin.Context) {
+	id, holdID, ok := h.ids(c)
+	if !ok {
+		return
+	}
+	out, err := h.svc.Release(c.Request.Context(), id, holdID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *HoldHandler) ids(c *gin.Context) (int, int, bool) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return 0, 0, false
+	}
+	holdID, err := parseInt(c.Param("holdID"))
+	if err != nil || holdID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid hold id", err))
+		return 0, 0, false
+	}
+	return id, holdID, true
+}
+
+func (h *HoldHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InterestHandler struct {
+	svc *service.InterestService
+}
+
+func NewInterestHandler(svc *service.InterestService) *InterestHandler {
+	return &InterestHandler{svc: svc}
+}
+
+// RegisterAccounts mounts per-account interest routes on the accounts group.
+func (h *InterestHandler) RegisterAccounts(rg *gin.RouterGroup) {
+	rg.GET("/:id/interest/accruals", h.ListAccruals)               // GET    /accounts/:id/interest/accruals?from=2026-01-01&to=2026-01-31
+	rg.GET("/:id/interest/capitalizations", h.ListCapitalizations) // GET    /accounts/:id/interest/capitalizations
+}
+
+func (h *InterestHandler) RegisterProducts(rg *gin.RouterGroup) {
+	rg.GET("", h.ListProducts) // GET    /account-products
+}
+
+// RegisterAdmin mounts product maintenance and manual runs on the admin group.
+func (h *InterestHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.PUT("/account-products/:code", h.UpsertProduct) // PUT    /admin/account-products/:code
+	rg.POST("/interest/run", h.Run)                    // POST   /admin/interest/run {"dry_run": true}
+}
+
+func (h *InterestHandler) ListProducts(c *gin.Context) {
+	out, err := h.svc.ListProducts(c.Request.Context())
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *InterestHandler) UpsertProduct(c *gin.Context) {
+	var in dto.AccountProductUpsert
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.UpsertProduct(c.Request.Context(), c.Param("code"), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *InterestHandler) ListAccruals(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.ListAccruals(c.Request.Context(), id, c.Query("from"), c.Query("to"))
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *InterestHandler) ListCapitalizations(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.ListCapitalizations(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *InterestHandler) Run(c *gin.Context) {
+	var in dto.InterestRunRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&in); err != nil {
+			h.respondError(c, http.StatusBadRequest, err)
+			return
+		}
+	}
+	out, err := h.svc.Run(c.Request.Context(), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *InterestHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
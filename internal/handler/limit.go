@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LimitHandler struct {
+	svc *service.LimitService
+}
+
+func NewLimitHandler(svc *service.LimitService) *LimitHandler {
+	return &LimitHandler{svc: svc}
+}
+
+// RegisterAccounts mounts the limit headroom route on the accounts group.
+func (h *LimitHandler) RegisterAccounts(rg *gin.RouterGroup) {
+	rg.GET("/:id/limits", h.Get) // GET    /accounts/:id/limits
+}
+
+// RegisterAdmin mounts limit maintenance on the admin group.
+func (h *LimitHandler) RegisterAdmin(rg *gin.RouterGroup) {
+	rg.GET("/limits", h.List)          // GET    /admin/limits?scope=client
+	rg.PUT("/limits", h.Upsert)        // PUT    /admin/limits
+	rg.DELETE("/limits/:id", h.Delete) // DELETE /admin/limits/:id
+}
+
+func (h *LimitHandler) Get(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.Get(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *LimitHandler) List(c *gin.Context) {
+	out, err := h.svc.List(c.Request.Context(), c.Query("scope"))
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *LimitHandler) Upsert(c *gin.Context) {
+	var in dto.TransferLimitUpsert
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.Upsert(c.Request.Context(), in)
+	if respondPending(c, err) {
+		return
+	}
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *LimitHandler) Delete(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if respondPending(c, err) {
+			return
+		}
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *LimitHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MonitoringHandler struct {
+	svc *service.MonitoringService
+}
+
+func NewMonitoringHandler(svc *service.MonitoringService) *MonitoringHandler {
+	return &MonitoringHandler{svc: svc}
+}
+
+// Register mounts monitoring rules and alert routes on the admin group.
+func (h *MonitoringHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/monitoring/rules", h.Rules)          // GET    /admin/monitoring/rules
+	rg.POST("/monitoring/rules/reload", h.Reload) // POST   /admin/monitoring/rules/reload
+	rg.GET("/alerts", h.ListAlerts)               // GET    /admin/alerts?status=open&account_id=12
+	rg.GET("/alerts/:id", h.GetAlert)             // GET    /admin/alerts/:id
+	rg.POST("/alerts/:id/close", h.CloseAlert)    // POST   /admin/alerts/:id/close
+}
+
+func (h *MonitoringHandler) Rules(c *gin.Context) {
+	c.JSON(http.StatusOK, h.svc.Rules())
+}
+
+func (h *MonitoringHandler) Reload(c *gin.Context) {
+	out, err := h.svc.ReloadRules()
+	if err != nil {
+		h.respondError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *MonitoringHandler) ListAlerts(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	accountID := 0
+	if v := c.Query("account_id"); v != "" {
+		id, err := parseInt(v)
+		if err != nil || id <= 0 {
+			h.respondError(c, http.StatusBadRequest, errOr("invalid account_id", err))
+			return
+		}
+		accountID = id
+	}
+
+	out, err := h.svc.ListAlerts(c.Request.Context(), c.Query("status"), accountID, limit, offset)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *MonitoringHandler) GetAlert(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.GetAlert(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *MonitoringHandler) CloseAlert(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.AlertClose
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.CloseAlert(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *MonitoringHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PayeeHandler struct {
+	svc *service.PayeeService
+}
+
+func NewPayeeHandler(svc *service.PayeeService) *PayeeHandler {
+	return &PayeeHandler{svc: svc}
+}
+
+func (h *PayeeHandler) Register(rg *gin.RouterGroup) {
+	rg.POST("/verify", h.Verify) // POST   /payees/verify
+}
+
+// Verify checks a name against an account's holder before a payment is sent.
+func (h *PayeeHandler) Verify(c *gin.Context) {
+	var in dto.PayeeVerify
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Verify(c.Request.Context(), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *PayeeHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"basic-gin/internal/service"
+	"errors"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// paymentFileMaxBytes caps the size of an uploaded payment file.
+	paymentFileMaxBytes = 5 << 20
+	// paymentFileReadTimeout replaces the server's read timeout for uploads.
+	paymentFileReadTimeout = 2 * time.Minute
+)
+
+type PaymentBatchHandler struct {
+	svc *service.PaymentBatchService
+}
+
+func NewPaymentBatchHandler(svc *service.PaymentBatchService) *PaymentBatchHandler {
+	return &PaymentBatchHandler{svc: svc}
+}
+
+func (h *PaymentBatchHandler) Register(rg *gin.RouterGroup) {
+	rg.POST("", h.Submit)             // POST   /payment-batches?format=pain001&mode=best_effort
+	rg.GET("/:id", h.GetByID)         // GET    /payment-batches/:id
+	rg.GET("/:id/items", h.ListItems) // GET    /payment-batches/:id/items?status=failed
+}
+
+// Submit takes the payment file as the raw request body. A batch that fails
+// validation is still stored and returned, with 422 and its line errors.
+func (h *PaymentBatchHandler) Submit(c *gin.Context) {
+	var in dto.PaymentBatchSubmit
+	if err := c.ShouldBindQuery(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	if in.Format == "" {
+		in.Format = formatFromContentType(c.GetHeader("Content-Type"))
+	}
+	if in.Format == "" {
+		h.respondError(c, http.StatusBadRequest, errStr("pass format=pain001 or format=csv, or send an XML or CSV Content-Type"))
+		return
+	}
+
+	_ = http.NewResponseController(c.Writer).SetReadDeadline(time.Now().Add(paymentFileReadTimeout))
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, paymentFileMaxBytes)
+
+	out, err := h.svc.Submit(c.Request.Context(), in, body)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.respondError(c, http.StatusRequestEntityTooLarge, errStr("payment file exceeds 5 MB"))
+			return
+		}
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	if out.Status == model.PaymentBatchStatusRejected {
+		c.JSON(http.StatusUnprocessableEntity, out)
+		return
+	}
+	c.JSON(http.StatusAccepted, out)
+}
+
+func (h *PaymentBatchHandler) GetByID(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *PaymentBatchHandler) ListItems(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.ListItems(c.Request.Context(), id, c.Query("status"))
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func formatFromContentType(ct string) string {
+	mt, _, _ := mime.ParseMediaType(ct)
+	switch mt {
+	case "application/xml", "text/xml":
+		return model.PaymentBatchFormatPain001
+	case "text/csv", "application/csv":
+		return model.PaymentBatchFormatCSV
+	}
+	return ""
+}
+
+func (h *PaymentBatchHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
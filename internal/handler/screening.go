@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScreeningHandler struct {
+	svc *service.ScreeningService
+}
+
+func NewScreeningHandler(svc *service.ScreeningService) *ScreeningHandler {
+	return &ScreeningHandler{svc: svc}
+}
+
+// Register mounts sanctions screening routes on the admin group.
+func (h *ScreeningHandler) Register(rg *gin.RouterGroup) {
+	rg.GET("/screening/list", h.List)                    // GET    /admin/screening/list
+	rg.POST("/screening/rescreen", h.Rescreen)           // POST   /admin/screening/rescreen
+	rg.GET("/screening/hits", h.ListHits)                // GET    /admin/screening/hits?status=pending&client_id=3
+	rg.GET("/screening/hits/:id", h.GetHit)              // GET    /admin/screening/hits/:id
+	rg.POST("/screening/hits/:id/clear", h.ClearHit)     // POST   /admin/screening/hits/:id/clear
+	rg.POST("/screening/hits/:id/confirm", h.ConfirmHit) // POST   /admin/screening/hits/:id/confirm
+}
+
+func (h *ScreeningHandler) List(c *gin.Context) {
+	out, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) Rescreen(c *gin.Context) {
+	out, err := h.svc.Rescreen(c.Request.Context())
+	if err != nil {
+		h.respondError(c, http.StatusUnprocessableEntity, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) ListHits(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	clientID := 0
+	if v := c.Query("client_id"); v != "" {
+		id, err := parseInt(v)
+		if err != nil || id <= 0 {
+			h.respondError(c, http.StatusBadRequest, errOr("invalid client_id", err))
+			return
+		}
+		clientID = id
+	}
+
+	out, err := h.svc.ListHits(c.Request.Context(), c.Query("status"), clientID, limit, offset)
+	if err != nil {
+		h.respondError(c, http.StatusInternalServerError, err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) GetHit(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+
+	out, err := h.svc.GetHit(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) ClearHit(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.ScreeningHitReview
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.ClearHit(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) ConfirmHit(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var in dto.ScreeningHitReview
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	out, err := h.svc.ConfirmHit(c.Request.Context(), id, in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *ScreeningHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
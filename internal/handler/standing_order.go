@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StandingOrderHandler struct {
+	svc *service.StandingOrderService
+}
+
+func NewStandingOrderHandler(svc *service.StandingOrderService) *StandingOrderHandler {
+	return &StandingOrderHandler{svc: svc}
+}
+
+func (h *StandingOrderHandler) Register(rg *gin.RouterGroup) {
+	rg.POST("", h.Create)                          // POST   /standing-orders
+	rg.GET("", h.ListByAccount)                    // GET    /standing-orders?account_id=1
+	rg.GET("/:id", h.GetByID)                      // GET    /standing-orders/:id
+	rg.GET("/:id/executions", h.ListExecutions)    // GET    /standing-orders/:id/executions
+	rg.POST("/:id/pause", h.action(h.svc.Pause))   // POST   /standing-orders/:id/pause
+	rg.POST("/:id/resume", h.action(h.svc.Resume)) // POST   /standing-orders/:id/resume
+	rg.POST("/:id/skip", h.action(h.svc.Skip))     // POST   /standing-orders/:id/skip
+	rg.POST("/:id/cancel", h.action(h.svc.Cancel)) // POST   /standing-orders/:id/cancel
+}
+
+func (h *StandingOrderHandler) Create(c *gin.Context) {
+	var in dto.StandingOrderCreate
+	if err := c.ShouldBindJSON(&in); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+	out, err := h.svc.Create(c.Request.Context(), in)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
+func (h *StandingOrderHandler) ListByAccount(c *gin.Context) {
+	q := c.Query("account_id")
+	if q == "" {
+		h.respondError(c, http.StatusBadRequest, errStr("missing query param: account_id"))
+		return
+	}
+	accountID, err := parseInt(q)
+	if err != nil || accountID <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid account_id", err))
+		return
+	}
+	out, err := h.svc.ListByAccountID(c.Request.Context(), accountID)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *StandingOrderHandler) GetByID(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	out, err := h.svc.GetById(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *StandingOrderHandler) ListExecutions(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	out, err := h.svc.ListExecutions(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+// action adapts a state transition (pause, resume, skip, cancel) to a handler.
+func (h *StandingOrderHandler) action(fn func(context.Context, int) (*dto.StandingOrderResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := parseInt(c.Param("id"))
+		if err != nil || id <= 0 {
+			h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+			return
+		}
+		out, err := fn(c.Request.Context(), id)
+		if err != nil {
+			h.respondError(c, statusFor(err), err)
+			return
+		}
+		c.JSON(http.StatusOK, out)
+	}
+}
+
+func (h *StandingOrderHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
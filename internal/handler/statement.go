@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/service"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statementWriteTimeout replaces the server's write timeout for statement
+// downloads, which stream for as long as the period needs.
+const statementWriteTimeout = 10 * time.Minute
+
+type StatementHandler struct {
+	svc *service.StatementService
+}
+
+func NewStatementHandler(svc *service.StatementService) *StatementHandler {
+	return &StatementHandler{svc: svc}
+}
+
+// RegisterAccounts mounts statement routes on the accounts group.
+func (h *StatementHandler) RegisterAccounts(rg *gin.RouterGroup) {
+	rg.GET("/:id/statements", h.Get) // GET    /accounts/:id/statements?from=2026-09-01&to=2026-09-30&format=pdf
+}
+
+func (h *StatementHandler) Get(c *gin.Context) {
+	id, err := parseInt(c.Param("id"))
+	if err != nil || id <= 0 {
+		h.respondError(c, http.StatusBadRequest, errOr("invalid id", err))
+		return
+	}
+	var q dto.StatementQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		h.respondError(c, http.StatusBadRequest, err)
+		return
+	}
+
+	job, err := h.svc.Prepare(c.Request.Context(), id, q)
+	if err != nil {
+		h.respondError(c, statusFor(err), err)
+		return
+	}
+
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(statementWriteTimeout))
+	c.Header("Content-Type", job.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+job.Filename()+`"`)
+	c.Status(http.StatusOK)
+
+	// Headers are sent with the first byte, so a failure part way can only be
+	// logged and the connection cut short.
+	if err := h.svc.Write(c.Request.Context(), job, c.Writer); err != nil {
+		log.Printf("statement for account %d failed: %v", id, err)
+		c.Abort()
+	}
+}
+
+func (h *StatementHandler) respondError(c *gin.Context, code int, err error) {
+	rid := c.Writer.Header().Get("X-Request-ID")
+	c.JSON(code, gin.H{
+		"error":       err.Error(),
+		"status_code": code,
+		"request_id":  rid,
+	})
+}
@@ -2,7 +2,9 @@ package handler
 
 import (
 	"basic-gin/internal/dto"
+	"basic-gin/internal/middleware"
 	"basic-gin/internal/service"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -17,9 +19,13 @@ func NewTransactionHandler(s *service.TransactionService) *TransactionHandler {
 	return &TransactionHandler{transactionService: *s}
 }
 
+// Register mounts the transaction routes. A reversal moves money back out of
+// the recipient's account, so only an operator named in X-Actor may make one.
 func (h *TransactionHandler) Register(rg *gin.RouterGroup) {
 	rg.POST("", h.Create)
 	rg.GET("/by-account/:accountID", h.ListByAccountID)
+	rg.GET("/:id", h.GetByID)
+	rg.POST("/:id/reverse", middleware.RequireActor(), h.Reverse)
 }
 
 func (h *TransactionHandler) Create(c *gin.Context) {
@@ -28,7 +34,17 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if in.IdempotencyKey == "" {
+		in.IdempotencyKey = c.GetHeader("Idempotency-Key")
+	}
 	out, err := h.transactionService.CreateTransfer(c.Request.Context(), in)
+	if respondPending(c, err) {
+		return
+	}
+	if errors.Is(err, service.ErrNotAuthorized) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
 		return
@@ -36,6 +52,39 @@ func (h *TransactionHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusCreated, out)
 }
 
+func (h *TransactionHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	out, err := h.transactionService.GetById(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, out)
+}
+
+func (h *TransactionHandler) Reverse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil || id <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid transaction id"})
+		return
+	}
+	var in dto.TransactionReverse
+	if err := c.ShouldBindJSON(&in); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	out, err := h.transactionService.Reverse(c.Request.Context(), id, in)
+	if err != nil {
+		c.JSON(statusFor(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, out)
+}
+
 func (h *TransactionHandler) ListByAccountID(c *gin.Context) {
 	accountID, err := strconv.Atoi(c.Param("accountID"))
 	if err != nil || accountID <= 0 {
@@ -0,0 +1,35 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FileOutbox writes each message to its own .eml file in a directory.
+type FileOutbox struct {
+	dir  string
+	from string
+	seq  atomic.Int64
+}
+
+func NewFileOutbox(dir, from string) (*FileOutbox, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create mail outbox: %w", err)
+	}
+	return &FileOutbox{dir: dir, from: from}, nil
+}
+
+func (o *FileOutbox) Send(_ context.Context, m Message) error {
+	now := time.Now().UTC()
+	to := strings.NewReplacer("@", "_at_", "/", "_", `\`, "_").Replace(m.To)
+	name := fmt.Sprintf("%s-%04d-%s.eml", now.Format("20060102T150405.000"), o.seq.Add(1), to)
+	if err := os.WriteFile(filepath.Join(o.dir, name), render(o.from, m, now), 0o644); err != nil {
+		return fmt.Errorf("write mail to outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+// Package mail sends mail to clients. SMTPMailer delivers it; FileOutbox and
+// MemoryOutbox keep it for inspection when running locally.
+package mail
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, m Message) error
+}
+
+// SentMessage is a message kept by an outbox.
+type SentMessage struct {
+	Message
+	From   string
+	SentAt time.Time
+}
+
+// MemoryOutbox keeps messages in memory and logs each one.
+type MemoryOutbox struct {
+	from string
+	mu   sync.Mutex
+	sent []SentMessage
+}
+
+func NewMemoryOutbox(from string) *MemoryOutbox {
+	return &MemoryOutbox{from: from}
+}
+
+func (o *MemoryOutbox) Send(_ context.Context, m Message) error {
+	o.mu.Lock()
+	o.sent = append(o.sent, SentMessage{Message: m, From: o.from, SentAt: time.Now().UTC()})
+	o.mu.Unlock()
+	log.Printf("mail: to %s: %s", m.To, m.Subject)
+	return nil
+}
+
+// Messages returns the messages sent so far, oldest first.
+func (o *MemoryOutbox) Messages() []SentMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append([]SentMessage(nil), o.sent...)
+}
@@ -7,11 +7,18 @@ import (
 
 func AccountToResponse(a *model.Account) *dto.AccountResponse {
 	return &dto.AccountResponse{
-		ID:            a.ID,
-		ClientID:      a.ClientId,
-		AccountNumber: a.AccountNumber,
-		Balance:       a.Balance,
-		CreatedAt:     a.CreatedAt,
+		ID:                  a.ID,
+		ClientID:            a.ClientId,
+		AccountNumber:       a.AccountNumber,
+		LegacyAccountNumber: a.LegacyAccountNumber,
+		Balance:             a.Balance,
+		AvailableBalance:    a.Available(),
+		OverdraftLimit:      a.OverdraftLimit,
+		AccountType:         a.AccountType,
+		Currency:            a.Currency,
+		Status:              a.Status,
+		Role:                a.HolderRole,
+		CreatedAt:           a.CreatedAt,
 	}
 }
 
@@ -40,3 +47,26 @@ func AccountsToResponseSlice(items []*model.Account) []*dto.AccountResponse {
 	}
 	return res
 }
+
+func AccountHolderToResponse(h *model.AccountHolder) *dto.AccountHolderResponse {
+	return &dto.AccountHolderResponse{
+		ID:         h.ID,
+		AccountID:  h.AccountID,
+		ClientID:   h.ClientID,
+		Role:       h.Role,
+		SpendLimit: h.SpendLimit,
+		Status:     h.Status,
+		AddedBy:    h.AddedBy,
+		Consents:   h.Consents,
+		CreatedAt:  h.CreatedAt,
+		UpdatedAt:  h.UpdatedAt,
+	}
+}
+
+func AccountHoldersToResponseSlice(items []*model.AccountHolder) []*dto.AccountHolderResponse {
+	res := make([]*dto.AccountHolderResponse, 0, len(items))
+	for _, h := range items {
+		res = append(res, AccountHolderToResponse(h))
+	}
+	return res
+}
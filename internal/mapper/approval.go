@@ -0,0 +1,33 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func ApprovalToResponse(a *model.Approval) *dto.ApprovalResponse {
+	return &dto.ApprovalResponse{
+		ID:         a.ID,
+		Operation:  a.Operation,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		Payload:    a.Payload,
+		Status:     a.Status,
+		Maker:      a.Maker,
+		Checker:    a.Checker,
+		Reason:     a.Reason,
+		Result:     a.Result,
+		RequestID:  a.RequestID,
+		ExpiresAt:  a.ExpiresAt,
+		DecidedAt:  a.DecidedAt,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+func ApprovalsToResponseSlice(items []*model.Approval) []*dto.ApprovalResponse {
+	res := make([]*dto.ApprovalResponse, 0, len(items))
+	for _, a := range items {
+		res = append(res, ApprovalToResponse(a))
+	}
+	return res
+}
@@ -0,0 +1,23 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func AuditEntriesToResponseSlice(items []*model.AuditEntry) []*dto.AuditEntryResponse {
+	res := make([]*dto.AuditEntryResponse, 0, len(items))
+	for _, e := range items {
+		res = append(res, &dto.AuditEntryResponse{
+			ID:         e.ID,
+			Actor:      e.Actor,
+			Action:     e.Action,
+			EntityType: e.EntityType,
+			EntityID:   e.EntityID,
+			Details:    e.Details,
+			RequestID:  e.RequestID,
+			CreatedAt:  e.CreatedAt,
+		})
+	}
+	return res
+}
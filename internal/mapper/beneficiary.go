@@ -0,0 +1,31 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"time"
+)
+
+func BeneficiaryToResponse(b *model.Beneficiary) *dto.BeneficiaryResponse {
+	return &dto.BeneficiaryResponse{
+		ID:            b.ID,
+		ClientID:      b.ClientID,
+		Nickname:      b.Nickname,
+		AccountNumber: b.AccountNumber,
+		Name:          b.Name,
+		Currency:      b.Currency,
+		PayeeMatch:    b.PayeeMatch,
+		Trusted:       !time.Now().Before(b.TrustedAt),
+		TrustedAt:     b.TrustedAt,
+		CreatedAt:     b.CreatedAt,
+		UpdatedAt:     b.UpdatedAt,
+	}
+}
+
+func BeneficiariesToResponseSlice(items []*model.Beneficiary) []*dto.BeneficiaryResponse {
+	res := make([]*dto.BeneficiaryResponse, 0, len(items))
+	for _, b := range items {
+		res = append(res, BeneficiaryToResponse(b))
+	}
+	return res
+}
@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func CaseToResponse(c *model.Case) *dto.CaseResponse {
+	return &dto.CaseResponse{
+		ID:        c.ID,
+		ClientID:  c.ClientID,
+		Status:    c.Status,
+		Assignee:  c.Assignee,
+		OpenedBy:  c.OpenedBy,
+		ClosedBy:  c.ClosedBy,
+		ClosedAt:  c.ClosedAt,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+func CasesToResponseSlice(items []*model.Case) []*dto.CaseResponse {
+	res := make([]*dto.CaseResponse, 0, len(items))
+	for _, c := range items {
+		res = append(res, CaseToResponse(c))
+	}
+	return res
+}
+
+func CaseNoteToResponse(n *model.CaseNote) *dto.CaseNoteResponse {
+	return &dto.CaseNoteResponse{ID: n.ID, Author: n.Author, Body: n.Body, CreatedAt: n.CreatedAt}
+}
+
+func CaseNotesToResponseSlice(items []*model.CaseNote) []*dto.CaseNoteResponse {
+	res := make([]*dto.CaseNoteResponse, 0, len(items))
+	for _, n := range items {
+		res = append(res, CaseNoteToResponse(n))
+	}
+	return res
+}
+
+func CaseAttachmentToResponse(a *model.CaseAttachment) *dto.CaseAttachmentResponse {
+	return &dto.CaseAttachmentResponse{
+		ID:          a.ID,
+		FileName:    a.FileName,
+		ContentType: a.ContentType,
+		SizeBytes:   a.SizeBytes,
+		SHA256:      a.SHA256,
+		StorageURL:  a.StorageURL,
+		UploadedBy:  a.UploadedBy,
+		CreatedAt:   a.CreatedAt,
+	}
+}
+
+func CaseAttachmentsToResponseSlice(items []*model.CaseAttachment) []*dto.CaseAttachmentResponse {
+	res := make([]*dto.CaseAttachmentResponse, 0, len(items))
+	for _, a := range items {
+		res = append(res, CaseAttachmentToResponse(a))
+	}
+	return res
+}
@@ -1,6 +1,7 @@
 package mapper
 
 import (
+	"basic-gin/internal/contact"
 	"basic-gin/internal/dto"
 	"basic-gin/internal/model"
 	"fmt"
@@ -11,14 +12,61 @@ import (
 const dateLayout = "2006-01-02"
 
 func ClientToResponse(c *model.Client) dto.ClientResponse {
-	return dto.ClientResponse{
-		ID:               c.ID,
-		FirstName:        c.FirstName,
-		LastName:         c.LastName,
-		Email:            c.Email,
-		ResidenceAddress: c.ResidenceAddress,
-		BirthDate:        c.BirthDate.Format("2006-01-02"),
-		CreatedAt:        c.CreatedAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+	res := dto.ClientResponse{
+		ID:                   c.ID,
+		Type:                 c.Type,
+		FirstName:            c.FirstName,
+		LastName:             c.LastName,
+		Email:                c.Email,
+		EmailVerified:        c.EmailVerifiedAt != nil,
+		ResidenceAddress:     c.ResidenceAddress,
+		RegisteredName:       c.RegisteredName,
+		RegistrationNumber:   c.RegistrationNumber,
+		TaxID:                c.TaxID,
+		IncorporationCountry: c.IncorporationCountry,
+		MergedInto:           c.MergedInto,
+		CreatedAt:            c.CreatedAt.UTC().Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if !c.BirthDate.IsZero() {
+		res.BirthDate = c.BirthDate.Format("2006-01-02")
+	}
+	if !c.IncorporationDate.IsZero() {
+		res.IncorporationDate = c.IncorporationDate.Format("2006-01-02")
+	}
+	if c.ErasedAt != nil {
+		res.ErasedAt = c.ErasedAt.UTC().Format("2006-01-02T15:04:05Z07:00")
+	}
+	return res
+}
+
+func ClientMergeToResponse(m *model.ClientMerge) *dto.ClientMergeResponse {
+	return &dto.ClientMergeResponse{
+		ID:          m.ID,
+		SurvivorID:  m.SurvivorID,
+		DuplicateID: m.DuplicateID,
+		Reason:      m.Reason,
+		MergedBy:    m.MergedBy,
+		Moved:       m.Moved,
+		CreatedAt:   m.CreatedAt,
+	}
+}
+
+func ClientMergesToResponseSlice(items []*model.ClientMerge) []*dto.ClientMergeResponse {
+	res := make([]*dto.ClientMergeResponse, 0, len(items))
+	for _, m := range items {
+		res = append(res, ClientMergeToResponse(m))
+	}
+	return res
+}
+
+func ClientErasureToResponse(e *model.ClientErasure) *dto.ClientErasureResponse {
+	return &dto.ClientErasureResponse{
+		ID:        e.ID,
+		ClientID:  e.ClientID,
+		Reason:    e.Reason,
+		ErasedBy:  e.ErasedBy,
+		Erased:    e.Erased,
+		CreatedAt: e.CreatedAt,
 	}
 }
 
@@ -30,34 +78,86 @@ func ClientsToResponseSlice(items []*model.Client) []dto.ClientResponse {
 	return out
 }
 
+// ToClientFromCreate keeps only the fields of the client's type, individual
+// unless given.
 func ToClientFromCreate(in dto.ClientCreate) (model.Client, error) {
-	bd, err := time.Parse(dateLayout, strings.TrimSpace(in.BirthDate))
-	if err != nil {
-		return model.Client{}, fmt.Errorf("invalid birth_date (use YYYY-MM-DD): %w", err)
+	clientType := strings.ToLower(strings.TrimSpace(in.Type))
+	if clientType == "" {
+		clientType = model.ClientTypeIndividual
 	}
-	return model.Client{
-		FirstName:        strings.TrimSpace(in.FirstName),
-		LastName:         strings.TrimSpace(in.LastName),
-		Email:            strings.TrimSpace(in.Email),
-		ResidenceAddress: strings.TrimSpace(in.ResidenceAddress),
-		BirthDate:        bd,
-	}, nil
+	if clientType != model.ClientTypeIndividual && clientType != model.ClientTypeBusiness {
+		return model.Client{}, fmt.Errorf("invalid client type %q (use %s or %s)", in.Type, model.ClientTypeIndividual, model.ClientTypeBusiness)
+	}
+	return toClient(clientType, dto.ClientUpdate{
+		FirstName:            in.FirstName,
+		LastName:             in.LastName,
+		Email:                in.Email,
+		ResidenceAddress:     in.ResidenceAddress,
+		BirthDate:            in.BirthDate,
+		RegisteredName:       in.RegisteredName,
+		RegistrationNumber:   in.RegistrationNumber,
+		TaxID:                in.TaxID,
+		IncorporationDate:    in.IncorporationDate,
+		IncorporationCountry: in.IncorporationCountry,
+	})
 }
 
-func ToClientFromUpdate(in dto.ClientUpdate) (model.Client, error) {
+// ToClientFromUpdate keeps only the fields of clientType, the type of the
+// client being updated.
+func ToClientFromUpdate(in dto.ClientUpdate, clientType string) (model.Client, error) {
 	if in.ID <= 0 {
 		return model.Client{}, fmt.Errorf("invalid id")
 	}
+	c, err := toClient(clientType, in)
+	c.ID = in.ID
+	return c, err
+}
+
+func toClient(clientType string, in dto.ClientUpdate) (model.Client, error) {
+	c := model.Client{
+		Type:             clientType,
+		Email:            strings.TrimSpace(in.Email),
+		ResidenceAddress: strings.TrimSpace(in.ResidenceAddress),
+	}
+	if clientType == model.ClientTypeBusiness {
+		incorporated, err := time.Parse(dateLayout, strings.TrimSpace(in.IncorporationDate))
+		if err != nil {
+			return model.Client{}, fmt.Errorf("invalid incorporation_date (use YYYY-MM-DD): %w", err)
+		}
+		c.RegisteredName = strings.Join(strings.Fields(in.RegisteredName), " ")
+		c.RegistrationNumber = strings.ToUpper(strings.TrimSpace(in.RegistrationNumber))
+		c.TaxID = strings.ToUpper(strings.TrimSpace(in.TaxID))
+		c.IncorporationDate = incorporated
+		c.IncorporationCountry = contact.NormalizeCountry(in.IncorporationCountry)
+		return c, nil
+	}
 	bd, err := time.Parse(dateLayout, strings.TrimSpace(in.BirthDate))
 	if err != nil {
 		return model.Client{}, fmt.Errorf("invalid birth_date (use YYYY-MM-DD): %w", err)
 	}
-	return model.Client{
-		ID:               in.ID,
-		FirstName:        strings.TrimSpace(in.FirstName),
-		LastName:         strings.TrimSpace(in.LastName),
-		Email:            strings.TrimSpace(in.Email),
-		ResidenceAddress: strings.TrimSpace(in.ResidenceAddress),
-		BirthDate:        bd,
-	}, nil
+	c.FirstName = strings.TrimSpace(in.FirstName)
+	c.LastName = strings.TrimSpace(in.LastName)
+	c.BirthDate = bd
+	return c, nil
+}
+
+func BusinessPartyToResponse(p *model.BusinessParty) *dto.BusinessPartyResponse {
+	return &dto.BusinessPartyResponse{
+		ID:               p.ID,
+		BusinessID:       p.BusinessID,
+		ClientID:         p.PersonID,
+		Name:             p.PersonName,
+		Role:             p.Role,
+		OwnershipPercent: p.OwnershipPercent,
+		Title:            p.Title,
+		CreatedAt:        p.CreatedAt,
+	}
+}
+
+func BusinessPartiesToResponseSlice(items []*model.BusinessParty) []*dto.BusinessPartyResponse {
+	res := make([]*dto.BusinessPartyResponse, 0, len(items))
+	for _, p := range items {
+		res = append(res, BusinessPartyToResponse(p))
+	}
+	return res
 }
@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"strings"
+)
+
+func ClientAddressToResponse(a *model.ClientAddress) *dto.ClientAddressResponse {
+	return &dto.ClientAddressResponse{
+		ID:          a.ID,
+		Type:        a.Type,
+		Street:      a.Street,
+		HouseNumber: a.HouseNumber,
+		PostalCode:  a.PostalCode,
+		City:        a.City,
+		Country:     a.Country,
+		Raw:         a.Raw,
+		ValidFrom:   a.ValidFrom,
+		ValidTo:     a.ValidTo,
+	}
+}
+
+func ClientAddressesToResponseSlice(items []*model.ClientAddress) []*dto.ClientAddressResponse {
+	res := make([]*dto.ClientAddressResponse, 0, len(items))
+	for _, a := range items {
+		res = append(res, ClientAddressToResponse(a))
+	}
+	return res
+}
+
+func ClientPhoneToResponse(p *model.ClientPhone) *dto.ClientPhoneResponse {
+	return &dto.ClientPhoneResponse{
+		ID:        p.ID,
+		Number:    p.Number,
+		Type:      p.Type,
+		Primary:   p.Primary,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
+func ClientPhonesToResponseSlice(items []*model.ClientPhone) []*dto.ClientPhoneResponse {
+	res := make([]*dto.ClientPhoneResponse, 0, len(items))
+	for _, p := range items {
+		res = append(res, ClientPhoneToResponse(p))
+	}
+	return res
+}
+
+// ClientEmailsToResponseSlice lists the primary email first, followed by
+// the others. pending is the address the client is moving to, if any.
+func ClientEmailsToResponseSlice(primary, pending string, items []*model.ClientEmail) []*dto.ClientEmailResponse {
+	res := make([]*dto.ClientEmailResponse, 0, len(items)+1)
+	res = append(res, &dto.ClientEmailResponse{Email: primary, Primary: true})
+	for _, e := range items {
+		res = append(res, &dto.ClientEmailResponse{ID: e.ID, Email: e.Email, Pending: pending != "" && strings.EqualFold(e.Email, pending)})
+	}
+	return res
+}
@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"strings"
+)
+
+func FeeScheduleToResponse(f *model.FeeSchedule) *dto.FeeScheduleResponse {
+	tiers := f.Tiers
+	if tiers == nil {
+		tiers = []model.FeeTier{}
+	}
+	return &dto.FeeScheduleResponse{
+		ID:           f.ID,
+		AccountType:  f.AccountType,
+		Operation:    f.Operation,
+		Currency:     f.Currency,
+		Kind:         f.Kind,
+		FlatAmount:   f.FlatAmount,
+		Percent:      f.Percent,
+		MinFee:       f.MinFee,
+		MaxFee:       f.MaxFee,
+		Tiers:        tiers,
+		FreePerMonth: f.FreePerMonth,
+		UpdatedAt:    f.UpdatedAt,
+	}
+}
+
+func FeeSchedulesToResponseSlice(items []*model.FeeSchedule) []*dto.FeeScheduleResponse {
+	res := make([]*dto.FeeScheduleResponse, 0, len(items))
+	for _, f := range items {
+		res = append(res, FeeScheduleToResponse(f))
+	}
+	return res
+}
+
+func ToFeeScheduleFromUpsert(in dto.FeeScheduleUpsert) model.FeeSchedule {
+	return model.FeeSchedule{
+		AccountType:  strings.ToLower(strings.TrimSpace(in.AccountType)),
+		Operation:    in.Operation,
+		Currency:     strings.ToUpper(strings.TrimSpace(in.Currency)),
+		Kind:         in.Kind,
+		FlatAmount:   in.FlatAmount,
+		Percent:      in.Percent,
+		MinFee:       in.MinFee,
+		MaxFee:       in.MaxFee,
+		Tiers:        in.Tiers,
+		FreePerMonth: in.FreePerMonth,
+	}
+}
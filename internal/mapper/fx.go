@@ -0,0 +1,34 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/fx"
+	"basic-gin/internal/model"
+	"time"
+)
+
+func FXQuoteToResponse(q *model.FXQuote) *dto.FXQuoteResponse {
+	return &dto.FXQuoteResponse{
+		ID:           q.ID,
+		FromCurrency: q.FromCurrency,
+		ToCurrency:   q.ToCurrency,
+		MidRate:      q.MidRate,
+		Rate:         q.Rate,
+		SpreadBps:    q.SpreadBps,
+		SellAmount:   q.SellAmount,
+		BuyAmount:    q.BuyAmount,
+		ExpiresAt:    q.ExpiresAt,
+	}
+}
+
+func FXRatesToResponse(rates []fx.Rate, source string, loadedAt time.Time) *dto.FXRatesResponse {
+	out := &dto.FXRatesResponse{
+		Source:   source,
+		LoadedAt: loadedAt,
+		Rates:    make([]dto.FXRateResponse, 0, len(rates)),
+	}
+	for _, r := range rates {
+		out.Rates = append(out.Rates, dto.FXRateResponse{Base: r.Base, Quote: r.Quote, Rate: r.Rate})
+	}
+	return out
+}
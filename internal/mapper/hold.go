@@ -0,0 +1,30 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func HoldToResponse(h *model.Hold) *dto.HoldResponse {
+	return &dto.HoldResponse{
+		ID:             h.ID,
+		AccountID:      h.AccountID,
+		Amount:         h.Amount,
+		CapturedAmount: h.CapturedAmount,
+		Currency:       h.Currency,
+		Status:         h.Status,
+		Reference:      h.Reference,
+		ExpiresAt:      h.ExpiresAt,
+		TransactionID:  h.TransactionID,
+		CreatedAt:      h.CreatedAt,
+		UpdatedAt:      h.UpdatedAt,
+	}
+}
+
+func HoldsToResponseSlice(items []*model.Hold) []*dto.HoldResponse {
+	res := make([]*dto.HoldResponse, 0, len(items))
+	for _, h := range items {
+		res = append(res, HoldToResponse(h))
+	}
+	return res
+}
@@ -0,0 +1,68 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func AccountProductToResponse(p *model.AccountProduct) *dto.AccountProductResponse {
+	return &dto.AccountProductResponse{
+		Code:       p.Code,
+		Name:       p.Name,
+		AnnualRate: p.AnnualRate,
+		UpdatedAt:  p.UpdatedAt,
+	}
+}
+
+func AccountProductsToResponseSlice(items []*model.AccountProduct) []*dto.AccountProductResponse {
+	res := make([]*dto.AccountProductResponse, 0, len(items))
+	for _, p := range items {
+		res = append(res, AccountProductToResponse(p))
+	}
+	return res
+}
+
+func InterestAccrualToResponse(a *model.InterestAccrual) *dto.InterestAccrualResponse {
+	return &dto.InterestAccrualResponse{
+		ID:               a.ID,
+		AccountID:        a.AccountID,
+		Date:             a.AccrualDate.Format("2006-01-02"),
+		Balance:          a.Balance,
+		AnnualRate:       a.AnnualRate,
+		Amount:           a.Amount,
+		CapitalizationID: a.CapitalizationID,
+	}
+}
+
+func InterestAccrualsToResponseSlice(items []*model.InterestAccrual) []*dto.InterestAccrualResponse {
+	res := make([]*dto.InterestAccrualResponse, 0, len(items))
+	for _, a := range items {
+		res = append(res, InterestAccrualToResponse(a))
+	}
+	return res
+}
+
+func InterestCapitalizationToResponse(c *model.InterestCapitalization) *dto.InterestCapitalizationResponse {
+	out := &dto.InterestCapitalizationResponse{
+		ID:            c.ID,
+		Period:        c.Period.Format("2006-01"),
+		Accrued:       c.Accrued,
+		CarriedIn:     c.CarriedIn,
+		Posted:        c.Posted,
+		CarryOut:      c.CarryOut,
+		TransactionID: c.TransactionID,
+	}
+	if !c.CreatedAt.IsZero() {
+		t := c.CreatedAt
+		out.CreatedAt = &t
+	}
+	return out
+}
+
+func InterestCapitalizationsToResponseSlice(items []*model.InterestCapitalization) []*dto.InterestCapitalizationResponse {
+	res := make([]*dto.InterestCapitalizationResponse, 0, len(items))
+	for _, c := range items {
+		res = append(res, InterestCapitalizationToResponse(c))
+	}
+	return res
+}
@@ -0,0 +1,45 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func ToTransferLimitFromUpsert(in dto.TransferLimitUpsert) model.TransferLimit {
+	return model.TransferLimit{
+		Scope:          in.Scope,
+		AccountType:    in.AccountType,
+		ClientID:       in.ClientID,
+		AccountID:      in.AccountID,
+		Operation:      in.Operation,
+		Currency:       in.Currency,
+		PerTransaction: in.PerTransaction,
+		Daily:          in.Daily,
+		Monthly:        in.Monthly,
+	}
+}
+
+func TransferLimitToResponse(l *model.TransferLimit) *dto.TransferLimitResponse {
+	return &dto.TransferLimitResponse{
+		ID:             l.ID,
+		Scope:          l.Scope,
+		AccountType:    l.AccountType,
+		ClientID:       l.ClientID,
+		AccountID:      l.AccountID,
+		Operation:      l.Operation,
+		Currency:       l.Currency,
+		PerTransaction: l.PerTransaction,
+		Daily:          l.Daily,
+		Monthly:        l.Monthly,
+		CreatedAt:      l.CreatedAt,
+		UpdatedAt:      l.UpdatedAt,
+	}
+}
+
+func TransferLimitsToResponseSlice(items []*model.TransferLimit) []*dto.TransferLimitResponse {
+	res := make([]*dto.TransferLimitResponse, 0, len(items))
+	for _, l := range items {
+		res = append(res, TransferLimitToResponse(l))
+	}
+	return res
+}
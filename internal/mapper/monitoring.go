@@ -0,0 +1,62 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
+	"time"
+)
+
+func MonitoringRulesToResponse(rs *monitoring.RuleSet, source string, loadedAt time.Time) *dto.MonitoringRulesResponse {
+	out := &dto.MonitoringRulesResponse{Source: source, LoadedAt: loadedAt, Rules: []monitoring.Rule{}}
+	if rs != nil {
+		out.Review, out.Block, out.Rules = rs.Review, rs.Block, rs.Rules
+	}
+	return out
+}
+
+func AlertHitsFromResult(res monitoring.Result) []model.AlertHit {
+	out := make([]model.AlertHit, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		out = append(out, model.AlertHit{Rule: h.Rule, Kind: h.Kind, Score: h.Score, Detail: h.Detail})
+	}
+	return out
+}
+
+func AlertHitsToResponse(hits []model.AlertHit) []dto.AlertHitResponse {
+	out := make([]dto.AlertHitResponse, 0, len(hits))
+	for _, h := range hits {
+		out = append(out, dto.AlertHitResponse{Rule: h.Rule, Kind: h.Kind, Score: h.Score, Detail: h.Detail})
+	}
+	return out
+}
+
+func AlertToResponse(a *model.MonitoringAlert) *dto.AlertResponse {
+	return &dto.AlertResponse{
+		ID:                    a.ID,
+		Operation:             a.Operation,
+		AccountID:             a.AccountID,
+		CounterpartyAccountID: a.CounterpartyAccountID,
+		Amount:                a.Amount,
+		Currency:              a.Currency,
+		Score:                 a.Score,
+		Action:                a.Action,
+		Hits:                  AlertHitsToResponse(a.Hits),
+		TransactionID:         a.TransactionID,
+		ApprovalID:            a.ApprovalID,
+		CaseID:                a.CaseID,
+		Status:                a.Status,
+		ClosedBy:              a.ClosedBy,
+		Note:                  a.Note,
+		ClosedAt:              a.ClosedAt,
+		CreatedAt:             a.CreatedAt,
+	}
+}
+
+func AlertsToResponseSlice(items []*model.MonitoringAlert) []*dto.AlertResponse {
+	res := make([]*dto.AlertResponse, 0, len(items))
+	for _, a := range items {
+		res = append(res, AlertToResponse(a))
+	}
+	return res
+}
@@ -0,0 +1,55 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func PaymentBatchToResponse(b *model.PaymentBatch) *dto.PaymentBatchResponse {
+	return &dto.PaymentBatchResponse{
+		ID:             b.ID,
+		Format:         b.Format,
+		Mode:           b.Mode,
+		Status:         b.Status,
+		MessageID:      b.MessageID,
+		ControlSum:     b.ControlSum,
+		CreatedBy:      b.CreatedBy,
+		Error:          b.Error,
+		ItemCount:      b.ItemCount,
+		PendingCount:   b.PendingCount,
+		InvalidCount:   b.InvalidCount,
+		SucceededCount: b.SucceededCount,
+		FailedCount:    b.FailedCount,
+		SkippedCount:   b.SkippedCount,
+		CreatedAt:      b.CreatedAt,
+		UpdatedAt:      b.UpdatedAt,
+		CompletedAt:    b.CompletedAt,
+	}
+}
+
+func PaymentBatchItemToResponse(i *model.PaymentBatchItem) *dto.PaymentBatchItemResponse {
+	return &dto.PaymentBatchItemResponse{
+		ID:                i.ID,
+		LineNo:            i.LineNo,
+		FromAccountNumber: i.FromAccountNumber,
+		ToAccountNumber:   i.ToAccountNumber,
+		FromAccountID:     i.FromAccountID,
+		ToAccountID:       i.ToAccountID,
+		Amount:            i.Amount,
+		Currency:          i.Currency,
+		EndToEndID:        i.EndToEndID,
+		Description:       i.Description,
+		Status:            i.Status,
+		Error:             i.Error,
+		TransactionID:     i.TransactionID,
+		UpdatedAt:         i.UpdatedAt,
+	}
+}
+
+func PaymentBatchItemsToResponseSlice(items []*model.PaymentBatchItem) []*dto.PaymentBatchItemResponse {
+	res := make([]*dto.PaymentBatchItemResponse, 0, len(items))
+	for _, i := range items {
+		res = append(res, PaymentBatchItemToResponse(i))
+	}
+	return res
+}
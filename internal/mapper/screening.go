@@ -0,0 +1,47 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func ScreeningHitToResponse(h *model.ScreeningHit) *dto.ScreeningHitResponse {
+	return &dto.ScreeningHitResponse{
+		ID:             h.ID,
+		ClientID:       h.ClientID,
+		ListVersion:    h.ListVersion,
+		EntryID:        h.EntryID,
+		EntryName:      h.EntryName,
+		Program:        h.Program,
+		MatchedName:    h.MatchedName,
+		ScreenedName:   h.ScreenedName,
+		Score:          h.Score,
+		BirthDateMatch: h.BirthDateMatch,
+		Status:         h.Status,
+		ReviewedBy:     h.ReviewedBy,
+		Note:           h.Note,
+		ReviewedAt:     h.ReviewedAt,
+		CreatedAt:      h.CreatedAt,
+	}
+}
+
+func ScreeningHitsToResponseSlice(items []*model.ScreeningHit) []*dto.ScreeningHitResponse {
+	res := make([]*dto.ScreeningHitResponse, 0, len(items))
+	for _, h := range items {
+		res = append(res, ScreeningHitToResponse(h))
+	}
+	return res
+}
+
+func ScreeningRunToResponse(r *model.ScreeningRun) *dto.ScreeningRunResponse {
+	if r == nil {
+		return nil
+	}
+	return &dto.ScreeningRunResponse{
+		ListVersion: r.ListVersion,
+		Clients:     r.Clients,
+		Hits:        r.Hits,
+		StartedAt:   r.StartedAt,
+		FinishedAt:  r.FinishedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package mapper
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+)
+
+func StandingOrderToResponse(o *model.StandingOrder) *dto.StandingOrderResponse {
+	return &dto.StandingOrderResponse{
+		ID:                   o.ID,
+		FromAccountID:        o.FromAccountID,
+		ToAccountID:          o.ToAccountID,
+		Amount:               o.Amount,
+		Currency:             o.Currency,
+		Schedule:             o.Schedule,
+		Reference:            o.Reference,
+		StartAt:              o.StartAt,
+		EndAt:                o.EndAt,
+		Status:               o.Status,
+		MaxRetries:           o.MaxRetries,
+		RetryIntervalSeconds: o.RetryIntervalSeconds,
+		NextOccurrence:       o.CurrentOccurrence,
+		NextRunAt:            o.NextRunAt,
+		Attempts:             o.Attempts,
+		CreatedAt:            o.CreatedAt,
+		UpdatedAt:            o.UpdatedAt,
+	}
+}
+
+func StandingOrdersToResponseSlice(items []*model.StandingOrder) []*dto.StandingOrderResponse {
+	res := make([]*dto.StandingOrderResponse, 0, len(items))
+	for _, o := range items {
+		res = append(res, StandingOrderToResponse(o))
+	}
+	return res
+}
+
+func ExecutionsToResponseSlice(items []*model.StandingOrderExecution) []*dto.StandingOrderExecutionResponse {
+	res := make([]*dto.StandingOrderExecutionResponse, 0, len(items))
+	for _, e := range items {
+		res = append(res, &dto.StandingOrderExecutionResponse{
+			ID:            e.ID,
+			ScheduledFor:  e.ScheduledFor,
+			Attempt:       e.Attempt,
+			Status:        e.Status,
+			TransactionID: e.TransactionID,
+			Error:         e.Error,
+			CreatedAt:     e.CreatedAt,
+		})
+	}
+	return res
+}
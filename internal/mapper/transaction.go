@@ -5,12 +5,36 @@ import (
 	"basic-gin/internal/model"
 )
 
+// TransactionToResponse identifies the accounts by number only.
 func TransactionToResponse(t *model.Transaction) *dto.TransactionResponse {
 	return &dto.TransactionResponse{
-		ID:            t.ID,
-		FromAccountID: t.FromAccountID,
-		ToAccountID:   t.ToAccountID,
-		Amount:        t.Amount,
-		CreatedAt:     t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		ID:             t.ID,
+		Type:           t.Type,
+		FromAccount:    t.FromAccountNumber,
+		ToAccount:      t.ToAccountNumber,
+		Amount:         t.Amount,
+		Currency:       t.Currency,
+		ToAmount:       t.ToAmount,
+		ToCurrency:     t.ToCurrency,
+		FXRate:         t.FXRate,
+		FXMidRate:      t.FXMidRate,
+		FXSpreadBps:    t.FXSpreadBps,
+		FXQuoteID:      t.FXQuoteID,
+		Description:    t.Description,
+		ReversalOf:     t.ReversalOf,
+		Reversals:      t.ReversalIDs,
+		ReversedAmount: t.ReversedAmount,
+		FeeFor:         t.FeeFor,
+		Fee:            t.FeeAmount,
+		InitiatedBy:    t.InitiatedBy,
+		CreatedAt:      t.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
 }
+
+// TransactionToAdminResponse adds the internal account ids for operators.
+func TransactionToAdminResponse(t *model.Transaction) *dto.TransactionResponse {
+	resp := TransactionToResponse(t)
+	resp.FromAccountID = t.FromAccountID
+	resp.ToAccountID = t.ToAccountID
+	return resp
+}
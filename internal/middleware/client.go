@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ClientIDKey = "client_id"
+
+// ClientID reads the client acting on its own accounts from the
+// X-Client-ID header and stores it on the request context. Debits without it
+// must name an operator in X-Actor; only those are not held to account
+// holder roles.
+func ClientID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := strings.TrimSpace(c.GetHeader("X-Client-ID"))
+		if raw != "" {
+			id, err := strconv.Atoi(raw)
+			if err != nil || id <= 0 {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error":       "invalid X-Client-ID header",
+					"status_code": http.StatusBadRequest,
+					"request_id":  c.Writer.Header().Get("X-Request-ID"),
+				})
+				return
+			}
+			c.Set(ClientIDKey, id)
+			ctx := context.WithValue(c.Request.Context(), ClientIDKey, id)
+			c.Request = c.Request.WithContext(ctx)
+		}
+		c.Next()
+	}
+}
+
+// ClientIDFromContext returns the acting client, or 0.
+func ClientIDFromContext(ctx context.Context) int {
+	v, _ := ctx.Value(ClientIDKey).(int)
+	return v
+}
@@ -2,10 +2,37 @@ package model
 
 import "time"
 
+const AccountTypeCurrent = "current"
+
+const (
+	AccountStatusActive = "active"
+	// AccountStatusFrozen accounts may be credited but not debited.
+	AccountStatusFrozen = "frozen"
+)
+
 type Account struct {
 	ID            int
 	ClientId      int
 	AccountNumber string
-	Balance       float64
-	CreatedAt     time.Time
+	// LegacyAccountNumber is the number the account had before it was
+	// renumbered into the current scheme.
+	LegacyAccountNumber string
+	Balance             float64
+	// HeldAmount is the sum of active, unexpired holds on the account.
+	HeldAmount     float64
+	OverdraftLimit float64
+	AccountType    string
+	Currency       string
+	Status         string
+	CreatedAt      time.Time
+	// HolderRole is the role of the client the account was listed for, when
+	// listed by holder.
+	HolderRole string
 }
+
+// Available is the ledger balance minus funds reserved by active holds.
+func (a *Account) Available() float64 { return a.Balance - a.HeldAmount }
+
+// Spendable is what a debit may draw on: the available balance plus the
+// account's overdraft limit.
+func (a *Account) Spendable() float64 { return a.Available() + a.OverdraftLimit }
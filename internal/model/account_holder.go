@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+const (
+	// HolderRoleOwner is the account's owner, accounts.client_id.
+	HolderRoleOwner      = "owner"
+	HolderRoleJointOwner = "joint_owner"
+	// HolderRoleAuthorizedUser may spend up to its SpendLimit a day.
+	HolderRoleAuthorizedUser = "authorized_user"
+	HolderRoleViewOnly       = "view_only"
+)
+
+const (
+	HolderStatusPending  = "pending"
+	HolderStatusActive   = "active"
+	HolderStatusDeclined = "declined"
+	HolderStatusRemoved  = "removed"
+)
+
+// HolderConsent is one consent a pending holder needs; ConsentedAt is nil
+// until it is given.
+type HolderConsent struct {
+	ClientID    int        `json:"client_id"`
+	ConsentedAt *time.Time `json:"consented_at"`
+}
+
+// AccountHolder is a client's role on an account.
+type AccountHolder struct {
+	ID         int
+	AccountID  int
+	ClientID   int
+	Role       string
+	SpendLimit float64
+	Status     string
+	// AddedBy is the client that added the holder, or 0 when an operator
+	// did or the holder is the owner.
+	AddedBy   int
+	Consents  []HolderConsent
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// CanManage reports whether the holder may add and remove other holders.
+func (h *AccountHolder) CanManage() bool {
+	return h.Role == HolderRoleOwner || h.Role == HolderRoleJointOwner
+}
+
+// CanDebit reports whether the holder may move money out of the account.
+func (h *AccountHolder) CanDebit() bool {
+	return h.CanManage() || h.Role == HolderRoleAuthorizedUser
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	ApprovalStatusPending  = "pending"
+	ApprovalStatusApproved = "approved"
+	ApprovalStatusRejected = "rejected"
+	ApprovalStatusExpired  = "expired"
+
+	ApprovalOperationWithdrawal     = "withdrawal"
+	ApprovalOperationDeposit        = "deposit"
+	ApprovalOperationTransfer       = "transfer"
+	ApprovalOperationOverdraftLimit = "overdraft_limit"
+	ApprovalOperationTransferLimit  = "transfer_limit"
+	ApprovalOperationHoldCapture    = "hold_capture"
+)
+
+// Approval is an operation waiting for, or decided by, a second person.
+type Approval struct {
+	ID         int
+	Operation  string
+	EntityType string
+	EntityID   string
+	Payload    json.RawMessage
+	Status     string
+	Maker      string
+	Checker    string
+	Reason     string
+	Result     json.RawMessage
+	RequestID  string
+	ExpiresAt  time.Time
+	DecidedAt  *time.Time
+	CreatedAt  time.Time
+}
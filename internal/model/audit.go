@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type AuditEntry struct {
+	ID         int64
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Details    map[string]any
+	RequestID  string
+	CreatedAt  time.Time
+}
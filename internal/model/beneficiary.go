@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Beneficiary is a payee a client has saved for repeated transfers.
+type Beneficiary struct {
+	ID            int
+	ClientID      int
+	Nickname      string
+	AccountID     int
+	AccountNumber string
+	Name          string
+	Currency      string
+	PayeeMatch    string
+	TrustedAt     time.Time
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	BusinessRoleBeneficialOwner = "beneficial_owner"
+	BusinessRoleSignatory       = "signatory"
+)
+
+// BusinessParty links an individual client to a business client as one of
+// its beneficial owners or authorized signatories.
+type BusinessParty struct {
+	ID         int
+	BusinessID int64
+	PersonID   int64
+	Role       string
+	// OwnershipPercent is the beneficial owner's share; 0 for signatories.
+	OwnershipPercent float64
+	Title            string
+	CreatedAt        time.Time
+
+	// PersonName is the person's full name, filled in on reads.
+	PersonName string
+}
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	CaseStatusOpen                = "open"
+	CaseStatusInvestigating       = "investigating"
+	CaseStatusEscalated           = "escalated"
+	CaseStatusClosedFalsePositive = "closed-false-positive"
+	CaseStatusClosedReported      = "closed-reported"
+)
+
+// Case groups a client's monitoring alerts for compliance analysts.
+type Case struct {
+	ID        int
+	ClientID  int
+	Status    string
+	Assignee  string
+	OpenedBy  string
+	ClosedBy  string
+	ClosedAt  *time.Time
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Closed reports whether the case was closed either way.
+func (c *Case) Closed() bool {
+	return c.Status == CaseStatusClosedFalsePositive || c.Status == CaseStatusClosedReported
+}
+
+type CaseNote struct {
+	ID        int
+	CaseID    int
+	Author    string
+	Body      string
+	CreatedAt time.Time
+}
+
+// CaseAttachment describes a file kept in document storage.
+type CaseAttachment struct {
+	ID          int
+	CaseID      int
+	FileName    string
+	ContentType string
+	SizeBytes   int64
+	SHA256      string
+	StorageURL  string
+	UploadedBy  string
+	CreatedAt   time.Time
+}
@@ -1,13 +1,53 @@
 package model
 
-import "time"
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	ClientTypeIndividual = "individual"
+	ClientTypeBusiness   = "business"
+)
 
 type Client struct {
-	ID               int64
+	ID int64
+	// Type is ClientTypeIndividual or ClientTypeBusiness. Individuals have
+	// names and a birth date, businesses registration details; the other
+	// kind's fields are empty.
+	Type             string
 	FirstName        string
 	LastName         string
 	Email            string
 	ResidenceAddress string
 	BirthDate        time.Time
-	CreatedAt        time.Time
+
+	RegisteredName       string
+	RegistrationNumber   string
+	TaxID                string
+	IncorporationDate    time.Time
+	IncorporationCountry string
+
+	// EmailVerifiedAt is when the client confirmed Email, nil until then.
+	EmailVerifiedAt *time.Time
+	// MergedInto is the client this one was merged into, or 0.
+	MergedInto int64
+	// ErasedAt is when the client's personal data was erased on its
+	// request, nil while it was not.
+	ErasedAt  *time.Time
+	CreatedAt time.Time
+}
+
+func (c *Client) IsBusiness() bool { return c.Type == ClientTypeBusiness }
+
+// Name is the registered name of a business or the full name of an
+// individual.
+func (c *Client) Name() string {
+	switch {
+	case c.IsBusiness():
+		return c.RegisteredName
+	case c.ErasedAt != nil:
+		return "erased client " + strconv.FormatInt(c.ID, 10)
+	}
+	return c.FirstName + " " + c.LastName
 }
@@ -0,0 +1,50 @@
+package model
+
+import "time"
+
+const (
+	AddressTypeResidence = "residence"
+	AddressTypeMailing   = "mailing"
+)
+
+const (
+	PhoneTypeMobile = "mobile"
+	PhoneTypeHome   = "home"
+	PhoneTypeWork   = "work"
+)
+
+// ClientAddress is a postal address of a client. The current address of a
+// type has no ValidTo; Raw is the free text it was parsed from, if any.
+type ClientAddress struct {
+	ID          int
+	ClientID    int64
+	Type        string
+	Street      string
+	HouseNumber string
+	PostalCode  string
+	City        string
+	Country     string
+	Raw         string
+	ValidFrom   time.Time
+	ValidTo     *time.Time
+	CreatedAt   time.Time
+}
+
+// ClientPhone is a phone number in E.164 form.
+type ClientPhone struct {
+	ID        int
+	ClientID  int64
+	Number    string
+	Type      string
+	Primary   bool
+	CreatedAt time.Time
+}
+
+// ClientEmail is an email address of a client other than its primary one,
+// which is Client.Email.
+type ClientEmail struct {
+	ID        int
+	ClientID  int64
+	Email     string
+	CreatedAt time.Time
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// ClientErased is what erasing a client removed or pseudonymized. Clients
+// lists the client and the duplicates merged into it earlier, which are
+// erased with it.
+type ClientErased struct {
+	Clients            []int64 `json:"clients"`
+	Addresses          int     `json:"addresses"`
+	Phones             int     `json:"phones"`
+	Emails             int     `json:"emails"`
+	EmailVerifications int     `json:"email_verifications"`
+	// AccountHolders counts the roles on other clients' accounts that
+	// ended; AccountsFrozen lists the client's own accounts, kept as
+	// records but frozen.
+	AccountHolders int   `json:"account_holders"`
+	AccountsFrozen []int `json:"accounts_frozen"`
+	// HolderInvitations, StandingOrders and PaymentBatches count what was
+	// cancelled on the frozen accounts or set up by the client: pending
+	// invitations to join them, active standing orders and batches not
+	// yet run.
+	HolderInvitations int `json:"holder_invitations"`
+	StandingOrders    int `json:"standing_orders"`
+	PaymentBatches    int `json:"payment_batches"`
+	AuditEntries      int `json:"audit_entries"`
+}
+
+type ClientErasure struct {
+	ID        int
+	ClientID  int
+	Reason    string
+	ErasedBy  string
+	Erased    ClientErased
+	CreatedAt time.Time
+}
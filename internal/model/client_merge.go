@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// ClientMergeMoved is what a merge reassigned from the duplicate to the
+// survivor. Rows the survivor already had an equivalent of are dropped and
+// counted separately.
+type ClientMergeMoved struct {
+	Accounts              []int `json:"accounts"`
+	Beneficiaries         int   `json:"beneficiaries"`
+	BeneficiariesDropped  int   `json:"beneficiaries_dropped"`
+	TransferLimits        int   `json:"transfer_limits"`
+	TransferLimitsDropped int   `json:"transfer_limits_dropped"`
+	Cases                 int   `json:"cases"`
+	ScreeningHits         int   `json:"screening_hits"`
+	ScreeningHitsDropped  int   `json:"screening_hits_dropped"`
+	Phones                int   `json:"phones"`
+	PhonesDropped         int   `json:"phones_dropped"`
+	Emails                int   `json:"emails"`
+	// BusinessParties counts the owner and signatory links moved, on the
+	// business side when merging businesses, on the person side otherwise.
+	BusinessParties        int `json:"business_parties"`
+	BusinessPartiesDropped int `json:"business_parties_dropped"`
+	// AccountHolders counts the holder rows moved; where both clients held
+	// a role on the same account the weaker one is removed and counted in
+	// AccountHoldersDropped.
+	AccountHolders        int `json:"account_holders"`
+	AccountHoldersDropped int `json:"account_holders_dropped"`
+	// StandingOrders, PaymentBatches and Approvals count what the duplicate
+	// had initiated and now acts for the survivor.
+	StandingOrders int `json:"standing_orders"`
+	PaymentBatches int `json:"payment_batches"`
+	Approvals      int `json:"approvals"`
+}
+
+type ClientMerge struct {
+	ID          int
+	SurvivorID  int
+	DuplicateID int
+	Reason      string
+	MergedBy    string
+	Moved       ClientMergeMoved
+	CreatedAt   time.Time
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+const (
+	EmailVerificationVerify = "verify"
+	EmailVerificationChange = "change"
+)
+
+const (
+	EmailVerificationPending    = "pending"
+	EmailVerificationConfirmed  = "confirmed"
+	EmailVerificationSuperseded = "superseded"
+)
+
+// EmailVerification is a token mailed to Email. Confirming a "verify" one
+// marks the client's email verified; confirming a "change" one makes Email
+// the client's email.
+type EmailVerification struct {
+	ID          int
+	ClientID    int64
+	Purpose     string
+	Email       string
+	TokenHash   string
+	Status      string
+	ExpiresAt   time.Time
+	ConfirmedAt *time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	FeeOperationTransfer   = "transfer"
+	FeeOperationWithdrawal = "withdrawal"
+
+	FeeKindFlat       = "flat"
+	FeeKindPercentage = "percentage"
+	FeeKindTiered     = "tiered"
+)
+
+// FeeTier applies Flat + Percent% of the amount to amounts up to UpTo; a nil
+// UpTo marks the open-ended last tier.
+type FeeTier struct {
+	UpTo    *float64 `json:"up_to,omitempty"`
+	Flat    float64  `json:"flat"`
+	Percent float64  `json:"percent"`
+}
+
+type FeeSchedule struct {
+	ID           int
+	AccountType  string
+	Operation    string
+	Currency     string
+	Kind         string
+	FlatAmount   float64
+	Percent      float64
+	MinFee       float64
+	MaxFee       *float64
+	Tiers        []FeeTier
+	FreePerMonth int
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+type FXQuote struct {
+	ID            string
+	FromCurrency  string
+	ToCurrency    string
+	MidRate       float64
+	Rate          float64
+	SpreadBps     int
+	SellAmount    float64
+	BuyAmount     float64
+	ExpiresAt     time.Time
+	UsedAt        *time.Time
+	TransactionID *int
+	CreatedAt     time.Time
+}
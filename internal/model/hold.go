@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	HoldStatusActive   = "active"
+	HoldStatusCaptured = "captured"
+	HoldStatusReleased = "released"
+	HoldStatusExpired  = "expired"
+)
+
+type Hold struct {
+	ID             int
+	AccountID      int
+	Amount         float64
+	CapturedAmount float64
+	Currency       string
+	Status         string
+	Reference      string
+	ExpiresAt      time.Time
+	TransactionID  *int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
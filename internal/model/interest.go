@@ -0,0 +1,58 @@
+package model
+
+import "time"
+
+const AccountTypeSavings = "savings"
+
+// AccountProduct is the kind of account; Account.AccountType holds its code.
+type AccountProduct struct {
+	Code string
+	Name string
+	// AnnualRate is a percentage accrued daily on an ACT/365 basis.
+	AnnualRate float64
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Interest amounts are exact decimals, so they are carried as strings rather
+// than float64.
+
+// InterestAccrual is the interest earned by one account on one day's
+// end-of-day balance, kept at 8 decimal places until capitalized.
+type InterestAccrual struct {
+	ID               int
+	AccountID        int
+	AccrualDate      time.Time
+	Balance          string
+	AnnualRate       string
+	Amount           string
+	CapitalizationID int
+	CreatedAt        time.Time
+}
+
+// InterestCapitalization posts a month of accruals to the account. Posted is
+// Accrued+CarriedIn rounded down to the currency's minor unit; the remainder
+// is carried into the next month.
+type InterestCapitalization struct {
+	ID            int
+	AccountID     int
+	Period        time.Time
+	Accrued       string
+	CarriedIn     string
+	Posted        string
+	CarryOut      string
+	TransactionID int
+	CreatedAt     time.Time
+}
+
+// DailyBalance is an account's balance at the end of Date (UTC).
+type DailyBalance struct {
+	Date    time.Time
+	Balance string
+}
+
+// InterestPeriodTotal is the sum of a month's uncapitalized accruals.
+type InterestPeriodTotal struct {
+	Period time.Time
+	Total  string
+}
@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+const (
+	LimitScopeProduct = "product"
+	LimitScopeClient  = "client"
+	LimitScopeAccount = "account"
+
+	LimitOperationTransfer   = TransactionTypeTransfer
+	LimitOperationWithdrawal = TransactionTypeWithdrawal
+)
+
+// TransferLimit caps one operation at one scope. Nil caps are left to the
+// less specific scopes.
+type TransferLimit struct {
+	ID             int
+	Scope          string
+	AccountType    string
+	ClientID       int
+	AccountID      int
+	Operation      string
+	Currency       string
+	PerTransaction *float64
+	Daily          *float64
+	Monthly        *float64
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// LimitCap is one effective cap and the scope it comes from.
+type LimitCap struct {
+	Amount float64
+	Scope  string
+}
+
+// EffectiveLimits are the caps that apply to an account for one operation;
+// nil means uncapped.
+type EffectiveLimits struct {
+	Operation      string
+	PerTransaction *LimitCap
+	Daily          *LimitCap
+	Monthly        *LimitCap
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	AlertStatusOpen   = "open"
+	AlertStatusClosed = "closed"
+)
+
+// AlertHit is a monitoring rule that fired on the alerted operation.
+type AlertHit struct {
+	Rule   string `json:"rule"`
+	Kind   string `json:"kind"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+type MonitoringAlert struct {
+	ID                    int
+	Operation             string
+	AccountID             int
+	CounterpartyAccountID int
+	Amount                float64
+	Currency              string
+	Score                 int
+	Action                string
+	Hits                  []AlertHit
+	TransactionID         string
+	ApprovalID            int
+	CaseID                int
+	Status                string
+	ClosedBy              string
+	Note                  string
+	ClosedAt              *time.Time
+	CreatedAt             time.Time
+}
@@ -0,0 +1,9 @@
+package model
+
+// Confirmation-of-payee outcomes for a beneficiary name checked against an
+// account holder's name.
+const (
+	PayeeMatch      = "match"
+	PayeeCloseMatch = "close_match"
+	PayeeNoMatch    = "no_match"
+)
@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+const (
+	PaymentBatchFormatPain001 = "pain001"
+	PaymentBatchFormatCSV     = "csv"
+
+	// PaymentBatchModeAllOrNothing posts every line in one transaction or none.
+	PaymentBatchModeAllOrNothing = "all_or_nothing"
+	// PaymentBatchModeBestEffort posts each valid line on its own.
+	PaymentBatchModeBestEffort = "best_effort"
+
+	PaymentBatchStatusPending            = "pending"
+	PaymentBatchStatusProcessing         = "processing"
+	PaymentBatchStatusCompleted          = "completed"
+	PaymentBatchStatusPartiallyCompleted = "partially_completed"
+	PaymentBatchStatusFailed             = "failed"
+	PaymentBatchStatusRejected           = "rejected"
+
+	PaymentItemStatusPending   = "pending"
+	PaymentItemStatusInvalid   = "invalid"
+	PaymentItemStatusSucceeded = "succeeded"
+	PaymentItemStatusFailed    = "failed"
+	PaymentItemStatusSkipped   = "skipped"
+)
+
+type PaymentBatch struct {
+	ID         int
+	Format     string
+	Mode       string
+	Status     string
+	MessageID  string
+	ControlSum float64
+	CreatedBy  string
+	// InitiatedBy is the account holder that submitted the batch, or 0.
+	InitiatedBy int
+	Error       string
+	ClaimToken  string
+	LeaseUntil  *time.Time
+	// Counts by item status, derived from payment_batch_items.
+	ItemCount      int
+	PendingCount   int
+	InvalidCount   int
+	SucceededCount int
+	FailedCount    int
+	SkippedCount   int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	CompletedAt    *time.Time
+}
+
+type PaymentBatchItem struct {
+	ID                int
+	BatchID           int
+	LineNo            int
+	FromAccountNumber string
+	ToAccountNumber   string
+	FromAccountID     int
+	ToAccountID       int
+	Amount            float64
+	Currency          string
+	EndToEndID        string
+	Description       string
+	Status            string
+	Error             string
+	TransactionID     int
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	ScreeningHitPending   = "pending"
+	ScreeningHitCleared   = "cleared"
+	ScreeningHitConfirmed = "confirmed"
+)
+
+// ScreeningHit is a client resembling a sanctions list entry.
+type ScreeningHit struct {
+	ID             int
+	ClientID       int
+	ListVersion    string
+	EntryID        string
+	EntryName      string
+	Program        string
+	MatchedName    string
+	ScreenedName   string
+	Score          float64
+	BirthDateMatch bool
+	Status         string
+	ReviewedBy     string
+	Note           string
+	ReviewedAt     *time.Time
+	CreatedAt      time.Time
+}
+
+// ScreeningRun is a rescreening of all clients against one list version.
+type ScreeningRun struct {
+	ID          int
+	ListVersion string
+	Clients     int
+	Hits        int
+	StartedAt   time.Time
+	FinishedAt  time.Time
+}
@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+const (
+	StandingOrderStatusActive    = "active"
+	StandingOrderStatusPaused    = "paused"
+	StandingOrderStatusCancelled = "cancelled"
+	StandingOrderStatusCompleted = "completed"
+
+	ExecutionStatusSucceeded      = "succeeded"
+	ExecutionStatusFailed         = "failed"
+	ExecutionStatusRetryScheduled = "retry_scheduled"
+	ExecutionStatusSkipped        = "skipped"
+	// ExecutionStatusPendingApproval marks an occurrence parked for a second
+	// person; it is posted if the approval is granted.
+	ExecutionStatusPendingApproval = "pending_approval"
+)
+
+type StandingOrder struct {
+	ID                   int
+	FromAccountID        int
+	ToAccountID          int
+	Amount               float64
+	Currency             string
+	Schedule             string
+	Reference            string
+	StartAt              time.Time
+	EndAt                *time.Time
+	Status               string
+	MaxRetries           int
+	RetryIntervalSeconds int
+	// CurrentOccurrence is the scheduled time of the occurrence being worked
+	// on; NextRunAt is when the worker should next attempt it (later than
+	// CurrentOccurrence while retrying).
+	CurrentOccurrence *time.Time
+	Attempts          int
+	NextRunAt         *time.Time
+	// InitiatedBy is the account holder that set the order up, or 0.
+	InitiatedBy int
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type StandingOrderExecution struct {
+	ID              int
+	StandingOrderID int
+	ScheduledFor    time.Time
+	Attempt         int
+	Status          string
+	TransactionID   *int
+	Error           string
+	CreatedAt       time.Time
+}
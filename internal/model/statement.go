@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// StatementLine is one movement on an account as seen from that account:
+// Amount is positive for credits and negative for debits, in the account's
+// currency.
+type StatementLine struct {
+	TransactionID  int
+	Type           string
+	Description    string
+	CounterpartyID int
+	// CounterpartyNumber is the other account's number, if there is one.
+	CounterpartyNumber string
+	Amount             float64
+	Currency           string
+	CreatedAt          time.Time
+}
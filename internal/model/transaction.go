@@ -2,10 +2,47 @@ package model
 
 import "time"
 
+const (
+	TransactionTypeTransfer   = "transfer"
+	TransactionTypeDeposit    = "deposit"
+	TransactionTypeWithdrawal = "withdrawal"
+	TransactionTypeReversal   = "reversal"
+	TransactionTypeFee        = "fee"
+	TransactionTypeInterest   = "interest"
+)
+
 type Transaction struct {
-	ID            string    `db:"id"`
-	FromAccountID int       `db:"from_account_id"`
-	ToAccountID   int       `db:"to_account_id"`
-	Amount        float64   `db:"amount"`
-	CreatedAt     time.Time `db:"created_at"`
+	ID            string  `db:"id"`
+	Type          string  `db:"type"`
+	FromAccountID int     `db:"from_account_id"`
+	ToAccountID   int     `db:"to_account_id"`
+	Amount        float64 `db:"amount"`
+	Currency      string  `db:"currency"`
+	ToAmount      float64 `db:"to_amount"`
+	ToCurrency    string  `db:"to_currency"`
+	FXRate        float64 `db:"fx_rate"`
+	FXMidRate     float64 `db:"fx_mid_rate"`
+	FXSpreadBps   int     `db:"fx_spread_bps"`
+	FXQuoteID     string  `db:"fx_quote_id"`
+	// IdempotencyKey makes retried submissions of the same transfer a no-op.
+	IdempotencyKey string `db:"idempotency_key"`
+	Description    string `db:"description"`
+	// ReversalOf links a reversal to the transfer it compensates.
+	ReversalOf int `db:"reversal_of"`
+	// FeeFor links a fee to the transfer or withdrawal it was charged on.
+	FeeFor int `db:"fee_for"`
+	// InitiatedBy is the account holder that made a transfer or withdrawal
+	// itself, or 0.
+	InitiatedBy int `db:"initiated_by"`
+	// FeeAmount is derived: the total of fees charged on this transaction.
+	FeeAmount float64 `db:"-"`
+	// ReversalIDs and ReversedAmount are derived: the reversals linked to this
+	// transaction and their total in this transaction's currency.
+	ReversalIDs    []int   `db:"-"`
+	ReversedAmount float64 `db:"-"`
+	// FromAccountNumber and ToAccountNumber are derived: the numbers of the
+	// accounts involved, which is how clients know them.
+	FromAccountNumber string    `db:"-"`
+	ToAccountNumber   string    `db:"-"`
+	CreatedAt         time.Time `db:"created_at"`
 }
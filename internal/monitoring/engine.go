@@ -0,0 +1,92 @@
+package monitoring
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Engine holds the rule set currently in force. It is safe for concurrent
+// use and can be reloaded while serving.
+type Engine struct {
+	mu       sync.RWMutex
+	rules    *RuleSet
+	source   string
+	modTime  time.Time
+	loadedAt time.Time
+}
+
+func NewEngine() *Engine {
+	return &Engine{}
+}
+
+// LoadFile replaces the rules with those read from path. On error the rules
+// in force are kept.
+func (e *Engine) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open rules file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat rules file: %w", err)
+	}
+	rs, err := Parse(path, f)
+	if err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.rules = rs
+	e.source = path
+	e.modTime = info.ModTime()
+	e.loadedAt = time.Now().UTC()
+	e.mu.Unlock()
+	return nil
+}
+
+// ReloadIfChanged loads path again when it was modified since the last load
+// and reports whether it did.
+func (e *Engine) ReloadIfChanged(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("stat rules file: %w", err)
+	}
+	e.mu.RLock()
+	same := e.source == path && info.ModTime().Equal(e.modTime)
+	e.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+	return true, e.LoadFile(path)
+}
+
+// Rules returns the rule set in force, nil when none was loaded, together
+// with load metadata.
+func (e *Engine) Rules() (rules *RuleSet, source string, loadedAt time.Time) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.rules, e.source, e.loadedAt
+}
+
+// Lookback is how much history Evaluate needs under the rules in force.
+func (e *Engine) Lookback() time.Duration {
+	rs, _, _ := e.Rules()
+	if rs == nil {
+		return 0
+	}
+	return rs.Lookback()
+}
+
+// Evaluate scores ev under the rules in force; without rules everything is
+// allowed.
+func (e *Engine) Evaluate(ev Event, history []Movement) Result {
+	rs, _, _ := e.Rules()
+	if rs == nil {
+		return Result{Action: ActionAllow}
+	}
+	return rs.Evaluate(ev, history)
+}
@@ -0,0 +1,189 @@
+package monitoring
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Event is the operation being screened. AccountID is the account debited,
+// or credited for a deposit.
+type Event struct {
+	Operation      string
+	AccountID      int
+	CounterpartyID int
+	Amount         float64
+	Currency       string
+	At             time.Time
+	// NewCounterparty marks a transfer to an account the payer has not paid
+	// before, or to a beneficiary still in its cooling-off period.
+	NewCounterparty bool
+}
+
+func (ev Event) incoming() bool { return ev.Operation == OperationDeposit }
+
+// Movement is an earlier posting on the event's account.
+type Movement struct {
+	Operation string
+	Incoming  bool
+	Amount    float64
+	Currency  string
+	At        time.Time
+}
+
+// Hit is a rule that fired.
+type Hit struct {
+	Rule   string `json:"rule"`
+	Kind   string `json:"kind"`
+	Score  int    `json:"score"`
+	Detail string `json:"detail"`
+}
+
+type Result struct {
+	Score  int    `json:"score"`
+	Action string `json:"action"`
+	Hits   []Hit  `json:"hits,omitempty"`
+}
+
+var actionRank = map[string]int{"": 0, ActionAllow: 0, ActionReview: 1, ActionBlock: 2}
+
+// Evaluate scores ev against the rules. history holds the account's
+// postings from at least Lookback before ev.At; older ones are ignored.
+func (rs *RuleSet) Evaluate(ev Event, history []Movement) Result {
+	res := Result{Action: ActionAllow}
+	for _, r := range rs.Rules {
+		if !r.appliesTo(ev) {
+			continue
+		}
+		detail, fired := r.check(ev, history)
+		if !fired {
+			continue
+		}
+		res.Score += r.Score
+		res.Hits = append(res.Hits, Hit{Rule: r.Name, Kind: r.Kind, Score: r.Score, Detail: detail})
+		if actionRank[r.Action] > actionRank[res.Action] {
+			res.Action = r.Action
+		}
+	}
+
+	byScore := ActionAllow
+	switch {
+	case res.Score >= rs.Block:
+		byScore = ActionBlock
+	case res.Score >= rs.Review:
+		byScore = ActionReview
+	}
+	if actionRank[byScore] > actionRank[res.Action] {
+		res.Action = byScore
+	}
+	return res
+}
+
+func (r *Rule) appliesTo(ev Event) bool {
+	if r.Currency != "" && r.Currency != ev.Currency {
+		return false
+	}
+	if len(r.Operations) == 0 {
+		return true
+	}
+	for _, op := range r.Operations {
+		if op == ev.Operation {
+			return true
+		}
+	}
+	return false
+}
+
+// window returns the movements in the rule's window before ev that the rule
+// counts alongside ev: same direction, in ev's currency and, when the rule
+// lists operations, one of those.
+func (r *Rule) window(ev Event, history []Movement) []Movement {
+	since := ev.At.Add(-time.Duration(r.Window))
+	var out []Movement
+	for _, m := range history {
+		if m.At.Before(since) || m.At.After(ev.At) || m.Incoming != ev.incoming() || m.Currency != ev.Currency {
+			continue
+		}
+		if len(r.Operations) > 0 && !r.appliesTo(Event{Operation: m.Operation, Currency: m.Currency}) {
+			continue
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func (r *Rule) check(ev Event, history []Movement) (string, bool) {
+	switch r.Kind {
+	case KindVelocity:
+		past := r.window(ev, history)
+		count, total := len(past)+1, ev.Amount
+		for _, m := range past {
+			total += m.Amount
+		}
+		if r.MaxCount > 0 && count > r.MaxCount {
+			return fmt.Sprintf("%d operations within %s, at most %d expected", count, r.Window, r.MaxCount), true
+		}
+		if r.MaxAmount > 0 && total > r.MaxAmount {
+			return fmt.Sprintf("%.2f %s within %s, at most %.2f expected", total, ev.Currency, r.Window, r.MaxAmount), true
+		}
+
+	case KindStructuring:
+		below := func(a float64) bool { return a < r.Threshold && a >= r.Threshold*(1-r.Margin) }
+		if !below(ev.Amount) {
+			return "", false
+		}
+		count := 1
+		for _, m := range r.window(ev, history) {
+			if below(m.Amount) {
+				count++
+			}
+		}
+		if count >= r.MinCount {
+			return fmt.Sprintf("%d amounts just below %.2f within %s", count, r.Threshold, r.Window), true
+		}
+
+	case KindRoundAmount:
+		round := func(a float64) bool {
+			q := a / r.Multiple
+			return a >= r.MinAmount && math.Abs(q-math.Round(q)) < 1e-9
+		}
+		if !round(ev.Amount) {
+			return "", false
+		}
+		count := 1
+		for _, m := range r.window(ev, history) {
+			if round(m.Amount) {
+				count++
+			}
+		}
+		if count >= r.MinCount {
+			return fmt.Sprintf("%d round amounts (multiples of %.2f) within %s", count, r.Multiple, r.Window), true
+		}
+
+	case KindNewCounterparty:
+		if ev.Operation == OperationTransfer && ev.NewCounterparty && ev.Amount >= r.MinAmount {
+			return fmt.Sprintf("%.2f %s to a new counterparty", ev.Amount, ev.Currency), true
+		}
+
+	case KindRapidInOut:
+		if ev.incoming() {
+			return "", false
+		}
+		since := ev.At.Add(-time.Duration(r.Window))
+		in, out := 0.0, ev.Amount
+		for _, m := range history {
+			if m.At.Before(since) || m.At.After(ev.At) || m.Currency != ev.Currency {
+				continue
+			}
+			if m.Incoming {
+				in += m.Amount
+			} else {
+				out += m.Amount
+			}
+		}
+		if in >= r.MinAmount && out >= in*r.MinRatio {
+			return fmt.Sprintf("%.2f %s out after %.2f in within %s", out, ev.Currency, in, r.Window), true
+		}
+	}
+	return "", false
+}
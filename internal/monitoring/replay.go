@@ -0,0 +1,86 @@
+package monitoring
+
+import "time"
+
+// Posted is a historical transaction fed to a Replayer. ToAmount and
+// ToCurrency are set for cross-currency transfers.
+type Posted struct {
+	ID            string
+	Operation     string
+	FromAccountID int
+	ToAccountID   int
+	Amount        float64
+	Currency      string
+	ToAmount      float64
+	ToCurrency    string
+	At            time.Time
+}
+
+// Movements returns how p shows up in the history of accountID: as a debit,
+// a credit, or not at all.
+func (p Posted) Movements(accountID int) []Movement {
+	var out []Movement
+	if p.FromAccountID == accountID && p.FromAccountID != 0 {
+		out = append(out, Movement{Operation: p.Operation, Amount: p.Amount, Currency: p.Currency, At: p.At})
+	}
+	if p.ToAccountID == accountID && p.ToAccountID != 0 {
+		m := Movement{Operation: p.Operation, Incoming: true, Amount: p.Amount, Currency: p.Currency, At: p.At}
+		if p.ToCurrency != "" {
+			m.Amount, m.Currency = p.ToAmount, p.ToCurrency
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+// Replayer runs historical transactions through a rule set in order, keeping
+// each account's history in memory the way the live engine would see it
+// in the database. Counterparties count as new the first time they are paid
+// within the replay.
+type Replayer struct {
+	rules   *RuleSet
+	history map[int][]Movement
+	paid    map[[2]int]bool
+}
+
+func NewReplayer(rules *RuleSet) *Replayer {
+	return &Replayer{rules: rules, history: map[int][]Movement{}, paid: map[[2]int]bool{}}
+}
+
+// Replay evaluates p and then records it. Transactions must be fed in the
+// order they were posted; types other than transfers, deposits and
+// withdrawals are skipped and ok is false.
+func (r *Replayer) Replay(p Posted) (res Result, ok bool) {
+	var ev Event
+	switch p.Operation {
+	case OperationTransfer, OperationWithdrawal:
+		ev = Event{Operation: p.Operation, AccountID: p.FromAccountID, CounterpartyID: p.ToAccountID, Amount: p.Amount, Currency: p.Currency, At: p.At}
+		if p.Operation == OperationTransfer {
+			pair := [2]int{p.FromAccountID, p.ToAccountID}
+			ev.NewCounterparty = !r.paid[pair]
+			r.paid[pair] = true
+		}
+	case OperationDeposit:
+		ev = Event{Operation: p.Operation, AccountID: p.ToAccountID, Amount: p.Amount, Currency: p.Currency, At: p.At}
+	default:
+		return Result{}, false
+	}
+
+	res = r.rules.Evaluate(ev, r.history[ev.AccountID])
+	r.record(p)
+	return res, true
+}
+
+func (r *Replayer) record(p Posted) {
+	since := p.At.Add(-r.rules.Lookback())
+	for _, id := range []int{p.FromAccountID, p.ToAccountID} {
+		if id == 0 {
+			continue
+		}
+		h := r.history[id]
+		for len(h) > 0 && h[0].At.Before(since) {
+			h = h[1:]
+		}
+		r.history[id] = append(h, p.Movements(id)...)
+	}
+}
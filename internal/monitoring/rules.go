@@ -0,0 +1,214 @@
+// Package monitoring scores transfers, deposits and withdrawals against a
+// set of fraud and AML rules and decides whether they may go ahead.
+package monitoring
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OperationTransfer   = "transfer"
+	OperationDeposit    = "deposit"
+	OperationWithdrawal = "withdrawal"
+
+	ActionAllow  = "allow"
+	ActionReview = "review"
+	ActionBlock  = "block"
+
+	// KindVelocity fires when the account makes more than MaxCount
+	// operations, or more than MaxAmount in total, within Window.
+	KindVelocity = "velocity"
+	// KindStructuring fires when MinCount amounts within Window, the current
+	// one included, fall just below Threshold: within Margin (a fraction).
+	KindStructuring = "structuring"
+	// KindRoundAmount fires when MinCount amounts within Window, the current
+	// one included, are multiples of Multiple of at least MinAmount.
+	KindRoundAmount = "round_amount"
+	// KindNewCounterparty fires on a transfer of at least MinAmount to an
+	// account the payer has not paid before or a beneficiary still in its
+	// cooling-off period.
+	KindNewCounterparty = "new_counterparty"
+	// KindRapidInOut fires when money leaves an account soon after arriving:
+	// at least MinAmount came in within Window and what went out, the
+	// current operation included, is at least MinRatio of it.
+	KindRapidInOut = "rapid_in_out"
+)
+
+// Duration is a time.Duration written as "90m" or "24h" in rule files.
+type Duration time.Duration
+
+func (d Duration) String() string { return time.Duration(d).String() }
+
+func (d Duration) MarshalJSON() ([]byte, error) { return json.Marshal(d.String()) }
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"24h\": %w", err)
+	}
+	return d.parse(s)
+}
+
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	return d.parse(n.Value)
+}
+
+func (d *Duration) parse(s string) error {
+	v, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Rule is one check. Which fields matter depends on Kind; Operations and
+// Currency narrow what the rule looks at and default to everything.
+type Rule struct {
+	Name       string   `json:"name" yaml:"name"`
+	Kind       string   `json:"kind" yaml:"kind"`
+	Operations []string `json:"operations,omitempty" yaml:"operations"`
+	Currency   string   `json:"currency,omitempty" yaml:"currency"`
+	Window     Duration `json:"window,omitempty" yaml:"window"`
+	MaxCount   int      `json:"max_count,omitempty" yaml:"max_count"`
+	MaxAmount  float64  `json:"max_amount,omitempty" yaml:"max_amount"`
+	Threshold  float64  `json:"threshold,omitempty" yaml:"threshold"`
+	Margin     float64  `json:"margin,omitempty" yaml:"margin"`
+	MinCount   int      `json:"min_count,omitempty" yaml:"min_count"`
+	Multiple   float64  `json:"multiple,omitempty" yaml:"multiple"`
+	MinAmount  float64  `json:"min_amount,omitempty" yaml:"min_amount"`
+	MinRatio   float64  `json:"min_ratio,omitempty" yaml:"min_ratio"`
+	// Score is added to the operation's total when the rule fires.
+	Score int `json:"score" yaml:"score"`
+	// Action, when set, is the least the operation gets when the rule fires,
+	// whatever the total score.
+	Action string `json:"action,omitempty" yaml:"action"`
+}
+
+// RuleSet is a rules file: operations scoring Review or more are held for
+// review, Block or more are refused.
+type RuleSet struct {
+	Review int    `json:"review" yaml:"review"`
+	Block  int    `json:"block" yaml:"block"`
+	Rules  []Rule `json:"rules" yaml:"rules"`
+}
+
+// Parse reads a rule set; name picks the format by extension (.json, .yaml
+// or .yml).
+func Parse(name string, r io.Reader) (*RuleSet, error) {
+	var rs RuleSet
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		dec := json.NewDecoder(r)
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rs); err != nil {
+			return nil, fmt.Errorf("parse rules json: %w", err)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(r)
+		dec.KnownFields(true)
+		if err := dec.Decode(&rs); err != nil {
+			return nil, fmt.Errorf("parse rules yaml: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rules file format: %s", name)
+	}
+	if err := rs.Validate(); err != nil {
+		return nil, err
+	}
+	return &rs, nil
+}
+
+func (rs *RuleSet) Validate() error {
+	if rs.Review <= 0 || rs.Block < rs.Review {
+		return errors.New("rules: need 0 < review <= block")
+	}
+	names := map[string]bool{}
+	for i := range rs.Rules {
+		r := &rs.Rules[i]
+		r.Currency = strings.ToUpper(strings.TrimSpace(r.Currency))
+		if r.Name == "" {
+			return fmt.Errorf("rule %d: name is required", i+1)
+		}
+		if names[r.Name] {
+			return fmt.Errorf("rule %s: duplicate name", r.Name)
+		}
+		names[r.Name] = true
+		if err := r.validate(); err != nil {
+			return fmt.Errorf("rule %s: %w", r.Name, err)
+		}
+	}
+	return nil
+}
+
+func (r *Rule) validate() error {
+	for _, op := range r.Operations {
+		switch op {
+		case OperationTransfer, OperationDeposit, OperationWithdrawal:
+		default:
+			return fmt.Errorf("unknown operation %q", op)
+		}
+	}
+	switch r.Action {
+	case "", ActionAllow, ActionReview, ActionBlock:
+	default:
+		return fmt.Errorf("unknown action %q", r.Action)
+	}
+	if r.Score < 0 {
+		return errors.New("score cannot be negative")
+	}
+
+	needWindow := func() error {
+		if r.Window <= 0 {
+			return errors.New("window is required")
+		}
+		return nil
+	}
+	switch r.Kind {
+	case KindVelocity:
+		if r.MaxCount <= 0 && r.MaxAmount <= 0 {
+			return errors.New("velocity needs max_count or max_amount")
+		}
+		return needWindow()
+	case KindStructuring:
+		if r.Threshold <= 0 || r.Margin <= 0 || r.Margin >= 1 || r.MinCount <= 0 {
+			return errors.New("structuring needs threshold, a margin between 0 and 1 and min_count")
+		}
+		return needWindow()
+	case KindRoundAmount:
+		if r.Multiple <= 0 || r.MinCount <= 0 {
+			return errors.New("round_amount needs multiple and min_count")
+		}
+		return needWindow()
+	case KindNewCounterparty:
+		if r.MinAmount <= 0 {
+			return errors.New("new_counterparty needs min_amount")
+		}
+	case KindRapidInOut:
+		if r.MinAmount <= 0 || r.MinRatio <= 0 {
+			return errors.New("rapid_in_out needs min_amount and min_ratio")
+		}
+		return needWindow()
+	default:
+		return fmt.Errorf("unknown kind %q", r.Kind)
+	}
+	return nil
+}
+
+// Lookback is the longest window of any rule: how much history Evaluate
+// needs.
+func (rs *RuleSet) Lookback() time.Duration {
+	var d time.Duration
+	for _, r := range rs.Rules {
+		d = max(d, time.Duration(r.Window))
+	}
+	return d
+}
@@ -0,0 +1,115 @@
+package paymentfile
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// CSVColumns is the template header. from_account_number, to_account_number
+// and amount are required; the other columns may be left out.
+var CSVColumns = []string{"from_account_number", "to_account_number", "amount", "currency", "end_to_end_id", "description"}
+
+// ParseCSV reads the CSV template. The first row must be the header; columns
+// may come in any order.
+func ParseCSV(r io.Reader) (*File, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("payment file is empty")
+		}
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	col := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, dup := col[name]; dup {
+			return nil, fmt.Errorf("csv header repeats column %q", name)
+		}
+		col[name] = i
+	}
+	for _, name := range CSVColumns[:3] {
+		if _, ok := col[name]; !ok {
+			return nil, fmt.Errorf("csv header is missing column %q; expected %s", name, strings.Join(CSVColumns, ","))
+		}
+	}
+	for name := range col {
+		if !knownColumn(name) {
+			return nil, fmt.Errorf("csv header has unknown column %q; expected %s", name, strings.Join(CSVColumns, ","))
+		}
+	}
+
+	f := &File{}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := cr.FieldPos(0)
+		if err != nil {
+			var perr *csv.ParseError
+			if !errors.As(err, &perr) {
+				return nil, fmt.Errorf("read csv: %w", err)
+			}
+			// A malformed row cannot be trusted, but the rest of the file can
+			// still be reported on.
+			f.Payments = append(f.Payments, Payment{LineNo: perr.StartLine, Err: perr.Err.Error()})
+			continue
+		}
+		if blank(rec) {
+			continue
+		}
+
+		field := func(name string) string {
+			i, ok := col[name]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+		p := Payment{
+			LineNo:      line,
+			FromAccount: field("from_account_number"),
+			ToAccount:   field("to_account_number"),
+			Currency:    strings.ToUpper(field("currency")),
+			EndToEndID:  field("end_to_end_id"),
+			Description: field("description"),
+		}
+		if len(rec) != len(header) {
+			p.Err = fmt.Sprintf("expected %d fields, got %d", len(header), len(rec))
+		} else if p.Amount, err = parseAmount(field("amount")); err != nil {
+			p.Err = err.Error()
+		}
+		f.ControlSum += p.Amount
+		check(&p)
+		f.Payments = append(f.Payments, p)
+	}
+	if len(f.Payments) == 0 {
+		return nil, errors.New("payment file contains no payments")
+	}
+	return f, nil
+}
+
+func knownColumn(name string) bool {
+	for _, c := range CSVColumns {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+func blank(rec []string) bool {
+	for _, v := range rec {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package paymentfile
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The structs match elements by local name, so any pain.001 version
+// (001.001.03, .09, ...) decodes as long as the elements used here keep their
+// names.
+type painDocument struct {
+	Initiation struct {
+		GrpHdr struct {
+			MsgId   string `xml:"MsgId"`
+			NbOfTxs string `xml:"NbOfTxs"`
+			CtrlSum string `xml:"CtrlSum"`
+		} `xml:"GrpHdr"`
+		PmtInf []painPaymentInfo `xml:"PmtInf"`
+	} `xml:"CstmrCdtTrfInitn"`
+}
+
+type painPaymentInfo struct {
+	DbtrAcct    painAccount `xml:"DbtrAcct"`
+	CdtTrfTxInf []struct {
+		EndToEndID string `xml:"PmtId>EndToEndId"`
+		InstdAmt   struct {
+			Ccy   string `xml:"Ccy,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Amt>InstdAmt"`
+		CdtrAcct painAccount `xml:"CdtrAcct"`
+		Ustrd    []string    `xml:"RmtInf>Ustrd"`
+	} `xml:"CdtTrfTxInf"`
+}
+
+type painAccount struct {
+	IBAN  string `xml:"Id>IBAN"`
+	Other string `xml:"Id>Othr>Id"`
+}
+
+func (a painAccount) number() string {
+	if a.IBAN != "" {
+		return strings.TrimSpace(a.IBAN)
+	}
+	return strings.TrimSpace(a.Other)
+}
+
+// ParsePain001 reads a CstmrCdtTrfInitn document. NbOfTxs and CtrlSum from the
+// group header must agree with the transactions in the file.
+func ParsePain001(r io.Reader) (*File, error) {
+	var doc painDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid pain.001 document: %w", err)
+	}
+	hdr := doc.Initiation.GrpHdr
+	if strings.TrimSpace(hdr.MsgId) == "" {
+		return nil, fmt.Errorf("pain.001 group header has no MsgId")
+	}
+
+	f := &File{MessageID: strings.TrimSpace(hdr.MsgId)}
+	if len(f.MessageID) > 35 {
+		return nil, fmt.Errorf("pain.001 MsgId is at most 35 characters")
+	}
+	var sum float64
+	for _, inf := range doc.Initiation.PmtInf {
+		from := inf.DbtrAcct.number()
+		for _, tx := range inf.CdtTrfTxInf {
+			p := Payment{
+				LineNo:      len(f.Payments) + 1,
+				FromAccount: from,
+				ToAccount:   tx.CdtrAcct.number(),
+				Currency:    strings.ToUpper(strings.TrimSpace(tx.InstdAmt.Ccy)),
+				EndToEndID:  strings.TrimSpace(tx.EndToEndID),
+				Description: strings.TrimSpace(strings.Join(tx.Ustrd, " ")),
+			}
+			// NOTPROVIDED is the ISO placeholder for a missing reference.
+			if p.EndToEndID == "NOTPROVIDED" {
+				p.EndToEndID = ""
+			}
+			amount, err := parseAmount(tx.InstdAmt.Value)
+			if err != nil {
+				p.Err = err.Error()
+			}
+			p.Amount = amount
+			sum += amount
+			check(&p)
+			f.Payments = append(f.Payments, p)
+		}
+	}
+	if len(f.Payments) == 0 {
+		return nil, fmt.Errorf("pain.001 document contains no credit transfers")
+	}
+
+	if n, err := strconv.Atoi(strings.TrimSpace(hdr.NbOfTxs)); err != nil || n != len(f.Payments) {
+		return nil, fmt.Errorf("pain.001 NbOfTxs %q does not match the %d transactions in the file", hdr.NbOfTxs, len(f.Payments))
+	}
+	if s := strings.TrimSpace(hdr.CtrlSum); s != "" {
+		ctrl, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pain.001 CtrlSum %q", s)
+		}
+		if math.Abs(ctrl-sum) > 0.0005 {
+			return nil, fmt.Errorf("pain.001 CtrlSum %s does not match the transactions' total %.3f", s, sum)
+		}
+		f.ControlSum = ctrl
+	} else {
+		f.ControlSum = sum
+	}
+	return f, nil
+}
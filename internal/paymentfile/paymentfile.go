@@ -0,0 +1,87 @@
+// Package paymentfile reads bulk payment files: ISO 20022 pain.001 customer
+// credit transfer initiations and the bank's CSV template. Parsing only
+// checks that each payment is well formed; whether the accounts exist and can
+// pay is up to the caller.
+package paymentfile
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatPain001 = "pain001"
+	FormatCSV     = "csv"
+)
+
+// Payment is one credit transfer from the file.
+type Payment struct {
+	// LineNo is the CSV line number, or the transaction's 1-based position
+	// in a pain.001 file.
+	LineNo      int
+	FromAccount string
+	ToAccount   string
+	Amount      float64
+	// Currency is empty when the file does not state one.
+	Currency    string
+	EndToEndID  string
+	Description string
+	// Err describes why the payment could not be read; the other fields hold
+	// whatever was recovered.
+	Err string
+}
+
+// File is a parsed payment file.
+type File struct {
+	MessageID  string
+	ControlSum float64
+	Payments   []Payment
+}
+
+// Parse reads a file in the given format. An error means the file as a whole
+// is unusable; problems with single payments are reported in Payment.Err.
+func Parse(format string, r io.Reader) (*File, error) {
+	switch format {
+	case FormatPain001:
+		return ParsePain001(r)
+	case FormatCSV:
+		return ParseCSV(r)
+	}
+	return nil, fmt.Errorf("unsupported payment file format %q", format)
+}
+
+// parseAmount accepts a plain decimal with a dot separator, as both formats
+// require.
+func parseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("amount is missing")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || strings.ContainsAny(s, "eE+") {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if v <= 0 {
+		return 0, fmt.Errorf("amount must be positive, got %s", s)
+	}
+	return v, nil
+}
+
+// check fills p.Err from the fields every payment needs.
+func check(p *Payment) {
+	switch {
+	case p.Err != "":
+	case p.FromAccount == "":
+		p.Err = "debtor account is missing"
+	case p.ToAccount == "":
+		p.Err = "creditor account is missing"
+	case len(p.FromAccount) > 34 || len(p.ToAccount) > 34:
+		p.Err = "account numbers are at most 34 characters"
+	case len(p.EndToEndID) > 35:
+		p.Err = "end-to-end id is at most 35 characters"
+	case len([]rune(p.Description)) > 140:
+		p.Err = "description is at most 140 characters"
+	}
+}
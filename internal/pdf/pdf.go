@@ -0,0 +1,221 @@
+// Package pdf writes simple text-only PDF documents without external
+// dependencies. Pages are flushed to the underlying writer as soon as they
+// are full, so long documents are produced in constant memory.
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 portrait, in points.
+const (
+	PageWidth  = 595.0
+	PageHeight = 842.0
+)
+
+const (
+	objCatalog = 1
+	objPages   = 2
+	objFont    = 3
+	objBold    = 4
+	firstFree  = 5
+)
+
+// Document is a stream of monospaced text lines laid out top to bottom.
+type Document struct {
+	w       *countingWriter
+	offsets map[int]int64
+	nextObj int
+	pages   []int
+
+	FontSize float64
+	Leading  float64
+	Margin   float64
+
+	// Header, when set, is called at the top of every page.
+	Header func(d *Document, page int)
+
+	content *bytes.Buffer
+	y       float64
+	err     error
+}
+
+// New starts a document on w. Call Close to finish it.
+func New(w io.Writer) *Document {
+	d := &Document{
+		w:        &countingWriter{w: bufio.NewWriter(w)},
+		offsets:  map[int]int64{},
+		nextObj:  firstFree,
+		FontSize: 9,
+		Leading:  12,
+		Margin:   40,
+	}
+	d.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	d.object(objFont, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>")
+	d.object(objBold, "<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>")
+	return d
+}
+
+// Columns is how many characters fit on a line at the current font size.
+func (d *Document) Columns() int {
+	return int((PageWidth - 2*d.Margin) / (d.FontSize * 0.6))
+}
+
+// Line writes one line of text, starting a new page when needed.
+func (d *Document) Line(text string) { d.line(text, false) }
+
+// BoldLine writes one line in the bold face.
+func (d *Document) BoldLine(text string) { d.line(text, true) }
+
+// Blank advances by one empty line.
+func (d *Document) Blank() { d.line("", false) }
+
+func (d *Document) line(text string, bold bool) {
+	if d.err != nil {
+		return
+	}
+	if d.content == nil || d.y < d.Margin {
+		d.newPage()
+	}
+	if text != "" {
+		font := "F1"
+		if bold {
+			font = "F2"
+		}
+		fmt.Fprintf(d.content, "BT /%s %.1f Tf %.1f %.1f Td (%s) Tj ET\n", font, d.FontSize, d.Margin, d.y, escape(text))
+	}
+	d.y -= d.Leading
+}
+
+func (d *Document) newPage() {
+	d.flushPage()
+	d.content = &bytes.Buffer{}
+	d.y = PageHeight - d.Margin
+	if d.Header != nil {
+		d.Header(d, len(d.pages)+1)
+	}
+}
+
+func (d *Document) flushPage() {
+	if d.content == nil {
+		return
+	}
+	contentObj := d.alloc()
+	d.stream(contentObj, d.content.Bytes())
+	pageObj := d.alloc()
+	d.object(pageObj, fmt.Sprintf(
+		"<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+		objPages, PageWidth, PageHeight, objFont, objBold, contentObj))
+	d.pages = append(d.pages, pageObj)
+	d.content = nil
+}
+
+// Close writes the page tree, cross-reference table and trailer.
+func (d *Document) Close() error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.content == nil && len(d.pages) == 0 {
+		d.newPage()
+	}
+	d.flushPage()
+
+	kids := make([]string, len(d.pages))
+	for i, p := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", p)
+	}
+	d.object(objPages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	d.object(objCatalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", objPages))
+
+	xref := d.w.n
+	d.printf("xref\n0 %d\n0000000000 65535 f \n", d.nextObj)
+	for i := 1; i < d.nextObj; i++ {
+		d.printf("%010d 00000 n \n", d.offsets[i])
+	}
+	d.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", d.nextObj, objCatalog, xref)
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.w.Flush()
+}
+
+func (d *Document) alloc() int {
+	n := d.nextObj
+	d.nextObj++
+	return n
+}
+
+func (d *Document) object(n int, body string) {
+	d.offsets[n] = d.w.n
+	d.printf("%d 0 obj\n%s\nendobj\n", n, body)
+}
+
+func (d *Document) stream(n int, data []byte) {
+	d.offsets[n] = d.w.n
+	d.printf("%d 0 obj\n<< /Length %d >>\nstream\n", n, len(data))
+	d.write(data)
+	d.printf("\nendstream\nendobj\n")
+}
+
+func (d *Document) printf(format string, args ...any) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, format, args...)
+	}
+}
+
+func (d *Document) write(p []byte) {
+	if d.err == nil {
+		_, d.err = d.w.Write(p)
+	}
+}
+
+type countingWriter struct {
+	w *bufio.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// winAnsi maps the characters outside Latin-1 that WinAnsiEncoding has.
+var winAnsi = map[rune]byte{
+	'€': 0x80, 'Š': 0x8A, 'š': 0x9A, 'Ž': 0x8E, 'ž': 0x9E,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '–': 0x96, '—': 0x97,
+}
+
+// fallback spells characters the standard fonts cannot show.
+var fallback = map[rune]string{
+	'Č': "C", 'č': "c", 'Ć': "C", 'ć': "c", 'Đ': "Dj", 'đ': "dj",
+}
+
+// escape converts text to a WinAnsi PDF string literal body.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7F:
+			b.WriteRune(r)
+		case r >= 0xA0 && r <= 0xFF:
+			b.WriteByte(byte(r))
+		default:
+			if c, ok := winAnsi[r]; ok {
+				b.WriteByte(c)
+			} else if f, ok := fallback[r]; ok {
+				b.WriteString(f)
+			} else {
+				b.WriteByte('?')
+			}
+		}
+	}
+	return b.String()
+}
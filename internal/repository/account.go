@@ -11,6 +11,13 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// accountColumns must be selected from (or returned by a statement on) the
+// accounts table unaliased: the held amount subquery refers to accounts.id.
+const accountColumns = `id, client_id, account_number, COALESCE(legacy_account_number, ''), balance,
+	COALESCE((SELECT SUM(h.amount) FROM holds h
+		WHERE h.account_id = accounts.id AND h.status = 'active' AND h.expires_at > NOW()), 0),
+	overdraft_limit, account_type, currency, status, created_at`
+
 type AccountRepository struct {
 	pool *pgxpool.Pool
 }
@@ -21,8 +28,42 @@ func NewAccountRepository(pool *pgxpool.Pool) *AccountRepository {
 	}
 }
 
+func scanAccount(row pgx.Row) (*model.Account, error) {
+	var a model.Account
+	if err := row.Scan(&a.ID, &a.ClientId, &a.AccountNumber, &a.LegacyAccountNumber, &a.Balance, &a.HeldAmount, &a.OverdraftLimit, &a.AccountType, &a.Currency, &a.Status, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// GetByHolder returns the accounts clientID holds an active role on, with
+// that role in HolderRole.
+func (r *AccountRepository) GetByHolder(ctx context.Context, clientID int) ([]*model.Account, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+accountColumns+`, h.role
+		FROM accounts
+		JOIN account_holders h ON h.account_id = accounts.id
+		WHERE h.client_id = $1 AND h.status = 'active'
+		ORDER BY accounts.id`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("get accounts by holder: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*model.Account
+	for rows.Next() {
+		var a model.Account
+		if err := rows.Scan(&a.ID, &a.ClientId, &a.AccountNumber, &a.LegacyAccountNumber, &a.Balance, &a.HeldAmount,
+			&a.OverdraftLimit, &a.AccountType, &a.Currency, &a.Status, &a.CreatedAt, &a.HolderRole); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		accounts = append(accounts, &a)
+	}
+	return accounts, rows.Err()
+}
+
 func (r *AccountRepository) GetByClientId(ctx context.Context, id int) ([]*model.Account, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, client_id, account_number, balance, created_at FROM accounts WHERE client_id = $1", id)
+	rows, err := r.pool.Query(ctx, "SELECT "+accountColumns+" FROM accounts WHERE client_id = $1", id)
 
 	if err != nil {
 		return nil, fmt.Errorf("get accounts by client id: %w", err)
@@ -33,13 +74,12 @@ func (r *AccountRepository) GetByClientId(ctx context.Context, id int) ([]*model
 	var accounts []*model.Account
 
 	for rows.Next() {
-		var account model.Account
-
-		if err := rows.Scan(&account.ID, &account.ClientId, &account.AccountNumber, &account.Balance, &account.CreatedAt); err != nil {
+		account, err := scanAccount(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scanning rows: %v", err)
 		}
 
-		accounts = append(accounts, &account)
+		accounts = append(accounts, account)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -50,70 +90,168 @@ func (r *AccountRepository) GetByClientId(ctx context.Context, id int) ([]*model
 }
 
 func (r *AccountRepository) GetById(ctx context.Context, id int) (*model.Account, error) {
-	var account model.Account
-
-	if err := r.pool.QueryRow(ctx, "SELECT id, account_number, balance, client_id, created_at FROM accounts WHERE id = $1", id).Scan(&account.ID, &account.AccountNumber, &account.Balance, &account.ClientId, &account.CreatedAt); err != nil {
+	account, err := scanAccount(r.pool.QueryRow(ctx, "SELECT "+accountColumns+" FROM accounts WHERE id = $1", id))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("account with an id: %d not found", id)
+			return nil, fmt.Errorf("account with an id: %d %w", id, ErrNotFound)
 		}
 		return nil, fmt.Errorf("get account by id: %v", err)
 	}
 
-	return &account, nil
+	return account, nil
+}
+
+// GetByAccountNumber finds an account by its current or legacy number.
+func (r *AccountRepository) GetByAccountNumber(ctx context.Context, number string) (*model.Account, error) {
+	account, err := scanAccount(r.pool.QueryRow(ctx, `
+		SELECT `+accountColumns+` FROM accounts
+		WHERE account_number = $1 OR legacy_account_number = $1
+		ORDER BY account_number = $1 DESC
+		LIMIT 1`, number))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account with number %q %w", number, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get account by number: %v", err)
+	}
+
+	return account, nil
 }
 
 func (r *AccountRepository) CreateAccount(ctx context.Context, account *model.Account) (*model.Account, error) {
-	var savedAccount model.Account
-	if err := r.pool.QueryRow(ctx, `INSERT INTO accounts(account_number, balance, client_id)
-		values($1,$2,$3)
-		RETURNING id, account_number, balance, client_id, created_at`,
+	accountType := account.AccountType
+	if accountType == "" {
+		accountType = model.AccountTypeCurrent
+	}
+	// The owner is recorded among the account's holders in the same
+	// statement.
+	savedAccount, err := scanAccount(r.pool.QueryRow(ctx, `WITH a AS (
+			INSERT INTO accounts(account_number, balance, client_id, currency, account_type)
+			values($1,$2,$3,$4,$5)
+			RETURNING `+accountColumns+`
+		), h AS (
+			INSERT INTO account_holders (account_id, client_id, role)
+			SELECT id, client_id, 'owner' FROM a
+		)
+		SELECT * FROM a`,
 		account.AccountNumber,
 		account.Balance,
 		account.ClientId,
-	).Scan(
-		&savedAccount.ID,
-		&savedAccount.AccountNumber,
-		&savedAccount.Balance,
-		&savedAccount.ClientId,
-		&savedAccount.CreatedAt,
-	); err != nil {
+		account.Currency,
+		accountType,
+	))
+	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return nil, fmt.Errorf("account_number already exists")
+			return nil, fmt.Errorf("%w: account_number already exists", ErrDuplicate)
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" && pgErr.ConstraintName == "accounts_account_type_fkey" {
+			return nil, fmt.Errorf("unknown account type %q", accountType)
 		}
 		return nil, fmt.Errorf("insert account: %w", err)
 	}
 
-	return &savedAccount, nil
+	return savedAccount, nil
 }
 
 func (r *AccountRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int, forUpdate bool) (*model.Account, error) {
-	q := `
-		SELECT id, account_number, balance, client_id, created_at
-		FROM accounts WHERE id = $1`
+	q := "SELECT " + accountColumns + " FROM accounts WHERE id = $1"
 	if forUpdate {
 		q += " FOR UPDATE"
 	}
-	var a model.Account
-	if err := tx.QueryRow(ctx, q, id).
-		Scan(&a.ID, &a.AccountNumber, &a.Balance, &a.ClientId, &a.CreatedAt); err != nil {
+	a, err := scanAccount(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account with an id: %d %w", id, ErrNotFound)
+		}
 		return nil, err
 	}
-	return &a, nil
+	return a, nil
 }
 
 func (r *AccountRepository) UpdateBalanceDeltaTx(ctx context.Context, tx pgx.Tx, id int, delta float64) (*model.Account, error) {
-	var a model.Account
-	if err := tx.QueryRow(ctx, `
+	a, err := scanAccount(tx.QueryRow(ctx, `
 		UPDATE accounts
 		SET balance = balance + $1
 		WHERE id = $2
-		RETURNING id, client_id, account_number, balance, created_at
-	`, delta, id).
-		Scan(&a.ID, &a.ClientId, &a.AccountNumber, &a.Balance, &a.CreatedAt); err != nil {
+		RETURNING `+accountColumns, delta, id))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			return nil, fmt.Errorf("%w: account %d would exceed its overdraft limit", ErrInsufficientFunds, id)
+		}
 		return nil, err
 	}
-	return &a, nil
+	return a, nil
+}
+
+func (r *AccountRepository) UpdateOverdraftLimitTx(ctx context.Context, tx pgx.Tx, id int, limit float64) (*model.Account, error) {
+	a, err := scanAccount(tx.QueryRow(ctx, `
+		UPDATE accounts
+		SET overdraft_limit = $1
+		WHERE id = $2
+		RETURNING `+accountColumns, limit, id))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23514" {
+			return nil, fmt.Errorf("overdraft limit %v is below the current negative balance of account %d", limit, id)
+		}
+		return nil, fmt.Errorf("update overdraft limit: %w", err)
+	}
+	return a, nil
+}
+
+// UpdateStatusTx freezes or unfreezes the account.
+func (r *AccountRepository) UpdateStatusTx(ctx context.Context, tx pgx.Tx, id int, status string) (*model.Account, error) {
+	a, err := scanAccount(tx.QueryRow(ctx, `
+		UPDATE accounts
+		SET status = $1
+		WHERE id = $2
+		RETURNING `+accountColumns, status, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account with an id: %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("update account status: %w", err)
+	}
+	return a, nil
+}
+
+// ListAfter pages through all accounts in id order.
+func (r *AccountRepository) ListAfter(ctx context.Context, afterID, limit int) ([]*model.Account, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+accountColumns+" FROM accounts WHERE id > $1 ORDER BY id LIMIT $2", afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var accounts []*model.Account
+	for rows.Next() {
+		a, err := scanAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		accounts = append(accounts, a)
+	}
+	return accounts, rows.Err()
+}
+
+// RenumberTx gives the account a new number. The first number it ever had is
+// kept as its legacy number.
+func (r *AccountRepository) RenumberTx(ctx context.Context, tx pgx.Tx, id int, number string) (*model.Account, error) {
+	a, err := scanAccount(tx.QueryRow(ctx, `
+		UPDATE accounts
+		SET legacy_account_number = COALESCE(legacy_account_number, account_number), account_number = $2
+		WHERE id = $1
+		RETURNING `+accountColumns, id, number))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: account_number already exists", ErrDuplicate)
+		}
+		return nil, fmt.Errorf("renumber account: %w", err)
+	}
+	return a, nil
 }
 
 func (r *AccountRepository) Pool() *pgxpool.Pool { return r.pool }
@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// accountHolderColumns must be selected from the account_holders table
+// unaliased: the consents subquery refers to account_holders.id.
+const accountHolderColumns = `id, account_id, client_id, role, COALESCE(spend_limit, 0), status, COALESCE(added_by, 0),
+	COALESCE((SELECT jsonb_agg(jsonb_build_object('client_id', c.client_id, 'consented_at', c.consented_at) ORDER BY c.client_id)
+		FROM account_holder_consents c WHERE c.holder_id = account_holders.id), '[]'),
+	created_at, updated_at`
+
+type AccountHolderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAccountHolderRepository(pool *pgxpool.Pool) *AccountHolderRepository {
+	return &AccountHolderRepository{pool: pool}
+}
+
+func (r *AccountHolderRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanAccountHolder(row pgx.Row) (*model.AccountHolder, error) {
+	var h model.AccountHolder
+	if err := row.Scan(&h.ID, &h.AccountID, &h.ClientID, &h.Role, &h.SpendLimit, &h.Status, &h.AddedBy,
+		&h.Consents, &h.CreatedAt, &h.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// List returns the account's active and pending holders or, with all, also
+// those that were declined or removed, owner first.
+func (r *AccountHolderRepository) List(ctx context.Context, accountID int, all bool) ([]*model.AccountHolder, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+accountHolderColumns+`
+		FROM account_holders
+		WHERE account_id = $1 AND ($2 OR status IN ('pending', 'active'))
+		ORDER BY role = 'owner' DESC, id`, accountID, all)
+	if err != nil {
+		return nil, fmt.Errorf("list account holders: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.AccountHolder{}
+	for rows.Next() {
+		h, err := scanAccountHolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// ListByClient returns every role the client has or had on any account,
+// oldest first.
+func (r *AccountHolderRepository) ListByClient(ctx context.Context, clientID int) ([]*model.AccountHolder, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+accountHolderColumns+`
+		FROM account_holders
+		WHERE client_id = $1
+		ORDER BY id`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list account holders: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.AccountHolder{}
+	for rows.Next() {
+		h, err := scanAccountHolder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// ClientIDs returns the clients holding an active role on the account.
+func (r *AccountHolderRepository) ClientIDs(ctx context.Context, accountID int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, "SELECT client_id FROM account_holders WHERE account_id = $1 AND status = 'active'", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list account holder clients: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("list account holder clients: %w", err)
+	}
+	return ids, nil
+}
+
+const managersQuery = `
+	SELECT client_id FROM account_holders
+	WHERE account_id = $1 AND status = 'active' AND role IN ('owner', 'joint_owner')
+	ORDER BY id`
+
+// Managers returns the clients that are active owners or joint owners of
+// the account.
+func (r *AccountHolderRepository) Managers(ctx context.Context, accountID int) ([]int, error) {
+	rows, err := r.pool.Query(ctx, managersQuery, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list account managers: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("list account managers: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *AccountHolderRepository) ManagersTx(ctx context.Context, tx pgx.Tx, accountID int) ([]int, error) {
+	rows, err := tx.Query(ctx, managersQuery, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list account managers: %w", err)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("list account managers: %w", err)
+	}
+	return ids, nil
+}
+
+const activeHolderQuery = `SELECT ` + accountHolderColumns + `
+	FROM account_holders
+	WHERE account_id = $1 AND client_id = $2 AND status = 'active'`
+
+// GetActive returns clientID's active role on the account, or nil.
+func (r *AccountHolderRepository) GetActive(ctx context.Context, accountID, clientID int) (*model.AccountHolder, error) {
+	h, err := scanAccountHolder(r.pool.QueryRow(ctx, activeHolderQuery, accountID, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get account holder: %w", err)
+	}
+	return h, nil
+}
+
+func (r *AccountHolderRepository) GetActiveTx(ctx context.Context, tx pgx.Tx, accountID, clientID int) (*model.AccountHolder, error) {
+	h, err := scanAccountHolder(tx.QueryRow(ctx, activeHolderQuery, accountID, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get account holder: %w", err)
+	}
+	return h, nil
+}
+
+func (r *AccountHolderRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, accountID, id int) (*model.AccountHolder, error) {
+	h, err := scanAccountHolder(tx.QueryRow(ctx, `
+		SELECT `+accountHolderColumns+`
+		FROM account_holders
+		WHERE id = $1 AND account_id = $2
+		FOR UPDATE`, id, accountID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("holder %d of account %d %w", id, accountID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get account holder: %w", err)
+	}
+	return h, nil
+}
+
+// CreateTx saves h as pending with a consent row, not yet given, for each
+// of consenters.
+func (r *AccountHolderRepository) CreateTx(ctx context.Context, tx pgx.Tx, h *model.AccountHolder, consenters []int) (*model.AccountHolder, error) {
+	var id int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO account_holders (account_id, client_id, role, spend_limit, status, added_by)
+		VALUES ($1, $2, $3, NULLIF($4::numeric, 0), 'pending', NULLIF($5::int, 0))
+		RETURNING id`, h.AccountID, h.ClientID, h.Role, h.SpendLimit, h.AddedBy,
+	).Scan(&id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: client %d is already a holder of account %d", ErrDuplicate, h.ClientID, h.AccountID)
+		}
+		return nil, fmt.Errorf("insert account holder: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		INSERT INTO account_holder_consents (holder_id, client_id)
+		SELECT $1, UNNEST($2::int[])
+		ON CONFLICT DO NOTHING`, id, consenters); err != nil {
+		return nil, fmt.Errorf("insert account holder consents: %w", err)
+	}
+	return r.GetByIdTx(ctx, tx, h.AccountID, id)
+}
+
+// ConsentTx records clientID's consent to holder id and returns how many
+// consents are still missing.
+func (r *AccountHolderRepository) ConsentTx(ctx context.Context, tx pgx.Tx, id, clientID int) (int, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE account_holder_consents SET consented_at = NOW()
+		WHERE holder_id = $1 AND client_id = $2 AND consented_at IS NULL`, id, clientID)
+	if err != nil {
+		return 0, fmt.Errorf("record consent: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, fmt.Errorf("no consent of client %d is pending for holder %d", clientID, id)
+	}
+	var missing int
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM account_holder_consents WHERE holder_id = $1 AND consented_at IS NULL", id).Scan(&missing); err != nil {
+		return 0, fmt.Errorf("count consents: %w", err)
+	}
+	return missing, nil
+}
+
+func (r *AccountHolderRepository) SetStatusTx(ctx context.Context, tx pgx.Tx, id int, status string) error {
+	if _, err := tx.Exec(ctx, "UPDATE account_holders SET status = $2, updated_at = NOW() WHERE id = $1", id, status); err != nil {
+		return fmt.Errorf("update account holder: %w", err)
+	}
+	return nil
+}
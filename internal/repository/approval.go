@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const approvalColumns = `id, operation, entity_type, entity_id, payload, status, maker,
+	COALESCE(checker, ''), COALESCE(reason, ''), result, COALESCE(request_id, ''),
+	expires_at, decided_at, created_at`
+
+type ApprovalRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewApprovalRepository(pool *pgxpool.Pool) *ApprovalRepository {
+	return &ApprovalRepository{pool: pool}
+}
+
+func (r *ApprovalRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanApproval(row pgx.Row) (*model.Approval, error) {
+	var a model.Approval
+	if err := row.Scan(
+		&a.ID, &a.Operation, &a.EntityType, &a.EntityID, &a.Payload, &a.Status, &a.Maker,
+		&a.Checker, &a.Reason, &a.Result, &a.RequestID,
+		&a.ExpiresAt, &a.DecidedAt, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func collectApprovals(rows pgx.Rows, err error) ([]*model.Approval, error) {
+	if err != nil {
+		return nil, fmt.Errorf("list approvals: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.Approval{}
+	for rows.Next() {
+		a, err := scanApproval(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *ApprovalRepository) CreateTx(ctx context.Context, tx pgx.Tx, a *model.Approval) (*model.Approval, error) {
+	saved, err := scanApproval(tx.QueryRow(ctx, `
+		INSERT INTO approvals (operation, entity_type, entity_id, payload, maker, request_id, expires_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7)
+		RETURNING `+approvalColumns,
+		a.Operation, a.EntityType, a.EntityID, a.Payload, a.Maker, a.RequestID, a.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert approval: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *ApprovalRepository) GetById(ctx context.Context, id int) (*model.Approval, error) {
+	a, err := scanApproval(r.pool.QueryRow(ctx, "SELECT "+approvalColumns+" FROM approvals WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("approval %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get approval: %w", err)
+	}
+	return a, nil
+}
+
+func (r *ApprovalRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int, forUpdate bool) (*model.Approval, error) {
+	q := "SELECT " + approvalColumns + " FROM approvals WHERE id = $1"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	a, err := scanApproval(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("approval %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get approval: %w", err)
+	}
+	return a, nil
+}
+
+func (r *ApprovalRepository) List(ctx context.Context, status string, limit, offset int) ([]*model.Approval, error) {
+	return collectApprovals(r.pool.Query(ctx, `
+		SELECT `+approvalColumns+`
+		FROM approvals
+		WHERE $1 = '' OR status = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`, status, limit, offset))
+}
+
+// DecideTx moves a pending approval to its final status.
+func (r *ApprovalRepository) DecideTx(ctx context.Context, tx pgx.Tx, id int, status, checker, reason string, result json.RawMessage) (*model.Approval, error) {
+	a, err := scanApproval(tx.QueryRow(ctx, `
+		UPDATE approvals
+		SET status = $2, checker = $3, reason = NULLIF($4, ''), result = $5, decided_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING `+approvalColumns,
+		id, status, checker, reason, result,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("approval %d is not pending", id)
+		}
+		return nil, fmt.Errorf("decide approval: %w", err)
+	}
+	return a, nil
+}
+
+// ExpireDue marks every pending approval past its expiry as expired and
+// returns the affected approvals.
+func (r *ApprovalRepository) ExpireDue(ctx context.Context) ([]*model.Approval, error) {
+	return collectApprovals(r.pool.Query(ctx, `
+		UPDATE approvals
+		SET status = 'expired', decided_at = NOW()
+		WHERE status = 'pending' AND expires_at <= NOW()
+		RETURNING `+approvalColumns))
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type AuditRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAuditRepository(pool *pgxpool.Pool) *AuditRepository {
+	return &AuditRepository{pool: pool}
+}
+
+const insertAudit = `
+	INSERT INTO audit_log (actor, action, entity_type, entity_id, details, request_id)
+	VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))`
+
+// RecordTx writes an audit entry as part of tx so it commits or rolls back
+// together with the change it describes.
+func (r *AuditRepository) RecordTx(ctx context.Context, tx pgx.Tx, e *model.AuditEntry) error {
+	if _, err := tx.Exec(ctx, insertAudit, e.Actor, e.Action, e.EntityType, e.EntityID, detailsOrEmpty(e.Details), e.RequestID); err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) Record(ctx context.Context, e *model.AuditEntry) error {
+	if _, err := r.pool.Exec(ctx, insertAudit, e.Actor, e.Action, e.EntityType, e.EntityID, detailsOrEmpty(e.Details), e.RequestID); err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) List(ctx context.Context, entityType, entityID string, limit, offset int) ([]*model.AuditEntry, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, actor, action, entity_type, entity_id, details, COALESCE(request_id, ''), created_at
+		FROM audit_log
+		WHERE ($1 = '' OR entity_type = $1) AND ($2 = '' OR entity_id = $2)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4
+	`, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.AuditEntry
+	for rows.Next() {
+		var e model.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &e.Details, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
+
+// ListByClient returns, oldest first, the entries about the client, about
+// the given accounts of it and those naming it as client_id in their
+// details.
+func (r *AuditRepository) ListByClient(ctx context.Context, clientID int64, accountIDs []int) ([]*model.AuditEntry, error) {
+	ids := make([]string, 0, len(accountIDs))
+	for _, id := range accountIDs {
+		ids = append(ids, strconv.Itoa(id))
+	}
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, actor, action, entity_type, entity_id, details, COALESCE(request_id, ''), created_at
+		FROM audit_log
+		WHERE (entity_type = 'client' AND entity_id = $1)
+			OR (entity_type = 'account' AND entity_id = ANY($2))
+			OR details->>'client_id' = $1
+		ORDER BY id
+	`, strconv.FormatInt(clientID, 10), ids)
+	if err != nil {
+		return nil, fmt.Errorf("list audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.AuditEntry{}
+	for rows.Next() {
+		var e model.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Actor, &e.Action, &e.EntityType, &e.EntityID, &e.Details, &e.RequestID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, &e)
+	}
+	return out, rows.Err()
+}
+
+func detailsOrEmpty(d map[string]any) map[string]any {
+	if d == nil {
+		return map[string]any{}
+	}
+	return d
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const beneficiaryColumns = `id, client_id, nickname, account_id, account_number, name, currency, payee_match,
+	trusted_at, created_at, updated_at`
+
+type BeneficiaryRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBeneficiaryRepository(pool *pgxpool.Pool) *BeneficiaryRepository {
+	return &BeneficiaryRepository{pool: pool}
+}
+
+func (r *BeneficiaryRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanBeneficiary(row pgx.Row) (*model.Beneficiary, error) {
+	var b model.Beneficiary
+	if err := row.Scan(
+		&b.ID, &b.ClientID, &b.Nickname, &b.AccountID, &b.AccountNumber, &b.Name, &b.Currency, &b.PayeeMatch,
+		&b.TrustedAt, &b.CreatedAt, &b.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// beneficiaryConflict turns unique violations into messages naming the
+// clashing field.
+func beneficiaryConflict(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		switch pgErr.ConstraintName {
+		case "beneficiaries_client_id_nickname_key":
+			return errors.New("a beneficiary with this nickname already exists")
+		case "beneficiaries_client_id_account_id_key":
+			return errors.New("this account is already saved as a beneficiary")
+		}
+	}
+	return nil
+}
+
+func (r *BeneficiaryRepository) CreateTx(ctx context.Context, tx pgx.Tx, b *model.Beneficiary) (*model.Beneficiary, error) {
+	saved, err := scanBeneficiary(tx.QueryRow(ctx, `
+		INSERT INTO beneficiaries (client_id, nickname, account_id, account_number, name, currency, payee_match, trusted_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING `+beneficiaryColumns,
+		b.ClientID, b.Nickname, b.AccountID, b.AccountNumber, b.Name, b.Currency, b.PayeeMatch, b.TrustedAt))
+	if err != nil {
+		if cErr := beneficiaryConflict(err); cErr != nil {
+			return nil, cErr
+		}
+		return nil, fmt.Errorf("insert beneficiary: %w", err)
+	}
+	return saved, nil
+}
+
+// GetById returns the client's beneficiary; one saved by another client is
+// reported as not found.
+func (r *BeneficiaryRepository) GetById(ctx context.Context, clientID, id int) (*model.Beneficiary, error) {
+	b, err := scanBeneficiary(r.pool.QueryRow(ctx,
+		"SELECT "+beneficiaryColumns+" FROM beneficiaries WHERE id = $1 AND client_id = $2", id, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("beneficiary %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get beneficiary: %w", err)
+	}
+	return b, nil
+}
+
+// GetByIdAnyClient looks a beneficiary up by id alone, for callers that
+// check ownership themselves.
+func (r *BeneficiaryRepository) GetByIdAnyClient(ctx context.Context, id int) (*model.Beneficiary, error) {
+	b, err := scanBeneficiary(r.pool.QueryRow(ctx, "SELECT "+beneficiaryColumns+" FROM beneficiaries WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("beneficiary %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get beneficiary: %w", err)
+	}
+	return b, nil
+}
+
+func (r *BeneficiaryRepository) ListByClientID(ctx context.Context, clientID int) ([]*model.Beneficiary, error) {
+	rows, err := r.pool.Query(ctx,
+		"SELECT "+beneficiaryColumns+" FROM beneficiaries WHERE client_id = $1 ORDER BY nickname", clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list beneficiaries: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.Beneficiary{}
+	for rows.Next() {
+		b, err := scanBeneficiary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+// RenameTx changes the nickname; nothing that affects where money goes can
+// be edited, so the cooling-off period is not restarted.
+func (r *BeneficiaryRepository) RenameTx(ctx context.Context, tx pgx.Tx, clientID, id int, nickname string) (*model.Beneficiary, error) {
+	b, err := scanBeneficiary(tx.QueryRow(ctx, `
+		UPDATE beneficiaries
+		SET nickname = $3, updated_at = NOW()
+		WHERE id = $1 AND client_id = $2
+		RETURNING `+beneficiaryColumns, id, clientID, nickname))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("beneficiary %d %w", id, ErrNotFound)
+		}
+		if cErr := beneficiaryConflict(err); cErr != nil {
+			return nil, cErr
+		}
+		return nil, fmt.Errorf("rename beneficiary: %w", err)
+	}
+	return b, nil
+}
+
+func (r *BeneficiaryRepository) DeleteTx(ctx context.Context, tx pgx.Tx, clientID, id int) (*model.Beneficiary, error) {
+	b, err := scanBeneficiary(tx.QueryRow(ctx, `
+		DELETE FROM beneficiaries
+		WHERE id = $1 AND client_id = $2
+		RETURNING `+beneficiaryColumns, id, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("beneficiary %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("delete beneficiary: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const businessPartyColumns = `p.id, p.business_id, p.person_id, p.role, COALESCE(p.ownership_percent, 0)::float8,
+	COALESCE(p.title, ''), p.created_at, COALESCE(c.first_name || ' ' || c.last_name, '')`
+
+type BusinessPartyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBusinessPartyRepository(pool *pgxpool.Pool) *BusinessPartyRepository {
+	return &BusinessPartyRepository{pool: pool}
+}
+
+func (r *BusinessPartyRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanBusinessParty(row pgx.Row) (*model.BusinessParty, error) {
+	var p model.BusinessParty
+	if err := row.Scan(&p.ID, &p.BusinessID, &p.PersonID, &p.Role, &p.OwnershipPercent, &p.Title, &p.CreatedAt, &p.PersonName); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// List returns the business's beneficial owners, largest share first, then
+// its signatories.
+func (r *BusinessPartyRepository) List(ctx context.Context, businessID int64) ([]*model.BusinessParty, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+businessPartyColumns+`
+		FROM business_parties p
+		JOIN clients c ON c.id = p.person_id
+		WHERE p.business_id = $1
+		ORDER BY p.role, p.ownership_percent DESC NULLS LAST, p.id`, businessID)
+	if err != nil {
+		return nil, fmt.Errorf("list business parties: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.BusinessParty{}
+	for rows.Next() {
+		p, err := scanBusinessParty(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// OwnershipTx sums the shares of the business's beneficial owners.
+func (r *BusinessPartyRepository) OwnershipTx(ctx context.Context, tx pgx.Tx, businessID int64) (float64, error) {
+	var total float64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(ownership_percent), 0)::float8
+		FROM business_parties
+		WHERE business_id = $1 AND role = 'beneficial_owner'`, businessID,
+	).Scan(&total); err != nil {
+		return 0, fmt.Errorf("sum business ownership: %w", err)
+	}
+	return total, nil
+}
+
+func (r *BusinessPartyRepository) CreateTx(ctx context.Context, tx pgx.Tx, p *model.BusinessParty) (*model.BusinessParty, error) {
+	var id int
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO business_parties (business_id, person_id, role, ownership_percent, title)
+		VALUES ($1, $2, $3, NULLIF($4, 0), NULLIF($5, ''))
+		RETURNING id`, p.BusinessID, p.PersonID, p.Role, p.OwnershipPercent, p.Title,
+	).Scan(&id); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: client %d is already a %s of business %d", ErrDuplicate, p.PersonID, p.Role, p.BusinessID)
+		}
+		return nil, fmt.Errorf("insert business party: %w", err)
+	}
+	return r.getTx(ctx, tx, p.BusinessID, id)
+}
+
+// DeleteTx removes party id of the business and returns it.
+func (r *BusinessPartyRepository) DeleteTx(ctx context.Context, tx pgx.Tx, businessID int64, id int) (*model.BusinessParty, error) {
+	p, err := r.getTx(ctx, tx, businessID, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(ctx, "DELETE FROM business_parties WHERE id = $1", id); err != nil {
+		return nil, fmt.Errorf("delete business party: %w", err)
+	}
+	return p, nil
+}
+
+func (r *BusinessPartyRepository) getTx(ctx context.Context, tx pgx.Tx, businessID int64, id int) (*model.BusinessParty, error) {
+	p, err := scanBusinessParty(tx.QueryRow(ctx, `
+		SELECT `+businessPartyColumns+`
+		FROM business_parties p
+		JOIN clients c ON c.id = p.person_id
+		WHERE p.id = $1 AND p.business_id = $2`, id, businessID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("party %d of business %d %w", id, businessID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get business party: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const caseColumns = `id, client_id, status, COALESCE(assignee, ''), opened_by, COALESCE(closed_by, ''),
+	closed_at, created_at, updated_at`
+
+// activeCaseStatuses must match the predicate of idx_cases_client_active.
+const activeCaseStatuses = `('open', 'investigating', 'escalated')`
+
+type CaseRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCaseRepository(pool *pgxpool.Pool) *CaseRepository {
+	return &CaseRepository{pool: pool}
+}
+
+func (r *CaseRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanCase(row pgx.Row) (*model.Case, error) {
+	var c model.Case
+	if err := row.Scan(
+		&c.ID, &c.ClientID, &c.Status, &c.Assignee, &c.OpenedBy, &c.ClosedBy,
+		&c.ClosedAt, &c.CreatedAt, &c.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// OpenForAccountTx returns the case of the client owning accountID that is
+// not closed, opening one when there is none. created reports whether it
+// did.
+func (r *CaseRepository) OpenForAccountTx(ctx context.Context, tx pgx.Tx, accountID int, openedBy string) (c *model.Case, created bool, err error) {
+	c, err = scanCase(tx.QueryRow(ctx, `
+		INSERT INTO cases (client_id, opened_by)
+		SELECT client_id, $2 FROM accounts WHERE id = $1
+		ON CONFLICT (client_id) WHERE status IN `+activeCaseStatuses+` DO NOTHING
+		RETURNING `+caseColumns, accountID, openedBy))
+	if err == nil {
+		return c, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, fmt.Errorf("open case: %w", err)
+	}
+
+	c, err = scanCase(tx.QueryRow(ctx, `
+		SELECT `+caseColumns+`
+		FROM cases
+		WHERE client_id = (SELECT client_id FROM accounts WHERE id = $1) AND status IN `+activeCaseStatuses,
+		accountID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("account with an id: %d %w", accountID, ErrNotFound)
+		}
+		return nil, false, fmt.Errorf("find open case: %w", err)
+	}
+	return c, false, nil
+}
+
+func (r *CaseRepository) GetById(ctx context.Context, id int) (*model.Case, error) {
+	c, err := scanCase(r.pool.QueryRow(ctx, "SELECT "+caseColumns+" FROM cases WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("case %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get case: %w", err)
+	}
+	return c, nil
+}
+
+func (r *CaseRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int, forUpdate bool) (*model.Case, error) {
+	q := "SELECT " + caseColumns + " FROM cases WHERE id = $1"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	c, err := scanCase(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("case %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get case: %w", err)
+	}
+	return c, nil
+}
+
+// List returns cases, most recently updated first. Empty filters match all.
+func (r *CaseRepository) List(ctx context.Context, status, assignee string, clientID, limit, offset int) ([]*model.Case, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+caseColumns+`
+		FROM cases
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR assignee = $2) AND ($3 = 0 OR client_id = $3)
+		ORDER BY updated_at DESC, id DESC
+		LIMIT $4 OFFSET $5`, status, assignee, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list cases: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.Case{}
+	for rows.Next() {
+		c, err := scanCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+func (r *CaseRepository) AssignTx(ctx context.Context, tx pgx.Tx, id int, assignee string) (*model.Case, error) {
+	c, err := scanCase(tx.QueryRow(ctx, `
+		UPDATE cases
+		SET assignee = NULLIF($2, ''), updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+caseColumns, id, assignee))
+	if err != nil {
+		return nil, fmt.Errorf("assign case: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateStatusTx moves the case to status; closing statuses record who
+// closed it.
+func (r *CaseRepository) UpdateStatusTx(ctx context.Context, tx pgx.Tx, id int, status, actor string) (*model.Case, error) {
+	c, err := scanCase(tx.QueryRow(ctx, `
+		UPDATE cases
+		SET status = $2, updated_at = NOW(),
+			closed_by = CASE WHEN $2 LIKE 'closed-%' THEN $3 END,
+			closed_at = CASE WHEN $2 LIKE 'closed-%' THEN NOW() END
+		WHERE id = $1
+		RETURNING `+caseColumns, id, status, actor))
+	if err != nil {
+		return nil, fmt.Errorf("update case status: %w", err)
+	}
+	return c, nil
+}
+
+// TouchTx marks the case as updated.
+func (r *CaseRepository) TouchTx(ctx context.Context, tx pgx.Tx, id int) error {
+	if _, err := tx.Exec(ctx, "UPDATE cases SET updated_at = NOW() WHERE id = $1", id); err != nil {
+		return fmt.Errorf("touch case: %w", err)
+	}
+	return nil
+}
+
+func (r *CaseRepository) AddNoteTx(ctx context.Context, tx pgx.Tx, n *model.CaseNote) (*model.CaseNote, error) {
+	var out model.CaseNote
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO case_notes (case_id, author, body)
+		VALUES ($1, $2, $3)
+		RETURNING id, case_id, author, body, created_at`, n.CaseID, n.Author, n.Body,
+	).Scan(&out.ID, &out.CaseID, &out.Author, &out.Body, &out.CreatedAt); err != nil {
+		return nil, fmt.Errorf("insert case note: %w", err)
+	}
+	return &out, nil
+}
+
+func (r *CaseRepository) ListNotes(ctx context.Context, caseID int) ([]*model.CaseNote, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT id, case_id, author, body, created_at
+		FROM case_notes
+		WHERE case_id = $1
+		ORDER BY id`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("list case notes: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.CaseNote{}
+	for rows.Next() {
+		var n model.CaseNote
+		if err := rows.Scan(&n.ID, &n.CaseID, &n.Author, &n.Body, &n.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, &n)
+	}
+	return out, rows.Err()
+}
+
+const caseAttachmentColumns = `id, case_id, file_name, content_type, size_bytes, COALESCE(sha256, ''),
+	storage_url, uploaded_by, created_at`
+
+func scanCaseAttachment(row pgx.Row) (*model.CaseAttachment, error) {
+	var a model.CaseAttachment
+	if err := row.Scan(
+		&a.ID, &a.CaseID, &a.FileName, &a.ContentType, &a.SizeBytes, &a.SHA256,
+		&a.StorageURL, &a.UploadedBy, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *CaseRepository) AddAttachmentTx(ctx context.Context, tx pgx.Tx, a *model.CaseAttachment) (*model.CaseAttachment, error) {
+	saved, err := scanCaseAttachment(tx.QueryRow(ctx, `
+		INSERT INTO case_attachments (case_id, file_name, content_type, size_bytes, sha256, storage_url, uploaded_by)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7)
+		RETURNING `+caseAttachmentColumns,
+		a.CaseID, a.FileName, a.ContentType, a.SizeBytes, a.SHA256, a.StorageURL, a.UploadedBy,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert case attachment: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *CaseRepository) ListAttachments(ctx context.Context, caseID int) ([]*model.CaseAttachment, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+caseAttachmentColumns+`
+		FROM case_attachments
+		WHERE case_id = $1
+		ORDER BY id`, caseID)
+	if err != nil {
+		return nil, fmt.Errorf("list case attachments: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.CaseAttachment{}
+	for rows.Next() {
+		a, err := scanCaseAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -5,12 +5,19 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// clientColumns leaves the other kind's fields empty: names and birth date
+// are NULL for businesses, registration details for individuals.
+const clientColumns = `id, type, COALESCE(first_name, ''), COALESCE(last_name, ''), email, COALESCE(residence_address, ''),
+	birth_date, COALESCE(registered_name, ''), COALESCE(registration_number, ''), COALESCE(tax_id, ''),
+	incorporation_date, COALESCE(incorporation_country, ''), email_verified_at, COALESCE(merged_into, 0), erased_at, created_at`
+
 type ClientRepository struct {
 	pool *pgxpool.Pool
 }
@@ -21,99 +28,219 @@ func NewClientRepository(pool *pgxpool.Pool) *ClientRepository {
 	}
 }
 
-func (r *ClientRepository) GetAll(ctx context.Context) ([]*model.Client, error) {
-	rows, err := r.pool.Query(ctx, "SELECT id, first_name, last_name, email, residence_address, birth_date, created_at FROM clients")
-
-	if err != nil {
-		return nil, fmt.Errorf("get all clients query: %v", err)
+func scanClient(row pgx.Row) (*model.Client, error) {
+	var c model.Client
+	var birthDate, incorporationDate *time.Time
+	if err := row.Scan(
+		&c.ID, &c.Type, &c.FirstName, &c.LastName, &c.Email, &c.ResidenceAddress,
+		&birthDate, &c.RegisteredName, &c.RegistrationNumber, &c.TaxID,
+		&incorporationDate, &c.IncorporationCountry, &c.EmailVerifiedAt, &c.MergedInto, &c.ErasedAt, &c.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if birthDate != nil {
+		c.BirthDate = *birthDate
 	}
+	if incorporationDate != nil {
+		c.IncorporationDate = *incorporationDate
+	}
+	return &c, nil
+}
+
+func scanClients(rows pgx.Rows) ([]*model.Client, error) {
 	defer rows.Close()
 
 	var clients []*model.Client
-
 	for rows.Next() {
-		var client model.Client
-
-		if err := rows.Scan(&client.ID, &client.FirstName, &client.LastName, &client.Email, &client.ResidenceAddress, &client.BirthDate, &client.CreatedAt); err != nil {
+		c, err := scanClient(rows)
+		if err != nil {
 			return nil, fmt.Errorf("scanning rows: %v", err)
 		}
-
-		clients = append(clients, &client)
+		clients = append(clients, c)
 	}
-
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("client rows: %v", err)
 	}
-
 	return clients, nil
 }
 
+// nullDate stores the zero time as NULL.
+func nullDate(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+func (r *ClientRepository) GetAll(ctx context.Context) ([]*model.Client, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+clientColumns+" FROM clients WHERE merged_into IS NULL AND erased_at IS NULL")
+
+	if err != nil {
+		return nil, fmt.Errorf("get all clients query: %v", err)
+	}
+	return scanClients(rows)
+}
+
 func (r *ClientRepository) GetById(ctx context.Context, id int64) (*model.Client, error) {
-	var c model.Client
-	if err := r.pool.QueryRow(ctx, "SELECT id, first_name, last_name, email, residence_address, birth_date, created_at FROM clients WHERE id = $1", id).Scan(
-		&c.ID,
-		&c.FirstName,
-		&c.LastName,
-		&c.Email,
-		&c.ResidenceAddress,
-		&c.BirthDate,
-		&c.CreatedAt,
-	); err != nil {
+	c, err := scanClient(r.pool.QueryRow(ctx, "SELECT "+clientColumns+" FROM clients WHERE id = $1", id))
+	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("client %d not found", id)
+			return nil, fmt.Errorf("client %d %w", id, ErrNotFound)
 		}
 		return nil, fmt.Errorf("get client by id query: %w", err)
 	}
 
-	return &c, nil
+	return c, nil
 }
 
 func (r *ClientRepository) CreateClient(ctx context.Context, client model.Client) (*model.Client, error) {
-	var result model.Client
-	err := r.pool.QueryRow(ctx, `INSERT INTO clients(first_name, last_name, email, residence_address, birth_date)
-			values($1,$2,$3,$4,$5)
-			RETURNING id, first_name, email, last_name, residence_address, birth_date`,
+	result, err := scanClient(r.pool.QueryRow(ctx, `INSERT INTO clients(type, first_name, last_name, email, residence_address, birth_date,
+				registered_name, registration_number, tax_id, incorporation_date, incorporation_country)
+			values($1, NULLIF($2, ''), NULLIF($3, ''), $4, $5, $6, NULLIF($7, ''), NULLIF($8, ''), NULLIF($9, ''), $10, NULLIF($11, ''))
+			RETURNING `+clientColumns,
+		client.Type,
 		client.FirstName,
 		client.LastName,
 		client.Email,
 		client.ResidenceAddress,
-		client.BirthDate,
-	).Scan(&result.ID, &result.FirstName, &result.LastName, &result.Email, &result.ResidenceAddress, &result.BirthDate)
+		nullDate(client.BirthDate),
+		client.RegisteredName,
+		client.RegistrationNumber,
+		client.TaxID,
+		nullDate(client.IncorporationDate),
+		client.IncorporationCountry,
+	))
 
 	if err != nil {
 		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique email
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			if pgErr.ConstraintName == "idx_clients_registration" {
+				return nil, fmt.Errorf("%w: registration number %s is already in use in %s", ErrDuplicate, client.RegistrationNumber, client.IncorporationCountry)
+			}
 			return nil, fmt.Errorf("email already exists")
 		}
 		return nil, fmt.Errorf("insert client: %w", err)
 	}
 
-	return &result, nil
+	return result, nil
 }
 
+// UpdateClient saves the fields of client's kind; its type stays as it is.
 func (r *ClientRepository) UpdateClient(ctx context.Context, client model.Client) (*model.Client, error) {
-	var result model.Client
-	err := r.pool.QueryRow(ctx, `UPDATE clients SET first_name = $1, last_name=$2, email=$3, residence_address=$4, birth_date=$5 WHERE id = $6 
-			RETURNING id, first_name, last_name, email, residence_address, birth_date, created_at`,
-		&client.FirstName,
-		&client.LastName,
-		&client.Email,
-		&client.ResidenceAddress,
-		&client.BirthDate,
-		&client.CreatedAt,
-	).Scan(&result.ID, &result.FirstName, &result.LastName, &client.Email, &result.ResidenceAddress, &result.BirthDate, &result.CreatedAt)
+	result, err := scanClient(r.pool.QueryRow(ctx, `UPDATE clients SET first_name = NULLIF($1, ''), last_name = NULLIF($2, ''), email = $3,
+			residence_address = $4, birth_date = $5, registered_name = NULLIF($6, ''), registration_number = NULLIF($7, ''),
+			tax_id = NULLIF($8, ''), incorporation_date = $9, incorporation_country = NULLIF($10, '')
+			WHERE id = $11 AND merged_into IS NULL
+			RETURNING `+clientColumns,
+		client.FirstName,
+		client.LastName,
+		client.Email,
+		client.ResidenceAddress,
+		nullDate(client.BirthDate),
+		client.RegisteredName,
+		client.RegistrationNumber,
+		client.TaxID,
+		nullDate(client.IncorporationDate),
+		client.IncorporationCountry,
+		client.ID,
+	))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("client %d not found", client.ID)
+			existing, gerr := r.GetById(ctx, client.ID)
+			if gerr != nil {
+				return nil, gerr
+			}
+			return nil, fmt.Errorf("client %d %w into client %d", client.ID, ErrClientMerged, existing.MergedInto)
 		}
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+			if pgErr.ConstraintName == "idx_clients_registration" {
+				return nil, fmt.Errorf("%w: registration number %s is already in use in %s", ErrDuplicate, client.RegistrationNumber, client.IncorporationCountry)
+			}
 			return nil, fmt.Errorf("email already exists")
 		}
 		return nil, fmt.Errorf("update client: %w", err)
 	}
-	return &result, nil
+	return result, nil
+}
+
+// ListAfter pages through all clients that were neither merged away nor
+// erased, in id order.
+func (r *ClientRepository) ListAfter(ctx context.Context, afterID int64, limit int) ([]*model.Client, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientColumns+`
+		FROM clients
+		WHERE id > $1 AND merged_into IS NULL AND erased_at IS NULL
+		ORDER BY id
+		LIMIT $2`, afterID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list clients: %w", err)
+	}
+	return scanClients(rows)
+}
+
+// FindByBirthDate returns the individual clients born on day other than
+// excludeID that were not merged away.
+func (r *ClientRepository) FindByBirthDate(ctx context.Context, day time.Time, excludeID int64) ([]*model.Client, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientColumns+`
+		FROM clients
+		WHERE birth_date = $1 AND id <> $2 AND merged_into IS NULL
+		ORDER BY id`, day, excludeID)
+	if err != nil {
+		return nil, fmt.Errorf("find clients by birth date: %w", err)
+	}
+	return scanClients(rows)
+}
+
+func (r *ClientRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int64, forUpdate bool) (*model.Client, error) {
+	q := "SELECT " + clientColumns + " FROM clients WHERE id = $1"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	c, err := scanClient(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("client %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get client by id query: %w", err)
+	}
+	return c, nil
+}
+
+// UpdateResidenceAddressTx sets the free-text residence address, which
+// mirrors the current structured residence address once one is set.
+func (r *ClientRepository) UpdateResidenceAddressTx(ctx context.Context, tx pgx.Tx, id int64, address string) error {
+	if _, err := tx.Exec(ctx, "UPDATE clients SET residence_address = $2 WHERE id = $1", id, address); err != nil {
+		return fmt.Errorf("update residence address: %w", err)
+	}
+	return nil
+}
+
+// EmailInUse reports whether email is the primary or another address of
+// any client.
+func (r *ClientRepository) EmailInUse(ctx context.Context, email string) (bool, error) {
+	var used bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM clients WHERE LOWER(email) = LOWER($1))
+			OR EXISTS (SELECT 1 FROM client_emails WHERE LOWER(email) = LOWER($1))`, email,
+	).Scan(&used); err != nil {
+		return false, fmt.Errorf("check email in use: %w", err)
+	}
+	return used, nil
+}
+
+// ConfirmEmailTx makes email the client's email and marks it verified.
+func (r *ClientRepository) ConfirmEmailTx(ctx context.Context, tx pgx.Tx, id int64, email string) error {
+	if _, err := tx.Exec(ctx, "UPDATE clients SET email = $2, email_verified_at = NOW() WHERE id = $1", id, email); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("%w: email %s is already in use", ErrDuplicate, email)
+		}
+		return fmt.Errorf("confirm email: %w", err)
+	}
+	return nil
 }
 
 func (r *ClientRepository) DeleteClient(ctx context.Context, id int64) error {
@@ -122,7 +249,7 @@ func (r *ClientRepository) DeleteClient(ctx context.Context, id int64) error {
 		return fmt.Errorf("delete client: %w", err)
 	}
 	if tag.RowsAffected() == 0 {
-		return fmt.Errorf("client %d not found", id)
+		return fmt.Errorf("client %d %w", id, ErrNotFound)
 	}
 	return nil
 }
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const clientAddressColumns = `id, client_id, type, COALESCE(street, ''), COALESCE(house_number, ''), COALESCE(postal_code, ''),
+	COALESCE(city, ''), COALESCE(country, ''), COALESCE(raw, ''), valid_from, valid_to, created_at`
+
+const clientPhoneColumns = `id, client_id, number, type, is_primary, created_at`
+
+const clientEmailColumns = `id, client_id, email, created_at`
+
+type ClientContactRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewClientContactRepository(pool *pgxpool.Pool) *ClientContactRepository {
+	return &ClientContactRepository{pool: pool}
+}
+
+func (r *ClientContactRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanClientAddress(row pgx.Row) (*model.ClientAddress, error) {
+	var a model.ClientAddress
+	if err := row.Scan(
+		&a.ID, &a.ClientID, &a.Type, &a.Street, &a.HouseNumber, &a.PostalCode,
+		&a.City, &a.Country, &a.Raw, &a.ValidFrom, &a.ValidTo, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func scanClientPhone(row pgx.Row) (*model.ClientPhone, error) {
+	var p model.ClientPhone
+	if err := row.Scan(&p.ID, &p.ClientID, &p.Number, &p.Type, &p.Primary, &p.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanClientEmail(row pgx.Row) (*model.ClientEmail, error) {
+	var e model.ClientEmail
+	if err := row.Scan(&e.ID, &e.ClientID, &e.Email, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// ListAddresses returns the client's current addresses or, with history,
+// all of them, newest first.
+func (r *ClientContactRepository) ListAddresses(ctx context.Context, clientID int64, history bool) ([]*model.ClientAddress, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientAddressColumns+`
+		FROM client_addresses
+		WHERE client_id = $1 AND ($2 OR valid_to IS NULL)
+		ORDER BY type DESC, valid_from DESC, id DESC`, clientID, history)
+	if err != nil {
+		return nil, fmt.Errorf("list client addresses: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.ClientAddress{}
+	for rows.Next() {
+		a, err := scanClientAddress(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+// ReplaceAddressTx ends the client's current address of a's type, if any,
+// and makes a the current one.
+func (r *ClientContactRepository) ReplaceAddressTx(ctx context.Context, tx pgx.Tx, a *model.ClientAddress) (*model.ClientAddress, error) {
+	if _, err := tx.Exec(ctx, `
+		UPDATE client_addresses
+		SET valid_to = NOW()
+		WHERE client_id = $1 AND type = $2 AND valid_to IS NULL`, a.ClientID, a.Type); err != nil {
+		return nil, fmt.Errorf("end client address: %w", err)
+	}
+	saved, err := scanClientAddress(tx.QueryRow(ctx, `
+		INSERT INTO client_addresses (client_id, type, street, house_number, postal_code, city, country)
+		VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), $6, $7)
+		RETURNING `+clientAddressColumns,
+		a.ClientID, a.Type, a.Street, a.HouseNumber, a.PostalCode, a.City, a.Country))
+	if err != nil {
+		return nil, fmt.Errorf("insert client address: %w", err)
+	}
+	return saved, nil
+}
+
+// ListPhones returns the client's phone numbers, the primary one first.
+func (r *ClientContactRepository) ListPhones(ctx context.Context, clientID int64) ([]*model.ClientPhone, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientPhoneColumns+`
+		FROM client_phones
+		WHERE client_id = $1
+		ORDER BY is_primary DESC, id`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list client phones: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.ClientPhone{}
+	for rows.Next() {
+		p, err := scanClientPhone(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// AddPhoneTx saves p. It becomes the primary number when asked to or when
+// the client has no other number.
+func (r *ClientContactRepository) AddPhoneTx(ctx context.Context, tx pgx.Tx, p *model.ClientPhone) (*model.ClientPhone, error) {
+	if p.Primary {
+		if _, err := tx.Exec(ctx, "UPDATE client_phones SET is_primary = FALSE WHERE client_id = $1 AND is_primary", p.ClientID); err != nil {
+			return nil, fmt.Errorf("clear primary phone: %w", err)
+		}
+	}
+	saved, err := scanClientPhone(tx.QueryRow(ctx, `
+		INSERT INTO client_phones (client_id, number, type, is_primary)
+		VALUES ($1, $2, $3, $4 OR NOT EXISTS (SELECT 1 FROM client_phones WHERE client_id = $1))
+		RETURNING `+clientPhoneColumns, p.ClientID, p.Number, p.Type, p.Primary))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: client %d already has phone number %s", ErrDuplicate, p.ClientID, p.Number)
+		}
+		return nil, fmt.Errorf("insert client phone: %w", err)
+	}
+	return saved, nil
+}
+
+// SetPrimaryPhoneTx makes phone id the client's primary number.
+func (r *ClientContactRepository) SetPrimaryPhoneTx(ctx context.Context, tx pgx.Tx, clientID int64, id int) (*model.ClientPhone, error) {
+	if _, err := tx.Exec(ctx, "UPDATE client_phones SET is_primary = FALSE WHERE client_id = $1 AND is_primary AND id <> $2", clientID, id); err != nil {
+		return nil, fmt.Errorf("clear primary phone: %w", err)
+	}
+	p, err := scanClientPhone(tx.QueryRow(ctx, `
+		UPDATE client_phones SET is_primary = TRUE
+		WHERE id = $1 AND client_id = $2
+		RETURNING `+clientPhoneColumns, id, clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("phone %d of client %d %w", id, clientID, ErrNotFound)
+		}
+		return nil, fmt.Errorf("set primary phone: %w", err)
+	}
+	return p, nil
+}
+
+// DeletePhoneTx removes phone id; when it was the primary number the oldest
+// remaining one takes its place.
+func (r *ClientContactRepository) DeletePhoneTx(ctx context.Context, tx pgx.Tx, clientID int64, id int) error {
+	var wasPrimary bool
+	if err := tx.QueryRow(ctx, `
+		DELETE FROM client_phones
+		WHERE id = $1 AND client_id = $2
+		RETURNING is_primary`, id, clientID).Scan(&wasPrimary); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("phone %d of client %d %w", id, clientID, ErrNotFound)
+		}
+		return fmt.Errorf("delete client phone: %w", err)
+	}
+	if !wasPrimary {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE client_phones SET is_primary = TRUE
+		WHERE id = (SELECT id FROM client_phones WHERE client_id = $1 ORDER BY id LIMIT 1)`, clientID); err != nil {
+		return fmt.Errorf("promote client phone: %w", err)
+	}
+	return nil
+}
+
+// ListEmails returns the client's email addresses other than the primary
+// one.
+func (r *ClientContactRepository) ListEmails(ctx context.Context, clientID int64) ([]*model.ClientEmail, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientEmailColumns+`
+		FROM client_emails
+		WHERE client_id = $1
+		ORDER BY id`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list client emails: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.ClientEmail{}
+	for rows.Next() {
+		e, err := scanClientEmail(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+// EmailInUseTx reports whether email is the primary or another address of
+// any client.
+func (r *ClientContactRepository) EmailInUseTx(ctx context.Context, tx pgx.Tx, email string) (bool, error) {
+	var used bool
+	if err := tx.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM clients WHERE LOWER(email) = LOWER($1))
+			OR EXISTS (SELECT 1 FROM client_emails WHERE LOWER(email) = LOWER($1))`, email,
+	).Scan(&used); err != nil {
+		return false, fmt.Errorf("check email in use: %w", err)
+	}
+	return used, nil
+}
+
+// FindEmailTx returns the other address row holding email, of any client,
+// or nil.
+func (r *ClientContactRepository) FindEmailTx(ctx context.Context, tx pgx.Tx, email string) (*model.ClientEmail, error) {
+	e, err := scanClientEmail(tx.QueryRow(ctx, "SELECT "+clientEmailColumns+" FROM client_emails WHERE LOWER(email) = LOWER($1)", email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find client email: %w", err)
+	}
+	return e, nil
+}
+
+func (r *ClientContactRepository) AddEmailTx(ctx context.Context, tx pgx.Tx, e *model.ClientEmail) (*model.ClientEmail, error) {
+	saved, err := scanClientEmail(tx.QueryRow(ctx, `
+		INSERT INTO client_emails (client_id, email)
+		VALUES ($1, $2)
+		RETURNING `+clientEmailColumns, e.ClientID, e.Email))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("%w: email %s is already in use", ErrDuplicate, e.Email)
+		}
+		return nil, fmt.Errorf("insert client email: %w", err)
+	}
+	return saved, nil
+}
+
+// SwapPrimaryEmailTx makes email id the client's primary address, not yet
+// verified; the previous primary address takes its place among the others.
+func (r *ClientContactRepository) SwapPrimaryEmailTx(ctx context.Context, tx pgx.Tx, clientID int64, id int) (string, error) {
+	var email string
+	if err := tx.QueryRow(ctx, `
+		UPDATE client_emails e
+		SET email = c.email
+		FROM clients c, (SELECT email FROM client_emails WHERE id = $1) old
+		WHERE e.id = $1 AND e.client_id = $2 AND c.id = e.client_id
+		RETURNING old.email`, id, clientID).Scan(&email); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("email %d of client %d %w", id, clientID, ErrNotFound)
+		}
+		return "", fmt.Errorf("swap primary email: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE clients SET email = $2, email_verified_at = NULL WHERE id = $1", clientID, email); err != nil {
+		return "", fmt.Errorf("set primary email: %w", err)
+	}
+	return email, nil
+}
+
+func (r *ClientContactRepository) DeleteEmail(ctx context.Context, clientID int64, id int) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM client_emails WHERE id = $1 AND client_id = $2", id, clientID)
+	if err != nil {
+		return fmt.Errorf("delete client email: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("email %d of client %d %w", id, clientID, ErrNotFound)
+	}
+	return nil
+}
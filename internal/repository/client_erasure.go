@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const clientErasureColumns = `id, client_id, reason, erased_by, erased, created_at`
+
+// erasedAuditKeys are the audit entry details that hold personal data.
+var erasedAuditKeys = []string{"email", "previous_email", "duplicate_email"}
+
+type ClientErasureRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewClientErasureRepository(pool *pgxpool.Pool) *ClientErasureRepository {
+	return &ClientErasureRepository{pool: pool}
+}
+
+func (r *ClientErasureRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanClientErasure(row pgx.Row) (*model.ClientErasure, error) {
+	var e model.ClientErasure
+	if err := row.Scan(&e.ID, &e.ClientID, &e.Reason, &e.ErasedBy, &e.Erased, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// EraseTx pseudonymizes the personal data of an individual client and of
+// the duplicates merged into it. Names, birth date and address are cleared
+// and the email replaced; addresses, phone numbers, other emails and email
+// tokens are deleted; the client's roles on other clients' accounts end
+// and its own accounts are frozen, with pending invitations to them,
+// standing orders debiting them or set up by the client, and payment
+// batches not yet run cancelled; emails are struck from audit entries
+// about it. Accounts, transactions, cases and screening hits stay as the
+// records the bank must keep. The client must be locked by the caller.
+func (r *ClientErasureRepository) EraseTx(ctx context.Context, tx pgx.Tx, clientID int64) (*model.ClientErased, error) {
+	var activeCases int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM cases
+		WHERE client_id = $1 AND status IN `+activeCaseStatuses, clientID,
+	).Scan(&activeCases); err != nil {
+		return nil, fmt.Errorf("count active cases: %w", err)
+	}
+	if activeCases > 0 {
+		return nil, fmt.Errorf("client %d has an %w: close it before erasing the client", clientID, ErrOpenCase)
+	}
+	var funded string
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(string_agg(account_number, ', ' ORDER BY id), '')
+		FROM accounts
+		WHERE client_id = $1 AND balance <> 0`, clientID,
+	).Scan(&funded); err != nil {
+		return nil, fmt.Errorf("check account balances: %w", err)
+	}
+	if funded != "" {
+		return nil, fmt.Errorf("client %d %w on %s: settle them before erasing the client", clientID, ErrFundsRemaining, funded)
+	}
+
+	erased := &model.ClientErased{AccountsFrozen: []int{}}
+	rows, err := tx.Query(ctx, "SELECT id FROM clients WHERE id = $1 OR merged_into = $1 ORDER BY id", clientID)
+	if err != nil {
+		return nil, fmt.Errorf("list erased clients: %w", err)
+	}
+	erased.Clients, err = pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("list erased clients: %w", err)
+	}
+	ids := make([]string, 0, len(erased.Clients))
+	for _, id := range erased.Clients {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+
+	for _, d := range []struct {
+		table string
+		count *int
+	}{
+		{"client_addresses", &erased.Addresses},
+		{"client_phones", &erased.Phones},
+		{"client_emails", &erased.Emails},
+		{"email_verifications", &erased.EmailVerifications},
+	} {
+		tag, err := tx.Exec(ctx, "DELETE FROM "+d.table+" WHERE client_id = ANY($1)", erased.Clients)
+		if err != nil {
+			return nil, fmt.Errorf("erase %s: %w", d.table, err)
+		}
+		*d.count = int(tag.RowsAffected())
+	}
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE account_holders SET status = 'removed', updated_at = NOW()
+		WHERE client_id = ANY($1) AND role <> 'owner' AND status IN ('pending', 'active')`, erased.Clients)
+	if err != nil {
+		return nil, fmt.Errorf("end account holders: %w", err)
+	}
+	erased.AccountHolders = int(tag.RowsAffected())
+	rows, err = tx.Query(ctx, "UPDATE accounts SET status = 'frozen' WHERE client_id = $1 AND status <> 'frozen' RETURNING id", clientID)
+	if err != nil {
+		return nil, fmt.Errorf("freeze accounts: %w", err)
+	}
+	erased.AccountsFrozen, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("freeze accounts: %w", err)
+	}
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE account_holders SET status = 'removed', updated_at = NOW()
+		WHERE status = 'pending' AND account_id IN (SELECT id FROM accounts WHERE client_id = $1)`, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("cancel holder invitations: %w", err)
+	}
+	erased.HolderInvitations = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, `
+		UPDATE standing_orders SET status = 'cancelled', updated_at = NOW()
+		WHERE status IN ('active', 'paused')
+			AND (from_account_id IN (SELECT id FROM accounts WHERE client_id = $1) OR initiated_by = ANY($2))`,
+		clientID, erased.Clients)
+	if err != nil {
+		return nil, fmt.Errorf("cancel standing orders: %w", err)
+	}
+	erased.StandingOrders = int(tag.RowsAffected())
+	rows, err = tx.Query(ctx, `
+		UPDATE payment_batches b
+		SET status = 'failed', error = 'cancelled: the client was erased', claim_token = NULL, lease_until = NULL,
+			completed_at = NOW(), updated_at = NOW()
+		WHERE (b.status = 'pending' OR (b.status = 'processing' AND b.lease_until < NOW()))
+			AND (b.initiated_by = ANY($2) OR EXISTS (
+				SELECT 1 FROM payment_batch_items i
+				WHERE i.batch_id = b.id AND i.status = 'pending'
+					AND i.from_account_id IN (SELECT id FROM accounts WHERE client_id = $1)))
+		RETURNING b.id`, clientID, erased.Clients)
+	if err != nil {
+		return nil, fmt.Errorf("cancel payment batches: %w", err)
+	}
+	batches, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("cancel payment batches: %w", err)
+	}
+	erased.PaymentBatches = len(batches)
+	if _, err := tx.Exec(ctx, `
+		UPDATE payment_batch_items SET status = 'skipped', error = 'batch cancelled', updated_at = NOW()
+		WHERE batch_id = ANY($1) AND status = 'pending'`, batches); err != nil {
+		return nil, fmt.Errorf("cancel payment batches: %w", err)
+	}
+
+	tag, err = tx.Exec(ctx, `
+		UPDATE audit_log SET details = details - $2::text[]
+		WHERE details ?| $2::text[]
+			AND ((entity_type = 'client' AND entity_id = ANY($1))
+				OR (action = 'client.merge' AND details->>'duplicate_id' = ANY($1)))`, ids, erasedAuditKeys)
+	if err != nil {
+		return nil, fmt.Errorf("erase audit entries: %w", err)
+	}
+	erased.AuditEntries = int(tag.RowsAffected())
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE clients
+		SET first_name = NULL, last_name = NULL, birth_date = NULL, residence_address = NULL,
+			email = 'erased-' || id || '@erased.invalid', email_verified_at = NULL, erased_at = NOW()
+		WHERE id = ANY($1)`, erased.Clients); err != nil {
+		return nil, fmt.Errorf("pseudonymize clients: %w", err)
+	}
+	return erased, nil
+}
+
+func (r *ClientErasureRepository) CreateTx(ctx context.Context, tx pgx.Tx, e *model.ClientErasure) (*model.ClientErasure, error) {
+	saved, err := scanClientErasure(tx.QueryRow(ctx, `
+		INSERT INTO client_erasures (client_id, reason, erased_by, erased)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+clientErasureColumns, e.ClientID, e.Reason, e.ErasedBy, e.Erased))
+	if err != nil {
+		return nil, fmt.Errorf("insert client erasure: %w", err)
+	}
+	return saved, nil
+}
+
+// GetByClient returns the erasure of the client, or nil when it was not
+// erased.
+func (r *ClientErasureRepository) GetByClient(ctx context.Context, clientID int64) (*model.ClientErasure, error) {
+	e, err := scanClientErasure(r.pool.QueryRow(ctx, "SELECT "+clientErasureColumns+" FROM client_erasures WHERE client_id = $1", clientID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get client erasure: %w", err)
+	}
+	return e, nil
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const clientMergeColumns = `id, survivor_id, duplicate_id, reason, merged_by, moved, created_at`
+
+type ClientMergeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewClientMergeRepository(pool *pgxpool.Pool) *ClientMergeRepository {
+	return &ClientMergeRepository{pool: pool}
+}
+
+func (r *ClientMergeRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanClientMerge(row pgx.Row) (*model.ClientMerge, error) {
+	var m model.ClientMerge
+	if err := row.Scan(&m.ID, &m.SurvivorID, &m.DuplicateID, &m.Reason, &m.MergedBy, &m.Moved, &m.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// MergeTx moves what the duplicate client owns to the survivor and marks the
+// duplicate as merged into it. Beneficiaries, client limits, screening hits,
+// phone numbers, business parties and account roles the survivor already has
+// an equivalent of are dropped; beneficiary nicknames the survivor already uses get the
+// beneficiary id appended and the survivor's primary phone stays primary.
+// Standing orders, payment batches and pending approvals the duplicate
+// initiated act for the survivor from then on. Addresses stay with the
+// duplicate as history. Both clients must be locked
+// by the caller.
+func (r *ClientMergeRepository) MergeTx(ctx context.Context, tx pgx.Tx, survivorID, duplicateID int64) (*model.ClientMergeMoved, error) {
+	var activeCases int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(DISTINCT client_id)
+		FROM cases
+		WHERE client_id IN ($1, $2) AND status IN `+activeCaseStatuses, survivorID, duplicateID,
+	).Scan(&activeCases); err != nil {
+		return nil, fmt.Errorf("count active cases: %w", err)
+	}
+	if activeCases > 1 {
+		return nil, fmt.Errorf("both clients have an %w: close one before merging", ErrOpenCase)
+	}
+
+	moved := &model.ClientMergeMoved{Accounts: []int{}}
+
+	rows, err := tx.Query(ctx, "UPDATE accounts SET client_id = $1 WHERE client_id = $2 RETURNING id", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move accounts: %w", err)
+	}
+	moved.Accounts, err = pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, fmt.Errorf("move accounts: %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, `
+		DELETE FROM beneficiaries d
+		USING beneficiaries s
+		WHERE d.client_id = $2 AND s.client_id = $1 AND s.account_id = d.account_id`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate beneficiaries: %w", err)
+	}
+	moved.BeneficiariesDropped = int(tag.RowsAffected())
+	if _, err := tx.Exec(ctx, `
+		UPDATE beneficiaries d
+		SET nickname = LEFT(d.nickname, 58) || ' #' || d.id, updated_at = NOW()
+		FROM beneficiaries s
+		WHERE d.client_id = $2 AND s.client_id = $1 AND s.nickname = d.nickname`, survivorID, duplicateID); err != nil {
+		return nil, fmt.Errorf("rename beneficiaries: %w", err)
+	}
+	tag, err = tx.Exec(ctx, "UPDATE beneficiaries SET client_id = $1, updated_at = NOW() WHERE client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move beneficiaries: %w", err)
+	}
+	moved.Beneficiaries = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx, `
+		DELETE FROM transfer_limits d
+		USING transfer_limits s
+		WHERE d.scope = 'client' AND s.scope = 'client' AND d.client_id = $2 AND s.client_id = $1
+			AND s.operation = d.operation AND s.currency = d.currency`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate transfer limits: %w", err)
+	}
+	moved.TransferLimitsDropped = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, "UPDATE transfer_limits SET client_id = $1, updated_at = NOW() WHERE scope = 'client' AND client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move transfer limits: %w", err)
+	}
+	moved.TransferLimits = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx, "UPDATE cases SET client_id = $1, updated_at = NOW() WHERE client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move cases: %w", err)
+	}
+	moved.Cases = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx, `
+		DELETE FROM screening_hits d
+		USING screening_hits s
+		WHERE d.client_id = $2 AND s.client_id = $1
+			AND s.entry_id = d.entry_id AND s.screened_name = d.screened_name`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate screening hits: %w", err)
+	}
+	moved.ScreeningHitsDropped = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, "UPDATE screening_hits SET client_id = $1 WHERE client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move screening hits: %w", err)
+	}
+	moved.ScreeningHits = int(tag.RowsAffected())
+
+	tag, err = tx.Exec(ctx, `
+		DELETE FROM client_phones d
+		USING client_phones s
+		WHERE d.client_id = $2 AND s.client_id = $1 AND s.number = d.number`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate phones: %w", err)
+	}
+	moved.PhonesDropped = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, `
+		UPDATE client_phones
+		SET client_id = $1,
+			is_primary = is_primary AND NOT EXISTS (SELECT 1 FROM client_phones WHERE client_id = $1)
+		WHERE client_id = $2`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move phones: %w", err)
+	}
+	moved.Phones = int(tag.RowsAffected())
+
+	// The duplicate keeps its primary email: clients.email is unique and the
+	// row stays.
+	tag, err = tx.Exec(ctx, "UPDATE client_emails SET client_id = $1 WHERE client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move emails: %w", err)
+	}
+	moved.Emails = int(tag.RowsAffected())
+
+	// Links the survivor already has with the same business, person and
+	// role are dropped; a client is only ever on one side of a link since
+	// businesses merge with businesses.
+	tag, err = tx.Exec(ctx, `
+		DELETE FROM business_parties d
+		USING business_parties s
+		WHERE s.role = d.role
+			AND ((d.business_id = $2 AND s.business_id = $1 AND s.person_id = d.person_id)
+				OR (d.person_id = $2 AND s.person_id = $1 AND s.business_id = d.business_id))`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate business parties: %w", err)
+	}
+	moved.BusinessPartiesDropped = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, `
+		UPDATE business_parties
+		SET business_id = CASE WHEN business_id = $2 THEN $1 ELSE business_id END,
+			person_id = CASE WHEN person_id = $2 THEN $1 ELSE person_id END
+		WHERE business_id = $2 OR person_id = $2`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move business parties: %w", err)
+	}
+	moved.BusinessParties = int(tag.RowsAffected())
+
+	// The duplicate's accounts now belong to the survivor, so any other role
+	// the survivor had on them gives way to the owner row moved below. On
+	// other accounts both held a role on, the survivor's is kept.
+	tag, err = tx.Exec(ctx, `
+		UPDATE account_holders h
+		SET status = 'removed', updated_at = NOW()
+		FROM account_holders o
+		WHERE o.account_id = h.account_id AND o.status IN ('pending', 'active') AND h.status IN ('pending', 'active')
+			AND ((h.client_id = $1 AND o.client_id = $2 AND o.role = 'owner')
+				OR (h.client_id = $2 AND o.client_id = $1 AND h.role <> 'owner'))`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("drop duplicate account holders: %w", err)
+	}
+	moved.AccountHoldersDropped = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, "UPDATE account_holders SET client_id = $1, updated_at = NOW() WHERE client_id = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move account holders: %w", err)
+	}
+	moved.AccountHolders = int(tag.RowsAffected())
+	if _, err := tx.Exec(ctx, "UPDATE account_holders SET added_by = $1 WHERE added_by = $2", survivorID, duplicateID); err != nil {
+		return nil, fmt.Errorf("move account holders added: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM account_holder_consents d
+		USING account_holder_consents s
+		WHERE d.client_id = $2 AND s.client_id = $1 AND s.holder_id = d.holder_id`, survivorID, duplicateID); err != nil {
+		return nil, fmt.Errorf("drop duplicate account holder consents: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "UPDATE account_holder_consents SET client_id = $1 WHERE client_id = $2", survivorID, duplicateID); err != nil {
+		return nil, fmt.Errorf("move account holder consents: %w", err)
+	}
+
+	// Standing orders, payment batches and pending approvals act for the
+	// holder that set them up; they now act for the survivor, which holds
+	// the duplicate's roles.
+	tag, err = tx.Exec(ctx, "UPDATE standing_orders SET initiated_by = $1, updated_at = NOW() WHERE initiated_by = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move standing orders: %w", err)
+	}
+	moved.StandingOrders = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, "UPDATE payment_batches SET initiated_by = $1, updated_at = NOW() WHERE initiated_by = $2", survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move payment batches: %w", err)
+	}
+	moved.PaymentBatches = int(tag.RowsAffected())
+	tag, err = tx.Exec(ctx, `
+		UPDATE approvals
+		SET payload = CASE operation
+			WHEN 'transfer' THEN jsonb_set(payload, '{initiated_by}', to_jsonb($1::bigint))
+			WHEN 'withdrawal', 'hold_capture' THEN jsonb_set(payload, '{client_id}', to_jsonb($1::bigint))
+			ELSE jsonb_set(payload, '{upsert,client_id}', to_jsonb($1::bigint)) END
+		WHERE status = 'pending'
+			AND ((operation = 'transfer' AND payload->>'initiated_by' = $2::bigint::text)
+				OR (operation IN ('withdrawal', 'hold_capture') AND payload->>'client_id' = $2::bigint::text)
+				OR (operation = 'transfer_limit' AND payload->'upsert'->>'scope' = 'client'
+					AND payload->'upsert'->>'client_id' = $2::bigint::text))`, survivorID, duplicateID)
+	if err != nil {
+		return nil, fmt.Errorf("move pending approvals: %w", err)
+	}
+	moved.Approvals = int(tag.RowsAffected())
+
+	// Clients merged into the duplicate earlier now point at the survivor,
+	// so merged_into always names a live client.
+	if _, err := tx.Exec(ctx, "UPDATE clients SET merged_into = $1 WHERE merged_into = $2 OR id = $2", survivorID, duplicateID); err != nil {
+		return nil, fmt.Errorf("mark client merged: %w", err)
+	}
+	return moved, nil
+}
+
+func (r *ClientMergeRepository) CreateTx(ctx context.Context, tx pgx.Tx, m *model.ClientMerge) (*model.ClientMerge, error) {
+	saved, err := scanClientMerge(tx.QueryRow(ctx, `
+		INSERT INTO client_merges (survivor_id, duplicate_id, reason, merged_by, moved)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+clientMergeColumns, m.SurvivorID, m.DuplicateID, m.Reason, m.MergedBy, m.Moved))
+	if err != nil {
+		return nil, fmt.Errorf("insert client merge: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *ClientMergeRepository) GetById(ctx context.Context, id int) (*model.ClientMerge, error) {
+	m, err := scanClientMerge(r.pool.QueryRow(ctx, "SELECT "+clientMergeColumns+" FROM client_merges WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("client merge %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get client merge: %w", err)
+	}
+	return m, nil
+}
+
+// List returns merges newest first; a non-zero clientID matches merges the
+// client took part in on either side.
+func (r *ClientMergeRepository) List(ctx context.Context, clientID, limit, offset int) ([]*model.ClientMerge, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+clientMergeColumns+`
+		FROM client_merges
+		WHERE $1 = 0 OR survivor_id = $1 OR duplicate_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list client merges: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.ClientMerge{}
+	for rows.Next() {
+		m, err := scanClientMerge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
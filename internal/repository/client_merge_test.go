@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"basic-gin/internal/testdb"
+	"context"
+	"testing"
+)
+
+func TestMergeTxReassignsInitiatedWork(t *testing.T) {
+	pool := testdb.Open(t)
+	ctx := context.Background()
+	// The seed data has clients 1 and 2 with accounts 1 and 2.
+	const survivor, duplicate = 1, 2
+
+	var orderID, batchID, transferID, withdrawalID, decidedID int
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO standing_orders (from_account_id, to_account_id, amount, currency, schedule, start_at, next_run_at, initiated_by)
+		VALUES (2, 1, 10, 'RSD', 'FREQ=MONTHLY', NOW(), NOW(), $1)
+		RETURNING id`, duplicate).Scan(&orderID); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO payment_batches (format, mode, initiated_by) VALUES ('csv', 'best_effort', $1)
+		RETURNING id`, duplicate).Scan(&batchID); err != nil {
+		t.Fatal(err)
+	}
+	approval := `
+		INSERT INTO approvals (operation, entity_type, entity_id, payload, maker, expires_at, status, decided_at)
+		VALUES ($1, 'account', '2', $2, 'maker', NOW() + INTERVAL '1 day', $3, CASE WHEN $3 <> 'pending' THEN NOW() END)
+		RETURNING id`
+	for _, a := range []struct {
+		id        *int
+		operation string
+		payload   string
+		status    string
+	}{
+		{&transferID, "transfer", `{"from_account_id": 2, "to_account_id": 1, "amount": 5, "initiated_by": 2}`, "pending"},
+		{&withdrawalID, "withdrawal", `{"account_id": 2, "amount": 5, "client_id": 2}`, "pending"},
+		{&decidedID, "withdrawal", `{"account_id": 2, "amount": 5, "client_id": 2}`, "rejected"},
+	} {
+		if err := pool.QueryRow(ctx, approval, a.operation, a.payload, a.status).Scan(a.id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	moved, err := NewClientMergeRepository(pool).MergeTx(ctx, tx, survivor, duplicate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.StandingOrders != 1 || moved.PaymentBatches != 1 || moved.Approvals != 2 {
+		t.Errorf("moved %d standing orders, %d batches, %d approvals; want 1, 1, 2",
+			moved.StandingOrders, moved.PaymentBatches, moved.Approvals)
+	}
+
+	checks := []struct {
+		name  string
+		query string
+		id    int
+		want  int
+	}{
+		{"standing order", "SELECT initiated_by FROM standing_orders WHERE id = $1", orderID, survivor},
+		{"payment batch", "SELECT initiated_by FROM payment_batches WHERE id = $1", batchID, survivor},
+		{"pending transfer", "SELECT (payload->>'initiated_by')::int FROM approvals WHERE id = $1", transferID, survivor},
+		{"pending withdrawal", "SELECT (payload->>'client_id')::int FROM approvals WHERE id = $1", withdrawalID, survivor},
+		{"decided withdrawal", "SELECT (payload->>'client_id')::int FROM approvals WHERE id = $1", decidedID, duplicate},
+	}
+	for _, c := range checks {
+		var got int
+		if err := tx.QueryRow(ctx, c.query, c.id).Scan(&got); err != nil {
+			t.Fatalf("%s: %v", c.name, err)
+		}
+		if got != c.want {
+			t.Errorf("%s initiated by %d, want %d", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const emailVerificationColumns = `id, client_id, purpose, email, token_hash, status, expires_at, confirmed_at, created_at`
+
+type EmailVerificationRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewEmailVerificationRepository(pool *pgxpool.Pool) *EmailVerificationRepository {
+	return &EmailVerificationRepository{pool: pool}
+}
+
+func (r *EmailVerificationRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanEmailVerification(row pgx.Row) (*model.EmailVerification, error) {
+	var v model.EmailVerification
+	if err := row.Scan(
+		&v.ID, &v.ClientID, &v.Purpose, &v.Email, &v.TokenHash, &v.Status,
+		&v.ExpiresAt, &v.ConfirmedAt, &v.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// CreateTx supersedes the client's pending verification for the same
+// purpose and saves v in its place.
+func (r *EmailVerificationRepository) CreateTx(ctx context.Context, tx pgx.Tx, v *model.EmailVerification) (*model.EmailVerification, error) {
+	if _, err := tx.Exec(ctx, `
+		UPDATE email_verifications SET status = 'superseded'
+		WHERE client_id = $1 AND purpose = $2 AND status = 'pending'`, v.ClientID, v.Purpose); err != nil {
+		return nil, fmt.Errorf("supersede email verification: %w", err)
+	}
+	saved, err := scanEmailVerification(tx.QueryRow(ctx, `
+		INSERT INTO email_verifications (client_id, purpose, email, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING `+emailVerificationColumns, v.ClientID, v.Purpose, v.Email, v.TokenHash, v.ExpiresAt))
+	if err != nil {
+		return nil, fmt.Errorf("insert email verification: %w", err)
+	}
+	return saved, nil
+}
+
+// GetPendingByTokenTx locks the client's pending verification with the
+// given token hash, expired or not.
+func (r *EmailVerificationRepository) GetPendingByTokenTx(ctx context.Context, tx pgx.Tx, clientID int64, tokenHash string) (*model.EmailVerification, error) {
+	v, err := scanEmailVerification(tx.QueryRow(ctx, `
+		SELECT `+emailVerificationColumns+`
+		FROM email_verifications
+		WHERE client_id = $1 AND token_hash = $2 AND status = 'pending'
+		FOR UPDATE`, clientID, tokenHash))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("invalid or %w verification token", ErrAlreadyUsed)
+		}
+		return nil, fmt.Errorf("get email verification: %w", err)
+	}
+	return v, nil
+}
+
+func (r *EmailVerificationRepository) ConfirmTx(ctx context.Context, tx pgx.Tx, id int) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE email_verifications SET status = 'confirmed', confirmed_at = NOW()
+		WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("confirm email verification: %w", err)
+	}
+	return nil
+}
+
+// SupersedePendingTx supersedes the client's pending verifications, as when
+// its email changed another way.
+func (r *EmailVerificationRepository) SupersedePendingTx(ctx context.Context, tx pgx.Tx, clientID int64) error {
+	if _, err := tx.Exec(ctx, `
+		UPDATE email_verifications SET status = 'superseded'
+		WHERE client_id = $1 AND status = 'pending'`, clientID); err != nil {
+		return fmt.Errorf("supersede email verifications: %w", err)
+	}
+	return nil
+}
+
+// Pending returns the client's unexpired pending verification for purpose,
+// or nil.
+func (r *EmailVerificationRepository) Pending(ctx context.Context, clientID int64, purpose string) (*model.EmailVerification, error) {
+	v, err := scanEmailVerification(r.pool.QueryRow(ctx, `
+		SELECT `+emailVerificationColumns+`
+		FROM email_verifications
+		WHERE client_id = $1 AND purpose = $2 AND status = 'pending' AND expires_at > NOW()`, clientID, purpose))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get pending email verification: %w", err)
+	}
+	return v, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import "errors"
+
+// ErrNotFound wraps pgx.ErrNoRows, and updates that match no row, for the
+// record a caller addressed by id, number or code.
+var ErrNotFound = errors.New("not found")
+
+// Errors for writes the stored data refuses. They are wrapped with %w so
+// callers can tell them apart from database failures with errors.Is; the
+// service package re-exports them.
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrDuplicate         = errors.New("duplicate")
+	ErrClientMerged      = errors.New("was merged")
+	ErrOpenCase          = errors.New("open case")
+	ErrFundsRemaining    = errors.New("still has funds")
+	ErrAlreadyUsed       = errors.New("already used")
+)
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const feeScheduleColumns = `id, account_type, operation, currency, kind, flat_amount, percent, min_fee, max_fee,
+	tiers, free_per_month, created_at, updated_at`
+
+type FeeRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFeeRepository(pool *pgxpool.Pool) *FeeRepository {
+	return &FeeRepository{pool: pool}
+}
+
+func (r *FeeRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanFeeSchedule(row pgx.Row) (*model.FeeSchedule, error) {
+	var f model.FeeSchedule
+	if err := row.Scan(
+		&f.ID, &f.AccountType, &f.Operation, &f.Currency, &f.Kind, &f.FlatAmount, &f.Percent, &f.MinFee, &f.MaxFee,
+		&f.Tiers, &f.FreePerMonth, &f.CreatedAt, &f.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+// FindTx returns the schedule for the combination, or nil when the operation
+// is free for that account type and currency.
+func (r *FeeRepository) FindTx(ctx context.Context, tx pgx.Tx, accountType, operation, currency string) (*model.FeeSchedule, error) {
+	f, err := scanFeeSchedule(tx.QueryRow(ctx, `
+		SELECT `+feeScheduleColumns+`
+		FROM fee_schedules
+		WHERE account_type = $1 AND operation = $2 AND currency = $3`,
+		accountType, operation, currency))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get fee schedule: %w", err)
+	}
+	return f, nil
+}
+
+func (r *FeeRepository) List(ctx context.Context) ([]*model.FeeSchedule, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+feeScheduleColumns+" FROM fee_schedules ORDER BY account_type, operation, currency")
+	if err != nil {
+		return nil, fmt.Errorf("list fee schedules: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.FeeSchedule
+	for rows.Next() {
+		f, err := scanFeeSchedule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, f)
+	}
+	return out, rows.Err()
+}
+
+// UpsertTx creates or replaces the schedule for its account type, operation
+// and currency.
+func (r *FeeRepository) UpsertTx(ctx context.Context, tx pgx.Tx, f *model.FeeSchedule) (*model.FeeSchedule, error) {
+	tiers := f.Tiers
+	if tiers == nil {
+		tiers = []model.FeeTier{}
+	}
+	saved, err := scanFeeSchedule(tx.QueryRow(ctx, `
+		INSERT INTO fee_schedules (account_type, operation, currency, kind, flat_amount, percent, min_fee, max_fee, tiers, free_per_month)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (account_type, operation, currency) DO UPDATE
+		SET kind = EXCLUDED.kind, flat_amount = EXCLUDED.flat_amount, percent = EXCLUDED.percent,
+			min_fee = EXCLUDED.min_fee, max_fee = EXCLUDED.max_fee, tiers = EXCLUDED.tiers,
+			free_per_month = EXCLUDED.free_per_month, updated_at = NOW()
+		RETURNING `+feeScheduleColumns,
+		f.AccountType, f.Operation, f.Currency, f.Kind, f.FlatAmount, f.Percent, f.MinFee, f.MaxFee, tiers, f.FreePerMonth,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("upsert fee schedule: %w", err)
+	}
+	return saved, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const fxQuoteColumns = `id::text, from_currency, to_currency, mid_rate, rate, spread_bps,
+	sell_amount, buy_amount, expires_at, used_at, transaction_id, created_at`
+
+type FXQuoteRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewFXQuoteRepository(pool *pgxpool.Pool) *FXQuoteRepository {
+	return &FXQuoteRepository{pool: pool}
+}
+
+func scanFXQuote(row pgx.Row) (*model.FXQuote, error) {
+	var q model.FXQuote
+	if err := row.Scan(
+		&q.ID, &q.FromCurrency, &q.ToCurrency, &q.MidRate, &q.Rate, &q.SpreadBps,
+		&q.SellAmount, &q.BuyAmount, &q.ExpiresAt, &q.UsedAt, &q.TransactionID, &q.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *FXQuoteRepository) Create(ctx context.Context, q *model.FXQuote) (*model.FXQuote, error) {
+	saved, err := scanFXQuote(r.pool.QueryRow(ctx, `
+		INSERT INTO fx_quotes (id, from_currency, to_currency, mid_rate, rate, spread_bps, sell_amount, buy_amount, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING `+fxQuoteColumns,
+		q.ID, q.FromCurrency, q.ToCurrency, q.MidRate, q.Rate, q.SpreadBps, q.SellAmount, q.BuyAmount, q.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert fx quote: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *FXQuoteRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id string, forUpdate bool) (*model.FXQuote, error) {
+	q := "SELECT " + fxQuoteColumns + " FROM fx_quotes WHERE id = $1::uuid"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	quote, err := scanFXQuote(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("fx quote %s %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get fx quote: %w", err)
+	}
+	return quote, nil
+}
+
+func (r *FXQuoteRepository) MarkUsedTx(ctx context.Context, tx pgx.Tx, id string, transactionID string) error {
+	tag, err := tx.Exec(ctx, `
+		UPDATE fx_quotes SET used_at = NOW(), transaction_id = $2::int
+		WHERE id = $1::uuid AND used_at IS NULL`, id, transactionID)
+	if err != nil {
+		return fmt.Errorf("mark fx quote used: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("fx quote %s %w", id, ErrAlreadyUsed)
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const holdColumns = `id, account_id, amount, captured_amount, currency, status,
+	COALESCE(reference, ''), expires_at, transaction_id, created_at, updated_at`
+
+type HoldRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewHoldRepository(pool *pgxpool.Pool) *HoldRepository {
+	return &HoldRepository{pool: pool}
+}
+
+func (r *HoldRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanHold(row pgx.Row) (*model.Hold, error) {
+	var h model.Hold
+	if err := row.Scan(
+		&h.ID, &h.AccountID, &h.Amount, &h.CapturedAmount, &h.Currency, &h.Status,
+		&h.Reference, &h.ExpiresAt, &h.TransactionID, &h.CreatedAt, &h.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *HoldRepository) CreateTx(ctx context.Context, tx pgx.Tx, h *model.Hold) (*model.Hold, error) {
+	saved, err := scanHold(tx.QueryRow(ctx, `
+		INSERT INTO holds (account_id, amount, currency, reference, expires_at)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5)
+		RETURNING `+holdColumns,
+		h.AccountID, h.Amount, h.Currency, h.Reference, h.ExpiresAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert hold: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *HoldRepository) GetById(ctx context.Context, id int) (*model.Hold, error) {
+	h, err := scanHold(r.pool.QueryRow(ctx, "SELECT "+holdColumns+" FROM holds WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("hold %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get hold: %w", err)
+	}
+	return h, nil
+}
+
+func (r *HoldRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int, forUpdate bool) (*model.Hold, error) {
+	q := "SELECT " + holdColumns + " FROM holds WHERE id = $1"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	h, err := scanHold(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("hold %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get hold: %w", err)
+	}
+	return h, nil
+}
+
+func (r *HoldRepository) ListByAccountID(ctx context.Context, accountID int, status string) ([]*model.Hold, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+holdColumns+`
+		FROM holds
+		WHERE account_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC
+	`, accountID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list holds: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.Hold
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// SettleTx moves an active hold to a final status, recording the captured
+// amount and the resulting transaction (if any).
+func (r *HoldRepository) SettleTx(ctx context.Context, tx pgx.Tx, id int, status string, captured float64, transactionID *int) (*model.Hold, error) {
+	h, err := scanHold(tx.QueryRow(ctx, `
+		UPDATE holds
+		SET status = $2, captured_amount = $3, transaction_id = $4, updated_at = NOW()
+		WHERE id = $1 AND status = 'active'
+		RETURNING `+holdColumns,
+		id, status, captured, transactionID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("hold %d is not active", id)
+		}
+		return nil, fmt.Errorf("settle hold: %w", err)
+	}
+	return h, nil
+}
+
+// LinkTransactionTx records the transaction a captured hold was posted as.
+func (r *HoldRepository) LinkTransactionTx(ctx context.Context, tx pgx.Tx, id, transactionID int) (*model.Hold, error) {
+	h, err := scanHold(tx.QueryRow(ctx, `
+		UPDATE holds
+		SET transaction_id = $2, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+holdColumns,
+		id, transactionID,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("hold %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("link hold transaction: %w", err)
+	}
+	return h, nil
+}
+
+// ExpireDue marks every active hold past its expiry as expired and returns
+// the affected holds.
+func (r *HoldRepository) ExpireDue(ctx context.Context) ([]*model.Hold, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE holds
+		SET status = 'expired', updated_at = NOW()
+		WHERE status = 'active' AND expires_at <= NOW()
+		RETURNING `+holdColumns)
+	if err != nil {
+		return nil, fmt.Errorf("expire holds: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.Hold
+	for rows.Next() {
+		h, err := scanHold(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
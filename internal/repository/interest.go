@@ -0,0 +1,290 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const accountProductColumns = `code, name, annual_rate, created_at, updated_at`
+
+const interestAccrualColumns = `id, account_id, accrual_date, balance::text, annual_rate::text, amount::text,
+	COALESCE(capitalization_id, 0), created_at`
+
+const interestCapitalizationColumns = `id, account_id, period, accrued::text, carried_in::text, posted::text,
+	carry_out::text, COALESCE(transaction_id, 0), created_at`
+
+type InterestRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewInterestRepository(pool *pgxpool.Pool) *InterestRepository {
+	return &InterestRepository{pool: pool}
+}
+
+func (r *InterestRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanAccountProduct(row pgx.Row) (*model.AccountProduct, error) {
+	var p model.AccountProduct
+	if err := row.Scan(&p.Code, &p.Name, &p.AnnualRate, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func scanInterestAccrual(row pgx.Row) (*model.InterestAccrual, error) {
+	var a model.InterestAccrual
+	if err := row.Scan(&a.ID, &a.AccountID, &a.AccrualDate, &a.Balance, &a.AnnualRate, &a.Amount, &a.CapitalizationID, &a.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func scanInterestCapitalization(row pgx.Row) (*model.InterestCapitalization, error) {
+	var c model.InterestCapitalization
+	if err := row.Scan(&c.ID, &c.AccountID, &c.Period, &c.Accrued, &c.CarriedIn, &c.Posted, &c.CarryOut, &c.TransactionID, &c.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *InterestRepository) ListProducts(ctx context.Context) ([]*model.AccountProduct, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+accountProductColumns+" FROM account_products ORDER BY code")
+	if err != nil {
+		return nil, fmt.Errorf("list account products: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.AccountProduct
+	for rows.Next() {
+		p, err := scanAccountProduct(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+func (r *InterestRepository) GetProductTx(ctx context.Context, tx pgx.Tx, code string) (*model.AccountProduct, error) {
+	p, err := scanAccountProduct(tx.QueryRow(ctx, "SELECT "+accountProductColumns+" FROM account_products WHERE code = $1", code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("account product %q %w", code, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get account product: %w", err)
+	}
+	return p, nil
+}
+
+func (r *InterestRepository) UpsertProductTx(ctx context.Context, tx pgx.Tx, p *model.AccountProduct) (*model.AccountProduct, error) {
+	saved, err := scanAccountProduct(tx.QueryRow(ctx, `
+		INSERT INTO account_products (code, name, annual_rate)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (code) DO UPDATE
+		SET name = EXCLUDED.name, annual_rate = EXCLUDED.annual_rate, updated_at = NOW()
+		RETURNING `+accountProductColumns,
+		p.Code, p.Name, p.AnnualRate))
+	if err != nil {
+		return nil, fmt.Errorf("upsert account product: %w", err)
+	}
+	return saved, nil
+}
+
+// AnnualRateTx returns the product's rate as an exact decimal string.
+func (r *InterestRepository) AnnualRateTx(ctx context.Context, tx pgx.Tx, code string) (string, error) {
+	var rate string
+	if err := tx.QueryRow(ctx, "SELECT annual_rate::text FROM account_products WHERE code = $1", code).Scan(&rate); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("account product %q %w", code, ErrNotFound)
+		}
+		return "", fmt.Errorf("get annual rate: %w", err)
+	}
+	return rate, nil
+}
+
+// ListAccruingAccountIDs returns accounts on an interest-bearing product, and
+// any account still holding uncapitalized accruals.
+func (r *InterestRepository) ListAccruingAccountIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT a.id
+		FROM accounts a
+		JOIN account_products p ON p.code = a.account_type
+		WHERE p.annual_rate > 0
+			OR EXISTS (SELECT 1 FROM interest_accruals i WHERE i.account_id = a.id AND i.capitalization_id IS NULL)
+		ORDER BY a.id`)
+	if err != nil {
+		return nil, fmt.Errorf("list accruing accounts: %w", err)
+	}
+	defer rows.Close()
+
+	var out []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, id)
+	}
+	return out, rows.Err()
+}
+
+// LastAccrualDateTx returns the latest day accrued for the account, or nil.
+func (r *InterestRepository) LastAccrualDateTx(ctx context.Context, tx pgx.Tx, accountID int) (*time.Time, error) {
+	var d *time.Time
+	if err := tx.QueryRow(ctx, "SELECT MAX(accrual_date) FROM interest_accruals WHERE account_id = $1", accountID).Scan(&d); err != nil {
+		return nil, fmt.Errorf("last accrual date: %w", err)
+	}
+	return d, nil
+}
+
+// EndOfDayBalancesTx reconstructs the account's closing balance for every day
+// from..to inclusive by unwinding the transactions posted after each day from
+// the current balance. Days after today get the current balance.
+func (r *InterestRepository) EndOfDayBalancesTx(ctx context.Context, tx pgx.Tx, accountID int, from, to time.Time) ([]model.DailyBalance, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT d.day::date,
+			(a.balance
+				- COALESCE((SELECT SUM(COALESCE(t.to_amount, t.amount)) FROM transactions t
+					WHERE t.to_account_id = a.id AND t.created_at >= (d.day + INTERVAL '1 day') AT TIME ZONE 'UTC'), 0)
+				+ COALESCE((SELECT SUM(t.amount) FROM transactions t
+					WHERE t.from_account_id = a.id AND t.created_at >= (d.day + INTERVAL '1 day') AT TIME ZONE 'UTC'), 0)
+			)::text
+		FROM accounts a
+		CROSS JOIN generate_series($2::date::timestamp, $3::date::timestamp, INTERVAL '1 day') AS d(day)
+		WHERE a.id = $1
+		ORDER BY d.day`, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("end of day balances: %w", err)
+	}
+	defer rows.Close()
+
+	var out []model.DailyBalance
+	for rows.Next() {
+		var b model.DailyBalance
+		if err := rows.Scan(&b.Date, &b.Balance); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, b)
+	}
+	return out, rows.Err()
+}
+
+func (r *InterestRepository) CreateAccrualTx(ctx context.Context, tx pgx.Tx, a *model.InterestAccrual) error {
+	return tx.QueryRow(ctx, `
+		INSERT INTO interest_accruals (account_id, accrual_date, balance, annual_rate, amount)
+		VALUES ($1, $2, $3::numeric, $4::numeric, $5::numeric)
+		RETURNING id, created_at`,
+		a.AccountID, a.AccrualDate, a.Balance, a.AnnualRate, a.Amount).Scan(&a.ID, &a.CreatedAt)
+}
+
+// OpenPeriodTotalsTx sums uncapitalized accruals per calendar month for the
+// months that start before the given day, oldest first.
+func (r *InterestRepository) OpenPeriodTotalsTx(ctx context.Context, tx pgx.Tx, accountID int, before time.Time) ([]model.InterestPeriodTotal, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT date_trunc('month', accrual_date)::date, SUM(amount)::text
+		FROM interest_accruals
+		WHERE account_id = $1 AND capitalization_id IS NULL AND accrual_date < $2::date
+		GROUP BY 1
+		ORDER BY 1`, accountID, before)
+	if err != nil {
+		return nil, fmt.Errorf("open interest periods: %w", err)
+	}
+	defer rows.Close()
+
+	var out []model.InterestPeriodTotal
+	for rows.Next() {
+		var p model.InterestPeriodTotal
+		if err := rows.Scan(&p.Period, &p.Total); err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, p)
+	}
+	return out, rows.Err()
+}
+
+// LastCarryTx returns the remainder carried out of the account's latest
+// capitalization, "0" if it has none.
+func (r *InterestRepository) LastCarryTx(ctx context.Context, tx pgx.Tx, accountID int) (string, error) {
+	var carry string
+	err := tx.QueryRow(ctx, `
+		SELECT carry_out::text FROM interest_capitalizations
+		WHERE account_id = $1
+		ORDER BY period DESC
+		LIMIT 1`, accountID).Scan(&carry)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "0", nil
+		}
+		return "", fmt.Errorf("last interest carry: %w", err)
+	}
+	return carry, nil
+}
+
+// CreateCapitalizationTx records c and links the month's open accruals to it.
+func (r *InterestRepository) CreateCapitalizationTx(ctx context.Context, tx pgx.Tx, c *model.InterestCapitalization) error {
+	if err := tx.QueryRow(ctx, `
+		INSERT INTO interest_capitalizations (account_id, period, accrued, carried_in, posted, carry_out, transaction_id)
+		VALUES ($1, $2, $3::numeric, $4::numeric, $5::numeric, $6::numeric, NULLIF($7::int, 0))
+		RETURNING id, created_at`,
+		c.AccountID, c.Period, c.Accrued, c.CarriedIn, c.Posted, c.CarryOut, c.TransactionID).Scan(&c.ID, &c.CreatedAt); err != nil {
+		return fmt.Errorf("create interest capitalization: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE interest_accruals
+		SET capitalization_id = $1
+		WHERE account_id = $2 AND capitalization_id IS NULL AND date_trunc('month', accrual_date)::date = $3::date`,
+		c.ID, c.AccountID, c.Period); err != nil {
+		return fmt.Errorf("link interest accruals: %w", err)
+	}
+	return nil
+}
+
+func (r *InterestRepository) ListAccruals(ctx context.Context, accountID int, from, to time.Time) ([]*model.InterestAccrual, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+interestAccrualColumns+`
+		FROM interest_accruals
+		WHERE account_id = $1 AND accrual_date >= $2::date AND accrual_date <= $3::date
+		ORDER BY accrual_date`, accountID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("list interest accruals: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.InterestAccrual
+	for rows.Next() {
+		a, err := scanInterestAccrual(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *InterestRepository) ListCapitalizations(ctx context.Context, accountID int) ([]*model.InterestCapitalization, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+interestCapitalizationColumns+`
+		FROM interest_capitalizations
+		WHERE account_id = $1
+		ORDER BY period`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list interest capitalizations: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.InterestCapitalization
+	for rows.Next() {
+		c, err := scanInterestCapitalization(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
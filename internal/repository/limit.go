@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const transferLimitColumns = `id, scope, COALESCE(account_type, ''), COALESCE(client_id, 0), COALESCE(account_id, 0),
+	operation, currency, per_transaction, daily, monthly, created_at, updated_at`
+
+type LimitRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewLimitRepository(pool *pgxpool.Pool) *LimitRepository {
+	return &LimitRepository{pool: pool}
+}
+
+func (r *LimitRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanTransferLimit(row pgx.Row) (*model.TransferLimit, error) {
+	var l model.TransferLimit
+	if err := row.Scan(
+		&l.ID, &l.Scope, &l.AccountType, &l.ClientID, &l.AccountID,
+		&l.Operation, &l.Currency, &l.PerTransaction, &l.Daily, &l.Monthly, &l.CreatedAt, &l.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+func collectTransferLimits(rows pgx.Rows, err error) ([]*model.TransferLimit, error) {
+	if err != nil {
+		return nil, fmt.Errorf("list transfer limits: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.TransferLimit{}
+	for rows.Next() {
+		l, err := scanTransferLimit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, l)
+	}
+	return out, rows.Err()
+}
+
+// ForAccountTx returns the product, client and account rows that apply to
+// acc for operation.
+func (r *LimitRepository) ForAccountTx(ctx context.Context, tx pgx.Tx, acc *model.Account, operation string) ([]*model.TransferLimit, error) {
+	return collectTransferLimits(tx.Query(ctx, `
+		SELECT `+transferLimitColumns+`
+		FROM transfer_limits
+		WHERE operation = $1 AND currency = $2 AND (
+			(scope = 'product' AND account_type = $3) OR
+			(scope = 'client' AND client_id = $4) OR
+			(scope = 'account' AND account_id = $5))`,
+		operation, acc.Currency, acc.AccountType, acc.ClientId, acc.ID))
+}
+
+func (r *LimitRepository) List(ctx context.Context, scope string) ([]*model.TransferLimit, error) {
+	return collectTransferLimits(r.pool.Query(ctx, `
+		SELECT `+transferLimitColumns+`
+		FROM transfer_limits
+		WHERE $1 = '' OR scope = $1
+		ORDER BY scope, account_type, client_id, account_id, operation, currency`, scope))
+}
+
+// UpsertTx creates or replaces the limit row for its scope, subject,
+// operation and currency.
+func (r *LimitRepository) UpsertTx(ctx context.Context, tx pgx.Tx, l *model.TransferLimit) (*model.TransferLimit, error) {
+	var conflict string
+	switch l.Scope {
+	case model.LimitScopeProduct:
+		conflict = "(account_type, operation, currency) WHERE scope = 'product'"
+	case model.LimitScopeClient:
+		conflict = "(client_id, operation, currency) WHERE scope = 'client'"
+	case model.LimitScopeAccount:
+		conflict = "(account_id, operation) WHERE scope = 'account'"
+	default:
+		return nil, fmt.Errorf("unknown limit scope %q", l.Scope)
+	}
+	saved, err := scanTransferLimit(tx.QueryRow(ctx, `
+		INSERT INTO transfer_limits (scope, account_type, client_id, account_id, operation, currency, per_transaction, daily, monthly)
+		VALUES ($1, NULLIF($2::text, ''), NULLIF($3::int, 0), NULLIF($4::int, 0), $5, $6, $7, $8, $9)
+		ON CONFLICT `+conflict+` DO UPDATE
+		SET currency = EXCLUDED.currency, per_transaction = EXCLUDED.per_transaction,
+			daily = EXCLUDED.daily, monthly = EXCLUDED.monthly, updated_at = NOW()
+		RETURNING `+transferLimitColumns,
+		l.Scope, l.AccountType, l.ClientID, l.AccountID, l.Operation, l.Currency, l.PerTransaction, l.Daily, l.Monthly))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, fmt.Errorf("limit subject %w: %s", ErrNotFound, pgErr.Detail)
+		}
+		return nil, fmt.Errorf("upsert transfer limit: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *LimitRepository) DeleteTx(ctx context.Context, tx pgx.Tx, id int) (*model.TransferLimit, error) {
+	l, err := scanTransferLimit(tx.QueryRow(ctx, "DELETE FROM transfer_limits WHERE id = $1 RETURNING "+transferLimitColumns, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("transfer limit %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("delete transfer limit: %w", err)
+	}
+	return l, nil
+}
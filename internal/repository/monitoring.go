@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const alertColumns = `id, operation, account_id, COALESCE(counterparty_account_id, 0), amount, currency,
+	score, action, hits, COALESCE(transaction_id::text, ''), COALESCE(approval_id, 0), COALESCE(case_id, 0), status,
+	COALESCE(closed_by, ''), COALESCE(note, ''), closed_at, created_at`
+
+type MonitoringRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMonitoringRepository(pool *pgxpool.Pool) *MonitoringRepository {
+	return &MonitoringRepository{pool: pool}
+}
+
+func (r *MonitoringRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanAlert(row pgx.Row) (*model.MonitoringAlert, error) {
+	var a model.MonitoringAlert
+	if err := row.Scan(
+		&a.ID, &a.Operation, &a.AccountID, &a.CounterpartyAccountID, &a.Amount, &a.Currency,
+		&a.Score, &a.Action, &a.Hits, &a.TransactionID, &a.ApprovalID, &a.CaseID, &a.Status,
+		&a.ClosedBy, &a.Note, &a.ClosedAt, &a.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *MonitoringRepository) CreateAlertTx(ctx context.Context, tx pgx.Tx, a *model.MonitoringAlert) (*model.MonitoringAlert, error) {
+	saved, err := scanAlert(tx.QueryRow(ctx, `
+		INSERT INTO monitoring_alerts (operation, account_id, counterparty_account_id, amount, currency,
+			score, action, hits, transaction_id, approval_id, case_id)
+		VALUES ($1, $2, NULLIF($3::int, 0), $4, $5, $6, $7, $8, NULLIF($9, '')::int, NULLIF($10::int, 0), NULLIF($11::int, 0))
+		RETURNING `+alertColumns,
+		a.Operation, a.AccountID, a.CounterpartyAccountID, a.Amount, a.Currency,
+		a.Score, a.Action, a.Hits, a.TransactionID, a.ApprovalID, a.CaseID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert alert: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *MonitoringRepository) GetAlert(ctx context.Context, id int) (*model.MonitoringAlert, error) {
+	a, err := scanAlert(r.pool.QueryRow(ctx, "SELECT "+alertColumns+" FROM monitoring_alerts WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("alert %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get alert: %w", err)
+	}
+	return a, nil
+}
+
+func collectAlerts(rows pgx.Rows, err error) ([]*model.MonitoringAlert, error) {
+	if err != nil {
+		return nil, fmt.Errorf("list alerts: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.MonitoringAlert{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
+
+func (r *MonitoringRepository) ListAlerts(ctx context.Context, status string, accountID, limit, offset int) ([]*model.MonitoringAlert, error) {
+	return collectAlerts(r.pool.Query(ctx, `
+		SELECT `+alertColumns+`
+		FROM monitoring_alerts
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR account_id = $2)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`, status, accountID, limit, offset))
+}
+
+func (r *MonitoringRepository) ListAlertsByCase(ctx context.Context, caseID int) ([]*model.MonitoringAlert, error) {
+	return collectAlerts(r.pool.Query(ctx, `
+		SELECT `+alertColumns+`
+		FROM monitoring_alerts
+		WHERE case_id = $1
+		ORDER BY id`, caseID))
+}
+
+func (r *MonitoringRepository) CloseAlertTx(ctx context.Context, tx pgx.Tx, id int, closedBy, note string) (*model.MonitoringAlert, error) {
+	a, err := scanAlert(tx.QueryRow(ctx, `
+		UPDATE monitoring_alerts
+		SET status = 'closed', closed_by = $2, note = NULLIF($3, ''), closed_at = NOW()
+		WHERE id = $1 AND status = 'open'
+		RETURNING `+alertColumns, id, closedBy, note))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, gerr := r.GetAlert(ctx, id); gerr != nil {
+				return nil, gerr
+			}
+			return nil, fmt.Errorf("alert %d is already closed", id)
+		}
+		return nil, fmt.Errorf("close alert: %w", err)
+	}
+	return a, nil
+}
+
+// CloseCaseAlertsTx closes the open alerts of a case and returns how many
+// it closed.
+func (r *MonitoringRepository) CloseCaseAlertsTx(ctx context.Context, tx pgx.Tx, caseID int, closedBy, note string) (int, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE monitoring_alerts
+		SET status = 'closed', closed_by = $2, note = NULLIF($3, ''), closed_at = NOW()
+		WHERE case_id = $1 AND status = 'open'`, caseID, closedBy, note)
+	if err != nil {
+		return 0, fmt.Errorf("close case alerts: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// paymentBatchColumns must be selected from payment_batches unaliased: the
+// item count subqueries refer to payment_batches.id.
+const paymentBatchColumns = `id, format, mode, status, COALESCE(message_id, ''), control_sum,
+	COALESCE(created_by, ''), COALESCE(initiated_by, 0), COALESCE(error, ''), COALESCE(claim_token::text, ''), lease_until,
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id),
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id AND i.status = 'pending'),
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id AND i.status = 'invalid'),
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id AND i.status = 'succeeded'),
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id AND i.status = 'failed'),
+	(SELECT COUNT(*) FROM payment_batch_items i WHERE i.batch_id = payment_batches.id AND i.status = 'skipped'),
+	created_at, updated_at, completed_at`
+
+const paymentBatchItemColumns = `id, batch_id, line_no, from_account_number, to_account_number,
+	COALESCE(from_account_id, 0), COALESCE(to_account_id, 0), amount, COALESCE(currency, ''),
+	COALESCE(end_to_end_id, ''), COALESCE(description, ''), status, COALESCE(error, ''),
+	COALESCE(transaction_id, 0), updated_at`
+
+type PaymentBatchRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPaymentBatchRepository(pool *pgxpool.Pool) *PaymentBatchRepository {
+	return &PaymentBatchRepository{pool: pool}
+}
+
+func (r *PaymentBatchRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanPaymentBatch(row pgx.Row) (*model.PaymentBatch, error) {
+	var b model.PaymentBatch
+	if err := row.Scan(
+		&b.ID, &b.Format, &b.Mode, &b.Status, &b.MessageID, &b.ControlSum,
+		&b.CreatedBy, &b.InitiatedBy, &b.Error, &b.ClaimToken, &b.LeaseUntil,
+		&b.ItemCount, &b.PendingCount, &b.InvalidCount, &b.SucceededCount, &b.FailedCount, &b.SkippedCount,
+		&b.CreatedAt, &b.UpdatedAt, &b.CompletedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func scanPaymentBatchItem(row pgx.Row) (*model.PaymentBatchItem, error) {
+	var i model.PaymentBatchItem
+	if err := row.Scan(
+		&i.ID, &i.BatchID, &i.LineNo, &i.FromAccountNumber, &i.ToAccountNumber,
+		&i.FromAccountID, &i.ToAccountID, &i.Amount, &i.Currency,
+		&i.EndToEndID, &i.Description, &i.Status, &i.Error,
+		&i.TransactionID, &i.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// CreateTx stores the batch and all of its lines.
+func (r *PaymentBatchRepository) CreateTx(ctx context.Context, tx pgx.Tx, b *model.PaymentBatch, items []*model.PaymentBatchItem) error {
+	err := tx.QueryRow(ctx, `
+		INSERT INTO payment_batches (format, mode, status, message_id, control_sum, created_by, initiated_by, error, completed_at)
+		VALUES ($1, $2, $3, NULLIF($4::text, ''), $5, NULLIF($6::text, ''), NULLIF($7::int, 0), NULLIF($8::text, ''),
+			CASE WHEN $3 = 'rejected' THEN NOW() END)
+		RETURNING id`,
+		b.Format, b.Mode, b.Status, b.MessageID, b.ControlSum, b.CreatedBy, b.InitiatedBy, b.Error).Scan(&b.ID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return fmt.Errorf("a payment batch with message id %q already exists", b.MessageID)
+		}
+		return fmt.Errorf("insert payment batch: %w", err)
+	}
+
+	rows := make([][]any, 0, len(items))
+	for _, i := range items {
+		rows = append(rows, []any{
+			b.ID, i.LineNo, i.FromAccountNumber, i.ToAccountNumber, nullInt(i.FromAccountID), nullInt(i.ToAccountID),
+			i.Amount, nullString(i.Currency), nullString(i.EndToEndID), nullString(i.Description), i.Status, nullString(i.Error),
+		})
+	}
+	if _, err := tx.CopyFrom(ctx, pgx.Identifier{"payment_batch_items"}, []string{
+		"batch_id", "line_no", "from_account_number", "to_account_number", "from_account_id", "to_account_id",
+		"amount", "currency", "end_to_end_id", "description", "status", "error",
+	}, pgx.CopyFromRows(rows)); err != nil {
+		return fmt.Errorf("insert payment batch items: %w", err)
+	}
+	return nil
+}
+
+func (r *PaymentBatchRepository) GetById(ctx context.Context, id int) (*model.PaymentBatch, error) {
+	b, err := scanPaymentBatch(r.pool.QueryRow(ctx, "SELECT "+paymentBatchColumns+" FROM payment_batches WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("payment batch %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get payment batch: %w", err)
+	}
+	return b, nil
+}
+
+// ListItems returns the batch's lines in file order, optionally filtered by
+// status.
+func (r *PaymentBatchRepository) ListItems(ctx context.Context, batchID int, status string) ([]*model.PaymentBatchItem, error) {
+	return r.listItems(ctx, r.pool, batchID, status)
+}
+
+func (r *PaymentBatchRepository) ListItemsTx(ctx context.Context, tx pgx.Tx, batchID int, status string) ([]*model.PaymentBatchItem, error) {
+	return r.listItems(ctx, tx, batchID, status)
+}
+
+func (r *PaymentBatchRepository) listItems(ctx context.Context, q interface {
+	Query(context.Context, string, ...any) (pgx.Rows, error)
+}, batchID int, status string) ([]*model.PaymentBatchItem, error) {
+	rows, err := q.Query(ctx, `
+		SELECT `+paymentBatchItemColumns+`
+		FROM payment_batch_items
+		WHERE batch_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY line_no`, batchID, status)
+	if err != nil {
+		return nil, fmt.Errorf("list payment batch items: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.PaymentBatchItem
+	for rows.Next() {
+		i, err := scanPaymentBatchItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, i)
+	}
+	return out, rows.Err()
+}
+
+// ClaimNext takes the oldest pending batch, or one whose worker's lease ran
+// out, and leases it to the caller under a fresh claim token. It commits on
+// its own so the batch shows as processing straight away.
+func (r *PaymentBatchRepository) ClaimNext(ctx context.Context, lease time.Duration) (*model.PaymentBatch, error) {
+	b, err := scanPaymentBatch(r.pool.QueryRow(ctx, `
+		UPDATE payment_batches
+		SET status = 'processing', claim_token = $1, lease_until = NOW() + $2::interval, updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM payment_batches
+			WHERE status = 'pending' OR (status = 'processing' AND lease_until < NOW())
+			ORDER BY id
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING `+paymentBatchColumns,
+		uuid.NewString(), lease.String()))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim payment batch: %w", err)
+	}
+	return b, nil
+}
+
+// RenewClaimTx locks the batch and extends the lease if token still holds
+// it. It reports false once another worker has taken the batch over.
+func (r *PaymentBatchRepository) RenewClaimTx(ctx context.Context, tx pgx.Tx, id int, token string, lease time.Duration) (bool, error) {
+	tag, err := tx.Exec(ctx, `
+		UPDATE payment_batches
+		SET lease_until = NOW() + $3::interval, updated_at = NOW()
+		WHERE id = $1 AND claim_token = $2::uuid AND status = 'processing'`,
+		id, token, lease.String())
+	if err != nil {
+		return false, fmt.Errorf("renew payment batch claim: %w", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *PaymentBatchRepository) UpdateItemTx(ctx context.Context, tx pgx.Tx, i *model.PaymentBatchItem) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE payment_batch_items
+		SET status = $2, error = NULLIF($3::text, ''), transaction_id = NULLIF($4::int, 0), updated_at = NOW()
+		WHERE id = $1`, i.ID, i.Status, i.Error, i.TransactionID)
+	if err != nil {
+		return fmt.Errorf("update payment batch item: %w", err)
+	}
+	return nil
+}
+
+// MarkItemsTx moves every item of the batch in status from to status to.
+func (r *PaymentBatchRepository) MarkItemsTx(ctx context.Context, tx pgx.Tx, batchID int, from, to, reason string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE payment_batch_items
+		SET status = $3, error = COALESCE(error, NULLIF($4::text, '')), updated_at = NOW()
+		WHERE batch_id = $1 AND status = $2`, batchID, from, to, reason)
+	if err != nil {
+		return fmt.Errorf("mark payment batch items: %w", err)
+	}
+	return nil
+}
+
+// FinishTx records the batch's outcome and releases its claim.
+func (r *PaymentBatchRepository) FinishTx(ctx context.Context, tx pgx.Tx, id int, status, reason string) error {
+	_, err := tx.Exec(ctx, `
+		UPDATE payment_batches
+		SET status = $2, error = NULLIF($3::text, ''), claim_token = NULL, lease_until = NULL,
+			completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1`, id, status, reason)
+	if err != nil {
+		return fmt.Errorf("finish payment batch: %w", err)
+	}
+	return nil
+}
+
+func nullInt(v int) any {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
+
+func nullString(v string) any {
+	if v == "" {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const screeningHitColumns = `id, client_id, list_version, entry_id, entry_name, COALESCE(program, ''), matched_name,
+	screened_name, score, birth_date_match, status, COALESCE(reviewed_by, ''), COALESCE(note, ''),
+	reviewed_at, created_at`
+
+type ScreeningRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewScreeningRepository(pool *pgxpool.Pool) *ScreeningRepository {
+	return &ScreeningRepository{pool: pool}
+}
+
+func (r *ScreeningRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanScreeningHit(row pgx.Row) (*model.ScreeningHit, error) {
+	var h model.ScreeningHit
+	if err := row.Scan(
+		&h.ID, &h.ClientID, &h.ListVersion, &h.EntryID, &h.EntryName, &h.Program, &h.MatchedName,
+		&h.ScreenedName, &h.Score, &h.BirthDateMatch, &h.Status, &h.ReviewedBy, &h.Note,
+		&h.ReviewedAt, &h.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// CreateHit records h unless the client was already hit on the same entry
+// with the same name and birth date; created reports whether it was new.
+func (r *ScreeningRepository) CreateHit(ctx context.Context, h *model.ScreeningHit) (saved *model.ScreeningHit, created bool, err error) {
+	saved, err = scanScreeningHit(r.pool.QueryRow(ctx, `
+		INSERT INTO screening_hits (client_id, list_version, entry_id, entry_name, program, matched_name,
+			screened_name, score, birth_date_match)
+		VALUES ($1, $2, $3, $4, NULLIF($5, ''), $6, $7, $8, $9)
+		ON CONFLICT (client_id, entry_id, screened_name) DO NOTHING
+		RETURNING `+screeningHitColumns,
+		h.ClientID, h.ListVersion, h.EntryID, h.EntryName, h.Program, h.MatchedName,
+		h.ScreenedName, h.Score, h.BirthDateMatch,
+	))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("insert screening hit: %w", err)
+	}
+	return saved, true, nil
+}
+
+func (r *ScreeningRepository) GetHit(ctx context.Context, id int) (*model.ScreeningHit, error) {
+	h, err := scanScreeningHit(r.pool.QueryRow(ctx, "SELECT "+screeningHitColumns+" FROM screening_hits WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("screening hit %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get screening hit: %w", err)
+	}
+	return h, nil
+}
+
+func (r *ScreeningRepository) ListHits(ctx context.Context, status string, clientID, limit, offset int) ([]*model.ScreeningHit, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+screeningHitColumns+`
+		FROM screening_hits
+		WHERE ($1 = '' OR status = $1) AND ($2 = 0 OR client_id = $2)
+		ORDER BY id DESC
+		LIMIT $3 OFFSET $4`, status, clientID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list screening hits: %w", err)
+	}
+	defer rows.Close()
+
+	out := []*model.ScreeningHit{}
+	for rows.Next() {
+		h, err := scanScreeningHit(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, h)
+	}
+	return out, rows.Err()
+}
+
+// CountBlocking counts the client's pending and confirmed hits.
+func (r *ScreeningRepository) CountBlocking(ctx context.Context, clientID int) (int, error) {
+	var n int
+	if err := r.pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM screening_hits WHERE client_id = $1 AND status IN ('pending', 'confirmed')`,
+		clientID).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count screening hits: %w", err)
+	}
+	return n, nil
+}
+
+// ReviewHitTx moves a pending hit to cleared or confirmed.
+func (r *ScreeningRepository) ReviewHitTx(ctx context.Context, tx pgx.Tx, id int, status, reviewer, note string) (*model.ScreeningHit, error) {
+	h, err := scanScreeningHit(tx.QueryRow(ctx, `
+		UPDATE screening_hits
+		SET status = $2, reviewed_by = $3, note = NULLIF($4, ''), reviewed_at = NOW()
+		WHERE id = $1 AND status = 'pending'
+		RETURNING `+screeningHitColumns, id, status, reviewer, note))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			existing, gerr := r.GetHit(ctx, id)
+			if gerr != nil {
+				return nil, gerr
+			}
+			return nil, fmt.Errorf("screening hit %d is already %s", id, existing.Status)
+		}
+		return nil, fmt.Errorf("review screening hit: %w", err)
+	}
+	return h, nil
+}
+
+// HasRun reports whether every client was screened against listVersion.
+func (r *ScreeningRepository) HasRun(ctx context.Context, listVersion string) (bool, error) {
+	var ok bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM screening_runs WHERE list_version = $1)`, listVersion).Scan(&ok); err != nil {
+		return false, fmt.Errorf("find screening run: %w", err)
+	}
+	return ok, nil
+}
+
+func (r *ScreeningRepository) CreateRun(ctx context.Context, run *model.ScreeningRun) (*model.ScreeningRun, error) {
+	var out model.ScreeningRun
+	if err := r.pool.QueryRow(ctx, `
+		INSERT INTO screening_runs (list_version, clients, hits, started_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, list_version, clients, hits, started_at, finished_at`,
+		run.ListVersion, run.Clients, run.Hits, run.StartedAt,
+	).Scan(&out.ID, &out.ListVersion, &out.Clients, &out.Hits, &out.StartedAt, &out.FinishedAt); err != nil {
+		return nil, fmt.Errorf("insert screening run: %w", err)
+	}
+	return &out, nil
+}
+
+// LastRun returns the latest run, nil when there was none.
+func (r *ScreeningRepository) LastRun(ctx context.Context) (*model.ScreeningRun, error) {
+	var out model.ScreeningRun
+	err := r.pool.QueryRow(ctx, `
+		SELECT id, list_version, clients, hits, started_at, finished_at
+		FROM screening_runs
+		ORDER BY id DESC
+		LIMIT 1`).Scan(&out.ID, &out.ListVersion, &out.Clients, &out.Hits, &out.StartedAt, &out.FinishedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get last screening run: %w", err)
+	}
+	return &out, nil
+}
@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const standingOrderColumns = `id, from_account_id, to_account_id, amount, currency, schedule, COALESCE(reference, ''),
+	start_at, end_at, status, max_retries, retry_interval_seconds, current_occurrence, attempts, next_run_at,
+	COALESCE(initiated_by, 0), created_at, updated_at`
+
+const executionColumns = `id, standing_order_id, scheduled_for, attempt, status, transaction_id, COALESCE(error, ''), created_at`
+
+type StandingOrderRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStandingOrderRepository(pool *pgxpool.Pool) *StandingOrderRepository {
+	return &StandingOrderRepository{pool: pool}
+}
+
+func (r *StandingOrderRepository) Pool() *pgxpool.Pool { return r.pool }
+
+func scanStandingOrder(row pgx.Row) (*model.StandingOrder, error) {
+	var o model.StandingOrder
+	if err := row.Scan(
+		&o.ID, &o.FromAccountID, &o.ToAccountID, &o.Amount, &o.Currency, &o.Schedule, &o.Reference,
+		&o.StartAt, &o.EndAt, &o.Status, &o.MaxRetries, &o.RetryIntervalSeconds, &o.CurrentOccurrence, &o.Attempts, &o.NextRunAt,
+		&o.InitiatedBy, &o.CreatedAt, &o.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+func scanExecution(row pgx.Row) (*model.StandingOrderExecution, error) {
+	var e model.StandingOrderExecution
+	if err := row.Scan(&e.ID, &e.StandingOrderID, &e.ScheduledFor, &e.Attempt, &e.Status, &e.TransactionID, &e.Error, &e.CreatedAt); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *StandingOrderRepository) Create(ctx context.Context, o *model.StandingOrder) (*model.StandingOrder, error) {
+	saved, err := scanStandingOrder(r.pool.QueryRow(ctx, `
+		INSERT INTO standing_orders (from_account_id, to_account_id, amount, currency, schedule, reference,
+			start_at, end_at, status, max_retries, retry_interval_seconds, current_occurrence, next_run_at, initiated_by)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, $8, $9, $10, $11, $12, $12, NULLIF($13::int, 0))
+		RETURNING `+standingOrderColumns,
+		o.FromAccountID, o.ToAccountID, o.Amount, o.Currency, o.Schedule, o.Reference,
+		o.StartAt, o.EndAt, o.Status, o.MaxRetries, o.RetryIntervalSeconds, o.CurrentOccurrence, o.InitiatedBy,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("insert standing order: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *StandingOrderRepository) GetById(ctx context.Context, id int) (*model.StandingOrder, error) {
+	o, err := scanStandingOrder(r.pool.QueryRow(ctx, "SELECT "+standingOrderColumns+" FROM standing_orders WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("standing order %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get standing order: %w", err)
+	}
+	return o, nil
+}
+
+func (r *StandingOrderRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int) (*model.StandingOrder, error) {
+	o, err := scanStandingOrder(tx.QueryRow(ctx, "SELECT "+standingOrderColumns+" FROM standing_orders WHERE id = $1 FOR UPDATE", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("standing order %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get standing order: %w", err)
+	}
+	return o, nil
+}
+
+// ClaimDueTx locks the next due active order, skipping rows another worker
+// already holds. It returns nil when nothing is due.
+func (r *StandingOrderRepository) ClaimDueTx(ctx context.Context, tx pgx.Tx, now time.Time) (*model.StandingOrder, error) {
+	o, err := scanStandingOrder(tx.QueryRow(ctx, `
+		SELECT `+standingOrderColumns+`
+		FROM standing_orders
+		WHERE status = 'active' AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED`, now))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("claim due standing order: %w", err)
+	}
+	return o, nil
+}
+
+func (r *StandingOrderRepository) ListByAccountID(ctx context.Context, accountID int) ([]*model.StandingOrder, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+standingOrderColumns+`
+		FROM standing_orders
+		WHERE from_account_id = $1 OR to_account_id = $1
+		ORDER BY id DESC`, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("list standing orders: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.StandingOrder
+	for rows.Next() {
+		o, err := scanStandingOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, o)
+	}
+	return out, rows.Err()
+}
+
+// UpdateStateTx persists the scheduling state of o (status, occurrence,
+// attempts and next run).
+func (r *StandingOrderRepository) UpdateStateTx(ctx context.Context, tx pgx.Tx, o *model.StandingOrder) (*model.StandingOrder, error) {
+	saved, err := scanStandingOrder(tx.QueryRow(ctx, `
+		UPDATE standing_orders
+		SET status = $2, current_occurrence = $3, attempts = $4, next_run_at = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING `+standingOrderColumns,
+		o.ID, o.Status, o.CurrentOccurrence, o.Attempts, o.NextRunAt,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("update standing order: %w", err)
+	}
+	return saved, nil
+}
+
+func (r *StandingOrderRepository) RecordExecutionTx(ctx context.Context, tx pgx.Tx, e *model.StandingOrderExecution) error {
+	return tx.QueryRow(ctx, `
+		INSERT INTO standing_order_executions (standing_order_id, scheduled_for, attempt, status, transaction_id, error)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''))
+		RETURNING id, created_at`,
+		e.StandingOrderID, e.ScheduledFor, e.Attempt, e.Status, e.TransactionID, e.Error,
+	).Scan(&e.ID, &e.CreatedAt)
+}
+
+func (r *StandingOrderRepository) ListExecutions(ctx context.Context, orderID, limit, offset int) ([]*model.StandingOrderExecution, error) {
+	rows, err := r.pool.Query(ctx, `
+		SELECT `+executionColumns+`
+		FROM standing_order_executions
+		WHERE standing_order_id = $1
+		ORDER BY id DESC
+		LIMIT $2 OFFSET $3`, orderID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list executions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.StandingOrderExecution
+	for rows.Next() {
+		e, err := scanExecution(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
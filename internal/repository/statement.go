@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"basic-gin/internal/model"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// StatementRepository reads an account's movements for statements and
+// exports. Run its methods in one repeatable-read transaction so balances
+// and movements come from the same snapshot.
+type StatementRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewStatementRepository(pool *pgxpool.Pool) *StatementRepository {
+	return &StatementRepository{pool: pool}
+}
+
+// BeginSnapshot starts a read-only repeatable-read transaction.
+func (r *StatementRepository) BeginSnapshot(ctx context.Context) (pgx.Tx, error) {
+	return r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+}
+
+// BalanceAtTx is the account's balance just before at: the current balance
+// with every later movement unwound.
+func (r *StatementRepository) BalanceAtTx(ctx context.Context, tx pgx.Tx, accountID int, at time.Time) (float64, error) {
+	var b float64
+	err := tx.QueryRow(ctx, `
+		SELECT a.balance
+			- COALESCE((SELECT SUM(COALESCE(t.to_amount, t.amount)) FROM transactions t
+				WHERE t.to_account_id = a.id AND t.created_at >= $2), 0)
+			+ COALESCE((SELECT SUM(t.amount) FROM transactions t
+				WHERE t.from_account_id = a.id AND t.created_at >= $2), 0)
+		FROM accounts a
+		WHERE a.id = $1`, accountID, at).Scan(&b)
+	if err != nil {
+		return 0, fmt.Errorf("balance at %s: %w", at.Format(time.RFC3339), err)
+	}
+	return b, nil
+}
+
+// TotalsTx counts and sums the account's credits and debits in [from, to).
+func (r *StatementRepository) TotalsTx(ctx context.Context, tx pgx.Tx, accountID int, from, to time.Time) (creditCount, debitCount int, credits, debits float64, err error) {
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*) FILTER (WHERE t.to_account_id = $1),
+			COUNT(*) FILTER (WHERE t.from_account_id = $1),
+			COALESCE(SUM(COALESCE(t.to_amount, t.amount)) FILTER (WHERE t.to_account_id = $1), 0),
+			COALESCE(SUM(t.amount) FILTER (WHERE t.from_account_id = $1), 0)
+		FROM transactions t
+		WHERE (t.from_account_id = $1 OR t.to_account_id = $1)
+			AND t.created_at >= $2 AND t.created_at < $3`, accountID, from, to).Scan(&creditCount, &debitCount, &credits, &debits)
+	if err != nil {
+		return 0, 0, 0, 0, fmt.Errorf("statement totals: %w", err)
+	}
+	return creditCount, debitCount, credits, debits, nil
+}
+
+// EachLineTx calls fn for every movement on the account in [from, to), oldest
+// first, without loading the period into memory.
+func (r *StatementRepository) EachLineTx(ctx context.Context, tx pgx.Tx, accountID int, from, to time.Time, fn func(*model.StatementLine) error) error {
+	rows, err := tx.Query(ctx, `
+		SELECT t.id, t.type, COALESCE(t.description, ''),
+			COALESCE(c.id, 0), COALESCE(c.account_number, ''),
+			CASE WHEN t.to_account_id = $1 THEN COALESCE(t.to_amount, t.amount) ELSE -t.amount END,
+			CASE WHEN t.to_account_id = $1 THEN COALESCE(t.to_currency, t.currency) ELSE t.currency END,
+			t.created_at
+		FROM transactions t
+		LEFT JOIN accounts c ON c.id = CASE WHEN t.to_account_id = $1 THEN t.from_account_id ELSE t.to_account_id END
+		WHERE (t.from_account_id = $1 OR t.to_account_id = $1)
+			AND t.created_at >= $2 AND t.created_at < $3
+		ORDER BY t.created_at, t.id`, accountID, from, to)
+	if err != nil {
+		return fmt.Errorf("statement lines: %w", err)
+	}
+	defer rows.Close()
+
+	var l model.StatementLine
+	for rows.Next() {
+		if err := rows.Scan(&l.TransactionID, &l.Type, &l.Description, &l.CounterpartyID, &l.CounterpartyNumber, &l.Amount, &l.Currency, &l.CreatedAt); err != nil {
+			return fmt.Errorf("scanning rows: %v", err)
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -3,11 +3,29 @@ package repository
 import (
 	"basic-gin/internal/model"
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// transactionColumns must be selected from the transactions table unaliased:
+// the reversal subqueries refer to transactions.id.
+const transactionColumns = `id, type, COALESCE(from_account_id, 0), COALESCE(to_account_id, 0), amount, currency,
+	COALESCE(to_amount, 0), COALESCE(to_currency, ''), COALESCE(fx_rate, 0), COALESCE(fx_mid_rate, 0),
+	COALESCE(fx_spread_bps, 0), COALESCE(fx_quote_id::text, ''), COALESCE(idempotency_key, ''),
+	COALESCE(description, ''), COALESCE(reversal_of, 0),
+	ARRAY(SELECT r.id FROM transactions r WHERE r.reversal_of = transactions.id ORDER BY r.id),
+	COALESCE((SELECT SUM(COALESCE(r.to_amount, r.amount)) FROM transactions r WHERE r.reversal_of = transactions.id), 0),
+	COALESCE(fee_for, 0),
+	COALESCE((SELECT SUM(f.amount) FROM transactions f WHERE f.fee_for = transactions.id), 0),
+	COALESCE(initiated_by, 0),
+	COALESCE((SELECT a.account_number FROM accounts a WHERE a.id = transactions.from_account_id), ''),
+	COALESCE((SELECT a.account_number FROM accounts a WHERE a.id = transactions.to_account_id), ''),
+	created_at`
+
 type TransactionRepository struct {
 	pool *pgxpool.Pool
 }
@@ -18,18 +36,120 @@ func NewTransactionRepository(pool *pgxpool.Pool) *TransactionRepository {
 
 func (r *TransactionRepository) Pool() *pgxpool.Pool { return r.pool }
 
+func scanTransaction(row pgx.Row) (*model.Transaction, error) {
+	var t model.Transaction
+	if err := row.Scan(
+		&t.ID, &t.Type, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.Currency,
+		&t.ToAmount, &t.ToCurrency, &t.FXRate, &t.FXMidRate,
+		&t.FXSpreadBps, &t.FXQuoteID, &t.IdempotencyKey,
+		&t.Description, &t.ReversalOf, &t.ReversalIDs, &t.ReversedAmount,
+		&t.FeeFor, &t.FeeAmount,
+		&t.InitiatedBy, &t.FromAccountNumber, &t.ToAccountNumber, &t.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
 func (r *TransactionRepository) SaveTx(ctx context.Context, tx pgx.Tx, t *model.Transaction) error {
+	if t.Type == "" {
+		t.Type = model.TransactionTypeTransfer
+	}
 	return tx.QueryRow(ctx, `
-		INSERT INTO transactions (from_account_id, to_account_id, amount)
-		VALUES ($1, $2, $3)
+		INSERT INTO transactions (type, from_account_id, to_account_id, amount, currency,
+			to_amount, to_currency, fx_rate, fx_mid_rate, fx_spread_bps, fx_quote_id, idempotency_key,
+			description, reversal_of, fee_for, initiated_by)
+		VALUES ($1, NULLIF($2::int, 0), NULLIF($3::int, 0), $4, $5,
+			NULLIF($6::numeric, 0), NULLIF($7::text, ''), NULLIF($8::numeric, 0), NULLIF($9::numeric, 0), NULLIF($10::int, 0), NULLIF($11::text, '')::uuid,
+			NULLIF($12::text, ''), NULLIF($13::text, ''), NULLIF($14::int, 0), NULLIF($15::int, 0), NULLIF($16::int, 0))
 		RETURNING id, created_at
-	`, t.FromAccountID, t.ToAccountID, t.Amount).
+	`, t.Type, t.FromAccountID, t.ToAccountID, t.Amount, t.Currency,
+		t.ToAmount, t.ToCurrency, t.FXRate, t.FXMidRate, t.FXSpreadBps, t.FXQuoteID, t.IdempotencyKey,
+		t.Description, t.ReversalOf, t.FeeFor, t.InitiatedBy).
 		Scan(&t.ID, &t.CreatedAt)
 }
 
+func (r *TransactionRepository) GetById(ctx context.Context, id int) (*model.Transaction, error) {
+	t, err := scanTransaction(r.pool.QueryRow(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE id = $1", id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("transaction %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get transaction: %w", err)
+	}
+	return t, nil
+}
+
+func (r *TransactionRepository) GetByIdTx(ctx context.Context, tx pgx.Tx, id int, forUpdate bool) (*model.Transaction, error) {
+	q := "SELECT " + transactionColumns + " FROM transactions WHERE id = $1"
+	if forUpdate {
+		q += " FOR UPDATE"
+	}
+	t, err := scanTransaction(tx.QueryRow(ctx, q, id))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("transaction %d %w", id, ErrNotFound)
+		}
+		return nil, fmt.Errorf("get transaction: %w", err)
+	}
+	return t, nil
+}
+
+// GetByIdempotencyKeyTx returns the transaction recorded under key, or nil
+// if there is none.
+func (r *TransactionRepository) GetByIdempotencyKeyTx(ctx context.Context, tx pgx.Tx, key string) (*model.Transaction, error) {
+	t, err := scanTransaction(tx.QueryRow(ctx, "SELECT "+transactionColumns+" FROM transactions WHERE idempotency_key = $1", key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get transaction by idempotency key: %w", err)
+	}
+	return t, nil
+}
+
+// CountOutgoingSinceTx counts transactions of type txType debited from
+// accountID since the given time.
+func (r *TransactionRepository) CountOutgoingSinceTx(ctx context.Context, tx pgx.Tx, accountID int, txType string, since time.Time) (int, error) {
+	var n int
+	if err := tx.QueryRow(ctx, `
+		SELECT COUNT(*) FROM transactions
+		WHERE from_account_id = $1 AND type = $2 AND created_at >= $3`,
+		accountID, txType, since).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count transactions: %w", err)
+	}
+	return n, nil
+}
+
+// SumOutgoingSinceTx totals the amounts of transactions of type txType
+// debited from accountID since the given time.
+func (r *TransactionRepository) SumOutgoingSinceTx(ctx context.Context, tx pgx.Tx, accountID int, txType string, since time.Time) (float64, error) {
+	var sum float64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM transactions
+		WHERE from_account_id = $1 AND type = $2 AND created_at >= $3`,
+		accountID, txType, since).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("sum transactions: %w", err)
+	}
+	return sum, nil
+}
+
+// SumInitiatedSinceTx totals the transfers and withdrawals clientID made
+// from accountID since the given time.
+func (r *TransactionRepository) SumInitiatedSinceTx(ctx context.Context, tx pgx.Tx, accountID, clientID int, since time.Time) (float64, error) {
+	var sum float64
+	if err := tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0) FROM transactions
+		WHERE from_account_id = $1 AND initiated_by = $2 AND type IN ('transfer', 'withdrawal') AND created_at >= $3`,
+		accountID, clientID, since).Scan(&sum); err != nil {
+		return 0, fmt.Errorf("sum transactions: %w", err)
+	}
+	return sum, nil
+}
+
 func (r *TransactionRepository) ListByAccountID(ctx context.Context, accountID, limit, offset int) ([]*model.Transaction, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, from_account_id, to_account_id, amount, created_at
+		SELECT `+transactionColumns+`
 		FROM transactions
 		WHERE from_account_id = $1 OR to_account_id = $1
 		ORDER BY created_at DESC
@@ -42,11 +162,97 @@ func (r *TransactionRepository) ListByAccountID(ctx context.Context, accountID,
 
 	var out []*model.Transaction
 	for rows.Next() {
-		var t model.Transaction
-		if err := rows.Scan(&t.ID, &t.FromAccountID, &t.ToAccountID, &t.Amount, &t.CreatedAt); err != nil {
+		t, err := scanTransaction(rows)
+		if err != nil {
 			return nil, err
 		}
-		out = append(out, &t)
+		out = append(out, t)
 	}
 	return out, rows.Err()
 }
+
+func collectTransactions(rows pgx.Rows, err error) ([]*model.Transaction, error) {
+	if err != nil {
+		return nil, fmt.Errorf("list transactions: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*model.Transaction
+	for rows.Next() {
+		t, err := scanTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning rows: %v", err)
+		}
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
+
+// ListByIds returns the transactions with the given ids, in id order.
+func (r *TransactionRepository) ListByIds(ctx context.Context, ids []int) ([]*model.Transaction, error) {
+	return collectTransactions(r.pool.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE id = ANY($1)
+		ORDER BY id`, ids))
+}
+
+// ListByClient returns, in id order, the transactions on accounts the
+// client owns or owned jointly and those it made itself on other accounts.
+func (r *TransactionRepository) ListByClient(ctx context.Context, clientID int) ([]*model.Transaction, error) {
+	return collectTransactions(r.pool.Query(ctx, `
+		WITH held AS (
+			SELECT account_id FROM account_holders
+			WHERE client_id = $1 AND role IN ('owner', 'joint_owner') AND status IN ('active', 'removed')
+		)
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE from_account_id IN (SELECT account_id FROM held)
+			OR to_account_id IN (SELECT account_id FROM held)
+			OR initiated_by = $1
+		ORDER BY id`, clientID))
+}
+
+// ListMovementsSince returns the transfers, deposits and withdrawals on
+// accountID from since on, oldest first.
+func (r *TransactionRepository) ListMovementsSince(ctx context.Context, accountID int, since time.Time) ([]*model.Transaction, error) {
+	return collectTransactions(r.pool.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE (from_account_id = $1 OR to_account_id = $1) AND created_at >= $2
+			AND type IN ('transfer', 'deposit', 'withdrawal')
+		ORDER BY created_at, id`, accountID, since))
+}
+
+// ListMovementsSinceTx is ListMovementsSince seeing what tx has posted.
+func (r *TransactionRepository) ListMovementsSinceTx(ctx context.Context, tx pgx.Tx, accountID int, since time.Time) ([]*model.Transaction, error) {
+	return collectTransactions(tx.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE (from_account_id = $1 OR to_account_id = $1) AND created_at >= $2
+			AND type IN ('transfer', 'deposit', 'withdrawal')
+		ORDER BY created_at, id`, accountID, since))
+}
+
+// HasTransfer reports whether fromID has ever transferred to toID.
+func (r *TransactionRepository) HasTransfer(ctx context.Context, fromID, toID int) (bool, error) {
+	var ok bool
+	if err := r.pool.QueryRow(ctx, `
+		SELECT EXISTS (SELECT 1 FROM transactions WHERE from_account_id = $1 AND to_account_id = $2 AND type = 'transfer')`,
+		fromID, toID).Scan(&ok); err != nil {
+		return false, fmt.Errorf("find transfer: %w", err)
+	}
+	return ok, nil
+}
+
+// ListPostedAfter pages through the transfers, deposits and withdrawals
+// created in [from, to) in id order, starting after afterID.
+func (r *TransactionRepository) ListPostedAfter(ctx context.Context, afterID int, from, to time.Time, limit int) ([]*model.Transaction, error) {
+	return collectTransactions(r.pool.Query(ctx, `
+		SELECT `+transactionColumns+`
+		FROM transactions
+		WHERE id > $1 AND created_at >= $2 AND created_at < $3
+			AND type IN ('transfer', 'deposit', 'withdrawal')
+		ORDER BY id
+		LIMIT $4`, afterID, from, to, limit))
+}
@@ -0,0 +1,195 @@
+// Package sanctions screens people against a locally loaded sanctions or
+// watchlist file, such as a consolidated list exported to CSV or XML.
+package sanctions
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"basic-gin/internal/textmatch"
+)
+
+// Entry is a listed person. Names holds the primary name first, then
+// aliases; BirthDates are "YYYY-MM-DD" or, when only the year is known,
+// "YYYY".
+type Entry struct {
+	ID         string
+	Names      []string
+	BirthDates []string
+	Program    string
+
+	normalized []string
+	sorted     []string
+}
+
+// List is a parsed list file. Version identifies its contents.
+type List struct {
+	Entries []Entry
+	Version string
+}
+
+// Parse reads a list file, choosing the format by name's extension.
+//
+// CSV files need a header naming at least the id and name columns; aliases
+// and birth_dates hold several values separated by ";", program is optional
+// and lines starting with "#" are skipped:
+//
+//	id,name,aliases,birth_dates,program
+//	EX-001,Petar Zlatković,Петар Златковић;P. Zlatkovic,1961-04-02,EXAMPLE
+//
+// XML files hold <entry id="" program=""> elements with <name>, <alias> and
+// <birth_date> children under any root element.
+func Parse(name string, r io.Reader) (*List, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read sanctions list: %w", err)
+	}
+	var entries []Entry
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		entries, err = parseCSV(raw)
+	case ".xml":
+		entries, err = parseXML(raw)
+	default:
+		return nil, fmt.Errorf("unsupported sanctions list format: %s", name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i := range entries {
+		e := &entries[i]
+		if e.ID == "" {
+			return nil, fmt.Errorf("sanctions list: entry %d has no id", i+1)
+		}
+		if seen[e.ID] {
+			return nil, fmt.Errorf("sanctions list: duplicate id %q", e.ID)
+		}
+		seen[e.ID] = true
+		for _, d := range e.BirthDates {
+			if !validDate(d) {
+				return nil, fmt.Errorf("sanctions list: entry %s: birth date %q is not YYYY or YYYY-MM-DD", e.ID, d)
+			}
+		}
+		for _, n := range e.Names {
+			if norm := textmatch.Normalize(n); norm != "" {
+				e.normalized = append(e.normalized, norm)
+				e.sorted = append(e.sorted, strings.Join(textmatch.Tokens(n), " "))
+			}
+		}
+		if len(e.normalized) == 0 {
+			return nil, fmt.Errorf("sanctions list: entry %s has no name", e.ID)
+		}
+	}
+
+	sum := sha256.Sum256(raw)
+	return &List{Entries: entries, Version: hex.EncodeToString(sum[:])}, nil
+}
+
+func validDate(d string) bool {
+	if len(d) != 4 && len(d) != 10 {
+		return false
+	}
+	for i, r := range d {
+		if (i == 4 || i == 7) && len(d) == 10 {
+			if r != '-' {
+				return false
+			}
+		} else if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ";") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+func parseCSV(raw []byte) ([]Entry, error) {
+	cr := csv.NewReader(bytes.NewReader(raw))
+	cr.Comment = '#'
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse sanctions csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("parse sanctions csv: missing header")
+	}
+
+	col := map[string]int{}
+	for i, h := range records[0] {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, required := range []string{"id", "name"} {
+		if _, ok := col[required]; !ok {
+			return nil, fmt.Errorf("parse sanctions csv: header has no %q column", required)
+		}
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[name]; ok {
+			return strings.TrimSpace(rec[i])
+		}
+		return ""
+	}
+
+	out := make([]Entry, 0, len(records)-1)
+	for _, rec := range records[1:] {
+		e := Entry{ID: field(rec, "id"), Program: field(rec, "program"), BirthDates: splitList(field(rec, "birth_dates"))}
+		if name := field(rec, "name"); name != "" {
+			e.Names = append(e.Names, name)
+		}
+		e.Names = append(e.Names, splitList(field(rec, "aliases"))...)
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+type xmlEntry struct {
+	ID         string   `xml:"id,attr"`
+	Program    string   `xml:"program,attr"`
+	Name       string   `xml:"name"`
+	Aliases    []string `xml:"alias"`
+	BirthDates []string `xml:"birth_date"`
+}
+
+func parseXML(raw []byte) ([]Entry, error) {
+	var doc struct {
+		Entries []xmlEntry `xml:"entry"`
+	}
+	if err := xml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse sanctions xml: %w", err)
+	}
+	out := make([]Entry, 0, len(doc.Entries))
+	for _, x := range doc.Entries {
+		e := Entry{ID: strings.TrimSpace(x.ID), Program: strings.TrimSpace(x.Program)}
+		for _, n := range append([]string{x.Name}, x.Aliases...) {
+			if n = strings.TrimSpace(n); n != "" {
+				e.Names = append(e.Names, n)
+			}
+		}
+		for _, d := range x.BirthDates {
+			if d = strings.TrimSpace(d); d != "" {
+				e.BirthDates = append(e.BirthDates, d)
+			}
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
@@ -0,0 +1,150 @@
+package sanctions
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"basic-gin/internal/textmatch"
+)
+
+// Subject is the person being screened. A zero BirthDate is not compared.
+type Subject struct {
+	Name      string
+	BirthDate time.Time
+}
+
+// Match is a list entry the subject resembles.
+type Match struct {
+	EntryID     string
+	EntryName   string
+	Program     string
+	MatchedName string
+	// Score is the Jaro-Winkler similarity of the closest name, 0 to 1.
+	Score          float64
+	BirthDateMatch bool
+}
+
+// Screen returns the entries whose names score at least threshold against
+// the subject's, best first. Entries listing birth dates are only matched
+// when one of them agrees with the subject's.
+func (l *List) Screen(s Subject, threshold float64) []Match {
+	norm := textmatch.Normalize(s.Name)
+	if norm == "" {
+		return nil
+	}
+	sorted := strings.Join(textmatch.Tokens(s.Name), " ")
+
+	var out []Match
+	for i := range l.Entries {
+		e := &l.Entries[i]
+		best, bestName := 0.0, ""
+		for j := range e.normalized {
+			score := max(textmatch.JaroWinkler(norm, e.normalized[j]), textmatch.JaroWinkler(sorted, e.sorted[j]))
+			if score > best {
+				best, bestName = score, e.Names[j]
+			}
+		}
+		if best < threshold {
+			continue
+		}
+		dobMatch, ok := birthDateAgrees(e.BirthDates, s.BirthDate)
+		if !ok {
+			continue
+		}
+		out = append(out, Match{
+			EntryID:        e.ID,
+			EntryName:      e.Names[0],
+			Program:        e.Program,
+			MatchedName:    bestName,
+			Score:          best,
+			BirthDateMatch: dobMatch,
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Score > out[j].Score })
+	return out
+}
+
+// birthDateAgrees reports whether dob agrees with one of dates (matched) and
+// whether the entry may match at all: it may when it lists no dates or the
+// subject's is unknown.
+func birthDateAgrees(dates []string, dob time.Time) (matched, ok bool) {
+	if len(dates) == 0 || dob.IsZero() {
+		return false, true
+	}
+	full, year := dob.Format("2006-01-02"), dob.Format("2006")
+	for _, d := range dates {
+		if d == full || d == year {
+			return true, true
+		}
+	}
+	return false, false
+}
+
+// Screener holds the list currently in force. It is safe for concurrent
+// use and can be reloaded while serving.
+type Screener struct {
+	mu       sync.RWMutex
+	list     *List
+	source   string
+	modTime  time.Time
+	loadedAt time.Time
+}
+
+func NewScreener() *Screener {
+	return &Screener{}
+}
+
+// LoadFile replaces the list with the one read from path. On error the list
+// in force is kept.
+func (s *Screener) LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open sanctions list: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat sanctions list: %w", err)
+	}
+	l, err := Parse(path, f)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.list = l
+	s.source = path
+	s.modTime = info.ModTime()
+	s.loadedAt = time.Now().UTC()
+	s.mu.Unlock()
+	return nil
+}
+
+// ReloadIfChanged loads path again when it was modified since the last load
+// and reports whether it did.
+func (s *Screener) ReloadIfChanged(path string) (bool, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("stat sanctions list: %w", err)
+	}
+	s.mu.RLock()
+	same := s.source == path && info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+	if same {
+		return false, nil
+	}
+	return true, s.LoadFile(path)
+}
+
+// List returns the list in force, nil when none was loaded, together with
+// load metadata.
+func (s *Screener) List() (list *List, source string, loadedAt time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.list, s.source, s.loadedAt
+}
@@ -0,0 +1,151 @@
+package schedule
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ruleSchedule struct {
+	freq     string
+	interval int
+	monthDay int // 1..31, or -1 for the last day of the month
+	weekdays []time.Weekday
+	hour     int
+	minute   int
+	start    time.Time
+}
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+func parseRule(expr string, start time.Time) (Schedule, error) {
+	// Occurrences fall on whole minutes; a start with seconds must not push
+	// the first one, at the start's own time of day, past the anchor.
+	start = start.Truncate(time.Minute)
+	r := &ruleSchedule{
+		interval: 1,
+		monthDay: start.Day(),
+		hour:     start.Hour(),
+		minute:   start.Minute(),
+		start:    start,
+	}
+	for _, part := range strings.Split(expr, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		key, val := strings.ToUpper(strings.TrimSpace(kv[0])), strings.ToUpper(strings.TrimSpace(kv[1]))
+		switch key {
+		case "FREQ":
+			switch val {
+			case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+				r.freq = val
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", val)
+			}
+			r.interval = n
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(val)
+			if err != nil || n == 0 || n > 31 || n < -1 {
+				return nil, fmt.Errorf("invalid BYMONTHDAY %q (1-31 or -1)", val)
+			}
+			r.monthDay = n
+		case "BYDAY":
+			for _, d := range strings.Split(val, ",") {
+				wd, ok := weekdayCodes[strings.TrimSpace(d)]
+				if !ok {
+					return nil, fmt.Errorf("invalid BYDAY %q", d)
+				}
+				r.weekdays = append(r.weekdays, wd)
+			}
+		case "BYHOUR":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 || n > 23 {
+				return nil, fmt.Errorf("invalid BYHOUR %q", val)
+			}
+			r.hour = n
+		case "BYMINUTE":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 || n > 59 {
+				return nil, fmt.Errorf("invalid BYMINUTE %q", val)
+			}
+			r.minute = n
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", key)
+		}
+	}
+	if r.freq == "" {
+		return nil, fmt.Errorf("rule is missing FREQ")
+	}
+	if len(r.weekdays) == 0 {
+		r.weekdays = []time.Weekday{start.Weekday()}
+	}
+	sort.Slice(r.weekdays, func(i, j int) bool { return r.weekdays[i] < r.weekdays[j] })
+	return r, nil
+}
+
+// Next walks the rule's periods starting from the anchor period. Month days
+// past the end of a short month are clamped to its last day, so
+// BYMONTHDAY=31 fires on 30 April and 28/29 February.
+func (r *ruleSchedule) Next(t time.Time) time.Time {
+	t = t.UTC()
+	for k := 0; k < maxIterations; k++ {
+		for _, c := range r.period(k) {
+			if c.Before(r.start) {
+				continue
+			}
+			if c.After(t) {
+				return c
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// period returns the occurrences of the k-th period after the anchor.
+func (r *ruleSchedule) period(k int) []time.Time {
+	s := r.start
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, r.hour, r.minute, 0, 0, time.UTC)
+	}
+	switch r.freq {
+	case "DAILY":
+		return []time.Time{at(s.Year(), s.Month(), s.Day()+k*r.interval)}
+	case "WEEKLY":
+		weekStart := s.Day() - int(s.Weekday()) + 7*k*r.interval
+		out := make([]time.Time, 0, len(r.weekdays))
+		for _, wd := range r.weekdays {
+			out = append(out, at(s.Year(), s.Month(), weekStart+int(wd)))
+		}
+		return out
+	case "MONTHLY":
+		first := time.Date(s.Year(), s.Month()+time.Month(k*r.interval), 1, 0, 0, 0, 0, time.UTC)
+		return []time.Time{at(first.Year(), first.Month(), r.clampDay(first.Year(), first.Month()))}
+	case "YEARLY":
+		y := s.Year() + k*r.interval
+		return []time.Time{at(y, s.Month(), r.clampDay(y, s.Month()))}
+	}
+	return nil
+}
+
+func (r *ruleSchedule) clampDay(y int, m time.Month) int {
+	last := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if r.monthDay == -1 || r.monthDay > last {
+		return last
+	}
+	return r.monthDay
+}
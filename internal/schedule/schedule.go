@@ -0,0 +1,39 @@
+// Package schedule parses recurrence expressions for standing orders.
+//
+// Two syntaxes are accepted:
+//
+//   - five-field cron: "minute hour day-of-month month day-of-week", e.g.
+//     "0 9 1 * *" for 09:00 on the first of every month;
+//   - an RRULE-like interval: "FREQ=MONTHLY;BYMONTHDAY=1", with FREQ one of
+//     DAILY, WEEKLY, MONTHLY, YEARLY and optional INTERVAL, BYMONTHDAY,
+//     BYDAY, BYHOUR and BYMINUTE parts.
+//
+// All times are evaluated in UTC.
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Schedule interface {
+	// Next returns the first occurrence strictly after t, or the zero time if
+	// there is none.
+	Next(t time.Time) time.Time
+}
+
+// Parse builds a Schedule from expr. start anchors interval rules (their
+// INTERVAL counts from start) and supplies default hour, minute and day.
+func Parse(expr string, start time.Time) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty schedule")
+	}
+	if strings.Contains(strings.ToUpper(expr), "FREQ=") {
+		return parseRule(expr, start.UTC())
+	}
+	return parseCron(expr)
+}
+
+const maxIterations = 100000
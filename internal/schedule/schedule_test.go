@@ -0,0 +1,92 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func at(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestRuleNext(t *testing.T) {
+	tests := []struct {
+		name  string
+		expr  string
+		start string
+		after string
+		want  string
+	}{
+		{"daily first occurrence with seconds", "FREQ=DAILY", "2026-03-02 09:00:30", "2026-03-02 08:00:00", "2026-03-02 09:00:00"},
+		{"weekly first occurrence with seconds", "FREQ=WEEKLY", "2026-03-02 09:00:45", "2026-03-01 00:00:00", "2026-03-02 09:00:00"},
+		{"daily strictly after", "FREQ=DAILY", "2026-03-02 09:00:00", "2026-03-02 09:00:00", "2026-03-03 09:00:00"},
+		{"by hour before start", "FREQ=DAILY;BYHOUR=8", "2026-03-02 09:00:00", "2026-03-02 09:00:00", "2026-03-03 08:00:00"},
+		{"weekly by day", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2026-03-02 09:00:00", "2026-03-02 09:00:00", "2026-03-06 09:00:00"},
+		{"weekly interval skips a week", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", "2026-03-02 09:00:00", "2026-03-06 09:00:00", "2026-03-16 09:00:00"},
+		{"month day 31 in February", "FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31 09:00:00", "2026-02-01 00:00:00", "2026-02-28 09:00:00"},
+		{"month day 31 in April", "FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31 09:00:00", "2026-04-01 00:00:00", "2026-04-30 09:00:00"},
+		{"month day 31 in a leap February", "FREQ=MONTHLY;BYMONTHDAY=31", "2028-01-31 09:00:00", "2028-02-01 00:00:00", "2028-02-29 09:00:00"},
+		{"month day 31 back to 31", "FREQ=MONTHLY;BYMONTHDAY=31", "2026-01-31 09:00:00", "2026-04-30 09:00:00", "2026-05-31 09:00:00"},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1", "2026-01-15 10:00:00", "2026-01-15 10:00:00", "2026-01-31 10:00:00"},
+		{"yearly from 29 February", "FREQ=YEARLY", "2028-02-29 12:00:00", "2028-02-29 12:00:00", "2029-02-28 12:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.expr, at(tt.start))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(at(tt.after)); !got.Equal(at(tt.want)) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got.Format(time.DateTime), tt.want)
+			}
+		})
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	tests := []struct {
+		expr  string
+		after string
+		want  string
+	}{
+		{"0 9 1 * *", "2026-03-15 10:00:00", "2026-04-01 09:00:00"},
+		{"0 9 * * *", "2026-03-02 09:00:00", "2026-03-03 09:00:00"},
+		{"*/15 * * * *", "2026-03-02 09:07:30", "2026-03-02 09:15:00"},
+		{"30 8 * * 1-5", "2026-03-06 09:00:00", "2026-03-09 08:30:00"},
+		{"0 0 * * 7", "2026-03-02 00:00:00", "2026-03-08 00:00:00"},
+		{"0 12 13 * 5", "2026-03-01 00:00:00", "2026-03-06 12:00:00"},
+		{"0 0 29 2 *", "2026-03-01 00:00:00", "2028-02-29 00:00:00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr, at(tt.after))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Next(at(tt.after)); !got.Equal(at(tt.want)) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got.Format(time.DateTime), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"0 9 1 *",
+		"60 * * * *",
+		"0 9 5-1 * *",
+		"FREQ=HOURLY",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=DAILY;COUNT=3",
+		"BYHOUR=9",
+	} {
+		if _, err := Parse(expr, at("2026-03-02 09:00:00")); err == nil {
+			t.Errorf("Parse(%q) succeeded", expr)
+		}
+	}
+}
@@ -15,6 +15,8 @@ func newRouter(h *handler.Dependencies) *gin.Engine {
 	r := gin.New()
 
 	r.Use(middleware.RequestID())
+	r.Use(middleware.Actor())
+	r.Use(middleware.ClientID())
 	r.Use(gin.Recovery())
 
 	r.GET("/", func(c *gin.Context) {
@@ -33,6 +35,30 @@ func newRouter(h *handler.Dependencies) *gin.Engine {
 	} else {
 		clients := v1.Group("/clients")
 		h.ClientHandler.Register(clients)
+
+		if h.BeneficiaryHandler == nil {
+			log.Println("WARN: beneficiary handler is nil - routes will be missing")
+		} else {
+			h.BeneficiaryHandler.RegisterClients(clients)
+		}
+
+		if h.ClientContactHandler == nil {
+			log.Println("WARN: client contact handler is nil - routes will be missing")
+		} else {
+			h.ClientContactHandler.RegisterClients(clients)
+		}
+
+		if h.BusinessHandler == nil {
+			log.Println("WARN: business handler is nil - routes will be missing")
+		} else {
+			h.BusinessHandler.RegisterClients(clients)
+		}
+
+		if h.ClientPrivacyHandler == nil {
+			log.Println("WARN: client privacy handler is nil - routes will be missing")
+		} else {
+			h.ClientPrivacyHandler.RegisterClients(clients)
+		}
 	}
 
 	// accounts
@@ -41,6 +67,38 @@ func newRouter(h *handler.Dependencies) *gin.Engine {
 	} else {
 		accounts := v1.Group("/accounts")
 		h.AccountHandler.Register(accounts)
+
+		if h.HoldHandler == nil {
+			log.Println("WARN: hold handler is nil - routes will be missing")
+		} else {
+			h.HoldHandler.Register(accounts)
+		}
+
+		if h.InterestHandler == nil {
+			log.Println("WARN: interest handler is nil - routes will be missing")
+		} else {
+			h.InterestHandler.RegisterAccounts(accounts)
+			products := v1.Group("/account-products")
+			h.InterestHandler.RegisterProducts(products)
+		}
+
+		if h.StatementHandler == nil {
+			log.Println("WARN: statement handler is nil - routes will be missing")
+		} else {
+			h.StatementHandler.RegisterAccounts(accounts)
+		}
+
+		if h.LimitHandler == nil {
+			log.Println("WARN: limit handler is nil - routes will be missing")
+		} else {
+			h.LimitHandler.RegisterAccounts(accounts)
+		}
+
+		if h.AccountHolderHandler == nil {
+			log.Println("WARN: account holder handler is nil - routes will be missing")
+		} else {
+			h.AccountHolderHandler.RegisterAccounts(accounts)
+		}
 	}
 
 	//transactions
@@ -51,6 +109,97 @@ func newRouter(h *handler.Dependencies) *gin.Engine {
 		h.TransactionHandler.Register(transactions)
 	}
 
+	// fx
+	if h == nil || h.FXHandler == nil {
+		log.Println("WARN: fx handler is nil - routes will be missing")
+	} else {
+		fxGroup := v1.Group("/fx")
+		h.FXHandler.Register(fxGroup)
+	}
+
+	// standing orders
+	if h == nil || h.StandingOrderHandler == nil {
+		log.Println("WARN: standing order handler is nil - routes will be missing")
+	} else {
+		standingOrders := v1.Group("/standing-orders")
+		h.StandingOrderHandler.Register(standingOrders)
+	}
+
+	// fees
+	if h == nil || h.FeeHandler == nil {
+		log.Println("WARN: fee handler is nil - routes will be missing")
+	} else {
+		fees := v1.Group("/fees")
+		h.FeeHandler.Register(fees)
+	}
+
+	// payment batches
+	if h == nil || h.PaymentBatchHandler == nil {
+		log.Println("WARN: payment batch handler is nil - routes will be missing")
+	} else {
+		paymentBatches := v1.Group("/payment-batches")
+		h.PaymentBatchHandler.Register(paymentBatches)
+	}
+
+	// confirmation of payee
+	if h == nil || h.PayeeHandler == nil {
+		log.Println("WARN: payee handler is nil - routes will be missing")
+	} else {
+		payees := v1.Group("/payees")
+		h.PayeeHandler.Register(payees)
+	}
+
+	// admin: operator-only routes, every call must identify its actor
+	admin := v1.Group("/admin", middleware.RequireActor())
+	if h != nil && h.AccountHandler != nil {
+		h.AccountHandler.RegisterAdmin(admin)
+	}
+	if h != nil && h.ClientHandler != nil {
+		h.ClientHandler.RegisterAdmin(admin)
+	}
+	if h == nil || h.AuditHandler == nil {
+		log.Println("WARN: audit handler is nil - routes will be missing")
+	} else {
+		h.AuditHandler.Register(admin)
+	}
+	if h != nil && h.FeeHandler != nil {
+		h.FeeHandler.RegisterAdmin(admin)
+	}
+	if h != nil && h.FXHandler != nil {
+		h.FXHandler.RegisterAdmin(admin)
+	}
+	if h != nil && h.InterestHandler != nil {
+		h.InterestHandler.RegisterAdmin(admin)
+	}
+	if h != nil && h.LimitHandler != nil {
+		h.LimitHandler.RegisterAdmin(admin)
+	}
+	if h == nil || h.ApprovalHandler == nil {
+		log.Println("WARN: approval handler is nil - routes will be missing")
+	} else {
+		h.ApprovalHandler.Register(admin)
+	}
+	if h == nil || h.MonitoringHandler == nil {
+		log.Println("WARN: monitoring handler is nil - routes will be missing")
+	} else {
+		h.MonitoringHandler.Register(admin)
+	}
+	if h == nil || h.CaseHandler == nil {
+		log.Println("WARN: case handler is nil - routes will be missing")
+	} else {
+		h.CaseHandler.Register(admin)
+	}
+	if h == nil || h.ScreeningHandler == nil {
+		log.Println("WARN: screening handler is nil - routes will be missing")
+	} else {
+		h.ScreeningHandler.Register(admin)
+	}
+	if h == nil || h.ClientMergeHandler == nil {
+		log.Println("WARN: client merge handler is nil - routes will be missing")
+	} else {
+		h.ClientMergeHandler.Register(admin)
+	}
+
 	r.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error":      "route not found",
@@ -11,13 +11,24 @@ import (
 	rediscache "basic-gin/internal/cache/redis"
 	"basic-gin/internal/config"
 	"basic-gin/internal/db"
+	"basic-gin/internal/fx"
 	"basic-gin/internal/handler"
+	"basic-gin/internal/mail"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/monitoring"
 	"basic-gin/internal/repository"
+	"basic-gin/internal/sanctions"
 	"basic-gin/internal/service"
+	"basic-gin/internal/worker"
 )
 
 func Run(ctx context.Context) error {
+	// The workers started below act for no client or operator.
+	ctx = middleware.WithSystem(ctx)
 	config.Load()
+	if err := config.App.AccountNumbers.Validate(); err != nil {
+		return fmt.Errorf("account number scheme: %w", err)
+	}
 
 	pool, err := db.Connect(ctx, config.App.PostgresDSN)
 
@@ -44,16 +55,127 @@ func Run(ctx context.Context) error {
 		}
 	}
 
+	audit_repo := repository.NewAuditRepository(pool)
+	audit_service := service.NewAuditService(audit_repo)
+
 	client_repo := repository.NewClientRepository(pool)
-	client_service := service.NewClientService(*client_repo, c)
+	screener := sanctions.NewScreener()
+	if err := screener.LoadFile(config.App.SanctionsListFile); err != nil {
+		log.Printf("sanctions list not loaded, clients are not screened: %v", err)
+	}
+	screening_repo := repository.NewScreeningRepository(pool)
+	screening_service := service.NewScreeningService(screener, screening_repo, client_repo, audit_repo)
+	go worker.Every(ctx, "sanctions-rescreen", config.App.SanctionsReloadInterval, screening_service.RescreenIfChanged)
+	var mailer mail.Mailer
+	switch config.App.Mailer {
+	case "smtp":
+		mailer = mail.NewSMTPMailer(config.App.SMTPAddr, config.App.MailFrom, config.App.SMTPUsername, config.App.SMTPPassword)
+	case "memory":
+		mailer = mail.NewMemoryOutbox(config.App.MailFrom)
+	default:
+		outbox, err := mail.NewFileOutbox(config.App.MailOutboxDir, config.App.MailFrom)
+		if err != nil {
+			log.Printf("mail outbox %s unusable, keeping mail in memory: %v", config.App.MailOutboxDir, err)
+			mailer = mail.NewMemoryOutbox(config.App.MailFrom)
+		} else {
+			mailer = outbox
+		}
+	}
+	client_contact_repo := repository.NewClientContactRepository(pool)
+	email_verification_repo := repository.NewEmailVerificationRepository(pool)
+	email_verification_service := service.NewEmailVerificationService(email_verification_repo, client_repo, client_contact_repo, audit_repo, mailer)
+	client_service := service.NewClientService(*client_repo, screening_service, email_verification_service, c)
+	client_contact_service := service.NewClientContactService(client_contact_repo, client_repo, client_service)
 
 	account_repo := repository.NewAccountRepository(pool)
-	account_service := service.NewAccountService(account_repo, client_service, c)
-
 	transaction_repo := repository.NewTransactionRepository(pool)
-	transaction_service := service.NewTransactionService(transaction_repo, account_repo)
-
-	deps := handler.NewDependencies(client_service, account_service, transaction_service)
+	fee_repo := repository.NewFeeRepository(pool)
+	fee_service := service.NewFeeService(fee_repo, account_repo, transaction_repo, audit_repo)
+	fee_service.WarnMissingRevenueAccounts(ctx)
+	approval_repo := repository.NewApprovalRepository(pool)
+	account_holder_repo := repository.NewAccountHolderRepository(pool)
+	limit_repo := repository.NewLimitRepository(pool)
+	limit_service := service.NewLimitService(limit_repo, account_repo, transaction_repo, audit_repo, approval_repo, account_holder_repo)
+	monitoring_engine := monitoring.NewEngine()
+	if err := monitoring_engine.LoadFile(config.App.MonitoringRulesFile); err != nil {
+		log.Printf("monitoring rules not loaded: %v", err)
+	}
+	monitoring_repo := repository.NewMonitoringRepository(pool)
+	case_repo := repository.NewCaseRepository(pool)
+	monitoring_service := service.NewMonitoringService(monitoring_engine, monitoring_repo, case_repo, transaction_repo, audit_repo)
+	go worker.Every(ctx, "monitoring-rules", config.App.MonitoringReloadInterval, monitoring_service.ReloadIfChanged)
+	business_party_repo := repository.NewBusinessPartyRepository(pool)
+	business_service := service.NewBusinessService(business_party_repo, client_repo, screening_repo, audit_repo)
+	account_service := service.NewAccountService(account_repo, transaction_repo, audit_repo, approval_repo, account_holder_repo, client_service, fee_service, limit_service, monitoring_service, screening_service, business_service, c)
+	account_holder_service := service.NewAccountHolderService(account_holder_repo, account_repo, client_repo, audit_repo, screening_service, account_service)
+
+	interest_repo := repository.NewInterestRepository(pool)
+	interest_service := service.NewInterestService(interest_repo, account_repo, transaction_repo, audit_repo, account_service)
+	go worker.Every(ctx, "interest", config.App.InterestAccrualInterval, interest_service.RunDue)
+
+	statement_repo := repository.NewStatementRepository(pool)
+	statement_service := service.NewStatementService(statement_repo, account_repo)
+
+	rates := fx.NewRateTable()
+	if err := rates.LoadFile(config.App.FXRatesFile); err != nil {
+		log.Printf("fx rates not loaded: %v", err)
+	}
+	fx_quote_repo := repository.NewFXQuoteRepository(pool)
+	fx_service := service.NewFXService(rates, fx_quote_repo)
+
+	payee_service := service.NewPayeeService(account_repo, client_repo, account_holder_repo)
+	beneficiary_repo := repository.NewBeneficiaryRepository(pool)
+	beneficiary_service := service.NewBeneficiaryService(beneficiary_repo, account_repo, account_holder_repo, client_repo, audit_repo, payee_service)
+	transaction_service := service.NewTransactionService(transaction_repo, account_repo, fx_quote_repo, audit_repo, approval_repo, fee_service, limit_service, monitoring_service, payee_service, beneficiary_service)
+
+	hold_repo := repository.NewHoldRepository(pool)
+	hold_service := service.NewHoldService(hold_repo, account_repo, approval_repo, audit_repo, account_service, transaction_service)
+	go worker.Every(ctx, "hold-expiry", config.App.HoldExpiryInterval, hold_service.ExpireDue)
+
+	approval_service := service.NewApprovalService(approval_repo, audit_repo, account_service, transaction_service, hold_service, limit_service)
+	go worker.Every(ctx, "approval-expiry", config.App.ApprovalExpiryInterval, approval_service.ExpireDue)
+
+	case_service := service.NewCaseService(case_repo, monitoring_repo, account_repo, transaction_repo, client_repo, audit_repo, account_service, approval_service)
+
+	client_merge_repo := repository.NewClientMergeRepository(pool)
+	client_merge_service := service.NewClientMergeService(client_merge_repo, client_repo, audit_repo, client_service, account_service)
+	client_erasure_repo := repository.NewClientErasureRepository(pool)
+	client_privacy_service := service.NewClientPrivacyService(client_erasure_repo, client_repo, client_contact_repo, account_repo,
+		account_holder_repo, transaction_repo, audit_repo, client_service, account_service)
+
+	standing_order_repo := repository.NewStandingOrderRepository(pool)
+	standing_order_service := service.NewStandingOrderService(standing_order_repo, account_repo, transaction_service)
+	go worker.Every(ctx, "standing-orders", config.App.StandingOrderInterval, standing_order_service.RunDue)
+
+	payment_batch_repo := repository.NewPaymentBatchRepository(pool)
+	payment_batch_service := service.NewPaymentBatchService(payment_batch_repo, account_repo, transaction_service)
+	go worker.Every(ctx, "payment-batches", config.App.PaymentBatchInterval, payment_batch_service.RunDue)
+
+	deps := handler.NewDependencies(handler.Services{
+		Client:        client_service,
+		Account:       account_service,
+		Transaction:   transaction_service,
+		FX:            fx_service,
+		Hold:          hold_service,
+		Audit:         audit_service,
+		StandingOrder: standing_order_service,
+		Fee:           fee_service,
+		Interest:      interest_service,
+		Statement:     statement_service,
+		PaymentBatch:  payment_batch_service,
+		Payee:         payee_service,
+		Beneficiary:   beneficiary_service,
+		Limit:         limit_service,
+		Approval:      approval_service,
+		Monitoring:    monitoring_service,
+		Case:          case_service,
+		Screening:     screening_service,
+		ClientMerge:   client_merge_service,
+		ClientContact: client_contact_service,
+		Business:      business_service,
+		AccountHolder: account_holder_service,
+		ClientPrivacy: client_privacy_service,
+	})
 
 	router := newRouter(deps)
 
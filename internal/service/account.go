@@ -2,30 +2,71 @@ package service
 
 import (
 	"basic-gin/internal/cache"
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
 	"basic-gin/internal/dto"
 	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
 	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
 	"basic-gin/internal/repository"
 	"context"
-	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math/big"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// ErrNotFound is matched by errors for an account, client or other record
+// that does not exist or that the acting client may not see.
+var ErrNotFound = repository.ErrNotFound
+
 type AccountService struct {
-	accountRepository repository.AccountRepository
-	clientService     ClientService
-	cache             cache.Cache
+	accountRepository     repository.AccountRepository
+	transactionRepository repository.TransactionRepository
+	auditRepository       repository.AuditRepository
+	approvalRepository    repository.ApprovalRepository
+	holderRepository      repository.AccountHolderRepository
+	clientService         ClientService
+	feeService            FeeService
+	limitService          LimitService
+	monitoringService     MonitoringService
+	screeningService      ScreeningService
+	businessService       BusinessService
+	cache                 cache.Cache
 }
 
-func NewAccountService(accountRepository *repository.AccountRepository, clientService *ClientService, cache cache.Cache) *AccountService {
+func NewAccountService(
+	accountRepository *repository.AccountRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+	approvalRepository *repository.ApprovalRepository,
+	holderRepository *repository.AccountHolderRepository,
+	clientService *ClientService,
+	feeService *FeeService,
+	limitService *LimitService,
+	monitoringService *MonitoringService,
+	screeningService *ScreeningService,
+	businessService *BusinessService,
+	cache cache.Cache,
+) *AccountService {
 	return &AccountService{
-		accountRepository: *accountRepository,
-		clientService:     *clientService,
-		cache:             cache,
+		accountRepository:     *accountRepository,
+		transactionRepository: *transactionRepository,
+		auditRepository:       *auditRepository,
+		approvalRepository:    *approvalRepository,
+		holderRepository:      *holderRepository,
+		clientService:         *clientService,
+		feeService:            *feeService,
+		limitService:          *limitService,
+		monitoringService:     *monitoringService,
+		screeningService:      *screeningService,
+		businessService:       *businessService,
+		cache:                 cache,
 	}
 }
 
@@ -80,7 +121,7 @@ func (s *AccountService) GetByClientId(ctx context.Context, id int) ([]*dto.Acco
 		}
 	}
 
-	accounts, err := s.accountRepository.GetByClientId(ctx, id)
+	accounts, err := s.accountRepository.GetByHolder(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("get accounts by client id: %w", err)
 	}
@@ -96,31 +137,60 @@ func (s *AccountService) GetByClientId(ctx context.Context, id int) ([]*dto.Acco
 	return resp, nil
 }
 
-func (s *AccountService) Save(ctx context.Context, clientId int) (dto.AccountResponse, error) {
+func (s *AccountService) Save(ctx context.Context, in dto.AccountCreate) (dto.AccountResponse, error) {
+	clientId := in.ClientID
 	if clientId <= 0 {
 		return dto.AccountResponse{}, fmt.Errorf("invalid client id")
 	}
 
-	if _, err := s.clientService.GetById(ctx, int64(clientId)); err != nil {
+	code := currency.Normalize(in.Currency)
+	if code == "" {
+		code = currency.Normalize(config.App.DefaultCurrency)
+	}
+	if !currency.IsSupported(code) {
+		return dto.AccountResponse{}, fmt.Errorf("unsupported currency: %q", in.Currency)
+	}
+
+	client, err := s.clientService.GetById(ctx, int64(clientId))
+	if err != nil {
 		return dto.AccountResponse{}, fmt.Errorf("client not found: %w", err)
 	}
+	if client.MergedInto != 0 {
+		return dto.AccountResponse{}, fmt.Errorf("client %d %w into client %d", clientId, ErrClientMerged, client.MergedInto)
+	}
+	if client.ErasedAt != "" {
+		return dto.AccountResponse{}, fmt.Errorf("client %d %w", clientId, ErrClientErased)
+	}
+	if err := s.screeningService.EnsureClear(ctx, clientId); err != nil {
+		return dto.AccountResponse{}, err
+	}
+	if client.Type == model.ClientTypeBusiness {
+		if err := s.businessService.EnsureKYB(ctx, int64(clientId)); err != nil {
+			return dto.AccountResponse{}, err
+		}
+	}
 
 	const maxAttempts = 3
 	var saved *model.Account
-	var err error
 
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		number, genErr := config.App.AccountNumbers.Generate()
+		if genErr != nil {
+			return dto.AccountResponse{}, fmt.Errorf("generate account number: %w", genErr)
+		}
 		acc := model.Account{
 			ClientId:      clientId,
-			AccountNumber: generateAccountNumber(16),
+			AccountNumber: number,
 			Balance:       0,
+			AccountType:   strings.ToLower(strings.TrimSpace(in.AccountType)),
+			Currency:      code,
 		}
 
 		saved, err = s.accountRepository.CreateAccount(ctx, &acc)
 		if err == nil {
 			break
 		}
-		if strings.Contains(strings.ToLower(err.Error()), "account_number already exists") {
+		if errors.Is(err, ErrDuplicate) {
 			if attempt < maxAttempts {
 				continue
 			}
@@ -143,12 +213,29 @@ func (s *AccountService) Save(ctx context.Context, clientId int) (dto.AccountRes
 		}
 	}
 
-	//simulacija KYC
-	time.Sleep(10 * time.Second)
-
 	return resp, nil
 }
 
+// DepositByNumber deposits to the account with the given number or IBAN.
+func (s *AccountService) DepositByNumber(ctx context.Context, number string, amount float64) (*dto.AccountResponse, error) {
+	acc, err := resolveAccountNumber(ctx, &s.accountRepository, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.Deposit(ctx, acc.ID, amount)
+}
+
+// WithdrawByNumber withdraws from the account with the given number or IBAN.
+func (s *AccountService) WithdrawByNumber(ctx context.Context, number string, amount float64) (*dto.AccountResponse, error) {
+	acc, err := resolveAccountNumber(ctx, &s.accountRepository, number)
+	if err != nil {
+		return nil, err
+	}
+	return s.Withdraw(ctx, acc.ID, amount)
+}
+
+// Deposit credits the account. Deposits monitoring holds for review wait for
+// an operator's approval.
 func (s *AccountService) Deposit(ctx context.Context, id int, amount float64) (*dto.AccountResponse, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid account id")
@@ -157,6 +244,25 @@ func (s *AccountService) Deposit(ctx context.Context, id int, amount float64) (*
 		return nil, fmt.Errorf("amount must be positive")
 	}
 
+	acc, err := s.accountRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, amount); err != nil {
+		return nil, err
+	}
+	ev := monitoring.Event{Operation: monitoring.OperationDeposit, AccountID: id, Amount: amount, Currency: acc.Currency}
+	verdict, err := s.monitoringService.screen(ctx, ev)
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Action == monitoring.ActionReview {
+		err := requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationDeposit,
+			"account", strconv.Itoa(id), depositPayload{AccountID: id, Amount: amount})
+		s.monitoringService.alert(ctx, ev, verdict, "", approvalID(err))
+		return nil, err
+	}
+
 	tx, err := s.accountRepository.Pool().Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
@@ -165,7 +271,7 @@ func (s *AccountService) Deposit(ctx context.Context, id int, amount float64) (*
 		_ = tx.Rollback(ctx)
 	}()
 
-	updated, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, id, amount)
+	t, updated, err := s.depositTx(ctx, tx, id, amount)
 	if err != nil {
 		return nil, err
 	}
@@ -173,10 +279,10 @@ func (s *AccountService) Deposit(ctx context.Context, id int, amount float64) (*
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
+	s.monitoringService.alert(ctx, ev, verdict, t.ID, 0)
 
+	s.evict(ctx, updated)
 	if s.cache != nil {
-		_ = s.cache.Del(ctx, s.keyAccount(updated.ID))
-		_ = s.cache.Del(ctx, s.keyAccountsByClient(updated.ClientId))
 		if b, mErr := json.Marshal(mapper.AccountToResponse(updated)); mErr == nil {
 			_ = s.cache.Set(ctx, s.keyAccount(updated.ID), b, 5*time.Minute)
 		}
@@ -185,6 +291,36 @@ func (s *AccountService) Deposit(ctx context.Context, id int, amount float64) (*
 	return mapper.AccountToResponse(updated), nil
 }
 
+// depositTx posts the deposit inside the caller's transaction.
+func (s *AccountService) depositTx(ctx context.Context, tx pgx.Tx, id int, amount float64) (*model.Transaction, *model.Account, error) {
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, amount); err != nil {
+		return nil, nil, err
+	}
+
+	updated, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, id, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := &model.Transaction{
+		Type:        model.TransactionTypeDeposit,
+		ToAccountID: id,
+		Amount:      amount,
+		Currency:    acc.Currency,
+	}
+	if err := s.transactionRepository.SaveTx(ctx, tx, t); err != nil {
+		return nil, nil, err
+	}
+	return t, updated, nil
+}
+
+// Withdraw debits the account. A withdrawal made by an operator waits for a
+// second person when withdrawals are configured for approval, as does one
+// monitoring holds for review.
 func (s *AccountService) Withdraw(ctx context.Context, id int, amount float64) (*dto.AccountResponse, error) {
 	if id <= 0 {
 		return nil, fmt.Errorf("invalid account id")
@@ -193,13 +329,37 @@ func (s *AccountService) Withdraw(ctx context.Context, id int, amount float64) (
 		return nil, fmt.Errorf("amount must be positive")
 	}
 
+	acc, err := s.accountRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, amount); err != nil {
+		return nil, err
+	}
+	clientID := middleware.ClientIDFromContext(ctx)
+	if err := s.limitService.checkHolder(ctx, acc, clientID, amount); err != nil {
+		return nil, err
+	}
+	ev := monitoring.Event{Operation: monitoring.OperationWithdrawal, AccountID: id, Amount: amount, Currency: acc.Currency}
+	verdict, err := s.monitoringService.screen(ctx, ev)
+	if err != nil {
+		return nil, err
+	}
+	operator := middleware.ActorFromContext(ctx) != "" && needsApproval(model.ApprovalOperationWithdrawal)
+	if operator || verdict.Action == monitoring.ActionReview {
+		err := requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationWithdrawal,
+			"account", strconv.Itoa(id), withdrawalPayload{AccountID: id, Amount: amount, ClientID: clientID})
+		s.monitoringService.alert(ctx, ev, verdict, "", approvalID(err))
+		return nil, err
+	}
+
 	tx, err := s.accountRepository.Pool().Begin(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("begin tx: %w", err)
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	updated, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, id, -amount)
+	t, updated, revenue, err := s.withdrawTx(ctx, tx, id, amount, clientID)
 	if err != nil {
 		return nil, err
 	}
@@ -207,10 +367,13 @@ func (s *AccountService) Withdraw(ctx context.Context, id int, amount float64) (
 	if err := tx.Commit(ctx); err != nil {
 		return nil, fmt.Errorf("commit: %w", err)
 	}
+	s.monitoringService.alert(ctx, ev, verdict, t.ID, 0)
+	if revenue != nil {
+		s.evict(ctx, revenue)
+	}
 
+	s.evict(ctx, updated)
 	if s.cache != nil {
-		_ = s.cache.Del(ctx, s.keyAccount(updated.ID))
-		_ = s.cache.Del(ctx, s.keyAccountsByClient(updated.ClientId))
 		if b, mErr := json.Marshal(mapper.AccountToResponse(updated)); mErr == nil {
 			_ = s.cache.Set(ctx, s.keyAccount(updated.ID), b, 5*time.Minute)
 		}
@@ -219,18 +382,252 @@ func (s *AccountService) Withdraw(ctx context.Context, id int, amount float64) (
 	return mapper.AccountToResponse(updated), nil
 }
 
-func generateAccountNumber(n int) string {
-	const digits = "0123456789"
-	if n <= 0 {
-		n = 16
+// withdrawTx posts the withdrawal and its fee inside the caller's
+// transaction; clientID is the holder making it, or 0. Besides the
+// withdrawal it returns the debited account and, when a fee was credited,
+// the revenue account, so the caller can evict both after commit.
+func (s *AccountService) withdrawTx(ctx context.Context, tx pgx.Tx, id int, amount float64, clientID int) (t *model.Transaction, updated, revenue *model.Account, err error) {
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, id, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := lockAccountsTx(ctx, tx, &s.accountRepository, id, feeRevenueAccount(acc.Currency)); err != nil {
+		return nil, nil, nil, err
+	}
+	if acc, err = s.accountRepository.GetByIdTx(ctx, tx, id, true); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, amount); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.limitService.checkHolderTx(ctx, tx, acc, clientID, amount); err != nil {
+		return nil, nil, nil, err
+	}
+	if err := s.limitService.checkTx(ctx, tx, acc, model.LimitOperationWithdrawal, amount); err != nil {
+		return nil, nil, nil, err
+	}
+	fee, err := s.feeService.quoteTx(ctx, tx, acc, model.FeeOperationWithdrawal, amount)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := checkFunds(acc, amount+fee.Amount); err != nil {
+		return nil, nil, nil, err
+	}
+
+	updated, err = s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, id, -amount)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	t = &model.Transaction{
+		Type:          model.TransactionTypeWithdrawal,
+		FromAccountID: id,
+		Amount:        amount,
+		Currency:      acc.Currency,
+		InitiatedBy:   clientID,
+	}
+	if err := s.transactionRepository.SaveTx(ctx, tx, t); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if fee.Amount > 0 {
+		if updated, revenue, err = s.feeService.postTx(ctx, tx, updated, t, fee.Amount); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return t, updated, revenue, nil
+}
+
+// SetOverdraftLimit changes how far below zero the account may go. The
+// change and its reason are written to the audit log in the same transaction.
+// When overdraft changes are configured for approval the change waits for a
+// second person instead.
+func (s *AccountService) SetOverdraftLimit(ctx context.Context, id int, in dto.OverdraftLimitUpdate) (*dto.AccountResponse, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid account id")
+	}
+	if in.Limit < 0 {
+		return nil, fmt.Errorf("overdraft limit cannot be negative")
+	}
+	if strings.TrimSpace(in.Reason) == "" {
+		return nil, fmt.Errorf("reason is required")
+	}
+
+	if needsApproval(model.ApprovalOperationOverdraftLimit) {
+		acc, err := s.accountRepository.GetById(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if err := currency.ValidateAmount(acc.Currency, in.Limit); err != nil {
+			return nil, err
+		}
+		return nil, requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationOverdraftLimit,
+			"account", strconv.Itoa(id), overdraftLimitPayload{AccountID: id, OverdraftLimitUpdate: in})
+	}
+
+	tx, err := s.accountRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	updated, err := s.setOverdraftLimitTx(ctx, tx, id, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.evict(ctx, updated)
+
+	return mapper.AccountToResponse(updated), nil
+}
+
+func (s *AccountService) setOverdraftLimitTx(ctx context.Context, tx pgx.Tx, id int, in dto.OverdraftLimitUpdate) (*model.Account, error) {
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, in.Limit); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.accountRepository.UpdateOverdraftLimitTx(ctx, tx, id, in.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "overdraft_limit.set", "account", strconv.Itoa(id), map[string]any{
+		"old_limit": acc.OverdraftLimit,
+		"new_limit": updated.OverdraftLimit,
+		"currency":  updated.Currency,
+		"reason":    strings.TrimSpace(in.Reason),
+	})); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// Renumber moves every account whose number is not in the configured scheme
+// to a newly generated one, keeping the old number as its legacy number. Each
+// account is renumbered and audited in its own transaction, so the run can be
+// interrupted and repeated. With dryRun nothing is written and the new
+// numbers are only indicative.
+func (s *AccountService) Renumber(ctx context.Context, dryRun bool, fn func(dto.AccountRenumbered)) (int, error) {
+	scheme := config.App.AccountNumbers
+	if err := scheme.Validate(); err != nil {
+		return 0, err
+	}
+	const page = 500
+	count := 0
+	for after := 0; ; {
+		accounts, err := s.accountRepository.ListAfter(ctx, after, page)
+		if err != nil {
+			return count, err
+		}
+		if len(accounts) == 0 {
+			return count, nil
+		}
+		after = accounts[len(accounts)-1].ID
+
+		for _, a := range accounts {
+			if scheme.IsCanonical(a.AccountNumber) {
+				continue
+			}
+			var res dto.AccountRenumbered
+			if dryRun {
+				number, err := scheme.Generate()
+				if err != nil {
+					return count, err
+				}
+				res = dto.AccountRenumbered{ID: a.ID, OldNumber: a.AccountNumber, NewNumber: number}
+			} else if res, err = s.renumberOne(ctx, a.ID); err != nil {
+				return count, fmt.Errorf("account %d: %w", a.ID, err)
+			}
+			count++
+			if fn != nil {
+				fn(res)
+			}
+		}
+	}
+}
+
+func (s *AccountService) renumberOne(ctx context.Context, id int) (dto.AccountRenumbered, error) {
+	const maxAttempts = 3
+	for attempt := 1; ; attempt++ {
+		res, err := s.tryRenumber(ctx, id)
+		if errors.Is(err, ErrDuplicate) && attempt < maxAttempts {
+			continue
+		}
+		return res, err
+	}
+}
+
+func (s *AccountService) tryRenumber(ctx context.Context, id int) (dto.AccountRenumbered, error) {
+	tx, err := s.accountRepository.Pool().Begin(ctx)
+	if err != nil {
+		return dto.AccountRenumbered{}, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return dto.AccountRenumbered{}, err
+	}
+	number, err := config.App.AccountNumbers.Generate()
+	if err != nil {
+		return dto.AccountRenumbered{}, err
+	}
+	updated, err := s.accountRepository.RenumberTx(ctx, tx, id, number)
+	if err != nil {
+		return dto.AccountRenumbered{}, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "account.renumber", "account", strconv.Itoa(id), map[string]any{
+		"old_number": acc.AccountNumber,
+		"new_number": updated.AccountNumber,
+		"scheme":     config.App.AccountNumbers.Kind,
+	})); err != nil {
+		return dto.AccountRenumbered{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return dto.AccountRenumbered{}, fmt.Errorf("commit: %w", err)
+	}
+	s.evict(ctx, updated)
+	return dto.AccountRenumbered{ID: id, OldNumber: acc.AccountNumber, NewNumber: updated.AccountNumber}, nil
+}
+
+// evict drops cached views of a after its balance or holds changed,
+// including the account lists of its joint holders and authorized users.
+func (s *AccountService) evict(ctx context.Context, a *model.Account) {
+	if s.cache == nil {
+		return
+	}
+	keys := []string{s.keyAccount(a.ID), s.keyAccountsByClient(a.ClientId)}
+	if holders, err := s.holderRepository.ClientIDs(ctx, a.ID); err == nil {
+		for _, id := range holders {
+			if id != a.ClientId {
+				keys = append(keys, s.keyAccountsByClient(id))
+			}
+		}
+	}
+	_ = s.cache.Del(ctx, keys...)
+}
+
+// evictMoved drops the cached accounts that changed owner and the account
+// lists of the clients involved.
+func (s *AccountService) evictMoved(ctx context.Context, accountIDs []int, clientIDs ...int) {
+	if s.cache == nil {
+		return
+	}
+	keys := make([]string, 0, len(accountIDs)+len(clientIDs))
+	for _, id := range accountIDs {
+		keys = append(keys, s.keyAccount(id))
 	}
-	b := make([]byte, n)
-	for i := 0; i < n; i++ {
-		// crypto/rand
-		idx, _ := rand.Int(rand.Reader, big.NewInt(int64(len(digits))))
-		b[i] = digits[idx.Int64()]
+	for _, id := range clientIDs {
+		keys = append(keys, s.keyAccountsByClient(id))
 	}
-	return string(b)
+	_ = s.cache.Del(ctx, keys...)
 }
 
 func (s *AccountService) keyAccount(id int) string { return fmt.Sprintf("account:%d", id) }
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AccountHolderService manages who besides the owner may see or use an
+// account. The acting client comes from the X-Client-ID header; without
+// one the request is taken to come from an operator.
+type AccountHolderService struct {
+	accountHolderRepository repository.AccountHolderRepository
+	accountRepository       repository.AccountRepository
+	clientRepository        repository.ClientRepository
+	auditRepository         repository.AuditRepository
+	screeningService        ScreeningService
+	accountService          AccountService
+}
+
+func NewAccountHolderService(
+	accountHolderRepository *repository.AccountHolderRepository,
+	accountRepository *repository.AccountRepository,
+	clientRepository *repository.ClientRepository,
+	auditRepository *repository.AuditRepository,
+	screeningService *ScreeningService,
+	accountService *AccountService,
+) *AccountHolderService {
+	return &AccountHolderService{
+		accountHolderRepository: *accountHolderRepository,
+		accountRepository:       *accountRepository,
+		clientRepository:        *clientRepository,
+		auditRepository:         *auditRepository,
+		screeningService:        *screeningService,
+		accountService:          *accountService,
+	}
+}
+
+// List returns the account's active and pending holders or, with all, also
+// the declined and removed ones.
+func (s *AccountHolderService) List(ctx context.Context, accountID int, all bool) ([]*dto.AccountHolderResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if _, err := s.accountRepository.GetById(ctx, accountID); err != nil {
+		return nil, err
+	}
+	items, err := s.accountHolderRepository.List(ctx, accountID, all)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AccountHoldersToResponseSlice(items), nil
+}
+
+// Add makes a client a pending holder of the account. It becomes active
+// once the client itself and every owner and joint owner other than the
+// one adding it have consented.
+func (s *AccountHolderService) Add(ctx context.Context, accountID int, in dto.AccountHolderCreate) (*dto.AccountHolderResponse, error) {
+	role := strings.ToLower(strings.TrimSpace(in.Role))
+	switch role {
+	case model.HolderRoleAuthorizedUser:
+		if in.SpendLimit <= 0 {
+			return nil, errors.New("an authorized user needs a spend_limit above 0")
+		}
+	case model.HolderRoleJointOwner, model.HolderRoleViewOnly:
+		if in.SpendLimit != 0 {
+			return nil, errors.New("spend_limit is only for authorized users")
+		}
+	case model.HolderRoleOwner:
+		return nil, fmt.Errorf("an account has a single owner; add a %s instead", model.HolderRoleJointOwner)
+	default:
+		return nil, fmt.Errorf("invalid role %q (use %s, %s or %s)", in.Role,
+			model.HolderRoleJointOwner, model.HolderRoleAuthorizedUser, model.HolderRoleViewOnly)
+	}
+	if accountID <= 0 || in.ClientID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if role != model.HolderRoleViewOnly {
+		if err := s.screeningService.EnsureClear(ctx, in.ClientID); err != nil {
+			return nil, err
+		}
+	}
+	requester := middleware.ClientIDFromContext(ctx)
+
+	tx, err := s.accountHolderRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.lockAccount(ctx, tx, accountID); err != nil {
+		return nil, err
+	}
+	if err := s.checkManager(ctx, tx, accountID, requester); err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepository.GetByIdTx(ctx, tx, int64(in.ClientID), false)
+	if err != nil {
+		return nil, err
+	}
+	if client.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", client.ID, ErrClientMerged, client.MergedInto)
+	}
+	if client.ErasedAt != nil {
+		return nil, fmt.Errorf("client %d %w", client.ID, ErrClientErased)
+	}
+	managers, err := s.accountHolderRepository.ManagersTx(ctx, tx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	consenters := []int{in.ClientID}
+	for _, id := range managers {
+		if id != requester && id != in.ClientID {
+			consenters = append(consenters, id)
+		}
+	}
+
+	saved, err := s.accountHolderRepository.CreateTx(ctx, tx, &model.AccountHolder{
+		AccountID:  accountID,
+		ClientID:   in.ClientID,
+		Role:       role,
+		SpendLimit: in.SpendLimit,
+		AddedBy:    requester,
+	}, consenters)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "account.holder_add", "account", strconv.Itoa(accountID), map[string]any{
+		"holder_id":   saved.ID,
+		"client_id":   saved.ClientID,
+		"role":        saved.Role,
+		"spend_limit": saved.SpendLimit,
+		"added_by":    requester,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.AccountHolderToResponse(saved), nil
+}
+
+// Consent records the acting client's consent to a pending holder; the
+// last consent makes the holder active.
+func (s *AccountHolderService) Consent(ctx context.Context, accountID, id int) (*dto.AccountHolderResponse, error) {
+	return s.answer(ctx, accountID, id, true)
+}
+
+// Decline turns a pending holder down on behalf of the acting client, who
+// must be one of those whose consent it needs.
+func (s *AccountHolderService) Decline(ctx context.Context, accountID, id int) (*dto.AccountHolderResponse, error) {
+	return s.answer(ctx, accountID, id, false)
+}
+
+func (s *AccountHolderService) answer(ctx context.Context, accountID, id int, consent bool) (*dto.AccountHolderResponse, error) {
+	if accountID <= 0 || id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	clientID := middleware.ClientIDFromContext(ctx)
+	if clientID == 0 {
+		return nil, errors.New("X-Client-ID header is required to answer for a client")
+	}
+
+	tx, err := s.accountHolderRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.lockAccount(ctx, tx, accountID); err != nil {
+		return nil, err
+	}
+	h, err := s.accountHolderRepository.GetByIdTx(ctx, tx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+	if h.Status != model.HolderStatusPending {
+		return nil, fmt.Errorf("holder %d of account %d is %s, not pending", id, accountID, h.Status)
+	}
+
+	action, status := "account.holder_decline", model.HolderStatusDeclined
+	if consent {
+		action = "account.holder_consent"
+		missing, err := s.accountHolderRepository.ConsentTx(ctx, tx, id, clientID)
+		if err != nil {
+			return nil, err
+		}
+		status = h.Status
+		if missing == 0 {
+			status = model.HolderStatusActive
+		}
+	} else if !slices.ContainsFunc(h.Consents, func(c model.HolderConsent) bool { return c.ClientID == clientID && c.ConsentedAt == nil }) {
+		return nil, fmt.Errorf("no consent of client %d is pending for holder %d", clientID, id)
+	}
+	if status != h.Status {
+		if err := s.accountHolderRepository.SetStatusTx(ctx, tx, id, status); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, action, "account", strconv.Itoa(accountID), map[string]any{
+		"holder_id": id,
+		"client_id": clientID,
+		"status":    status,
+	})); err != nil {
+		return nil, err
+	}
+	updated, err := s.accountHolderRepository.GetByIdTx(ctx, tx, accountID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if updated.Status == model.HolderStatusActive {
+		s.accountService.evictMoved(ctx, nil, updated.ClientID)
+	}
+	return mapper.AccountHolderToResponse(updated), nil
+}
+
+// Remove ends a holder's access. Holders may leave on their own, and an
+// operator may remove anyone but the owner. Otherwise an owner or joint
+// owner may remove authorized users, view-only and pending holders, and
+// only the owner may remove a joint owner.
+func (s *AccountHolderService) Remove(ctx context.Context, accountID, id int) error {
+	if accountID <= 0 || id <= 0 {
+		return errors.New("invalid id")
+	}
+	requester := middleware.ClientIDFromContext(ctx)
+
+	tx, err := s.accountHolderRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.lockAccount(ctx, tx, accountID); err != nil {
+		return err
+	}
+	h, err := s.accountHolderRepository.GetByIdTx(ctx, tx, accountID, id)
+	if err != nil {
+		return err
+	}
+	switch {
+	case h.Role == model.HolderRoleOwner:
+		return fmt.Errorf("the owner of account %d cannot be removed", accountID)
+	case h.Status != model.HolderStatusPending && h.Status != model.HolderStatusActive:
+		return fmt.Errorf("holder %d of account %d is already %s", id, accountID, h.Status)
+	}
+	if requester != 0 && requester != h.ClientID {
+		rh, err := s.accountHolderRepository.GetActiveTx(ctx, tx, accountID, requester)
+		if err != nil {
+			return err
+		}
+		switch {
+		case rh == nil || !rh.CanManage():
+			return fmt.Errorf("%w: client %d may not remove holders of account %d", ErrNotAuthorized, requester, accountID)
+		case h.Role == model.HolderRoleJointOwner && h.Status == model.HolderStatusActive && rh.Role != model.HolderRoleOwner:
+			return fmt.Errorf("%w: only the owner may remove joint owner %d of account %d", ErrNotAuthorized, h.ClientID, accountID)
+		}
+	}
+	if err := s.accountHolderRepository.SetStatusTx(ctx, tx, id, model.HolderStatusRemoved); err != nil {
+		return err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "account.holder_remove", "account", strconv.Itoa(accountID), map[string]any{
+		"holder_id":  id,
+		"client_id":  h.ClientID,
+		"role":       h.Role,
+		"removed_by": requester,
+	})); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	s.accountService.evictMoved(ctx, nil, h.ClientID)
+	return nil
+}
+
+// lockAccount locks the account row, which serializes changes to its
+// holders.
+func (s *AccountHolderService) lockAccount(ctx context.Context, tx pgx.Tx, accountID int) error {
+	_, err := s.accountRepository.GetByIdTx(ctx, tx, accountID, true)
+	return err
+}
+
+// checkManager refuses clients other than the account's owner and joint
+// owners; operators, with no acting client, are let through.
+func (s *AccountHolderService) checkManager(ctx context.Context, tx pgx.Tx, accountID, clientID int) error {
+	if clientID == 0 {
+		return nil
+	}
+	h, err := s.accountHolderRepository.GetActiveTx(ctx, tx, accountID, clientID)
+	if err != nil {
+		return err
+	}
+	if h == nil || !h.CanManage() {
+		return fmt.Errorf("%w: client %d may not add holders to account %d", ErrNotAuthorized, clientID, accountID)
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrApprovalForbidden = errors.New("not allowed to decide this approval")
+
+// ApprovalRequiredError is returned instead of a result when an operation
+// was parked for a second person; Approval is the request that was created.
+type ApprovalRequiredError struct {
+	Approval *dto.ApprovalResponse
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("%s is waiting for approval %d", e.Approval.Operation, e.Approval.ID)
+}
+
+// Payloads the parked operations are executed with on approval. Transfers
+// store their dto.TransactionCreate as is.
+type withdrawalPayload struct {
+	AccountID int     `json:"account_id"`
+	Amount    float64 `json:"amount"`
+	// ClientID is the account holder that asked for the withdrawal, or 0.
+	ClientID int `json:"client_id,omitempty"`
+}
+
+type depositPayload withdrawalPayload
+
+type overdraftLimitPayload struct {
+	AccountID int `json:"account_id"`
+	dto.OverdraftLimitUpdate
+}
+
+type holdCapturePayload struct {
+	AccountID   int     `json:"account_id"`
+	HoldID      int     `json:"hold_id"`
+	Amount      float64 `json:"amount"`
+	ToAccountID int     `json:"to_account_id,omitempty"`
+	// ClientID is the account holder that asked for the capture, or 0.
+	ClientID int `json:"client_id,omitempty"`
+}
+
+type transferLimitPayload struct {
+	Upsert   *dto.TransferLimitUpsert `json:"upsert,omitempty"`
+	DeleteID int                      `json:"delete_id,omitempty"`
+}
+
+func needsApproval(operation string) bool {
+	return config.App.ApprovalOperations[operation]
+}
+
+// approvalID returns the id of the approval err reports as created, or 0.
+func approvalID(err error) int {
+	var pending *ApprovalRequiredError
+	if errors.As(err, &pending) {
+		return pending.Approval.ID
+	}
+	return 0
+}
+
+// requestApproval parks operation on the entity until a second person
+// decides it. On success it returns an *ApprovalRequiredError.
+func requestApproval(ctx context.Context, approvalRepo *repository.ApprovalRepository, auditRepo *repository.AuditRepository, operation, entityType, entityID string, payload any) error {
+	tx, err := approvalRepo.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	pending := requestApprovalTx(ctx, tx, approvalRepo, auditRepo, operation, entityType, entityID, payload)
+	if approvalID(pending) == 0 {
+		return pending
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	return pending
+}
+
+// requestApprovalTx is requestApproval inside the caller's transaction, for
+// an operation whose own bookkeeping must commit or roll back with the
+// approval it waits for.
+func requestApprovalTx(ctx context.Context, tx pgx.Tx, approvalRepo *repository.ApprovalRepository, auditRepo *repository.AuditRepository, operation, entityType, entityID string, payload any) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode approval payload: %w", err)
+	}
+	maker := middleware.ActorFromContext(ctx)
+	if maker == "" {
+		maker = "system"
+	}
+
+	saved, err := approvalRepo.CreateTx(ctx, tx, &model.Approval{
+		Operation:  operation,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Payload:    raw,
+		Maker:      maker,
+		RequestID:  middleware.RequestIDFromContext(ctx),
+		ExpiresAt:  time.Now().Add(config.App.ApprovalTTL),
+	})
+	if err != nil {
+		return err
+	}
+	if err := auditRepo.RecordTx(ctx, tx, auditEntry(ctx, "approval.request", "approval", strconv.Itoa(saved.ID), map[string]any{
+		"operation":   operation,
+		"entity_type": entityType,
+		"entity_id":   entityID,
+	})); err != nil {
+		return err
+	}
+	return &ApprovalRequiredError{Approval: mapper.ApprovalToResponse(saved)}
+}
+
+type ApprovalService struct {
+	approvalRepository repository.ApprovalRepository
+	auditRepository    repository.AuditRepository
+	accountService     AccountService
+	transactionService TransactionService
+	holdService        HoldService
+	limitService       LimitService
+}
+
+func NewApprovalService(
+	approvalRepository *repository.ApprovalRepository,
+	auditRepository *repository.AuditRepository,
+	accountService *AccountService,
+	transactionService *TransactionService,
+	holdService *HoldService,
+	limitService *LimitService,
+) *ApprovalService {
+	return &ApprovalService{
+		approvalRepository: *approvalRepository,
+		auditRepository:    *auditRepository,
+		accountService:     *accountService,
+		transactionService: *transactionService,
+		holdService:        *holdService,
+		limitService:       *limitService,
+	}
+}
+
+func (s *ApprovalService) List(ctx context.Context, status string, limit, offset int) ([]*dto.ApprovalResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.approvalRepository.List(ctx, strings.ToLower(strings.TrimSpace(status)), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ApprovalsToResponseSlice(items), nil
+}
+
+func (s *ApprovalService) GetById(ctx context.Context, id int) (*dto.ApprovalResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	a, err := s.approvalRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ApprovalToResponse(a), nil
+}
+
+// Approve executes the parked operation and records its result in the same
+// transaction, so an operation runs at most once. Approving an approval that
+// was already approved returns it unchanged.
+func (s *ApprovalService) Approve(ctx context.Context, id int) (*dto.ApprovalResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	tx, err := s.approvalRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	a, err := s.lockForDecision(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if a.Status == model.ApprovalStatusApproved {
+		return mapper.ApprovalToResponse(a), nil
+	}
+	if err := checkPending(a); err != nil {
+		return nil, err
+	}
+
+	result, touched, err := s.execute(ctx, tx, a)
+	if err != nil {
+		return nil, fmt.Errorf("approval %d: %w", a.ID, err)
+	}
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("encode approval result: %w", err)
+	}
+	decided, err := s.approvalRepository.DecideTx(ctx, tx, a.ID, model.ApprovalStatusApproved, middleware.ActorFromContext(ctx), "", raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "approval.approve", "approval", strconv.Itoa(a.ID), map[string]any{
+		"operation": a.Operation,
+		"maker":     a.Maker,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	for _, acc := range touched {
+		s.accountService.evict(ctx, acc)
+	}
+	return mapper.ApprovalToResponse(decided), nil
+}
+
+// Reject closes the approval without executing the operation.
+func (s *ApprovalService) Reject(ctx context.Context, id int, in dto.ApprovalReject) (*dto.ApprovalResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	tx, err := s.approvalRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	a, err := s.lockForDecision(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPending(a); err != nil {
+		return nil, err
+	}
+	decided, err := s.approvalRepository.DecideTx(ctx, tx, a.ID, model.ApprovalStatusRejected, middleware.ActorFromContext(ctx), reason, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "approval.reject", "approval", strconv.Itoa(a.ID), map[string]any{
+		"operation": a.Operation,
+		"maker":     a.Maker,
+		"reason":    reason,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.ApprovalToResponse(decided), nil
+}
+
+// ExpireDue marks overdue approvals as expired. It is run periodically by
+// the approval expiry worker.
+func (s *ApprovalService) ExpireDue(ctx context.Context) error {
+	expired, err := s.approvalRepository.ExpireDue(ctx)
+	if err != nil {
+		return err
+	}
+	if len(expired) > 0 {
+		log.Printf("expired %d approvals", len(expired))
+	}
+	return nil
+}
+
+// lockForDecision locks the approval and checks that the actor on ctx may
+// decide it: a configured approver who is not the maker.
+func (s *ApprovalService) lockForDecision(ctx context.Context, tx pgx.Tx, id int) (*model.Approval, error) {
+	checker := middleware.ActorFromContext(ctx)
+	if checker == "" {
+		return nil, fmt.Errorf("%w: missing actor", ErrApprovalForbidden)
+	}
+	if len(config.App.ApprovalApprovers) > 0 && !config.App.ApprovalApprovers[checker] {
+		return nil, fmt.Errorf("%w: %s is not an approver", ErrApprovalForbidden, checker)
+	}
+
+	a, err := s.approvalRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if a.Maker == checker {
+		return nil, fmt.Errorf("%w: %s requested it", ErrApprovalForbidden, checker)
+	}
+	return a, nil
+}
+
+func checkPending(a *model.Approval) error {
+	if a.Status != model.ApprovalStatusPending {
+		return fmt.Errorf("approval %d is %s", a.ID, a.Status)
+	}
+	if !time.Now().Before(a.ExpiresAt) {
+		return fmt.Errorf("approval %d expired at %s", a.ID, a.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// execute runs the parked operation inside tx. It returns what the
+// operation would have returned and the accounts whose cache entries must go
+// after commit.
+func (s *ApprovalService) execute(ctx context.Context, tx pgx.Tx, a *model.Approval) (any, []*model.Account, error) {
+	switch a.Operation {
+	case model.ApprovalOperationWithdrawal:
+		var p withdrawalPayload
+		if err := json.Unmarshal(a.Payload, &p); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		_, updated, revenue, err := s.accountService.withdrawTx(ctx, tx, p.AccountID, p.Amount, p.ClientID)
+		if err != nil {
+			return nil, nil, err
+		}
+		touched := []*model.Account{updated}
+		if revenue != nil {
+			touched = append(touched, revenue)
+		}
+		return mapper.AccountToResponse(updated), touched, nil
+
+	case model.ApprovalOperationDeposit:
+		var p depositPayload
+		if err := json.Unmarshal(a.Payload, &p); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		_, updated, err := s.accountService.depositTx(ctx, tx, p.AccountID, p.Amount)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mapper.AccountToResponse(updated), []*model.Account{updated}, nil
+
+	case model.ApprovalOperationOverdraftLimit:
+		var p overdraftLimitPayload
+		if err := json.Unmarshal(a.Payload, &p); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		updated, err := s.accountService.setOverdraftLimitTx(ctx, tx, p.AccountID, p.OverdraftLimitUpdate)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mapper.AccountToResponse(updated), []*model.Account{updated}, nil
+
+	case model.ApprovalOperationTransfer:
+		var in dto.TransactionCreate
+		if err := json.Unmarshal(a.Payload, &in); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		if in.IdempotencyKey == "" {
+			in.IdempotencyKey = "approval:" + strconv.Itoa(a.ID)
+		}
+		t, _, err := s.transactionService.transferTx(ctx, tx, in)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mapper.TransactionToAdminResponse(t), nil, nil
+
+	case model.ApprovalOperationHoldCapture:
+		var p holdCapturePayload
+		if err := json.Unmarshal(a.Payload, &p); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		settled, _, touched, err := s.holdService.captureTx(ctx, tx, p)
+		if err != nil {
+			return nil, nil, err
+		}
+		return mapper.HoldToResponse(settled), touched, nil
+
+	case model.ApprovalOperationTransferLimit:
+		var p transferLimitPayload
+		if err := json.Unmarshal(a.Payload, &p); err != nil {
+			return nil, nil, fmt.Errorf("decode payload: %w", err)
+		}
+		if p.Upsert != nil {
+			resp, err := s.limitService.upsertTx(ctx, tx, *p.Upsert)
+			return resp, nil, err
+		}
+		resp, err := s.limitService.deleteTx(ctx, tx, p.DeleteID)
+		return resp, nil, err
+	}
+	return nil, nil, fmt.Errorf("unknown operation %q", a.Operation)
+}
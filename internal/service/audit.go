@@ -0,0 +1,49 @@
+package service
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+)
+
+type AuditService struct {
+	auditRepository repository.AuditRepository
+}
+
+func NewAuditService(auditRepository *repository.AuditRepository) *AuditService {
+	return &AuditService{auditRepository: *auditRepository}
+}
+
+func (s *AuditService) List(ctx context.Context, entityType, entityID string, limit, offset int) ([]*dto.AuditEntryResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.auditRepository.List(ctx, entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AuditEntriesToResponseSlice(items), nil
+}
+
+// auditEntry builds an audit record attributed to the actor and request id
+// carried on ctx. Requests without an actor are recorded as "system".
+func auditEntry(ctx context.Context, action, entityType, entityID string, details map[string]any) *model.AuditEntry {
+	actor := middleware.ActorFromContext(ctx)
+	if actor == "" {
+		actor = "system"
+	}
+	return &model.AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Details:    details,
+		RequestID:  middleware.RequestIDFromContext(ctx),
+	}
+}
@@ -0,0 +1,225 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type BeneficiaryService struct {
+	beneficiaryRepository repository.BeneficiaryRepository
+	accountRepository     repository.AccountRepository
+	holderRepository      repository.AccountHolderRepository
+	clientRepository      repository.ClientRepository
+	auditRepository       repository.AuditRepository
+	payeeService          PayeeService
+}
+
+func NewBeneficiaryService(
+	beneficiaryRepository *repository.BeneficiaryRepository,
+	accountRepository *repository.AccountRepository,
+	holderRepository *repository.AccountHolderRepository,
+	clientRepository *repository.ClientRepository,
+	auditRepository *repository.AuditRepository,
+	payeeService *PayeeService,
+) *BeneficiaryService {
+	return &BeneficiaryService{
+		beneficiaryRepository: *beneficiaryRepository,
+		accountRepository:     *accountRepository,
+		holderRepository:      *holderRepository,
+		clientRepository:      *clientRepository,
+		auditRepository:       *auditRepository,
+		payeeService:          *payeeService,
+	}
+}
+
+// Create saves a beneficiary for the client. The account must exist and the
+// name must belong to its holder. Large transfers to the beneficiary are
+// held back for the cooling-off period, except when the account is the
+// client's own.
+func (s *BeneficiaryService) Create(ctx context.Context, clientID int, in dto.BeneficiaryCreate) (*dto.BeneficiaryResponse, error) {
+	if clientID <= 0 {
+		return nil, errors.New("invalid client id")
+	}
+	if _, err := s.clientRepository.GetById(ctx, int64(clientID)); err != nil {
+		return nil, err
+	}
+	nickname := strings.TrimSpace(in.Nickname)
+	if nickname == "" {
+		return nil, errors.New("nickname is required")
+	}
+
+	acc, err := resolveAccountNumber(ctx, &s.accountRepository, in.Account)
+	if err != nil {
+		return nil, err
+	}
+	code := currency.Normalize(in.Currency)
+	if code == "" {
+		code = acc.Currency
+	}
+	if code != acc.Currency {
+		return nil, fmt.Errorf("account %s is held in %s, not %s", acc.AccountNumber, acc.Currency, code)
+	}
+
+	match, holder, err := s.payeeService.check(ctx, acc, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case match == model.PayeeNoMatch:
+		return nil, errors.New("name does not match the holder of the account")
+	case match == model.PayeeCloseMatch && !in.AcceptCloseMatch:
+		return nil, fmt.Errorf("name is a close match to the account holder %q; repeat with accept_close_match to save it", holder)
+	}
+
+	trustedAt := time.Now().UTC().Add(config.App.BeneficiaryCoolingOff)
+	if acc.ClientId == clientID {
+		trustedAt = time.Now().UTC()
+	}
+
+	tx, err := s.beneficiaryRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	saved, err := s.beneficiaryRepository.CreateTx(ctx, tx, &model.Beneficiary{
+		ClientID:      clientID,
+		Nickname:      nickname,
+		AccountID:     acc.ID,
+		AccountNumber: acc.AccountNumber,
+		Name:          strings.TrimSpace(in.Name),
+		Currency:      code,
+		PayeeMatch:    match,
+		TrustedAt:     trustedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "beneficiary.create", "beneficiary", strconv.Itoa(saved.ID), map[string]any{
+		"client_id":      clientID,
+		"account_number": saved.AccountNumber,
+		"name":           saved.Name,
+		"payee_match":    match,
+		"trusted_at":     saved.TrustedAt,
+	})); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.BeneficiaryToResponse(saved), nil
+}
+
+func (s *BeneficiaryService) List(ctx context.Context, clientID int) ([]*dto.BeneficiaryResponse, error) {
+	if clientID <= 0 {
+		return nil, errors.New("invalid client id")
+	}
+	items, err := s.beneficiaryRepository.ListByClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.BeneficiariesToResponseSlice(items), nil
+}
+
+func (s *BeneficiaryService) GetById(ctx context.Context, clientID, id int) (*dto.BeneficiaryResponse, error) {
+	b, err := s.beneficiaryRepository.GetById(ctx, clientID, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.BeneficiaryToResponse(b), nil
+}
+
+func (s *BeneficiaryService) Rename(ctx context.Context, clientID, id int, in dto.BeneficiaryUpdate) (*dto.BeneficiaryResponse, error) {
+	nickname := strings.TrimSpace(in.Nickname)
+	if nickname == "" {
+		return nil, errors.New("nickname is required")
+	}
+	tx, err := s.beneficiaryRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	b, err := s.beneficiaryRepository.RenameTx(ctx, tx, clientID, id, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.BeneficiaryToResponse(b), nil
+}
+
+func (s *BeneficiaryService) Delete(ctx context.Context, clientID, id int) error {
+	tx, err := s.beneficiaryRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	b, err := s.beneficiaryRepository.DeleteTx(ctx, tx, clientID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "beneficiary.delete", "beneficiary", strconv.Itoa(id), map[string]any{
+		"client_id":      clientID,
+		"account_number": b.AccountNumber,
+		"name":           b.Name,
+	})); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// destination points a transfer at its beneficiary's account.
+func (s *BeneficiaryService) destination(ctx context.Context, in *dto.TransactionCreate) (*model.Beneficiary, error) {
+	if in.ToAccount != "" {
+		return nil, errors.New("give either beneficiary_id or a destination account, not both")
+	}
+	b, err := s.beneficiaryRepository.GetByIdAnyClient(ctx, in.BeneficiaryID)
+	if err != nil {
+		return nil, err
+	}
+	in.ToAccountID = b.AccountID
+	return b, nil
+}
+
+// checkTransfer makes sure a transfer to b is made by the client who saved
+// it, from an account that client may debit, and holds back large amounts
+// while b is new. Without an acting client, as for operators, the client who
+// saved b must still be able to debit the account.
+func (s *BeneficiaryService) checkTransfer(ctx context.Context, b *model.Beneficiary, fromAccountID int, amount float64) error {
+	from, err := s.accountRepository.GetById(ctx, fromAccountID)
+	if err != nil {
+		return err
+	}
+	// Other clients' beneficiaries are indistinguishable from missing ones.
+	if actor := middleware.ClientIDFromContext(ctx); actor != 0 && actor != b.ClientID {
+		return fmt.Errorf("beneficiary %d %w", b.ID, ErrNotFound)
+	}
+	h, err := s.holderRepository.GetActive(ctx, from.ID, b.ClientID)
+	if err != nil {
+		return err
+	}
+	if h == nil || !h.CanDebit() {
+		return fmt.Errorf("beneficiary %d %w", b.ID, ErrNotFound)
+	}
+	if time.Now().Before(b.TrustedAt) {
+		if limit, ok := config.App.BeneficiaryLargeAmounts[from.Currency]; ok && amount >= float64(limit) {
+			return fmt.Errorf("beneficiary %q was added recently: transfers of %d %s or more are allowed from %s",
+				b.Nickname, limit, from.Currency, b.TrustedAt.UTC().Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrKYBFailed is returned when a business client is not ready to transact.
+var ErrKYBFailed = errors.New("fails KYB checks")
+
+type BusinessService struct {
+	businessPartyRepository repository.BusinessPartyRepository
+	clientRepository        repository.ClientRepository
+	screeningRepository     repository.ScreeningRepository
+	auditRepository         repository.AuditRepository
+}
+
+func NewBusinessService(
+	businessPartyRepository *repository.BusinessPartyRepository,
+	clientRepository *repository.ClientRepository,
+	screeningRepository *repository.ScreeningRepository,
+	auditRepository *repository.AuditRepository,
+) *BusinessService {
+	return &BusinessService{
+		businessPartyRepository: *businessPartyRepository,
+		clientRepository:        *clientRepository,
+		screeningRepository:     *screeningRepository,
+		auditRepository:         *auditRepository,
+	}
+}
+
+// Parties returns the beneficial owners and signatories of business client
+// businessID.
+func (s *BusinessService) Parties(ctx context.Context, businessID int64) ([]*dto.BusinessPartyResponse, error) {
+	if _, err := s.business(ctx, businessID); err != nil {
+		return nil, err
+	}
+	items, err := s.businessPartyRepository.List(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.BusinessPartiesToResponseSlice(items), nil
+}
+
+// AddParty links an individual client to the business as a beneficial owner
+// or signatory. Owners' shares may not add up to more than 100%.
+func (s *BusinessService) AddParty(ctx context.Context, businessID int64, in dto.BusinessPartyCreate) (*dto.BusinessPartyResponse, error) {
+	role := strings.ToLower(strings.TrimSpace(in.Role))
+	switch role {
+	case model.BusinessRoleBeneficialOwner:
+		if in.OwnershipPercent <= 0 || in.OwnershipPercent > 100 {
+			return nil, errors.New("a beneficial owner needs an ownership_percent above 0 and at most 100")
+		}
+	case model.BusinessRoleSignatory:
+		if in.OwnershipPercent != 0 {
+			return nil, errors.New("ownership_percent is only for beneficial owners")
+		}
+	default:
+		return nil, fmt.Errorf("invalid role %q (use %s or %s)", in.Role, model.BusinessRoleBeneficialOwner, model.BusinessRoleSignatory)
+	}
+	if businessID <= 0 || in.ClientID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+
+	tx, err := s.businessPartyRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// The business row lock serializes changes to its parties, so the
+	// ownership total below stays true until commit.
+	business, err := s.clientRepository.GetByIdTx(ctx, tx, businessID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkBusiness(business); err != nil {
+		return nil, err
+	}
+	person, err := s.clientRepository.GetByIdTx(ctx, tx, in.ClientID, false)
+	if err != nil {
+		return nil, err
+	}
+	if person.IsBusiness() {
+		return nil, fmt.Errorf("client %d is a business: owners and signatories must be individuals", person.ID)
+	}
+	if person.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", person.ID, ErrClientMerged, person.MergedInto)
+	}
+	if person.ErasedAt != nil {
+		return nil, fmt.Errorf("client %d %w", person.ID, ErrClientErased)
+	}
+	if role == model.BusinessRoleBeneficialOwner {
+		total, err := s.businessPartyRepository.OwnershipTx(ctx, tx, businessID)
+		if err != nil {
+			return nil, err
+		}
+		if total+in.OwnershipPercent > 100 {
+			return nil, fmt.Errorf("ownership %w: beneficial owners of business %d already hold %.2f%%", ErrLimitExceeded, businessID, total)
+		}
+	}
+
+	saved, err := s.businessPartyRepository.CreateTx(ctx, tx, &model.BusinessParty{
+		BusinessID:       businessID,
+		PersonID:         person.ID,
+		Role:             role,
+		OwnershipPercent: in.OwnershipPercent,
+		Title:            strings.TrimSpace(in.Title),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "business.party_add", "client", strconv.FormatInt(businessID, 10), map[string]any{
+		"party_id":          saved.ID,
+		"client_id":         saved.PersonID,
+		"role":              saved.Role,
+		"ownership_percent": saved.OwnershipPercent,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.BusinessPartyToResponse(saved), nil
+}
+
+func (s *BusinessService) RemoveParty(ctx context.Context, businessID int64, id int) error {
+	if businessID <= 0 || id <= 0 {
+		return errors.New("invalid id")
+	}
+	tx, err := s.businessPartyRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	business, err := s.clientRepository.GetByIdTx(ctx, tx, businessID, true)
+	if err != nil {
+		return err
+	}
+	if err := checkBusiness(business); err != nil {
+		return err
+	}
+	removed, err := s.businessPartyRepository.DeleteTx(ctx, tx, businessID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "business.party_remove", "client", strconv.FormatInt(businessID, 10), map[string]any{
+		"party_id":          removed.ID,
+		"client_id":         removed.PersonID,
+		"role":              removed.Role,
+		"ownership_percent": removed.OwnershipPercent,
+	})); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// KYB runs the know-your-business checks on the business's parties: it
+// needs at least one beneficial owner and one signatory, owners may not
+// hold more than 100% together (as after a merge) and no party may have
+// sanctions screening hits pending review or confirmed. The registration
+// details are checked when the client is saved.
+func (s *BusinessService) KYB(ctx context.Context, businessID int64) (*dto.KYBResponse, error) {
+	if _, err := s.business(ctx, businessID); err != nil {
+		return nil, err
+	}
+	parties, err := s.businessPartyRepository.List(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dto.KYBResponse{
+		ClientID: businessID,
+		Problems: []string{},
+		Parties:  mapper.BusinessPartiesToResponseSlice(parties),
+	}
+	owners, signatories := 0, 0
+	for _, p := range parties {
+		if p.Role == model.BusinessRoleBeneficialOwner {
+			owners++
+			out.OwnershipPercent += p.OwnershipPercent
+		} else {
+			signatories++
+		}
+		n, err := s.screeningRepository.CountBlocking(ctx, int(p.PersonID))
+		if err != nil {
+			return nil, err
+		}
+		if n > 0 {
+			out.Problems = append(out.Problems, fmt.Sprintf("%s %d (%s) has %d screening hits to resolve",
+				strings.ReplaceAll(p.Role, "_", " "), p.PersonID, p.PersonName, n))
+		}
+	}
+	if owners == 0 {
+		out.Problems = append(out.Problems, "no beneficial owner is recorded")
+	}
+	if out.OwnershipPercent > 100 {
+		out.Problems = append(out.Problems, fmt.Sprintf("beneficial owners hold %.2f%% together", out.OwnershipPercent))
+	}
+	if signatories == 0 {
+		out.Problems = append(out.Problems, "no authorized signatory is recorded")
+	}
+	out.Passed = len(out.Problems) == 0
+	return out, nil
+}
+
+// EnsureKYB fails while business client businessID does not pass the KYB
+// checks.
+func (s *BusinessService) EnsureKYB(ctx context.Context, businessID int64) error {
+	res, err := s.KYB(ctx, businessID)
+	if err != nil {
+		return err
+	}
+	if !res.Passed {
+		return fmt.Errorf("business client %d %w: %s", businessID, ErrKYBFailed, strings.Join(res.Problems, "; "))
+	}
+	return nil
+}
+
+func (s *BusinessService) business(ctx context.Context, businessID int64) (*model.Client, error) {
+	if businessID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	c, err := s.clientRepository.GetById(ctx, businessID)
+	if err != nil {
+		return nil, err
+	}
+	return c, checkBusiness(c)
+}
+
+func checkBusiness(c *model.Client) error {
+	if !c.IsBusiness() {
+		return fmt.Errorf("client %d is not a business", c.ID)
+	}
+	if c.MergedInto != 0 {
+		return fmt.Errorf("client %d %w into client %d", c.ID, ErrClientMerged, c.MergedInto)
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// caseTransitions lists the statuses a case may move to from each status.
+// Closed cases stay closed; later alerts open a new case.
+var caseTransitions = map[string][]string{
+	model.CaseStatusOpen: {
+		model.CaseStatusInvestigating, model.CaseStatusEscalated,
+		model.CaseStatusClosedFalsePositive, model.CaseStatusClosedReported,
+	},
+	model.CaseStatusInvestigating: {
+		model.CaseStatusEscalated,
+		model.CaseStatusClosedFalsePositive, model.CaseStatusClosedReported,
+	},
+	model.CaseStatusEscalated: {
+		model.CaseStatusInvestigating,
+		model.CaseStatusClosedFalsePositive, model.CaseStatusClosedReported,
+	},
+}
+
+type CaseService struct {
+	caseRepository        repository.CaseRepository
+	monitoringRepository  repository.MonitoringRepository
+	accountRepository     repository.AccountRepository
+	transactionRepository repository.TransactionRepository
+	clientRepository      repository.ClientRepository
+	auditRepository       repository.AuditRepository
+	accountService        AccountService
+	approvalService       ApprovalService
+}
+
+func NewCaseService(
+	caseRepository *repository.CaseRepository,
+	monitoringRepository *repository.MonitoringRepository,
+	accountRepository *repository.AccountRepository,
+	transactionRepository *repository.TransactionRepository,
+	clientRepository *repository.ClientRepository,
+	auditRepository *repository.AuditRepository,
+	accountService *AccountService,
+	approvalService *ApprovalService,
+) *CaseService {
+	return &CaseService{
+		caseRepository:        *caseRepository,
+		monitoringRepository:  *monitoringRepository,
+		accountRepository:     *accountRepository,
+		transactionRepository: *transactionRepository,
+		clientRepository:      *clientRepository,
+		auditRepository:       *auditRepository,
+		accountService:        *accountService,
+		approvalService:       *approvalService,
+	}
+}
+
+func (s *CaseService) List(ctx context.Context, status, assignee string, clientID, limit, offset int) ([]*dto.CaseResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.caseRepository.List(ctx, strings.ToLower(strings.TrimSpace(status)), strings.TrimSpace(assignee), clientID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CasesToResponseSlice(items), nil
+}
+
+// Get returns the case with its client, the client's accounts, its alerts
+// and the transactions they were raised on, notes and attachments.
+func (s *CaseService) Get(ctx context.Context, id int) (*dto.CaseDetailResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	c, err := s.caseRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepository.GetById(ctx, int64(c.ClientID))
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepository.GetByClientId(ctx, c.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	alerts, err := s.monitoringRepository.ListAlertsByCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var txIDs []int
+	for _, a := range alerts {
+		if n, err := strconv.Atoi(a.TransactionID); err == nil {
+			txIDs = append(txIDs, n)
+		}
+	}
+	txs, err := s.transactionRepository.ListByIds(ctx, txIDs)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.caseRepository.ListNotes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := s.caseRepository.ListAttachments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dto.CaseDetailResponse{
+		CaseResponse: *mapper.CaseToResponse(c),
+		Client:       mapper.ClientToResponse(client),
+		Accounts:     mapper.AccountsToResponseSlice(accounts),
+		Alerts:       mapper.AlertsToResponseSlice(alerts),
+		Transactions: make([]*dto.TransactionResponse, 0, len(txs)),
+		Notes:        mapper.CaseNotesToResponseSlice(notes),
+		Attachments:  mapper.CaseAttachmentsToResponseSlice(attachments),
+	}
+	for _, t := range txs {
+		out.Transactions = append(out.Transactions, mapper.TransactionToAdminResponse(t))
+	}
+	return out, nil
+}
+
+func (s *CaseService) Assign(ctx context.Context, id int, in dto.CaseAssign) (*dto.CaseResponse, error) {
+	assignee := strings.TrimSpace(in.Assignee)
+	if assignee != "" && len(config.App.CaseAnalysts) > 0 && !config.App.CaseAnalysts[assignee] {
+		return nil, fmt.Errorf("%s is not a case analyst", assignee)
+	}
+
+	var updated *model.Case
+	err := s.withOpenCase(ctx, id, func(tx pgx.Tx, c *model.Case) error {
+		var err error
+		if updated, err = s.caseRepository.AssignTx(ctx, tx, id, assignee); err != nil {
+			return err
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.assign", "case", strconv.Itoa(id), map[string]any{
+			"from": c.Assignee,
+			"to":   assignee,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CaseToResponse(updated), nil
+}
+
+// UpdateStatus moves the case along its workflow. Closing it needs a note,
+// which is kept on the case and closes its open alerts.
+func (s *CaseService) UpdateStatus(ctx context.Context, id int, in dto.CaseStatusUpdate) (*dto.CaseResponse, error) {
+	status := strings.ToLower(strings.TrimSpace(in.Status))
+	note := strings.TrimSpace(in.Note)
+	closing := status == model.CaseStatusClosedFalsePositive || status == model.CaseStatusClosedReported
+	if closing && note == "" {
+		return nil, errors.New("note is required to close a case")
+	}
+	actor := middleware.ActorFromContext(ctx)
+
+	var updated *model.Case
+	err := s.withOpenCase(ctx, id, func(tx pgx.Tx, c *model.Case) error {
+		if !slices.Contains(caseTransitions[c.Status], status) {
+			return fmt.Errorf("case %d cannot move from %s to %q", id, c.Status, status)
+		}
+		var err error
+		if updated, err = s.caseRepository.UpdateStatusTx(ctx, tx, id, status, actor); err != nil {
+			return err
+		}
+		if note != "" {
+			if _, err := s.caseRepository.AddNoteTx(ctx, tx, &model.CaseNote{CaseID: id, Author: actor, Body: note}); err != nil {
+				return err
+			}
+		}
+		alertsClosed := 0
+		if closing {
+			if alertsClosed, err = s.monitoringRepository.CloseCaseAlertsTx(ctx, tx, id, actor, fmt.Sprintf("case %d %s", id, status)); err != nil {
+				return err
+			}
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.status", "case", strconv.Itoa(id), map[string]any{
+			"from":          c.Status,
+			"to":            status,
+			"note":          note,
+			"alerts_closed": alertsClosed,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CaseToResponse(updated), nil
+}
+
+func (s *CaseService) AddNote(ctx context.Context, id int, in dto.CaseNoteCreate) (*dto.CaseNoteResponse, error) {
+	body := strings.TrimSpace(in.Body)
+	if body == "" {
+		return nil, errors.New("body is required")
+	}
+
+	var saved *model.CaseNote
+	err := s.withOpenCase(ctx, id, func(tx pgx.Tx, _ *model.Case) error {
+		var err error
+		if saved, err = s.caseRepository.AddNoteTx(ctx, tx, &model.CaseNote{CaseID: id, Author: middleware.ActorFromContext(ctx), Body: body}); err != nil {
+			return err
+		}
+		if err := s.caseRepository.TouchTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.note", "case", strconv.Itoa(id), map[string]any{
+			"note_id": saved.ID,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CaseNoteToResponse(saved), nil
+}
+
+func (s *CaseService) AddAttachment(ctx context.Context, id int, in dto.CaseAttachmentCreate) (*dto.CaseAttachmentResponse, error) {
+	var saved *model.CaseAttachment
+	err := s.withOpenCase(ctx, id, func(tx pgx.Tx, _ *model.Case) error {
+		var err error
+		if saved, err = s.caseRepository.AddAttachmentTx(ctx, tx, &model.CaseAttachment{
+			CaseID:      id,
+			FileName:    strings.TrimSpace(in.FileName),
+			ContentType: strings.TrimSpace(in.ContentType),
+			SizeBytes:   in.SizeBytes,
+			SHA256:      strings.ToLower(in.SHA256),
+			StorageURL:  in.StorageURL,
+			UploadedBy:  middleware.ActorFromContext(ctx),
+		}); err != nil {
+			return err
+		}
+		if err := s.caseRepository.TouchTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.attachment", "case", strconv.Itoa(id), map[string]any{
+			"attachment_id": saved.ID,
+			"file_name":     saved.FileName,
+			"sha256":        saved.SHA256,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.CaseAttachmentToResponse(saved), nil
+}
+
+// FreezeAccount stops all debits from an account of the case's client.
+func (s *CaseService) FreezeAccount(ctx context.Context, id int, in dto.CaseAccountAction) (*dto.AccountResponse, error) {
+	return s.setAccountStatus(ctx, id, in, model.AccountStatusFrozen, "case.freeze_account")
+}
+
+func (s *CaseService) UnfreezeAccount(ctx context.Context, id int, in dto.CaseAccountAction) (*dto.AccountResponse, error) {
+	return s.setAccountStatus(ctx, id, in, model.AccountStatusActive, "case.unfreeze_account")
+}
+
+// setAccountStatus records the change as a case note as well, so the case
+// reads as a complete history. Setting the status an account already has
+// changes nothing.
+func (s *CaseService) setAccountStatus(ctx context.Context, id int, in dto.CaseAccountAction, status, action string) (*dto.AccountResponse, error) {
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	var acc *model.Account
+	err := s.withOpenCase(ctx, id, func(tx pgx.Tx, c *model.Case) error {
+		var err error
+		if acc, err = s.accountRepository.GetByIdTx(ctx, tx, in.AccountID, true); err != nil {
+			return err
+		}
+		if acc.ClientId != c.ClientID {
+			return fmt.Errorf("account %d does not belong to the client of case %d", acc.ID, id)
+		}
+		if acc.Status == status {
+			return nil
+		}
+		if acc, err = s.accountRepository.UpdateStatusTx(ctx, tx, acc.ID, status); err != nil {
+			return err
+		}
+		verb := map[string]string{model.AccountStatusFrozen: "Froze", model.AccountStatusActive: "Unfroze"}[status]
+		if _, err := s.caseRepository.AddNoteTx(ctx, tx, &model.CaseNote{
+			CaseID: id,
+			Author: middleware.ActorFromContext(ctx),
+			Body:   fmt.Sprintf("%s account %s: %s", verb, acc.AccountNumber, reason),
+		}); err != nil {
+			return err
+		}
+		if err := s.caseRepository.TouchTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, action, "case", strconv.Itoa(id), map[string]any{
+			"account_id": acc.ID,
+			"reason":     reason,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.accountService.evict(ctx, acc)
+	return mapper.AccountToResponse(acc), nil
+}
+
+// Release approves an operation that monitoring held for review on one of
+// the case's alerts, executing it. The approval rules apply as usual: the
+// analyst must be an approver and may not have requested it.
+func (s *CaseService) Release(ctx context.Context, id int, in dto.CaseRelease) (*dto.ApprovalResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	c, err := s.caseRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if c.Closed() {
+		return nil, fmt.Errorf("case %d is %s", id, c.Status)
+	}
+	alerts, err := s.monitoringRepository.ListAlertsByCase(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.ContainsFunc(alerts, func(a *model.MonitoringAlert) bool { return a.ApprovalID == in.ApprovalID }) {
+		return nil, fmt.Errorf("approval %d is not held on case %d", in.ApprovalID, id)
+	}
+
+	released, err := s.approvalService.Approve(ctx, in.ApprovalID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The approval is decided and audited on its own by now; this records
+	// the release on the case.
+	err = s.withCase(ctx, id, func(tx pgx.Tx, _ *model.Case) error {
+		if _, err := s.caseRepository.AddNoteTx(ctx, tx, &model.CaseNote{
+			CaseID: id,
+			Author: middleware.ActorFromContext(ctx),
+			Body:   fmt.Sprintf("Released %s held for approval %d", released.Operation, released.ID),
+		}); err != nil {
+			return err
+		}
+		if err := s.caseRepository.TouchTx(ctx, tx, id); err != nil {
+			return err
+		}
+		return s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.release", "case", strconv.Itoa(id), map[string]any{
+			"approval_id": released.ID,
+			"operation":   released.Operation,
+		}))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// withOpenCase runs fn in a transaction holding the case locked, refusing
+// closed cases.
+func (s *CaseService) withOpenCase(ctx context.Context, id int, fn func(pgx.Tx, *model.Case) error) error {
+	return s.withCase(ctx, id, func(tx pgx.Tx, c *model.Case) error {
+		if c.Closed() {
+			return fmt.Errorf("case %d is %s", id, c.Status)
+		}
+		return fn(tx, c)
+	})
+}
+
+func (s *CaseService) withCase(ctx context.Context, id int, fn func(pgx.Tx, *model.Case) error) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+	tx, err := s.caseRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	c, err := s.caseRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx, c); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
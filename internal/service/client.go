@@ -2,26 +2,51 @@ package service
 
 import (
 	"basic-gin/internal/cache"
+	"basic-gin/internal/config"
+	"basic-gin/internal/contact"
 	"basic-gin/internal/dto"
 	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
 	"basic-gin/internal/repository"
+	"basic-gin/internal/textmatch"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 )
 
+// ErrDuplicate and ErrClientMerged are returned when an email, registration
+// number or other unique detail is already taken, or when the client was
+// merged into another one.
+var (
+	ErrDuplicate    = repository.ErrDuplicate
+	ErrClientMerged = repository.ErrClientMerged
+)
+
+// ErrClientErased is returned when the client's personal data was erased.
+var ErrClientErased = errors.New("was erased")
+
+// ErrAlreadyVerified is returned when the client's email needs no verification.
+var ErrAlreadyVerified = errors.New("already verified")
+
 type ClientService struct {
-	clientRepository repository.ClientRepository
-	cache            cache.Cache
+	clientRepository         repository.ClientRepository
+	screeningService         ScreeningService
+	emailVerificationService EmailVerificationService
+	cache                    cache.Cache
 }
 
-func NewClientService(clientRepository repository.ClientRepository, cache cache.Cache) *ClientService {
+func NewClientService(clientRepository repository.ClientRepository, screeningService *ScreeningService, emailVerificationService *EmailVerificationService, cache cache.Cache) *ClientService {
 	return &ClientService{
-		clientRepository: clientRepository,
-		cache:            cache,
+		clientRepository:         clientRepository,
+		screeningService:         *screeningService,
+		emailVerificationService: *emailVerificationService,
+		cache:                    cache,
 	}
 }
 
@@ -94,6 +119,16 @@ func (s *ClientService) Save(ctx context.Context, in dto.ClientCreate) (*dto.Cli
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+	if err := validateClient(&client); err != nil {
+		return nil, err
+	}
+
+	if client.Email, err = contact.NormalizeEmail(client.Email); err != nil {
+		return nil, err
+	}
+	if err := s.checkEmailFree(ctx, client.Email); err != nil {
+		return nil, err
+	}
 
 	saved, createErr := s.clientRepository.CreateClient(ctx, client)
 
@@ -101,6 +136,9 @@ func (s *ClientService) Save(ctx context.Context, in dto.ClientCreate) (*dto.Cli
 		return nil, fmt.Errorf("%w", createErr)
 	}
 
+	s.screen(ctx, saved)
+	s.requestVerification(ctx, saved, model.EmailVerificationVerify, saved.Email)
+
 	response := mapper.ClientToResponse(saved)
 
 	if s.cache != nil {
@@ -110,6 +148,8 @@ func (s *ClientService) Save(ctx context.Context, in dto.ClientCreate) (*dto.Cli
 		}
 	}
 
+	response.PossibleDuplicates = s.possibleDuplicates(ctx, saved)
+
 	return &response, nil
 }
 
@@ -120,11 +160,36 @@ func (s *ClientService) Update(ctx context.Context, in dto.ClientUpdate) (*dto.C
 		return nil, fmt.Errorf("%w", validationErr)
 	}
 
-	client, err := mapper.ToClientFromUpdate(in)
+	existing, err := s.clientRepository.GetById(ctx, in.ID)
+	if err != nil {
+		return nil, err
+	}
+	if existing.ErasedAt != nil {
+		return nil, fmt.Errorf("client %d %w", in.ID, ErrClientErased)
+	}
+
+	client, err := mapper.ToClientFromUpdate(in, existing.Type)
 
 	if err != nil {
 		return nil, fmt.Errorf("%w", err)
 	}
+	if err := validateClient(&client); err != nil {
+		return nil, err
+	}
+
+	// A new email only replaces the current one once the client confirms
+	// it; until then the update keeps the current email.
+	newEmail, err := contact.NormalizeEmail(client.Email)
+	if err != nil {
+		return nil, err
+	}
+	client.Email = existing.Email
+	changing := !strings.EqualFold(newEmail, existing.Email)
+	if changing {
+		if err := s.checkEmailFree(ctx, newEmail); err != nil {
+			return nil, err
+		}
+	}
 
 	saved, createErr := s.clientRepository.UpdateClient(ctx, client)
 
@@ -132,7 +197,12 @@ func (s *ClientService) Update(ctx context.Context, in dto.ClientUpdate) (*dto.C
 		return nil, fmt.Errorf("%w", createErr)
 	}
 
+	s.screen(ctx, saved)
+
 	response := mapper.ClientToResponse(saved)
+	if changing && s.requestVerification(ctx, saved, model.EmailVerificationChange, newEmail) {
+		response.PendingEmail = newEmail
+	}
 
 	if s.cache != nil {
 		_ = s.cache.Del(ctx, s.keyClientsAll())
@@ -141,14 +211,190 @@ func (s *ClientService) Update(ctx context.Context, in dto.ClientUpdate) (*dto.C
 		}
 	}
 
+	response.PossibleDuplicates = s.possibleDuplicates(ctx, saved)
+
 	return &response, nil
 }
 
+// screen raises sanctions hits on a saved client early, for review. The
+// client is saved either way: account opening screens again and refuses
+// while hits are open.
+func (s *ClientService) screen(ctx context.Context, c *model.Client) {
+	if _, err := s.screeningService.ScreenClient(ctx, c); err != nil {
+		log.Printf("screening: client %d not screened: %v", c.ID, err)
+	}
+}
+
+// requestVerification mails the client a confirmation token for email and
+// reports whether it went out. Failures are logged: the client can ask for
+// the token again.
+func (s *ClientService) requestVerification(ctx context.Context, c *model.Client, purpose, email string) bool {
+	if err := s.emailVerificationService.Request(ctx, c, purpose, email); err != nil {
+		log.Printf("email verification: client %d, %s: %v", c.ID, email, err)
+		return false
+	}
+	return true
+}
+
+// EmailStatus tells whether the client confirmed its email and which
+// address it is moving to, if any.
+func (s *ClientService) EmailStatus(ctx context.Context, id int64) (*dto.ClientEmailStatus, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := &dto.ClientEmailStatus{
+		ClientID:   client.ID,
+		Email:      client.Email,
+		Verified:   client.EmailVerifiedAt != nil,
+		VerifiedAt: client.EmailVerifiedAt,
+	}
+	pending, err := s.emailVerificationService.Pending(ctx, id, model.EmailVerificationChange)
+	if err != nil {
+		return nil, err
+	}
+	if pending != nil {
+		out.PendingEmail, out.PendingExpiresAt = pending.Email, &pending.ExpiresAt
+	}
+	return out, nil
+}
+
+// VerifyEmail confirms the client's email, or the address it is moving to,
+// with the token mailed to it.
+func (s *ClientService) VerifyEmail(ctx context.Context, id int64, in dto.EmailVerifyRequest) (*dto.ClientEmailStatus, error) {
+	if _, err := s.emailVerificationService.Confirm(ctx, id, in.Token); err != nil {
+		return nil, err
+	}
+	s.evict(ctx, id)
+	return s.EmailStatus(ctx, id)
+}
+
+// ResendVerification mails a new token for the pending email change or,
+// without one, for the unverified email. Earlier tokens stop working.
+func (s *ClientService) ResendVerification(ctx context.Context, id int64) (*dto.ClientEmailStatus, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", id, ErrClientMerged, client.MergedInto)
+	}
+	pending, err := s.emailVerificationService.Pending(ctx, id, model.EmailVerificationChange)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case pending != nil:
+		err = s.emailVerificationService.Request(ctx, client, model.EmailVerificationChange, pending.Email)
+	case client.EmailVerifiedAt == nil:
+		err = s.emailVerificationService.Request(ctx, client, model.EmailVerificationVerify, client.Email)
+	default:
+		return nil, fmt.Errorf("email %s is %w", client.Email, ErrAlreadyVerified)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.EmailStatus(ctx, id)
+}
+
+// checkEmailFree refuses an email that is already the primary or another
+// address of some client.
+func (s *ClientService) checkEmailFree(ctx context.Context, email string) error {
+	used, err := s.clientRepository.EmailInUse(ctx, email)
+	if err != nil {
+		return err
+	}
+	if used {
+		return fmt.Errorf("%w: email %s is already in use", ErrDuplicate, email)
+	}
+	return nil
+}
+
+// Duplicates returns the clients that resemble client id, best match
+// first.
+func (s *ClientService) Duplicates(ctx context.Context, id int64) ([]dto.ClientMatch, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if client.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", id, ErrClientMerged, client.MergedInto)
+	}
+	return s.findDuplicates(ctx, client)
+}
+
+// possibleDuplicates reports duplicates of a saved client. Like screening it
+// only informs: the client is saved either way and a failure is logged.
+func (s *ClientService) possibleDuplicates(ctx context.Context, c *model.Client) []dto.ClientMatch {
+	matches, err := s.findDuplicates(ctx, c)
+	if err != nil {
+		log.Printf("duplicates: client %d not checked: %v", c.ID, err)
+	}
+	return matches
+}
+
+// findDuplicates compares c with the clients born on the same day. The name
+// similarity counts alone unless both clients have an address, in which case
+// the address weighs a quarter of the score. Businesses are kept apart by
+// their registration number instead.
+func (s *ClientService) findDuplicates(ctx context.Context, c *model.Client) ([]dto.ClientMatch, error) {
+	if c.IsBusiness() {
+		return []dto.ClientMatch{}, nil
+	}
+	candidates, err := s.clientRepository.FindByBirthDate(ctx, c.BirthDate, c.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := []dto.ClientMatch{}
+	for _, other := range candidates {
+		nameScore := textmatch.NameSimilarity(c.Name(), other.Name())
+		addressScore, score := 0.0, nameScore
+		if strings.TrimSpace(c.ResidenceAddress) != "" && strings.TrimSpace(other.ResidenceAddress) != "" {
+			addressScore = textmatch.NameSimilarity(c.ResidenceAddress, other.ResidenceAddress)
+			score = 0.75*nameScore + 0.25*addressScore
+		}
+		if score < config.App.ClientDuplicateThreshold {
+			continue
+		}
+		matches = append(matches, dto.ClientMatch{
+			ClientID:     other.ID,
+			FirstName:    other.FirstName,
+			LastName:     other.LastName,
+			Email:        other.Email,
+			BirthDate:    other.BirthDate.Format("2006-01-02"),
+			NameScore:    nameScore,
+			AddressScore: addressScore,
+			Score:        score,
+		})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	return matches, nil
+}
+
+// evict drops the cached clients, for changes made outside this service.
+func (s *ClientService) evict(ctx context.Context, ids ...int64) {
+	if s.cache == nil {
+		return
+	}
+	keys := []string{s.keyClientsAll()}
+	for _, id := range ids {
+		keys = append(keys, s.keyClient(id))
+	}
+	_ = s.cache.Del(ctx, keys...)
+}
+
 func validateClientCreate(in dto.ClientCreate) error {
-	if strings.TrimSpace(in.FirstName) == "" ||
-		strings.TrimSpace(in.LastName) == "" ||
-		strings.TrimSpace(in.Email) == "" ||
-		strings.TrimSpace(in.BirthDate) == "" {
+	if strings.TrimSpace(in.Email) == "" {
 		return errors.New("missing required fields")
 	}
 	return nil
@@ -158,14 +404,43 @@ func validateClientUpdate(in dto.ClientUpdate) error {
 	if in.ID <= 0 {
 		return errors.New("invalid id")
 	}
-	if strings.TrimSpace(in.FirstName) == "" ||
-		strings.TrimSpace(in.LastName) == "" ||
-		strings.TrimSpace(in.Email) == "" ||
-		strings.TrimSpace(in.BirthDate) == "" {
+	if strings.TrimSpace(in.Email) == "" {
 		return errors.New("missing required fields")
 	}
 	return nil
 }
 
+var (
+	registrationNumberPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 ./-]{0,48}[A-Z0-9]$`)
+	taxIDPattern              = regexp.MustCompile(`^[A-Z0-9][A-Z0-9 ./-]{2,48}[A-Z0-9]$`)
+)
+
+// validateClient checks the fields of c's type. A business needs its full
+// registration details (the KYB rules on the entity itself); the rules on
+// its owners and signatories are in BusinessService.
+func validateClient(c *model.Client) error {
+	if !c.IsBusiness() {
+		if c.FirstName == "" || c.LastName == "" {
+			return errors.New("missing required fields")
+		}
+		return nil
+	}
+	switch {
+	case c.RegisteredName == "" || c.RegistrationNumber == "" || c.TaxID == "" || c.IncorporationCountry == "":
+		return errors.New("missing required fields: a business needs registered_name, registration_number, tax_id, incorporation_date and incorporation_country")
+	case len(c.RegisteredName) > 200:
+		return errors.New("registered_name is longer than 200 characters")
+	case !registrationNumberPattern.MatchString(c.RegistrationNumber):
+		return fmt.Errorf("invalid registration_number %q (2-50 letters, digits, spaces, '.', '/' or '-')", c.RegistrationNumber)
+	case !taxIDPattern.MatchString(c.TaxID):
+		return fmt.Errorf("invalid tax_id %q (4-50 letters, digits, spaces, '.', '/' or '-')", c.TaxID)
+	case !contact.IsCountry(c.IncorporationCountry):
+		return fmt.Errorf("invalid incorporation_country %q (use an ISO 3166-1 alpha-2 code)", c.IncorporationCountry)
+	case c.IncorporationDate.After(time.Now()):
+		return errors.New("incorporation_date is in the future")
+	}
+	return nil
+}
+
 func (s *ClientService) keyClient(id int64) string { return fmt.Sprintf("client:%d", id) }
 func (s *ClientService) keyClientsAll() string     { return "clients:all" }
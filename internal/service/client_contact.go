@@ -0,0 +1,298 @@
+package service
+
+import (
+	"basic-gin/internal/contact"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type ClientContactService struct {
+	clientContactRepository repository.ClientContactRepository
+	clientRepository        repository.ClientRepository
+	clientService           ClientService
+}
+
+func NewClientContactService(
+	clientContactRepository *repository.ClientContactRepository,
+	clientRepository *repository.ClientRepository,
+	clientService *ClientService,
+) *ClientContactService {
+	return &ClientContactService{
+		clientContactRepository: *clientContactRepository,
+		clientRepository:        *clientRepository,
+		clientService:           *clientService,
+	}
+}
+
+// Contacts returns the client's current addresses, phone numbers and email
+// addresses.
+func (s *ClientContactService) Contacts(ctx context.Context, clientID int64) (*dto.ClientContactsResponse, error) {
+	if clientID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := s.clientContactRepository.ListAddresses(ctx, clientID, false)
+	if err != nil {
+		return nil, err
+	}
+	phones, err := s.clientContactRepository.ListPhones(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := s.emails(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ClientContactsResponse{
+		ClientID:  clientID,
+		Addresses: mapper.ClientAddressesToResponseSlice(addresses),
+		Phones:    mapper.ClientPhonesToResponseSlice(phones),
+		Emails:    emails,
+	}, nil
+}
+
+// Addresses returns the client's current addresses or, with history, every
+// address it had.
+func (s *ClientContactService) Addresses(ctx context.Context, clientID int64, history bool) ([]*dto.ClientAddressResponse, error) {
+	if clientID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if _, err := s.clientRepository.GetById(ctx, clientID); err != nil {
+		return nil, err
+	}
+	items, err := s.clientContactRepository.ListAddresses(ctx, clientID, history)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientAddressesToResponseSlice(items), nil
+}
+
+// SetAddress replaces the client's current address of the given type; the
+// old one stays in the history. A residence address is also written to the
+// client's residence_address.
+func (s *ClientContactService) SetAddress(ctx context.Context, clientID int64, addressType string, in dto.ClientAddressRequest) (*dto.ClientAddressResponse, error) {
+	addressType = strings.ToLower(strings.TrimSpace(addressType))
+	if addressType != model.AddressTypeResidence && addressType != model.AddressTypeMailing {
+		return nil, fmt.Errorf("invalid address type %q (use %s or %s)", addressType, model.AddressTypeResidence, model.AddressTypeMailing)
+	}
+	a := &model.ClientAddress{
+		ClientID:    clientID,
+		Type:        addressType,
+		Street:      strings.TrimSpace(in.Street),
+		HouseNumber: strings.TrimSpace(in.HouseNumber),
+		PostalCode:  strings.TrimSpace(in.PostalCode),
+		City:        strings.TrimSpace(in.City),
+		Country:     contact.NormalizeCountry(in.Country),
+	}
+	if a.Street == "" || a.City == "" {
+		return nil, errors.New("street and city are required")
+	}
+	if !contact.IsCountry(a.Country) {
+		return nil, fmt.Errorf("invalid country %q (use an ISO 3166-1 alpha-2 code)", in.Country)
+	}
+
+	var saved *model.ClientAddress
+	err := s.withClient(ctx, clientID, func(tx pgx.Tx) error {
+		var err error
+		if saved, err = s.clientContactRepository.ReplaceAddressTx(ctx, tx, a); err != nil {
+			return err
+		}
+		if addressType != model.AddressTypeResidence {
+			return nil
+		}
+		return s.clientRepository.UpdateResidenceAddressTx(ctx, tx, clientID,
+			contact.FormatAddress(a.Street, a.HouseNumber, a.PostalCode, a.City, a.Country))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientAddressToResponse(saved), nil
+}
+
+func (s *ClientContactService) AddPhone(ctx context.Context, clientID int64, in dto.ClientPhoneCreate) (*dto.ClientPhoneResponse, error) {
+	number, err := contact.NormalizePhone(in.Number)
+	if err != nil {
+		return nil, err
+	}
+	phoneType := strings.ToLower(strings.TrimSpace(in.Type))
+	if phoneType == "" {
+		phoneType = model.PhoneTypeMobile
+	}
+	switch phoneType {
+	case model.PhoneTypeMobile, model.PhoneTypeHome, model.PhoneTypeWork:
+	default:
+		return nil, fmt.Errorf("invalid phone type %q (use mobile, home or work)", in.Type)
+	}
+
+	var saved *model.ClientPhone
+	err = s.withClient(ctx, clientID, func(tx pgx.Tx) error {
+		var err error
+		saved, err = s.clientContactRepository.AddPhoneTx(ctx, tx, &model.ClientPhone{
+			ClientID: clientID,
+			Number:   number,
+			Type:     phoneType,
+			Primary:  in.Primary,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientPhoneToResponse(saved), nil
+}
+
+func (s *ClientContactService) SetPrimaryPhone(ctx context.Context, clientID int64, id int) (*dto.ClientPhoneResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid phone id")
+	}
+	var saved *model.ClientPhone
+	err := s.withClient(ctx, clientID, func(tx pgx.Tx) error {
+		var err error
+		saved, err = s.clientContactRepository.SetPrimaryPhoneTx(ctx, tx, clientID, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientPhoneToResponse(saved), nil
+}
+
+func (s *ClientContactService) DeletePhone(ctx context.Context, clientID int64, id int) error {
+	if id <= 0 {
+		return errors.New("invalid phone id")
+	}
+	return s.withClient(ctx, clientID, func(tx pgx.Tx) error {
+		return s.clientContactRepository.DeletePhoneTx(ctx, tx, clientID, id)
+	})
+}
+
+// AddEmail adds an email address to the client. Asked to make it the
+// primary one, it mails a confirmation token to it; the address becomes
+// primary once confirmed. It returns all of the client's addresses.
+func (s *ClientContactService) AddEmail(ctx context.Context, clientID int64, in dto.ClientEmailCreate) ([]*dto.ClientEmailResponse, error) {
+	email, err := contact.NormalizeEmail(in.Email)
+	if err != nil {
+		return nil, err
+	}
+	err = s.withClient(ctx, clientID, func(tx pgx.Tx) error {
+		used, err := s.clientContactRepository.EmailInUseTx(ctx, tx, email)
+		if err != nil {
+			return err
+		}
+		if used {
+			return fmt.Errorf("%w: email %s is already in use", ErrDuplicate, email)
+		}
+		_, err = s.clientContactRepository.AddEmailTx(ctx, tx, &model.ClientEmail{ClientID: clientID, Email: email})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	client, err := s.clientRepository.GetById(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Primary {
+		s.clientService.requestVerification(ctx, client, model.EmailVerificationChange, email)
+	}
+	return s.emails(ctx, client)
+}
+
+// SetPrimaryEmail mails a confirmation token to email id; once confirmed it
+// becomes the client's primary address and the previous one is kept among
+// the others.
+func (s *ClientContactService) SetPrimaryEmail(ctx context.Context, clientID int64, id int) ([]*dto.ClientEmailResponse, error) {
+	if clientID <= 0 || id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if client.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", clientID, ErrClientMerged, client.MergedInto)
+	}
+	items, err := s.clientContactRepository.ListEmails(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(items, func(e *model.ClientEmail) bool { return e.ID == id })
+	if i < 0 {
+		return nil, fmt.Errorf("email %d of client %d %w", id, clientID, ErrNotFound)
+	}
+	if err := s.clientService.emailVerificationService.Request(ctx, client, model.EmailVerificationChange, items[i].Email); err != nil {
+		return nil, err
+	}
+	return s.emails(ctx, client)
+}
+
+// DeleteEmail removes one of the client's other addresses; the primary
+// address cannot be removed, only replaced.
+func (s *ClientContactService) DeleteEmail(ctx context.Context, clientID int64, id int) error {
+	if clientID <= 0 || id <= 0 {
+		return errors.New("invalid id")
+	}
+	return s.clientContactRepository.DeleteEmail(ctx, clientID, id)
+}
+
+func (s *ClientContactService) emails(ctx context.Context, client *model.Client) ([]*dto.ClientEmailResponse, error) {
+	items, err := s.clientContactRepository.ListEmails(ctx, client.ID)
+	if err != nil {
+		return nil, err
+	}
+	pending, err := s.clientService.emailVerificationService.Pending(ctx, client.ID, model.EmailVerificationChange)
+	if err != nil {
+		return nil, err
+	}
+	pendingEmail := ""
+	if pending != nil {
+		pendingEmail = pending.Email
+	}
+	return mapper.ClientEmailsToResponseSlice(client.Email, pendingEmail, items), nil
+}
+
+// withClient runs fn in a transaction holding the client's row lock, so
+// concurrent changes to its contacts apply one after the other. Merged
+// clients are refused. The cached client is dropped afterwards as its email
+// or residence address may have changed.
+func (s *ClientContactService) withClient(ctx context.Context, clientID int64, fn func(tx pgx.Tx) error) error {
+	if clientID <= 0 {
+		return errors.New("invalid id")
+	}
+	tx, err := s.clientContactRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	client, err := s.clientRepository.GetByIdTx(ctx, tx, clientID, true)
+	if err != nil {
+		return err
+	}
+	if client.MergedInto != 0 {
+		return fmt.Errorf("client %d %w into client %d", clientID, ErrClientMerged, client.MergedInto)
+	}
+	if client.ErasedAt != nil {
+		return fmt.Errorf("client %d %w", clientID, ErrClientErased)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	s.clientService.evict(ctx, clientID)
+	return nil
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrOpenCase is returned when an open case stands in the way of a merge or
+// an erasure.
+var ErrOpenCase = repository.ErrOpenCase
+
+type ClientMergeService struct {
+	clientMergeRepository repository.ClientMergeRepository
+	clientRepository      repository.ClientRepository
+	auditRepository       repository.AuditRepository
+	clientService         ClientService
+	accountService        AccountService
+}
+
+func NewClientMergeService(
+	clientMergeRepository *repository.ClientMergeRepository,
+	clientRepository *repository.ClientRepository,
+	auditRepository *repository.AuditRepository,
+	clientService *ClientService,
+	accountService *AccountService,
+) *ClientMergeService {
+	return &ClientMergeService{
+		clientMergeRepository: *clientMergeRepository,
+		clientRepository:      *clientRepository,
+		auditRepository:       *auditRepository,
+		clientService:         *clientService,
+		accountService:        *accountService,
+	}
+}
+
+// Merge folds the duplicate client into the survivor: accounts,
+// beneficiaries, client limits, cases and screening hits move over, the
+// duplicate is marked as merged and the merge is recorded. Transaction
+// history follows the accounts.
+func (s *ClientMergeService) Merge(ctx context.Context, in dto.ClientMergeRequest) (*dto.ClientMergeResponse, error) {
+	if in.SurvivorID <= 0 || in.DuplicateID <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if in.SurvivorID == in.DuplicateID {
+		return nil, errors.New("a client cannot be merged into itself")
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	tx, err := s.clientMergeRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Lock in id order so two merges of the same pair cannot deadlock.
+	clients := map[int64]*model.Client{}
+	for _, id := range []int64{min(in.SurvivorID, in.DuplicateID), max(in.SurvivorID, in.DuplicateID)} {
+		c, err := s.clientRepository.GetByIdTx(ctx, tx, id, true)
+		if err != nil {
+			return nil, err
+		}
+		if c.MergedInto != 0 {
+			return nil, fmt.Errorf("client %d %w into client %d", c.ID, ErrClientMerged, c.MergedInto)
+		}
+		if c.ErasedAt != nil {
+			return nil, fmt.Errorf("client %d %w", c.ID, ErrClientErased)
+		}
+		clients[id] = c
+	}
+	if clients[in.SurvivorID].Type != clients[in.DuplicateID].Type {
+		return nil, errors.New("a business client can only be merged with another business")
+	}
+
+	moved, err := s.clientMergeRepository.MergeTx(ctx, tx, in.SurvivorID, in.DuplicateID)
+	if err != nil {
+		return nil, err
+	}
+	merge, err := s.clientMergeRepository.CreateTx(ctx, tx, &model.ClientMerge{
+		SurvivorID:  int(in.SurvivorID),
+		DuplicateID: int(in.DuplicateID),
+		Reason:      reason,
+		MergedBy:    middleware.ActorFromContext(ctx),
+		Moved:       *moved,
+	})
+	if err != nil {
+		return nil, err
+	}
+	duplicate := clients[in.DuplicateID]
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "client.merge", "client", strconv.FormatInt(in.SurvivorID, 10), map[string]any{
+		"merge_id":        merge.ID,
+		"duplicate_id":    in.DuplicateID,
+		"duplicate_email": duplicate.Email,
+		"reason":          reason,
+		"moved":           moved,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	s.clientService.evict(ctx, in.SurvivorID, in.DuplicateID)
+	s.accountService.evictMoved(ctx, moved.Accounts, int(in.SurvivorID), int(in.DuplicateID))
+	return mapper.ClientMergeToResponse(merge), nil
+}
+
+func (s *ClientMergeService) List(ctx context.Context, clientID, limit, offset int) ([]*dto.ClientMergeResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.clientMergeRepository.List(ctx, clientID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientMergesToResponseSlice(items), nil
+}
+
+func (s *ClientMergeService) Get(ctx context.Context, id int) (*dto.ClientMergeResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	m, err := s.clientMergeRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ClientMergeToResponse(m), nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrFundsRemaining is returned when a client to be erased still holds
+// money on one of their accounts.
+var ErrFundsRemaining = repository.ErrFundsRemaining
+
+// ClientPrivacyService answers data subject requests: access, by exporting
+// what is held about a client, and erasure.
+type ClientPrivacyService struct {
+	clientErasureRepository repository.ClientErasureRepository
+	clientRepository        repository.ClientRepository
+	clientContactRepository repository.ClientContactRepository
+	accountRepository       repository.AccountRepository
+	accountHolderRepository repository.AccountHolderRepository
+	transactionRepository   repository.TransactionRepository
+	auditRepository         repository.AuditRepository
+	clientService           ClientService
+	accountService          AccountService
+}
+
+func NewClientPrivacyService(
+	clientErasureRepository *repository.ClientErasureRepository,
+	clientRepository *repository.ClientRepository,
+	clientContactRepository *repository.ClientContactRepository,
+	accountRepository *repository.AccountRepository,
+	accountHolderRepository *repository.AccountHolderRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+	clientService *ClientService,
+	accountService *AccountService,
+) *ClientPrivacyService {
+	return &ClientPrivacyService{
+		clientErasureRepository: *clientErasureRepository,
+		clientRepository:        *clientRepository,
+		clientContactRepository: *clientContactRepository,
+		accountRepository:       *accountRepository,
+		accountHolderRepository: *accountHolderRepository,
+		transactionRepository:   *transactionRepository,
+		auditRepository:         *auditRepository,
+		clientService:           *clientService,
+		accountService:          *accountService,
+	}
+}
+
+// Export gathers the personal data held about the client. The export
+// itself is audited.
+func (s *ClientPrivacyService) Export(ctx context.Context, id int64) (*dto.ClientExport, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	client, err := s.clientRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := s.clientContactRepository.ListAddresses(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	phones, err := s.clientContactRepository.ListPhones(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	emails, err := s.clientContactRepository.ListEmails(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.accountRepository.GetByHolder(ctx, int(id))
+	if err != nil {
+		return nil, err
+	}
+	roles, err := s.accountHolderRepository.ListByClient(ctx, int(id))
+	if err != nil {
+		return nil, err
+	}
+	transactions, err := s.transactionRepository.ListByClient(ctx, int(id))
+	if err != nil {
+		return nil, err
+	}
+	var held []int
+	for _, r := range roles {
+		if r.CanManage() {
+			held = append(held, r.AccountID)
+		}
+	}
+	entries, err := s.auditRepository.ListByClient(ctx, id, held)
+	if err != nil {
+		return nil, err
+	}
+	erasure, err := s.clientErasureRepository.GetByClient(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &dto.ClientExport{
+		ExportedAt:   time.Now().UTC(),
+		Client:       mapper.ClientToResponse(client),
+		Addresses:    mapper.ClientAddressesToResponseSlice(addresses),
+		Phones:       mapper.ClientPhonesToResponseSlice(phones),
+		Emails:       mapper.ClientEmailsToResponseSlice(client.Email, "", emails),
+		Accounts:     mapper.AccountsToResponseSlice(accounts),
+		AccountRoles: mapper.AccountHoldersToResponseSlice(roles),
+		Transactions: make([]*dto.TransactionResponse, 0, len(transactions)),
+		AuditEntries: mapper.AuditEntriesToResponseSlice(entries),
+	}
+	for _, t := range transactions {
+		out.Transactions = append(out.Transactions, mapper.TransactionToAdminResponse(t))
+	}
+	if erasure != nil {
+		out.Erasure = mapper.ClientErasureToResponse(erasure)
+	}
+
+	if err := s.auditRepository.Record(ctx, auditEntry(ctx, "client.export", "client", strconv.FormatInt(id, 10), map[string]any{
+		"accounts":      len(out.Accounts),
+		"transactions":  len(out.Transactions),
+		"audit_entries": len(out.AuditEntries),
+	})); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Erase pseudonymizes the personal data of an individual client, and of the
+// duplicates merged into it, while keeping the financial records the bank
+// must retain; see ClientErasureRepository.EraseTx. The client's accounts
+// must be empty and it may have no open case. The erasure is recorded and
+// the cached views of the client and its accounts are dropped.
+func (s *ClientPrivacyService) Erase(ctx context.Context, id int64, in dto.ClientEraseRequest) (*dto.ClientErasureResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	tx, err := s.clientErasureRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	client, err := s.clientRepository.GetByIdTx(ctx, tx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case client.IsBusiness():
+		return nil, fmt.Errorf("client %d is a business: only individuals' data can be erased", id)
+	case client.ErasedAt != nil:
+		return nil, fmt.Errorf("client %d %w on %s", id, ErrClientErased, client.ErasedAt.UTC().Format("2006-01-02"))
+	}
+
+	erased, err := s.clientErasureRepository.EraseTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	erasure, err := s.clientErasureRepository.CreateTx(ctx, tx, &model.ClientErasure{
+		ClientID: int(id),
+		Reason:   reason,
+		ErasedBy: middleware.ActorFromContext(ctx),
+		Erased:   *erased,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "client.erase", "client", strconv.FormatInt(id, 10), map[string]any{
+		"erasure_id": erasure.ID,
+		"reason":     reason,
+		"erased":     erased,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	s.clientService.evict(ctx, erased.Clients...)
+	clientIDs := make([]int, 0, len(erased.Clients))
+	for _, c := range erased.Clients {
+		clientIDs = append(clientIDs, int(c))
+	}
+	s.accountService.evictMoved(ctx, nil, clientIDs...)
+	for _, accountID := range erased.AccountsFrozen {
+		s.accountService.evict(ctx, &model.Account{ID: accountID, ClientId: int(id)})
+	}
+	return mapper.ClientErasureToResponse(erasure), nil
+}
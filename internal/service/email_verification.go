@@ -0,0 +1,173 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/mail"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrVerificationExpired = errors.New("verification token expired")
+
+type EmailVerificationService struct {
+	emailVerificationRepository repository.EmailVerificationRepository
+	clientRepository            repository.ClientRepository
+	clientContactRepository     repository.ClientContactRepository
+	auditRepository             repository.AuditRepository
+	mailer                      mail.Mailer
+}
+
+func NewEmailVerificationService(
+	emailVerificationRepository *repository.EmailVerificationRepository,
+	clientRepository *repository.ClientRepository,
+	clientContactRepository *repository.ClientContactRepository,
+	auditRepository *repository.AuditRepository,
+	mailer mail.Mailer,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		emailVerificationRepository: *emailVerificationRepository,
+		clientRepository:            *clientRepository,
+		clientContactRepository:     *clientContactRepository,
+		auditRepository:             *auditRepository,
+		mailer:                      mailer,
+	}
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Request mails c a token confirming email: its current address for
+// model.EmailVerificationVerify, the address it is moving to for
+// model.EmailVerificationChange. An earlier pending token for the same
+// purpose stops working.
+func (s *EmailVerificationService) Request(ctx context.Context, c *model.Client, purpose, email string) error {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("generate verification token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	tx, err := s.emailVerificationRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	v, err := s.emailVerificationRepository.CreateTx(ctx, tx, &model.EmailVerification{
+		ClientID:  c.ID,
+		Purpose:   purpose,
+		Email:     email,
+		TokenHash: hashToken(token),
+		ExpiresAt: time.Now().Add(config.App.EmailVerificationTTL),
+	})
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	subject, action := "Confirm your email address", "confirm this email address"
+	if purpose == model.EmailVerificationChange {
+		subject, action = "Confirm your new email address", "make this your email address with us"
+	}
+	return s.mailer.Send(ctx, mail.Message{
+		To:      email,
+		Subject: subject,
+		Body: fmt.Sprintf("Hello %s,\n\nTo %s, use this code:\n\n%s\n\nThe code expires at %s. If you did not ask for this, ignore this message.\n",
+			c.Name(), action, token, v.ExpiresAt.UTC().Format("2006-01-02 15:04 MST")),
+	})
+}
+
+// Confirm checks token against the client's pending verifications. A
+// "verify" token marks the client's email verified; a "change" token makes
+// the new address the client's email, verified. Moving to one of the
+// client's other addresses swaps it with the current one.
+func (s *EmailVerificationService) Confirm(ctx context.Context, clientID int64, token string) (*model.EmailVerification, error) {
+	token = strings.TrimSpace(token)
+	if clientID <= 0 || token == "" {
+		return nil, errors.New("client id and token are required")
+	}
+
+	tx, err := s.emailVerificationRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	client, err := s.clientRepository.GetByIdTx(ctx, tx, clientID, true)
+	if err != nil {
+		return nil, err
+	}
+	if client.MergedInto != 0 {
+		return nil, fmt.Errorf("client %d %w into client %d", clientID, ErrClientMerged, client.MergedInto)
+	}
+	v, err := s.emailVerificationRepository.GetPendingByTokenTx(ctx, tx, clientID, hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !time.Now().Before(v.ExpiresAt) {
+		return nil, ErrVerificationExpired
+	}
+
+	action := "client.email_verify"
+	switch v.Purpose {
+	case model.EmailVerificationVerify:
+		if !strings.EqualFold(v.Email, client.Email) {
+			return nil, fmt.Errorf("%w: it is for an email the client no longer uses", ErrVerificationExpired)
+		}
+	case model.EmailVerificationChange:
+		action = "client.email_change"
+		other, err := s.clientContactRepository.FindEmailTx(ctx, tx, v.Email)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case other != nil && other.ClientID != clientID:
+			return nil, fmt.Errorf("%w: email %s is already in use", ErrDuplicate, v.Email)
+		case other != nil:
+			if _, err := s.clientContactRepository.SwapPrimaryEmailTx(ctx, tx, clientID, other.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Tokens for the address being replaced stop working.
+	if err := s.emailVerificationRepository.SupersedePendingTx(ctx, tx, clientID); err != nil {
+		return nil, err
+	}
+	if err := s.emailVerificationRepository.ConfirmTx(ctx, tx, v.ID); err != nil {
+		return nil, err
+	}
+	if err := s.clientRepository.ConfirmEmailTx(ctx, tx, clientID, v.Email); err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, action, "client", strconv.FormatInt(clientID, 10), map[string]any{
+		"email":          v.Email,
+		"previous_email": client.Email,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// Pending returns the client's unexpired pending verification for purpose,
+// or nil.
+func (s *EmailVerificationService) Pending(ctx context.Context, clientID int64, purpose string) (*model.EmailVerification, error) {
+	return s.emailVerificationRepository.Pending(ctx, clientID, purpose)
+}
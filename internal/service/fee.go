@@ -0,0 +1,300 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type FeeService struct {
+	feeRepository         repository.FeeRepository
+	accountRepository     repository.AccountRepository
+	transactionRepository repository.TransactionRepository
+	auditRepository       repository.AuditRepository
+}
+
+func NewFeeService(
+	feeRepository *repository.FeeRepository,
+	accountRepository *repository.AccountRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+) *FeeService {
+	return &FeeService{
+		feeRepository:         *feeRepository,
+		accountRepository:     *accountRepository,
+		transactionRepository: *transactionRepository,
+		auditRepository:       *auditRepository,
+	}
+}
+
+// feeCharge is the fee due on one operation.
+type feeCharge struct {
+	Amount        float64
+	Kind          string
+	Waived        bool
+	FreeRemaining int
+}
+
+// quoteTx works out the fee for debiting amount from acc. acc should be
+// locked by the caller when the fee is going to be posted, so the monthly
+// free quota is counted consistently. Revenue accounts are never charged,
+// and neither is anyone in a currency without a revenue account, as the fee
+// would have nowhere to go.
+func (s *FeeService) quoteTx(ctx context.Context, tx pgx.Tx, acc *model.Account, operation string, amount float64) (feeCharge, error) {
+	revenueID := feeRevenueAccount(acc.Currency)
+	if revenueID == 0 || acc.ID == revenueID {
+		return feeCharge{}, nil
+	}
+	sch, err := s.feeRepository.FindTx(ctx, tx, acc.AccountType, operation, acc.Currency)
+	if err != nil || sch == nil {
+		return feeCharge{}, err
+	}
+
+	charge := feeCharge{Kind: sch.Kind}
+	if sch.FreePerMonth > 0 {
+		now := time.Now().UTC()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		used, err := s.transactionRepository.CountOutgoingSinceTx(ctx, tx, acc.ID, operation, monthStart)
+		if err != nil {
+			return feeCharge{}, err
+		}
+		if used < sch.FreePerMonth {
+			charge.Waived = true
+			charge.FreeRemaining = sch.FreePerMonth - used
+			return charge, nil
+		}
+	}
+
+	charge.Amount = currency.Round(acc.Currency, computeFee(sch, amount))
+	return charge, nil
+}
+
+// computeFee applies a schedule to amount, before rounding.
+func computeFee(sch *model.FeeSchedule, amount float64) float64 {
+	var fee float64
+	switch sch.Kind {
+	case model.FeeKindFlat:
+		fee = sch.FlatAmount
+	case model.FeeKindPercentage:
+		fee = amount * sch.Percent / 100
+	case model.FeeKindTiered:
+		// Amounts above every bound fall into the last tier.
+		for i, t := range sch.Tiers {
+			if t.UpTo == nil || amount <= *t.UpTo || i == len(sch.Tiers)-1 {
+				fee = t.Flat + amount*t.Percent/100
+				break
+			}
+		}
+	}
+	if fee < sch.MinFee {
+		fee = sch.MinFee
+	}
+	if sch.MaxFee != nil && fee > *sch.MaxFee {
+		fee = *sch.MaxFee
+	}
+	return fee
+}
+
+// postTx debits fee from payer and credits the revenue account for its
+// currency, recording a fee transaction linked to parent. The caller must
+// have locked the revenue account together with its own accounts through
+// lockAccountsTx. It returns both accounts as updated.
+func (s *FeeService) postTx(ctx context.Context, tx pgx.Tx, payer *model.Account, parent *model.Transaction, fee float64) (*model.Account, *model.Account, error) {
+	parentID, err := strconv.Atoi(parent.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fee parent transaction id %q: %w", parent.ID, err)
+	}
+	revenueID := feeRevenueAccount(payer.Currency)
+	if revenueID == 0 {
+		return nil, nil, fmt.Errorf("no fee revenue account configured for %s", payer.Currency)
+	}
+	revenue, err := s.accountRepository.GetByIdTx(ctx, tx, revenueID, true)
+	if err != nil {
+		return nil, nil, fmt.Errorf("fee revenue account: %w", err)
+	}
+	if revenue.Currency != payer.Currency {
+		return nil, nil, fmt.Errorf("fee revenue account %d is in %s, fee is in %s", revenue.ID, revenue.Currency, payer.Currency)
+	}
+
+	updatedPayer, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, payer.ID, -fee)
+	if err != nil {
+		return nil, nil, err
+	}
+	updatedRevenue, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, revenue.ID, +fee)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.transactionRepository.SaveTx(ctx, tx, &model.Transaction{
+		Type:          model.TransactionTypeFee,
+		FromAccountID: payer.ID,
+		ToAccountID:   revenue.ID,
+		Amount:        fee,
+		Currency:      payer.Currency,
+		Description:   fmt.Sprintf("%s fee for transaction %s", parent.Type, parent.ID),
+		FeeFor:        parentID,
+	}); err != nil {
+		return nil, nil, err
+	}
+	parent.FeeAmount = fee
+	return updatedPayer, updatedRevenue, nil
+}
+
+// Quote previews the fee for an operation without posting anything.
+func (s *FeeService) Quote(ctx context.Context, accountID int, operation string, amount float64) (*dto.FeeQuoteResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+	if operation != model.FeeOperationTransfer && operation != model.FeeOperationWithdrawal {
+		return nil, fmt.Errorf("operation must be %q or %q", model.FeeOperationTransfer, model.FeeOperationWithdrawal)
+	}
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	tx, err := s.accountRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, accountID, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, amount); err != nil {
+		return nil, err
+	}
+	charge, err := s.quoteTx(ctx, tx, acc, operation, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.FeeQuoteResponse{
+		AccountID:     acc.ID,
+		Operation:     operation,
+		Amount:        amount,
+		Fee:           charge.Amount,
+		Total:         currency.Round(acc.Currency, amount+charge.Amount),
+		Currency:      acc.Currency,
+		Kind:          charge.Kind,
+		Waived:        charge.Waived,
+		FreeRemaining: charge.FreeRemaining,
+	}, nil
+}
+
+// WarnMissingRevenueAccounts logs the currencies that have fee schedules but
+// no revenue account configured; operations in them are not charged.
+func (s *FeeService) WarnMissingRevenueAccounts(ctx context.Context) {
+	items, err := s.feeRepository.List(ctx)
+	if err != nil {
+		log.Printf("fee schedules not checked: %v", err)
+		return
+	}
+	warned := map[string]bool{}
+	for _, sch := range items {
+		if config.App.FeeRevenueAccounts[sch.Currency] == 0 && !warned[sch.Currency] {
+			warned[sch.Currency] = true
+			log.Printf("WARN: fee schedules exist for %s but FEE_REVENUE_ACCOUNTS has no %s account - no fees are charged in %s", sch.Currency, sch.Currency, sch.Currency)
+		}
+	}
+}
+
+func (s *FeeService) ListSchedules(ctx context.Context) ([]*dto.FeeScheduleResponse, error) {
+	items, err := s.feeRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.FeeSchedulesToResponseSlice(items), nil
+}
+
+// UpsertSchedule creates or replaces a fee schedule; the change is audited.
+func (s *FeeService) UpsertSchedule(ctx context.Context, in dto.FeeScheduleUpsert) (*dto.FeeScheduleResponse, error) {
+	sch := mapper.ToFeeScheduleFromUpsert(in)
+	if err := validateFeeSchedule(&sch); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.feeRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	saved, err := s.feeRepository.UpsertTx(ctx, tx, &sch)
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.FeeScheduleToResponse(saved)
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "fee_schedule.upsert", "fee_schedule", strconv.Itoa(saved.ID), map[string]any{
+		"schedule": resp,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func validateFeeSchedule(sch *model.FeeSchedule) error {
+	if !currency.IsSupported(sch.Currency) {
+		return fmt.Errorf("unsupported currency: %q", sch.Currency)
+	}
+	if sch.MaxFee != nil && *sch.MaxFee < sch.MinFee {
+		return errors.New("max_fee cannot be lower than min_fee")
+	}
+	for _, v := range []float64{sch.FlatAmount, sch.MinFee} {
+		if err := currency.ValidateAmount(sch.Currency, v); err != nil {
+			return err
+		}
+	}
+
+	switch sch.Kind {
+	case model.FeeKindFlat:
+		if sch.FlatAmount <= 0 {
+			return errors.New("flat fee needs a positive flat_amount")
+		}
+	case model.FeeKindPercentage:
+		if sch.Percent <= 0 {
+			return errors.New("percentage fee needs a positive percent")
+		}
+	case model.FeeKindTiered:
+		if len(sch.Tiers) == 0 {
+			return errors.New("tiered fee needs at least one tier")
+		}
+		prev := math.Inf(-1)
+		for i, t := range sch.Tiers {
+			if t.Flat < 0 || t.Percent < 0 || t.Percent > 100 {
+				return fmt.Errorf("tier %d: flat and percent must be non-negative, percent at most 100", i+1)
+			}
+			if t.UpTo == nil {
+				if i != len(sch.Tiers)-1 {
+					return fmt.Errorf("tier %d: only the last tier may omit up_to", i+1)
+				}
+				continue
+			}
+			if *t.UpTo <= prev {
+				return fmt.Errorf("tier %d: up_to must be greater than the previous tier", i+1)
+			}
+			prev = *t.UpTo
+		}
+	}
+	if sch.Kind != model.FeeKindTiered {
+		sch.Tiers = nil
+	}
+	return nil
+}
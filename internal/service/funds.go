@@ -0,0 +1,55 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrInsufficientFunds = repository.ErrInsufficientFunds
+	ErrAccountFrozen     = errors.New("account is frozen")
+)
+
+// checkFunds applies the negative-balance policy to a debit of amount from a
+// and refuses any debit from a frozen account. a must have been read with
+// FOR UPDATE in the caller's transaction; the
+// accounts_balance_within_overdraft constraint is the backstop if it was not.
+func checkFunds(a *model.Account, amount float64) error {
+	if a.Status == model.AccountStatusFrozen {
+		return fmt.Errorf("%w: account %d", ErrAccountFrozen, a.ID)
+	}
+	if a.Spendable() < amount {
+		return fmt.Errorf("%w: account %d can spend %.3f %s (overdraft limit %.3f)", ErrInsufficientFunds, a.ID, a.Spendable(), a.Currency, a.OverdraftLimit)
+	}
+	return nil
+}
+
+// lockAccountsTx locks the accounts in ascending id order, skipping zero ids
+// and repeats. Everything that moves money between accounts, fee revenue
+// accounts included, takes its locks through it, so two such transactions
+// never wait on each other's locks in opposite order.
+func lockAccountsTx(ctx context.Context, tx pgx.Tx, accounts *repository.AccountRepository, ids ...int) error {
+	ids = slices.Clone(ids)
+	slices.Sort(ids)
+	for _, id := range slices.Compact(ids) {
+		if id == 0 {
+			continue
+		}
+		if _, err := accounts.GetByIdTx(ctx, tx, id, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// feeRevenueAccount returns the account fees in cur are credited to, or 0.
+func feeRevenueAccount(cur string) int {
+	return config.App.FeeRevenueAccounts[cur]
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/fx"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FXService struct {
+	rates           *fx.RateTable
+	quoteRepository repository.FXQuoteRepository
+}
+
+func NewFXService(rates *fx.RateTable, quoteRepository *repository.FXQuoteRepository) *FXService {
+	return &FXService{
+		rates:           rates,
+		quoteRepository: *quoteRepository,
+	}
+}
+
+// Quote locks a customer rate (mid rate minus the configured spread) for
+// selling amount of from into to. The quote is valid for config.App.FXQuoteTTL
+// and can be redeemed by exactly one transfer.
+func (s *FXService) Quote(ctx context.Context, from, to string, amount float64) (*dto.FXQuoteResponse, error) {
+	from, to = currency.Normalize(from), currency.Normalize(to)
+	if !currency.IsSupported(from) || !currency.IsSupported(to) {
+		return nil, fmt.Errorf("unsupported currency pair %s/%s", from, to)
+	}
+	if from == to {
+		return nil, fmt.Errorf("from and to currencies must differ")
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+	if err := currency.ValidateAmount(from, amount); err != nil {
+		return nil, err
+	}
+
+	mid, err := s.rates.Mid(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	spread := config.App.FXSpreadBps
+	rate := mid * (1 - float64(spread)/10000)
+	buy := currency.Round(to, amount*rate)
+	if buy <= 0 {
+		return nil, fmt.Errorf("amount too small to convert")
+	}
+
+	saved, err := s.quoteRepository.Create(ctx, &model.FXQuote{
+		ID:           uuid.NewString(),
+		FromCurrency: from,
+		ToCurrency:   to,
+		MidRate:      mid,
+		Rate:         rate,
+		SpreadBps:    spread,
+		SellAmount:   amount,
+		BuyAmount:    buy,
+		ExpiresAt:    time.Now().UTC().Add(config.App.FXQuoteTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapper.FXQuoteToResponse(saved), nil
+}
+
+func (s *FXService) Rates() *dto.FXRatesResponse {
+	return mapper.FXRatesToResponse(s.rates.Snapshot())
+}
+
+func (s *FXService) ReloadRates() (*dto.FXRatesResponse, error) {
+	if err := s.rates.LoadFile(config.App.FXRatesFile); err != nil {
+		return nil, err
+	}
+	return s.Rates(), nil
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type HoldService struct {
+	holdRepository     repository.HoldRepository
+	accountRepository  repository.AccountRepository
+	approvalRepository repository.ApprovalRepository
+	auditRepository    repository.AuditRepository
+	accountService     AccountService
+	transactionService TransactionService
+}
+
+func NewHoldService(
+	holdRepository *repository.HoldRepository,
+	accountRepository *repository.AccountRepository,
+	approvalRepository *repository.ApprovalRepository,
+	auditRepository *repository.AuditRepository,
+	accountService *AccountService,
+	transactionService *TransactionService,
+) *HoldService {
+	return &HoldService{
+		holdRepository:     *holdRepository,
+		accountRepository:  *accountRepository,
+		approvalRepository: *approvalRepository,
+		auditRepository:    *auditRepository,
+		accountService:     *accountService,
+		transactionService: *transactionService,
+	}
+}
+
+// Create reserves amount on the account. The reservation counts against the
+// available balance until it is captured, released or expires. A client
+// placing it needs a holder role that may debit the account.
+func (s *HoldService) Create(ctx context.Context, accountID int, in dto.HoldCreate) (*dto.HoldResponse, error) {
+	if accountID <= 0 {
+		return nil, fmt.Errorf("invalid account id")
+	}
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	ttl := config.App.HoldDefaultTTL
+	if in.ExpiresInSeconds > 0 {
+		ttl = time.Duration(in.ExpiresInSeconds) * time.Second
+	}
+	if ttl > config.App.HoldMaxTTL {
+		return nil, fmt.Errorf("hold expiry cannot exceed %s", config.App.HoldMaxTTL)
+	}
+
+	tx, err := s.holdRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, accountID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.accountService.limitService.checkHolderTx(ctx, tx, acc, middleware.ClientIDFromContext(ctx), in.Amount); err != nil {
+		return nil, err
+	}
+	if err := currency.ValidateAmount(acc.Currency, in.Amount); err != nil {
+		return nil, err
+	}
+	if err := checkFunds(acc, in.Amount); err != nil {
+		return nil, err
+	}
+
+	h, err := s.holdRepository.CreateTx(ctx, tx, &model.Hold{
+		AccountID: accountID,
+		Amount:    in.Amount,
+		Currency:  acc.Currency,
+		Reference: in.Reference,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.accountService.evict(ctx, acc)
+
+	return mapper.HoldToResponse(h), nil
+}
+
+func (s *HoldService) GetById(ctx context.Context, accountID, holdID int) (*dto.HoldResponse, error) {
+	h, err := s.holdRepository.GetById(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if h.AccountID != accountID {
+		return nil, fmt.Errorf("hold %d %w", holdID, ErrNotFound)
+	}
+	return mapper.HoldToResponse(h), nil
+}
+
+func (s *HoldService) ListByAccountID(ctx context.Context, accountID int, status string) ([]*dto.HoldResponse, error) {
+	if accountID <= 0 {
+		return nil, fmt.Errorf("invalid account id")
+	}
+	switch status {
+	case "", model.HoldStatusActive, model.HoldStatusCaptured, model.HoldStatusReleased, model.HoldStatusExpired:
+	default:
+		return nil, fmt.Errorf("invalid status: %q", status)
+	}
+	items, err := s.holdRepository.ListByAccountID(ctx, accountID, status)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.HoldsToResponseSlice(items), nil
+}
+
+// Capture settles all or part of an active hold, either as a withdrawal or,
+// when in.ToAccount is set, as a transfer. Any uncaptured remainder is
+// released. The capture is posted like the withdrawal or transfer it
+// becomes: monitoring screens it, limits and fees apply, and it waits for a
+// second person where that operation would.
+func (s *HoldService) Capture(ctx context.Context, accountID, holdID int, in dto.HoldCapture) (*dto.HoldResponse, error) {
+	h, err := s.holdRepository.GetById(ctx, holdID)
+	if err != nil {
+		return nil, err
+	}
+	if h.AccountID != accountID {
+		return nil, fmt.Errorf("hold %d %w", holdID, ErrNotFound)
+	}
+	if h.Status != model.HoldStatusActive {
+		return nil, fmt.Errorf("hold %d is %s", holdID, h.Status)
+	}
+	p := holdCapturePayload{
+		AccountID: accountID,
+		HoldID:    holdID,
+		Amount:    in.Amount,
+		ClientID:  middleware.ClientIDFromContext(ctx),
+	}
+	if strings.TrimSpace(in.ToAccount) != "" {
+		to, err := resolveAccountNumber(ctx, &s.accountRepository, in.ToAccount)
+		if err != nil {
+			return nil, fmt.Errorf("to_account: %w", err)
+		}
+		if to.ID == accountID {
+			return nil, errors.New("from and to accounts must differ")
+		}
+		p.ToAccountID = to.ID
+	}
+	if p.Amount == 0 {
+		p.Amount = h.Amount
+	}
+	if p.Amount > h.Amount {
+		return nil, fmt.Errorf("capture amount %v exceeds hold amount %v", p.Amount, h.Amount)
+	}
+	if err := currency.ValidateAmount(h.Currency, p.Amount); err != nil {
+		return nil, err
+	}
+	from, err := s.accountRepository.GetById(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.accountService.limitService.checkHolder(ctx, from, p.ClientID, p.Amount); err != nil {
+		return nil, err
+	}
+
+	monitoringService := &s.accountService.monitoringService
+	ev := monitoring.Event{Operation: monitoring.OperationWithdrawal, AccountID: accountID, Amount: p.Amount, Currency: h.Currency}
+	approve := middleware.ActorFromContext(ctx) != "" && needsApproval(model.ApprovalOperationWithdrawal)
+	if p.ToAccountID > 0 {
+		ev.Operation = monitoring.OperationTransfer
+		ev.CounterpartyID = p.ToAccountID
+		if ev.NewCounterparty, err = monitoringService.newCounterparty(ctx, accountID, p.ToAccountID); err != nil {
+			return nil, err
+		}
+		approve = largeTransfer(h.Currency, p.Amount)
+	}
+	verdict, err := monitoringService.screen(ctx, ev)
+	if err != nil {
+		return nil, err
+	}
+	if approve || verdict.Action == monitoring.ActionReview {
+		err := requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationHoldCapture,
+			"hold", strconv.Itoa(holdID), p)
+		monitoringService.alert(ctx, ev, verdict, "", approvalID(err))
+		return nil, err
+	}
+
+	tx, err := s.holdRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	settled, t, touched, err := s.captureTx(ctx, tx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	monitoringService.alert(ctx, ev, verdict, t.ID, 0)
+	for _, a := range touched {
+		s.accountService.evict(ctx, a)
+	}
+
+	return mapper.HoldToResponse(settled), nil
+}
+
+// captureTx settles the hold and posts the capture through withdrawTx or
+// transferTx inside the caller's transaction. The accounts are locked before
+// the hold, as Release does. Besides the settled hold and the transaction it
+// returns the accounts whose cached copies are stale after commit.
+func (s *HoldService) captureTx(ctx context.Context, tx pgx.Tx, p holdCapturePayload) (*model.Hold, *model.Transaction, []*model.Account, error) {
+	from, err := s.accountRepository.GetByIdTx(ctx, tx, p.AccountID, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := lockAccountsTx(ctx, tx, &s.accountRepository, p.AccountID, p.ToAccountID, feeRevenueAccount(from.Currency)); err != nil {
+		return nil, nil, nil, err
+	}
+	h, err := s.lockActive(ctx, tx, p.AccountID, p.HoldID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if p.Amount > h.Amount {
+		return nil, nil, nil, fmt.Errorf("capture amount %v exceeds hold amount %v", p.Amount, h.Amount)
+	}
+	var to *model.Account
+	if p.ToAccountID > 0 {
+		if to, err = s.accountRepository.GetByIdTx(ctx, tx, p.ToAccountID, true); err != nil {
+			return nil, nil, nil, err
+		}
+		if to.Currency != h.Currency {
+			return nil, nil, nil, fmt.Errorf("currency mismatch: hold is in %s, account %d is in %s", h.Currency, to.ID, to.Currency)
+		}
+	}
+
+	// Settling first takes the hold out of the held amount, so the capture
+	// draws on the funds it reserved rather than being refused for them.
+	if _, err := s.holdRepository.SettleTx(ctx, tx, h.ID, model.HoldStatusCaptured, p.Amount, nil); err != nil {
+		return nil, nil, nil, err
+	}
+	var t *model.Transaction
+	var touched []*model.Account
+	if to != nil {
+		if t, _, err = s.transactionService.transferTx(ctx, tx, dto.TransactionCreate{
+			FromAccountID: p.AccountID,
+			ToAccountID:   p.ToAccountID,
+			Amount:        p.Amount,
+			InitiatedBy:   p.ClientID,
+		}); err != nil {
+			return nil, nil, nil, err
+		}
+		touched = []*model.Account{from, to}
+		if id := feeRevenueAccount(from.Currency); id != 0 {
+			revenue, err := s.accountRepository.GetByIdTx(ctx, tx, id, false)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			touched = append(touched, revenue)
+		}
+	} else {
+		var updated, revenue *model.Account
+		if t, updated, revenue, err = s.accountService.withdrawTx(ctx, tx, p.AccountID, p.Amount, p.ClientID); err != nil {
+			return nil, nil, nil, err
+		}
+		touched = []*model.Account{updated}
+		if revenue != nil {
+			touched = append(touched, revenue)
+		}
+	}
+
+	txID, err := strconv.Atoi(t.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unexpected transaction id %q", t.ID)
+	}
+	settled, err := s.holdRepository.LinkTransactionTx(ctx, tx, h.ID, txID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return settled, t, touched, nil
+}
+
+func (s *HoldService) Release(ctx context.Context, accountID, holdID int) (*dto.HoldResponse, error) {
+	tx, err := s.holdRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, accountID, true)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.lockActive(ctx, tx, accountID, holdID); err != nil {
+		return nil, err
+	}
+	settled, err := s.holdRepository.SettleTx(ctx, tx, holdID, model.HoldStatusReleased, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	s.accountService.evict(ctx, acc)
+
+	return mapper.HoldToResponse(settled), nil
+}
+
+// ExpireDue marks overdue holds as expired. It is run periodically by the
+// hold expiry worker.
+func (s *HoldService) ExpireDue(ctx context.Context) error {
+	expired, err := s.holdRepository.ExpireDue(ctx)
+	if err != nil {
+		return err
+	}
+	for _, h := range expired {
+		if acc, err := s.accountRepository.GetById(ctx, h.AccountID); err == nil {
+			s.accountService.evict(ctx, acc)
+		}
+	}
+	if len(expired) > 0 {
+		log.Printf("expired %d holds", len(expired))
+	}
+	return nil
+}
+
+func (s *HoldService) lockActive(ctx context.Context, tx pgx.Tx, accountID, holdID int) (*model.Hold, error) {
+	h, err := s.holdRepository.GetByIdTx(ctx, tx, holdID, true)
+	if err != nil {
+		return nil, err
+	}
+	if h.AccountID != accountID {
+		return nil, fmt.Errorf("hold %d %w", holdID, ErrNotFound)
+	}
+	if h.Status != model.HoldStatusActive {
+		return nil, fmt.Errorf("hold %d is %s", holdID, h.Status)
+	}
+	if !time.Now().Before(h.ExpiresAt) {
+		return nil, fmt.Errorf("hold %d is expired", holdID)
+	}
+	return h, nil
+}
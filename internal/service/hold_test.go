@@ -0,0 +1,92 @@
+package service
+
+import (
+	"basic-gin/internal/accountnumber"
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/testdb"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+func newTestHoldService(pool *pgxpool.Pool) *HoldService {
+	accountService, transactionService := newTestServices(pool)
+	return NewHoldService(repository.NewHoldRepository(pool), repository.NewAccountRepository(pool),
+		repository.NewApprovalRepository(pool), repository.NewAuditRepository(pool), accountService, transactionService)
+}
+
+func TestCaptureAboveApprovalThreshold(t *testing.T) {
+	pool := testdb.Open(t)
+	saved := config.App
+	t.Cleanup(func() { config.App = saved })
+	config.App.ApprovalOperations = map[string]bool{model.ApprovalOperationTransfer: true}
+	config.App.ApprovalTransferThresholds = map[string]int{"RSD": 500}
+	config.App.HoldDefaultTTL = time.Hour
+	config.App.HoldMaxTTL = time.Hour
+	config.App.AccountNumbers = accountnumber.Scheme{Kind: accountnumber.KindLegacy}
+
+	// The seed data has client 1 owning account 1 with 1000 RSD, and account
+	// 2 numbered 2345678901234567.
+	s := newTestHoldService(pool)
+	ctx := context.WithValue(context.Background(), middleware.ClientIDKey, 1)
+	h, err := s.Create(ctx, 1, dto.HoldCreate{Amount: 800})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.Capture(ctx, 1, h.ID, dto.HoldCapture{ToAccount: "2345678901234567"})
+	var pending *ApprovalRequiredError
+	if !errors.As(err, &pending) {
+		t.Fatalf("Capture = %v, want an approval request", err)
+	}
+	if pending.Approval.Operation != model.ApprovalOperationHoldCapture {
+		t.Errorf("approval operation = %s, want %s", pending.Approval.Operation, model.ApprovalOperationHoldCapture)
+	}
+	got, err := s.holdRepository.GetById(ctx, h.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != model.HoldStatusActive {
+		t.Errorf("hold is %s while the capture waits, want active", got.Status)
+	}
+	acc, err := s.accountRepository.GetById(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 1000 || acc.HeldAmount != 800 {
+		t.Errorf("balance %v held %v while the capture waits, want 1000 and 800", acc.Balance, acc.HeldAmount)
+	}
+
+	// The approved capture is posted as a transfer that draws on the hold.
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+	settled, posted, _, err := s.captureTx(ctx, tx, holdCapturePayload{AccountID: 1, HoldID: h.ID, Amount: 800, ToAccountID: 2, ClientID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if settled.Status != model.HoldStatusCaptured || settled.TransactionID == nil {
+		t.Errorf("hold is %s with transaction %v, want captured with a transaction", settled.Status, settled.TransactionID)
+	}
+	if posted.Type != model.TransactionTypeTransfer || posted.ToAccountID != 2 {
+		t.Errorf("capture posted as %s to account %d, want a transfer to 2", posted.Type, posted.ToAccountID)
+	}
+	if acc, err = s.accountRepository.GetById(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	if acc.Balance != 200 || acc.HeldAmount != 0 {
+		t.Errorf("balance %v held %v after capture, want 200 and 0", acc.Balance, acc.HeldAmount)
+	}
+}
@@ -0,0 +1,446 @@
+package service
+
+import (
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// accrualPlaces is the precision daily accruals are stored at.
+const accrualPlaces = 8
+
+// maxAccrualDays bounds how far back a single run catches up per account.
+const maxAccrualDays = 3660
+
+type InterestService struct {
+	interestRepository    repository.InterestRepository
+	accountRepository     repository.AccountRepository
+	transactionRepository repository.TransactionRepository
+	auditRepository       repository.AuditRepository
+	accountService        AccountService
+}
+
+func NewInterestService(
+	interestRepository *repository.InterestRepository,
+	accountRepository *repository.AccountRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+	accountService *AccountService,
+) *InterestService {
+	return &InterestService{
+		interestRepository:    *interestRepository,
+		accountRepository:     *accountRepository,
+		transactionRepository: *transactionRepository,
+		auditRepository:       *auditRepository,
+		accountService:        *accountService,
+	}
+}
+
+func (s *InterestService) ListProducts(ctx context.Context) ([]*dto.AccountProductResponse, error) {
+	items, err := s.interestRepository.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AccountProductsToResponseSlice(items), nil
+}
+
+// UpsertProduct creates or changes an account product. A new rate applies to
+// days accrued from the next run on; past accruals keep the rate they used.
+func (s *InterestService) UpsertProduct(ctx context.Context, code string, in dto.AccountProductUpsert) (*dto.AccountProductResponse, error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if code == "" || len(code) > 20 {
+		return nil, errors.New("product code must be 1 to 20 characters")
+	}
+
+	tx, err := s.interestRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	saved, err := s.interestRepository.UpsertProductTx(ctx, tx, &model.AccountProduct{
+		Code:       code,
+		Name:       strings.TrimSpace(in.Name),
+		AnnualRate: in.AnnualRate,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "account_product.upsert", "account_product", code, map[string]any{
+		"name":        saved.Name,
+		"annual_rate": saved.AnnualRate,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.AccountProductToResponse(saved), nil
+}
+
+func (s *InterestService) ListAccruals(ctx context.Context, accountID int, from, to string) ([]*dto.InterestAccrualResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+	now := time.Now().UTC()
+	fromDate, err := parseDate(from, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return nil, err
+	}
+	toDate, err := parseDate(to, now)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.accountRepository.GetById(ctx, accountID); err != nil {
+		return nil, err
+	}
+	items, err := s.interestRepository.ListAccruals(ctx, accountID, fromDate, toDate)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.InterestAccrualsToResponseSlice(items), nil
+}
+
+func (s *InterestService) ListCapitalizations(ctx context.Context, accountID int) ([]*dto.InterestCapitalizationResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+	if _, err := s.accountRepository.GetById(ctx, accountID); err != nil {
+		return nil, err
+	}
+	items, err := s.interestRepository.ListCapitalizations(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.InterestCapitalizationsToResponseSlice(items), nil
+}
+
+// RunDue is the worker entry point: it accrues every interest-bearing account
+// up to yesterday and capitalizes completed months.
+func (s *InterestService) RunDue(ctx context.Context) error {
+	report, err := s.Run(ctx, dto.InterestRunRequest{})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, a := range report.Accounts {
+		if a.Error != "" {
+			failed++
+			log.Printf("interest: account %d: %s", a.AccountID, a.Error)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("interest run failed for %d of %d accounts", failed, len(report.Accounts))
+	}
+	return nil
+}
+
+// Run accrues interest for each day before as_of on each account's end-of-day
+// balance, then capitalizes every month that ended before as_of's month with
+// one interest transaction. Each account is processed in its own transaction
+// under its row lock. A dry run computes the same without writing, so the
+// report shows exactly what would be posted; only dry runs may look ahead.
+func (s *InterestService) Run(ctx context.Context, in dto.InterestRunRequest) (*dto.InterestRunReport, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	asOf, err := parseDate(in.AsOf, today)
+	if err != nil {
+		return nil, err
+	}
+	if asOf.After(today) && !in.DryRun {
+		return nil, errors.New("as_of in the future is only allowed for dry runs")
+	}
+
+	ids := []int{in.AccountID}
+	if in.AccountID == 0 {
+		if ids, err = s.interestRepository.ListAccruingAccountIDs(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &dto.InterestRunReport{
+		AsOf:     asOf.Format("2006-01-02"),
+		DryRun:   in.DryRun,
+		Accounts: make([]*dto.InterestAccountReport, 0, len(ids)),
+	}
+	for _, id := range ids {
+		res, err := s.runAccount(ctx, id, asOf, in.DryRun)
+		if err != nil {
+			res = &dto.InterestAccountReport{AccountID: id, Accrued: "0", Error: err.Error()}
+		}
+		report.Accounts = append(report.Accounts, res)
+	}
+	return report, nil
+}
+
+// runAccount accrues and capitalizes one account. A dry run reads in a
+// read-only transaction without the row lock and writes nothing, so it
+// neither holds up postings nor uses up ids.
+func (s *InterestService) runAccount(ctx context.Context, id int, asOf time.Time, dryRun bool) (*dto.InterestAccountReport, error) {
+	var opts pgx.TxOptions
+	if dryRun {
+		opts.AccessMode = pgx.ReadOnly
+	}
+	tx, err := s.accountRepository.Pool().BeginTx(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, id, !dryRun)
+	if err != nil {
+		return nil, err
+	}
+	rateStr, err := s.interestRepository.AnnualRateTx(ctx, tx, acc.AccountType)
+	if err != nil {
+		return nil, err
+	}
+	rate, ok := new(big.Rat).SetString(rateStr)
+	if !ok {
+		return nil, fmt.Errorf("invalid annual rate %q", rateStr)
+	}
+
+	res := &dto.InterestAccountReport{
+		AccountID:       acc.ID,
+		Currency:        acc.Currency,
+		AnnualRate:      rateStr,
+		Capitalizations: []*dto.InterestCapitalizationResponse{},
+	}
+
+	// Months are capitalized from what was accrued before this run plus what
+	// it accrues, so a dry run reports the same without storing anything.
+	monthStart := time.Date(asOf.Year(), asOf.Month(), 1, 0, 0, 0, 0, time.UTC)
+	periods, err := s.interestRepository.OpenPeriodTotalsTx(ctx, tx, acc.ID, monthStart)
+	if err != nil {
+		return nil, err
+	}
+
+	// Accrue each day not yet accrued, from the day the account was opened.
+	last, err := s.interestRepository.LastAccrualDateTx(ctx, tx, acc.ID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := accrualWindow(acc.CreatedAt, last, asOf)
+
+	// Once an account has started accruing it keeps a row per day, at zero
+	// while its product pays nothing, so a later rate never applies backwards.
+	accrued := new(big.Rat)
+	var accruals []*model.InterestAccrual
+	if (rate.Sign() > 0 || last != nil) && !start.After(end) {
+		balances, err := s.interestRepository.EndOfDayBalancesTx(ctx, tx, acc.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range balances {
+			amount, err := dailyInterest(b.Balance, rate)
+			if err != nil {
+				return nil, err
+			}
+			a := &model.InterestAccrual{
+				AccountID:   acc.ID,
+				AccrualDate: b.Date,
+				Balance:     b.Balance,
+				AnnualRate:  rateStr,
+				Amount:      amount.FloatString(accrualPlaces),
+			}
+			if !dryRun {
+				if err := s.interestRepository.CreateAccrualTx(ctx, tx, a); err != nil {
+					return nil, fmt.Errorf("accrue %s: %w", b.Date.Format("2006-01-02"), err)
+				}
+			}
+			accruals = append(accruals, a)
+			accrued.Add(accrued, amount)
+			res.DaysAccrued++
+		}
+	}
+	res.Accrued = accrued.FloatString(accrualPlaces)
+	if periods, err = addAccruals(periods, accruals, monthStart); err != nil {
+		return nil, err
+	}
+
+	// Capitalize completed months, carrying sub-minor-unit remainders forward.
+	places, err := currency.MinorUnits(acc.Currency)
+	if err != nil {
+		return nil, err
+	}
+	carryStr, err := s.interestRepository.LastCarryTx(ctx, tx, acc.ID)
+	if err != nil {
+		return nil, err
+	}
+	caps, err := capitalize(periods, carryStr, places)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *model.Account
+	for _, c := range caps {
+		c.AccountID = acc.ID
+		if !dryRun {
+			amount, err := strconv.ParseFloat(c.Posted, 64)
+			if err != nil {
+				return nil, err
+			}
+			if amount > 0 {
+				if updated, err = s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, acc.ID, amount); err != nil {
+					return nil, err
+				}
+				t := &model.Transaction{
+					Type:        model.TransactionTypeInterest,
+					ToAccountID: acc.ID,
+					Amount:      amount,
+					Currency:    acc.Currency,
+					Description: "interest for " + c.Period.Format("2006-01"),
+				}
+				if err := s.transactionRepository.SaveTx(ctx, tx, t); err != nil {
+					return nil, err
+				}
+				if c.TransactionID, err = strconv.Atoi(t.ID); err != nil {
+					return nil, err
+				}
+			}
+			if err := s.interestRepository.CreateCapitalizationTx(ctx, tx, c); err != nil {
+				return nil, err
+			}
+		}
+		res.Capitalizations = append(res.Capitalizations, mapper.InterestCapitalizationToResponse(c))
+	}
+
+	if dryRun {
+		return res, nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	if updated != nil {
+		s.accountService.evict(ctx, updated)
+	}
+	return res, nil
+}
+
+// accrualWindow is the first and last day a run as of asOf accrues: from
+// the day after the last accrual, or the day the account was opened, to the
+// day before asOf, at most maxAccrualDays back.
+func accrualWindow(createdAt time.Time, last *time.Time, asOf time.Time) (start, end time.Time) {
+	start = createdAt.UTC().Truncate(24 * time.Hour)
+	if last != nil {
+		start = last.UTC().AddDate(0, 0, 1)
+	}
+	end = asOf.AddDate(0, 0, -1)
+	if earliest := end.AddDate(0, 0, -maxAccrualDays); start.Before(earliest) {
+		start = earliest
+	}
+	return start, end
+}
+
+// addAccruals adds the accruals dated before the given day to the open
+// period totals, by calendar month, oldest first.
+func addAccruals(periods []model.InterestPeriodTotal, accruals []*model.InterestAccrual, before time.Time) ([]model.InterestPeriodTotal, error) {
+	totals := map[time.Time]*big.Rat{}
+	for _, p := range periods {
+		total, ok := new(big.Rat).SetString(p.Total)
+		if !ok {
+			return nil, fmt.Errorf("invalid accrued total %q", p.Total)
+		}
+		totals[p.Period.UTC()] = total
+	}
+	for _, a := range accruals {
+		if !a.AccrualDate.Before(before) {
+			continue
+		}
+		amount, ok := new(big.Rat).SetString(a.Amount)
+		if !ok {
+			return nil, fmt.Errorf("invalid accrual %q", a.Amount)
+		}
+		d := a.AccrualDate.UTC()
+		period := time.Date(d.Year(), d.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if totals[period] == nil {
+			totals[period] = new(big.Rat)
+		}
+		totals[period].Add(totals[period], amount)
+	}
+
+	out := make([]model.InterestPeriodTotal, 0, len(totals))
+	for period, total := range totals {
+		out = append(out, model.InterestPeriodTotal{Period: period, Total: total.FloatString(accrualPlaces)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Period.Before(out[j].Period) })
+	return out, nil
+}
+
+// capitalize posts each period's total plus what the previous one carried,
+// rounded down to places decimals; the remainder is carried into the next.
+func capitalize(periods []model.InterestPeriodTotal, carryIn string, places int) ([]*model.InterestCapitalization, error) {
+	carry, ok := new(big.Rat).SetString(carryIn)
+	if !ok {
+		return nil, fmt.Errorf("invalid interest carry %q", carryIn)
+	}
+	out := make([]*model.InterestCapitalization, 0, len(periods))
+	for _, p := range periods {
+		total, ok := new(big.Rat).SetString(p.Total)
+		if !ok {
+			return nil, fmt.Errorf("invalid accrued total %q", p.Total)
+		}
+		gross := new(big.Rat).Add(total, carry)
+		posted := floorRat(gross, places)
+		carryOut := new(big.Rat).Sub(gross, posted)
+		out = append(out, &model.InterestCapitalization{
+			Period:    p.Period,
+			Accrued:   total.FloatString(accrualPlaces),
+			CarriedIn: carry.FloatString(accrualPlaces),
+			Posted:    posted.FloatString(places),
+			CarryOut:  carryOut.FloatString(accrualPlaces),
+		})
+		carry = carryOut
+	}
+	return out, nil
+}
+
+// dailyInterest is one day of interest on balance at the annual percentage
+// rate, ACT/365, rounded half away from zero to accrualPlaces. Zero and
+// negative balances earn nothing.
+func dailyInterest(balance string, rate *big.Rat) (*big.Rat, error) {
+	b, ok := new(big.Rat).SetString(balance)
+	if !ok {
+		return nil, fmt.Errorf("invalid balance %q", balance)
+	}
+	if b.Sign() <= 0 {
+		return new(big.Rat), nil
+	}
+	v := new(big.Rat).Mul(b, rate)
+	v.Quo(v, big.NewRat(36500, 1))
+	out, _ := new(big.Rat).SetString(v.FloatString(accrualPlaces))
+	return out, nil
+}
+
+// floorRat rounds a non-negative r down to places decimals.
+func floorRat(r *big.Rat, places int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(places)), nil)
+	n := new(big.Int).Mul(r.Num(), scale)
+	n.Quo(n, r.Denom())
+	return new(big.Rat).SetFrac(n, scale)
+}
+
+// parseDate reads a YYYY-MM-DD day in UTC, returning def when s is empty.
+func parseDate(s string, def time.Time) (time.Time, error) {
+	if strings.TrimSpace(s) == "" {
+		return def.UTC().Truncate(24 * time.Hour), nil
+	}
+	d, err := time.Parse("2006-01-02", strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"basic-gin/internal/model"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func day(s string) time.Time {
+	d, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestDailyInterest(t *testing.T) {
+	tests := []struct {
+		balance, rate, want string
+	}{
+		{"36500", "1", "1.00000000"},
+		{"1000", "3.5", "0.09589041"},
+		// 0.000000005 exactly is rounded half away from zero.
+		{"0.01825", "0.01", "0.00000001"},
+		{"0.01824", "0.01", "0.00000000"},
+		{"0", "5", "0.00000000"},
+		{"-2500", "5", "0.00000000"},
+	}
+	for _, tt := range tests {
+		rate, _ := new(big.Rat).SetString(tt.rate)
+		got, err := dailyInterest(tt.balance, rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.FloatString(accrualPlaces) != tt.want {
+			t.Errorf("dailyInterest(%s, %s%%) = %s, want %s", tt.balance, tt.rate, got.FloatString(accrualPlaces), tt.want)
+		}
+	}
+}
+
+func TestFloorRat(t *testing.T) {
+	tests := []struct {
+		in     string
+		places int
+		want   string
+	}{
+		{"1.239", 2, "1.23"},
+		{"1.23", 2, "1.23"},
+		{"0.00999999", 2, "0.00"},
+		{"5.9999", 0, "5"},
+		{"12.3456789", 3, "12.345"},
+	}
+	for _, tt := range tests {
+		r, _ := new(big.Rat).SetString(tt.in)
+		if got := floorRat(r, tt.places).FloatString(tt.places); got != tt.want {
+			t.Errorf("floorRat(%s, %d) = %s, want %s", tt.in, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestAccrualWindow(t *testing.T) {
+	feb28 := day("2024-02-28")
+	tests := []struct {
+		name       string
+		created    time.Time
+		last       *time.Time
+		asOf       time.Time
+		start, end string
+		days       int
+	}{
+		{"first run from the opening day", day("2024-01-15").Add(13 * time.Hour), nil, day("2024-02-01"), "2024-01-15", "2024-01-31", 17},
+		{"leap day is accrued", day("2023-01-01"), &feb28, day("2024-03-01"), "2024-02-29", "2024-02-29", 1},
+		{"leap year has 366 days", day("2024-01-01"), nil, day("2025-01-01"), "2024-01-01", "2024-12-31", 366},
+		{"nothing left to accrue", day("2023-01-01"), &feb28, day("2024-02-29"), "2024-02-29", "2024-02-28", 0},
+		{"catch-up is bounded", day("2000-01-01"), nil, day("2024-01-01"), "2013-12-23", "2023-12-31", maxAccrualDays + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := accrualWindow(tt.created, tt.last, tt.asOf)
+			if got := start.Format("2006-01-02"); got != tt.start {
+				t.Errorf("start = %s, want %s", got, tt.start)
+			}
+			if got := end.Format("2006-01-02"); got != tt.end {
+				t.Errorf("end = %s, want %s", got, tt.end)
+			}
+			if days := int(end.Sub(start).Hours()/24) + 1; days != tt.days {
+				t.Errorf("window has %d days, want %d", days, tt.days)
+			}
+		})
+	}
+}
+
+func TestLeapYearAccruesMoreThanTheRate(t *testing.T) {
+	// ACT/365 accrues every day of a leap year at 1/365 of the rate.
+	rate := big.NewRat(1, 1)
+	start, end := accrualWindow(day("2024-01-01"), nil, day("2025-01-01"))
+	total := new(big.Rat)
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		amount, err := dailyInterest("36500", rate)
+		if err != nil {
+			t.Fatal(err)
+		}
+		total.Add(total, amount)
+	}
+	if got := total.FloatString(accrualPlaces); got != "366.00000000" {
+		t.Errorf("2024 accrued %s on 36500 at 1%%, want 366.00000000", got)
+	}
+}
+
+func TestAddAccruals(t *testing.T) {
+	stored := []model.InterestPeriodTotal{{Period: day("2024-01-01"), Total: "0.50000000"}}
+	accruals := []*model.InterestAccrual{
+		{AccrualDate: day("2024-01-31"), Amount: "0.12345678"},
+		{AccrualDate: day("2024-02-01"), Amount: "0.00000001"},
+		{AccrualDate: day("2024-02-29"), Amount: "0.10000000"},
+		{AccrualDate: day("2024-03-01"), Amount: "9.00000000"},
+	}
+	got, err := addAccruals(stored, accruals, day("2024-03-01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []model.InterestPeriodTotal{
+		{Period: day("2024-01-01"), Total: "0.62345678"},
+		{Period: day("2024-02-01"), Total: "0.10000001"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("addAccruals = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Period.Equal(want[i].Period) || got[i].Total != want[i].Total {
+			t.Errorf("period %d = %s %s, want %s %s", i, got[i].Period.Format("2006-01"), got[i].Total,
+				want[i].Period.Format("2006-01"), want[i].Total)
+		}
+	}
+}
+
+func TestCapitalizeCarriesRemainders(t *testing.T) {
+	tests := []struct {
+		name    string
+		carryIn string
+		totals  []string
+		places  int
+		posted  []string
+		carry   []string
+	}{
+		{"whole minor units", "0", []string{"1.25000000"}, 2, []string{"1.25"}, []string{"0.00000000"}},
+		{"remainder carried", "0", []string{"1.23456789", "0.00543211"}, 2, []string{"1.23", "0.01"}, []string{"0.00456789", "0.00000000"}},
+		{"carry in from the last capitalization", "0.00900000", []string{"0.00100000"}, 2, []string{"0.01"}, []string{"0.00000000"}},
+		{"nothing posted below a minor unit", "0", []string{"0.00400000", "0.00400000"}, 2, []string{"0.00", "0.00"}, []string{"0.00400000", "0.00800000"}},
+		{"currency without minor units", "0.5", []string{"0.70000000"}, 0, []string{"1"}, []string{"0.20000000"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods := make([]model.InterestPeriodTotal, len(tt.totals))
+			for i, total := range tt.totals {
+				periods[i] = model.InterestPeriodTotal{Period: day("2024-01-01").AddDate(0, i, 0), Total: total}
+			}
+			caps, err := capitalize(periods, tt.carryIn, tt.places)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, c := range caps {
+				if c.Posted != tt.posted[i] || c.CarryOut != tt.carry[i] {
+					t.Errorf("%s posted %s carrying %s, want %s carrying %s",
+						c.Period.Format("2006-01"), c.Posted, c.CarryOut, tt.posted[i], tt.carry[i])
+				}
+				if i > 0 && c.CarriedIn != caps[i-1].CarryOut {
+					t.Errorf("%s carried in %s, the previous month carried out %s", c.Period.Format("2006-01"), c.CarriedIn, caps[i-1].CarryOut)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrLimitExceeded = errors.New("limit exceeded")
+
+// ErrNotAuthorized is returned when the acting client's role on an account
+// does not allow the operation.
+var ErrNotAuthorized = errors.New("not authorized")
+
+type LimitService struct {
+	limitRepository         repository.LimitRepository
+	accountRepository       repository.AccountRepository
+	transactionRepository   repository.TransactionRepository
+	auditRepository         repository.AuditRepository
+	approvalRepository      repository.ApprovalRepository
+	accountHolderRepository repository.AccountHolderRepository
+}
+
+func NewLimitService(
+	limitRepository *repository.LimitRepository,
+	accountRepository *repository.AccountRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+	approvalRepository *repository.ApprovalRepository,
+	accountHolderRepository *repository.AccountHolderRepository,
+) *LimitService {
+	return &LimitService{
+		limitRepository:         *limitRepository,
+		accountRepository:       *accountRepository,
+		transactionRepository:   *transactionRepository,
+		auditRepository:         *auditRepository,
+		approvalRepository:      *approvalRepository,
+		accountHolderRepository: *accountHolderRepository,
+	}
+}
+
+// limitWindows returns the start of the current UTC day and month.
+func limitWindows(now time.Time) (day, month time.Time) {
+	now = now.UTC()
+	day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	month = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return day, month
+}
+
+// effectiveTx merges the limit rows for acc cap by cap; an account row wins
+// over a client row, which wins over the product default.
+func (s *LimitService) effectiveTx(ctx context.Context, tx pgx.Tx, acc *model.Account, operation string) (*model.EffectiveLimits, error) {
+	rows, err := s.limitRepository.ForAccountTx(ctx, tx, acc, operation)
+	if err != nil {
+		return nil, err
+	}
+	rank := map[string]int{model.LimitScopeProduct: 1, model.LimitScopeClient: 2, model.LimitScopeAccount: 3}
+	pick := func(cur *model.LimitCap, v *float64, scope string) *model.LimitCap {
+		if v == nil || (cur != nil && rank[cur.Scope] >= rank[scope]) {
+			return cur
+		}
+		return &model.LimitCap{Amount: *v, Scope: scope}
+	}
+
+	eff := &model.EffectiveLimits{Operation: operation}
+	for _, l := range rows {
+		eff.PerTransaction = pick(eff.PerTransaction, l.PerTransaction, l.Scope)
+		eff.Daily = pick(eff.Daily, l.Daily, l.Scope)
+		eff.Monthly = pick(eff.Monthly, l.Monthly, l.Scope)
+	}
+	return eff, nil
+}
+
+// checkTx refuses a debit of amount from acc that would break one of its
+// caps. acc must be locked by the caller so concurrent debits are counted.
+func (s *LimitService) checkTx(ctx context.Context, tx pgx.Tx, acc *model.Account, operation string, amount float64) error {
+	eff, err := s.effectiveTx(ctx, tx, acc, operation)
+	if err != nil {
+		return err
+	}
+	if c := eff.PerTransaction; c != nil && amount > c.Amount {
+		return fmt.Errorf("%w: %s of %.3f %s is above the per-transaction limit of %.3f", ErrLimitExceeded, operation, amount, acc.Currency, c.Amount)
+	}
+
+	day, month := limitWindows(time.Now())
+	for _, w := range []struct {
+		name  string
+		cap   *model.LimitCap
+		since time.Time
+	}{{"daily", eff.Daily, day}, {"monthly", eff.Monthly, month}} {
+		if w.cap == nil {
+			continue
+		}
+		used, err := s.transactionRepository.SumOutgoingSinceTx(ctx, tx, acc.ID, operation, w.since)
+		if err != nil {
+			return err
+		}
+		if used+amount > w.cap.Amount {
+			remaining := max(w.cap.Amount-used, 0)
+			return fmt.Errorf("%w: %s %s limit of %.3f %s has %.3f remaining", ErrLimitExceeded, w.name, operation, w.cap.Amount, acc.Currency, remaining)
+		}
+	}
+	return nil
+}
+
+// checkHolder refuses a debit of amount from acc made by clientID unless the
+// client's role on the account allows it. Only debits that an operator or the
+// system makes may name no client; any other debit without one is refused.
+func (s *LimitService) checkHolder(ctx context.Context, acc *model.Account, clientID int, amount float64) error {
+	if clientID == 0 {
+		return unattributedDebit(ctx, acc)
+	}
+	h, err := s.accountHolderRepository.GetActive(ctx, acc.ID, clientID)
+	if err != nil {
+		return err
+	}
+	return holderMayDebit(h, acc, clientID, amount)
+}
+
+// checkHolderTx is checkHolder inside the caller's transaction, which also
+// counts what an authorized user already spent today against its spend
+// limit. acc must be locked by the caller.
+func (s *LimitService) checkHolderTx(ctx context.Context, tx pgx.Tx, acc *model.Account, clientID int, amount float64) error {
+	if clientID == 0 {
+		return unattributedDebit(ctx, acc)
+	}
+	h, err := s.accountHolderRepository.GetActiveTx(ctx, tx, acc.ID, clientID)
+	if err != nil {
+		return err
+	}
+	if err := holderMayDebit(h, acc, clientID, amount); err != nil {
+		return err
+	}
+	if h.Role != model.HolderRoleAuthorizedUser {
+		return nil
+	}
+	day, _ := limitWindows(time.Now())
+	used, err := s.transactionRepository.SumInitiatedSinceTx(ctx, tx, acc.ID, clientID, day)
+	if err != nil {
+		return err
+	}
+	if used+amount > h.SpendLimit {
+		remaining := max(h.SpendLimit-used, 0)
+		return fmt.Errorf("%w: daily spend limit of %.3f %s for client %d has %.3f remaining", ErrLimitExceeded, h.SpendLimit, acc.Currency, clientID, remaining)
+	}
+	return nil
+}
+
+// unattributedDebit allows a debit from acc that names no client only when
+// an operator or the system makes it.
+func unattributedDebit(ctx context.Context, acc *model.Account) error {
+	if middleware.ActorFromContext(ctx) != "" || middleware.IsSystem(ctx) {
+		return nil
+	}
+	return fmt.Errorf("%w: a debit from account %d needs X-Client-ID or X-Actor", ErrNotAuthorized, acc.ID)
+}
+
+func holderMayDebit(h *model.AccountHolder, acc *model.Account, clientID int, amount float64) error {
+	switch {
+	case h == nil:
+		return fmt.Errorf("%w: client %d is not a holder of account %d", ErrNotAuthorized, clientID, acc.ID)
+	case !h.CanDebit():
+		return fmt.Errorf("%w: client %d may only view account %d", ErrNotAuthorized, clientID, acc.ID)
+	case h.Role == model.HolderRoleAuthorizedUser && amount > h.SpendLimit:
+		return fmt.Errorf("%w: %.3f %s is above the daily spend limit of %.3f for client %d", ErrLimitExceeded, amount, acc.Currency, h.SpendLimit, clientID)
+	}
+	return nil
+}
+
+// Get shows the caps on the account and how much of each is left.
+func (s *LimitService) Get(ctx context.Context, accountID int) (*dto.AccountLimitsResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+
+	tx, err := s.accountRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc, err := s.accountRepository.GetByIdTx(ctx, tx, accountID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	day, month := limitWindows(now)
+	resp := &dto.AccountLimitsResponse{AccountID: acc.ID, Currency: acc.Currency}
+	for _, op := range []string{model.LimitOperationTransfer, model.LimitOperationWithdrawal} {
+		eff, err := s.effectiveTx(ctx, tx, acc, op)
+		if err != nil {
+			return nil, err
+		}
+		ol := &dto.OperationLimitsResponse{Operation: op}
+		if c := eff.PerTransaction; c != nil {
+			ol.PerTransaction = &dto.LimitCapResponse{Limit: c.Amount, Source: c.Scope}
+		}
+		usage := func(c *model.LimitCap, since, resets time.Time) (*dto.LimitUsageResponse, error) {
+			if c == nil {
+				return nil, nil
+			}
+			used, err := s.transactionRepository.SumOutgoingSinceTx(ctx, tx, acc.ID, op, since)
+			if err != nil {
+				return nil, err
+			}
+			return &dto.LimitUsageResponse{
+				Limit:     c.Amount,
+				Source:    c.Scope,
+				Used:      currency.Round(acc.Currency, used),
+				Remaining: currency.Round(acc.Currency, max(c.Amount-used, 0)),
+				ResetsAt:  resets,
+			}, nil
+		}
+		if ol.Daily, err = usage(eff.Daily, day, day.AddDate(0, 0, 1)); err != nil {
+			return nil, err
+		}
+		if ol.Monthly, err = usage(eff.Monthly, month, month.AddDate(0, 1, 0)); err != nil {
+			return nil, err
+		}
+		resp.Limits = append(resp.Limits, ol)
+	}
+	return resp, nil
+}
+
+func (s *LimitService) List(ctx context.Context, scope string) ([]*dto.TransferLimitResponse, error) {
+	scope = strings.ToLower(strings.TrimSpace(scope))
+	switch scope {
+	case "", model.LimitScopeProduct, model.LimitScopeClient, model.LimitScopeAccount:
+	default:
+		return nil, fmt.Errorf("unknown limit scope %q", scope)
+	}
+	items, err := s.limitRepository.List(ctx, scope)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TransferLimitsToResponseSlice(items), nil
+}
+
+// Upsert creates or replaces a limit; the change is audited. When limit
+// changes are configured for approval it waits for a second person instead.
+func (s *LimitService) Upsert(ctx context.Context, in dto.TransferLimitUpsert) (*dto.TransferLimitResponse, error) {
+	l := mapper.ToTransferLimitFromUpsert(in)
+	if err := checkLimitSubject(&l); err != nil {
+		return nil, err
+	}
+
+	if needsApproval(model.ApprovalOperationTransferLimit) {
+		return nil, requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationTransferLimit,
+			l.Scope, limitSubject(&l), transferLimitPayload{Upsert: &in})
+	}
+
+	tx, err := s.limitRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	resp, err := s.upsertTx(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (s *LimitService) upsertTx(ctx context.Context, tx pgx.Tx, in dto.TransferLimitUpsert) (*dto.TransferLimitResponse, error) {
+	l := mapper.ToTransferLimitFromUpsert(in)
+	if err := checkLimitSubject(&l); err != nil {
+		return nil, err
+	}
+
+	if l.Scope == model.LimitScopeAccount {
+		acc, err := s.accountRepository.GetByIdTx(ctx, tx, l.AccountID, false)
+		if err != nil {
+			return nil, err
+		}
+		if l.Currency != "" && l.Currency != acc.Currency {
+			return nil, fmt.Errorf("account %d is in %s, not %s", acc.ID, acc.Currency, l.Currency)
+		}
+		l.Currency = acc.Currency
+	}
+	if !currency.IsSupported(l.Currency) {
+		return nil, fmt.Errorf("unsupported currency: %q", in.Currency)
+	}
+	for _, v := range []*float64{l.PerTransaction, l.Daily, l.Monthly} {
+		if v == nil {
+			continue
+		}
+		if err := currency.ValidateAmount(l.Currency, *v); err != nil {
+			return nil, err
+		}
+	}
+
+	saved, err := s.limitRepository.UpsertTx(ctx, tx, &l)
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.TransferLimitToResponse(saved)
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "transfer_limit.upsert", "transfer_limit", strconv.Itoa(saved.ID), map[string]any{
+		"limit": resp,
+	})); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// checkLimitSubject normalizes l and checks that exactly the subject its
+// scope needs is set and that it caps something.
+func checkLimitSubject(l *model.TransferLimit) error {
+	l.AccountType = strings.ToLower(strings.TrimSpace(l.AccountType))
+	l.Currency = currency.Normalize(l.Currency)
+
+	switch l.Scope {
+	case model.LimitScopeProduct:
+		if l.AccountType == "" || l.ClientID != 0 || l.AccountID != 0 {
+			return errors.New("a product limit needs account_type and nothing else")
+		}
+	case model.LimitScopeClient:
+		if l.ClientID == 0 || l.AccountType != "" || l.AccountID != 0 {
+			return errors.New("a client limit needs client_id and nothing else")
+		}
+	case model.LimitScopeAccount:
+		if l.AccountID == 0 || l.AccountType != "" || l.ClientID != 0 {
+			return errors.New("an account limit needs account_id and nothing else")
+		}
+	default:
+		return fmt.Errorf("unknown limit scope %q", l.Scope)
+	}
+	if l.PerTransaction == nil && l.Daily == nil && l.Monthly == nil {
+		return errors.New("at least one of per_transaction, daily and monthly is required")
+	}
+	return nil
+}
+
+// limitSubject is the id of whatever l applies to.
+func limitSubject(l *model.TransferLimit) string {
+	switch l.Scope {
+	case model.LimitScopeClient:
+		return strconv.Itoa(l.ClientID)
+	case model.LimitScopeAccount:
+		return strconv.Itoa(l.AccountID)
+	}
+	return l.AccountType
+}
+
+// Delete removes a limit, so the less specific scopes apply again. When
+// limit changes are configured for approval it waits for a second person
+// instead.
+func (s *LimitService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("invalid id")
+	}
+
+	if needsApproval(model.ApprovalOperationTransferLimit) {
+		return requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationTransferLimit,
+			"transfer_limit", strconv.Itoa(id), transferLimitPayload{DeleteID: id})
+	}
+
+	tx, err := s.limitRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := s.deleteTx(ctx, tx, id); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *LimitService) deleteTx(ctx context.Context, tx pgx.Tx, id int) (*dto.TransferLimitResponse, error) {
+	deleted, err := s.limitRepository.DeleteTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.TransferLimitToResponse(deleted)
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "transfer_limit.delete", "transfer_limit", strconv.Itoa(id), map[string]any{
+		"limit": resp,
+	})); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestUnattributedDebit(t *testing.T) {
+	acc := &model.Account{ID: 1}
+	tests := []struct {
+		name string
+		ctx  context.Context
+		ok   bool
+	}{
+		{"operator", context.WithValue(context.Background(), middleware.ActorKey, "ops"), true},
+		{"system", middleware.WithSystem(context.Background()), true},
+		{"neither", context.Background(), false},
+	}
+	for _, tt := range tests {
+		err := unattributedDebit(tt.ctx, acc)
+		if tt.ok && err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrNotAuthorized) {
+			t.Errorf("%s: got %v, want ErrNotAuthorized", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrMonitoringBlocked = errors.New("blocked by transaction monitoring")
+
+type MonitoringService struct {
+	engine                *monitoring.Engine
+	monitoringRepository  repository.MonitoringRepository
+	caseRepository        repository.CaseRepository
+	transactionRepository repository.TransactionRepository
+	auditRepository       repository.AuditRepository
+}
+
+func NewMonitoringService(
+	engine *monitoring.Engine,
+	monitoringRepository *repository.MonitoringRepository,
+	caseRepository *repository.CaseRepository,
+	transactionRepository *repository.TransactionRepository,
+	auditRepository *repository.AuditRepository,
+) *MonitoringService {
+	return &MonitoringService{
+		engine:                engine,
+		monitoringRepository:  *monitoringRepository,
+		caseRepository:        *caseRepository,
+		transactionRepository: *transactionRepository,
+		auditRepository:       *auditRepository,
+	}
+}
+
+func postedFrom(t *model.Transaction) monitoring.Posted {
+	return monitoring.Posted{
+		ID:            t.ID,
+		Operation:     t.Type,
+		FromAccountID: t.FromAccountID,
+		ToAccountID:   t.ToAccountID,
+		Amount:        t.Amount,
+		Currency:      t.Currency,
+		ToAmount:      t.ToAmount,
+		ToCurrency:    t.ToCurrency,
+		At:            t.CreatedAt,
+	}
+}
+
+// screen scores ev against the rules in force and the account's recent
+// history. A blocked operation is alerted on and refused with
+// ErrMonitoringBlocked; the caller holds a review verdict for approval and
+// alerts on the rest once it knows the outcome.
+func (s *MonitoringService) screen(ctx context.Context, ev monitoring.Event) (monitoring.Result, error) {
+	return s.evaluate(ctx, ev, func(since time.Time) ([]*model.Transaction, error) {
+		return s.transactionRepository.ListMovementsSince(ctx, ev.AccountID, since)
+	})
+}
+
+// screenTx is screen for an operation posted inside tx; the history includes
+// what tx has already posted, such as earlier items of the same batch.
+func (s *MonitoringService) screenTx(ctx context.Context, tx pgx.Tx, ev monitoring.Event) (monitoring.Result, error) {
+	return s.evaluate(ctx, ev, func(since time.Time) ([]*model.Transaction, error) {
+		return s.transactionRepository.ListMovementsSinceTx(ctx, tx, ev.AccountID, since)
+	})
+}
+
+func (s *MonitoringService) evaluate(ctx context.Context, ev monitoring.Event, movements func(since time.Time) ([]*model.Transaction, error)) (monitoring.Result, error) {
+	ev.At = time.Now()
+	var history []monitoring.Movement
+	if lookback := s.engine.Lookback(); lookback > 0 {
+		txs, err := movements(ev.At.Add(-lookback))
+		if err != nil {
+			return monitoring.Result{}, err
+		}
+		for _, t := range txs {
+			history = append(history, postedFrom(t).Movements(ev.AccountID)...)
+		}
+	}
+
+	res := s.engine.Evaluate(ev, history)
+	if res.Action == monitoring.ActionBlock {
+		id := s.alert(ctx, ev, res, "", 0)
+		return res, fmt.Errorf("%w: alert %d", ErrMonitoringBlocked, id)
+	}
+	return res, nil
+}
+
+// newCounterparty reports whether fromID has never paid toID.
+func (s *MonitoringService) newCounterparty(ctx context.Context, fromID, toID int) (bool, error) {
+	paid, err := s.transactionRepository.HasTransfer(ctx, fromID, toID)
+	return !paid, err
+}
+
+// alert records res when any rule fired and returns the alert id. The alert
+// joins the client's open case, or opens one. The operation has been decided
+// by then, so a failure is only logged.
+func (s *MonitoringService) alert(ctx context.Context, ev monitoring.Event, res monitoring.Result, transactionID string, approvalID int) int {
+	if len(res.Hits) == 0 {
+		return 0
+	}
+	id, err := s.recordAlert(ctx, &model.MonitoringAlert{
+		Operation:             ev.Operation,
+		AccountID:             ev.AccountID,
+		CounterpartyAccountID: ev.CounterpartyID,
+		Amount:                ev.Amount,
+		Currency:              ev.Currency,
+		Score:                 res.Score,
+		Action:                res.Action,
+		Hits:                  mapper.AlertHitsFromResult(res),
+		TransactionID:         transactionID,
+		ApprovalID:            approvalID,
+	})
+	if err != nil {
+		log.Printf("monitoring: could not record alert for %s on account %d: %v", ev.Operation, ev.AccountID, err)
+		return 0
+	}
+	return id
+}
+
+func (s *MonitoringService) recordAlert(ctx context.Context, a *model.MonitoringAlert) (int, error) {
+	tx, err := s.monitoringRepository.Pool().Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	c, created, err := s.caseRepository.OpenForAccountTx(ctx, tx, a.AccountID, "monitoring")
+	if err != nil {
+		return 0, err
+	}
+	if created {
+		if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "case.open", "case", strconv.Itoa(c.ID), map[string]any{
+			"client_id":  c.ClientID,
+			"account_id": a.AccountID,
+		})); err != nil {
+			return 0, err
+		}
+	}
+	a.CaseID = c.ID
+	saved, err := s.monitoringRepository.CreateAlertTx(ctx, tx, a)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return saved.ID, nil
+}
+
+func (s *MonitoringService) Rules() *dto.MonitoringRulesResponse {
+	return mapper.MonitoringRulesToResponse(s.engine.Rules())
+}
+
+func (s *MonitoringService) ReloadRules() (*dto.MonitoringRulesResponse, error) {
+	if err := s.engine.LoadFile(config.App.MonitoringRulesFile); err != nil {
+		return nil, err
+	}
+	return s.Rules(), nil
+}
+
+// ReloadIfChanged picks up edits to the rules file. It is run periodically
+// by the monitoring rules worker; a broken file leaves the rules in force.
+func (s *MonitoringService) ReloadIfChanged(ctx context.Context) error {
+	reloaded, err := s.engine.ReloadIfChanged(config.App.MonitoringRulesFile)
+	if err != nil {
+		return err
+	}
+	if reloaded {
+		log.Printf("monitoring rules reloaded from %s", config.App.MonitoringRulesFile)
+	}
+	return nil
+}
+
+func (s *MonitoringService) ListAlerts(ctx context.Context, status string, accountID, limit, offset int) ([]*dto.AlertResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.monitoringRepository.ListAlerts(ctx, strings.ToLower(strings.TrimSpace(status)), accountID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AlertsToResponseSlice(items), nil
+}
+
+func (s *MonitoringService) GetAlert(ctx context.Context, id int) (*dto.AlertResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	a, err := s.monitoringRepository.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.AlertToResponse(a), nil
+}
+
+// CloseAlert records an analyst's conclusion on an alert; it is audited.
+func (s *MonitoringService) CloseAlert(ctx context.Context, id int, in dto.AlertClose) (*dto.AlertResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	note := strings.TrimSpace(in.Note)
+	if note == "" {
+		return nil, errors.New("note is required")
+	}
+
+	tx, err := s.monitoringRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	closed, err := s.monitoringRepository.CloseAlertTx(ctx, tx, id, middleware.ActorFromContext(ctx), note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "monitoring_alert.close", "monitoring_alert", strconv.Itoa(id), map[string]any{
+		"note": note,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.AlertToResponse(closed), nil
+}
+
+// Replay runs the transactions posted in [from, to) through rules in the
+// order they were posted and calls fn for each one on which a rule fired.
+// Nothing is written. It returns how many transactions were evaluated.
+func (s *MonitoringService) Replay(ctx context.Context, rules *monitoring.RuleSet, from, to time.Time, fn func(dto.MonitoringReplayed)) (int, error) {
+	const page = 1000
+	r := monitoring.NewReplayer(rules)
+	count := 0
+	for after := 0; ; {
+		txs, err := s.transactionRepository.ListPostedAfter(ctx, after, from, to, page)
+		if err != nil {
+			return count, err
+		}
+		if len(txs) == 0 {
+			return count, nil
+		}
+		if after, err = strconv.Atoi(txs[len(txs)-1].ID); err != nil {
+			return count, fmt.Errorf("transaction id %q: %w", txs[len(txs)-1].ID, err)
+		}
+
+		for _, t := range txs {
+			p := postedFrom(t)
+			res, ok := r.Replay(p)
+			if !ok {
+				continue
+			}
+			count++
+			if len(res.Hits) == 0 {
+				continue
+			}
+			accountID := p.FromAccountID
+			if p.Operation == monitoring.OperationDeposit {
+				accountID = p.ToAccountID
+			}
+			fn(dto.MonitoringReplayed{
+				TransactionID: t.ID,
+				Operation:     t.Type,
+				AccountID:     accountID,
+				Amount:        t.Amount,
+				Currency:      t.Currency,
+				At:            t.CreatedAt,
+				Score:         res.Score,
+				Action:        res.Action,
+				Hits:          mapper.AlertHitsToResponse(mapper.AlertHitsFromResult(res)),
+			})
+		}
+	}
+}
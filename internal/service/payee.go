@@ -0,0 +1,162 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/textmatch"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// payeeCloseMatchScore is the name similarity from which a name that is not
+// an exact match still counts as a close one.
+const payeeCloseMatchScore = 0.9
+
+// PayeeService resolves account numbers and confirms payees: it tells a
+// payer whether the name they have for the recipient belongs to the account.
+type PayeeService struct {
+	accountRepository repository.AccountRepository
+	clientRepository  repository.ClientRepository
+	holderRepository  repository.AccountHolderRepository
+}
+
+func NewPayeeService(
+	accountRepository *repository.AccountRepository,
+	clientRepository *repository.ClientRepository,
+	holderRepository *repository.AccountHolderRepository,
+) *PayeeService {
+	return &PayeeService{
+		accountRepository: *accountRepository,
+		clientRepository:  *clientRepository,
+		holderRepository:  *holderRepository,
+	}
+}
+
+// resolveAccountNumber validates number against the configured scheme and
+// finds the account it (or its legacy form) belongs to.
+func resolveAccountNumber(ctx context.Context, repo *repository.AccountRepository, number string) (*model.Account, error) {
+	canonical, err := config.App.AccountNumbers.Canonical(number)
+	if err != nil {
+		return nil, err
+	}
+	return repo.GetByAccountNumber(ctx, canonical)
+}
+
+func (s *PayeeService) Verify(ctx context.Context, in dto.PayeeVerify) (*dto.PayeeVerifyResponse, error) {
+	acc, err := resolveAccountNumber(ctx, &s.accountRepository, in.Account)
+	if err != nil {
+		return nil, err
+	}
+	result, holder, err := s.check(ctx, acc, in.Name)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.PayeeVerifyResponse{Result: result}
+	if result == model.PayeeCloseMatch {
+		resp.AccountName = holder
+	}
+	return resp, nil
+}
+
+// resolveParties fills in the account ids of a transfer from the account
+// numbers it is addressed by. The recipient may already have been filled in
+// from a saved beneficiary.
+func (s *PayeeService) resolveParties(ctx context.Context, in *dto.TransactionCreate) error {
+	for _, p := range []struct {
+		field  string
+		number string
+		id     *int
+	}{
+		{"from_account", in.FromAccount, &in.FromAccountID},
+		{"to_account", in.ToAccount, &in.ToAccountID},
+	} {
+		if strings.TrimSpace(p.number) == "" {
+			if *p.id <= 0 {
+				return fmt.Errorf("%s is required", p.field)
+			}
+			continue
+		}
+		acc, err := resolveAccountNumber(ctx, &s.accountRepository, p.number)
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.field, err)
+		}
+		*p.id = acc.ID
+	}
+	return nil
+}
+
+// confirm checks name against the holders of the recipient account and
+// returns the outcome. A mismatch is an error; so is a close match the payer
+// has not accepted.
+func (s *PayeeService) confirm(ctx context.Context, accountID int, name string, acceptClose bool) (string, error) {
+	acc, err := s.accountRepository.GetById(ctx, accountID)
+	if err != nil {
+		return "", err
+	}
+	result, holder, err := s.check(ctx, acc, name)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case result == model.PayeeNoMatch:
+		return result, errors.New("beneficiary name does not match any holder of the recipient account")
+	case result == model.PayeeCloseMatch && !acceptClose:
+		return result, fmt.Errorf("beneficiary name is a close match to the account holder %q; repeat with accept_close_match to proceed", holder)
+	}
+	return result, nil
+}
+
+// check compares name with the owner and every active joint owner of acc.
+// holder is the name a match or close match was found on.
+func (s *PayeeService) check(ctx context.Context, acc *model.Account, name string) (result, holder string, err error) {
+	ids, err := s.holderRepository.Managers(ctx, acc.ID)
+	if err != nil {
+		return "", "", err
+	}
+	if !slices.Contains(ids, acc.ClientId) {
+		ids = append([]int{acc.ClientId}, ids...)
+	}
+	holders := make([]string, 0, len(ids))
+	for _, id := range ids {
+		client, err := s.clientRepository.GetById(ctx, int64(id))
+		if err != nil {
+			return "", "", err
+		}
+		holders = append(holders, strings.TrimSpace(client.Name()))
+	}
+	result, holder = matchHolders(name, holders)
+	return result, holder, nil
+}
+
+// matchHolders returns the best outcome of matching given against any of
+// holders and the name it was reached on; a close match is only reported
+// when no holder matches outright.
+func matchHolders(given string, holders []string) (result, holder string) {
+	result = model.PayeeNoMatch
+	for _, h := range holders {
+		switch matchPayee(given, h) {
+		case model.PayeeMatch:
+			return model.PayeeMatch, h
+		case model.PayeeCloseMatch:
+			if result == model.PayeeNoMatch {
+				result, holder = model.PayeeCloseMatch, h
+			}
+		}
+	}
+	return result, holder
+}
+
+func matchPayee(given, holder string) string {
+	switch {
+	case textmatch.SameTokens(given, holder):
+		return model.PayeeMatch
+	case textmatch.InitialsMatch(given, holder), textmatch.NameSimilarity(given, holder) >= payeeCloseMatchScore:
+		return model.PayeeCloseMatch
+	}
+	return model.PayeeNoMatch
+}
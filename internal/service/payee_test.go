@@ -0,0 +1,29 @@
+package service
+
+import (
+	"basic-gin/internal/model"
+	"testing"
+)
+
+func TestMatchHolders(t *testing.T) {
+	holders := []string{"Ana Ivić", "Marko Jovanović"}
+	tests := []struct {
+		given, result, holder string
+	}{
+		{"Ana Ivic", model.PayeeMatch, "Ana Ivić"},
+		{"Jovanović Marko", model.PayeeMatch, "Marko Jovanović"},
+		{"M. Jovanovic", model.PayeeCloseMatch, "Marko Jovanović"},
+		{"Petar Petrović", model.PayeeNoMatch, ""},
+	}
+	for _, tt := range tests {
+		result, holder := matchHolders(tt.given, holders)
+		if result != tt.result || holder != tt.holder {
+			t.Errorf("matchHolders(%q) = %s on %q, want %s on %q", tt.given, result, holder, tt.result, tt.holder)
+		}
+	}
+
+	// An exact match on a joint owner wins over a close match on the owner.
+	if result, holder := matchHolders("Ana Jovanović", []string{"Ana Jovanovic Ivić", "Ana Jovanović"}); result != model.PayeeMatch || holder != "Ana Jovanović" {
+		t.Errorf("matchHolders preferred %s on %q over the joint owner's exact match", result, holder)
+	}
+}
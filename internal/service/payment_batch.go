@@ -0,0 +1,516 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/paymentfile"
+	"basic-gin/internal/repository"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxBatchesPerTick bounds how many batches one worker tick works through.
+const maxBatchesPerTick = 10
+
+type PaymentBatchService struct {
+	paymentBatchRepository repository.PaymentBatchRepository
+	accountRepository      repository.AccountRepository
+	transactionService     TransactionService
+}
+
+func NewPaymentBatchService(
+	paymentBatchRepository *repository.PaymentBatchRepository,
+	accountRepository *repository.AccountRepository,
+	transactionService *TransactionService,
+) *PaymentBatchService {
+	return &PaymentBatchService{
+		paymentBatchRepository: *paymentBatchRepository,
+		accountRepository:      *accountRepository,
+		transactionService:     *transactionService,
+	}
+}
+
+// Submit parses and validates an uploaded payment file and stores it for the
+// worker. Every line is checked; in all_or_nothing mode a single invalid line
+// rejects the batch, in best_effort mode only the invalid lines are dropped.
+// A rejected batch is stored too, so its report stays available.
+func (s *PaymentBatchService) Submit(ctx context.Context, in dto.PaymentBatchSubmit, body io.Reader) (*dto.PaymentBatchResponse, error) {
+	if in.Format != model.PaymentBatchFormatPain001 && in.Format != model.PaymentBatchFormatCSV {
+		return nil, fmt.Errorf("format must be %q or %q", model.PaymentBatchFormatPain001, model.PaymentBatchFormatCSV)
+	}
+	if in.Mode == "" {
+		in.Mode = model.PaymentBatchModeAllOrNothing
+	}
+	if in.Mode != model.PaymentBatchModeAllOrNothing && in.Mode != model.PaymentBatchModeBestEffort {
+		return nil, fmt.Errorf("mode must be %q or %q", model.PaymentBatchModeAllOrNothing, model.PaymentBatchModeBestEffort)
+	}
+
+	file, err := paymentfile.Parse(in.Format, body)
+	if err != nil {
+		return nil, err
+	}
+	if max := config.App.PaymentBatchMaxItems; max > 0 && len(file.Payments) > max {
+		return nil, fmt.Errorf("payment file has %d payments, at most %d are allowed per batch", len(file.Payments), max)
+	}
+
+	clientID := middleware.ClientIDFromContext(ctx)
+	items, err := s.validate(ctx, file.Payments, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &model.PaymentBatch{
+		Format:      in.Format,
+		Mode:        in.Mode,
+		Status:      model.PaymentBatchStatusPending,
+		MessageID:   file.MessageID,
+		ControlSum:  math.Round(file.ControlSum*1000) / 1000,
+		CreatedBy:   middleware.ActorFromContext(ctx),
+		InitiatedBy: clientID,
+	}
+	var invalid []*model.PaymentBatchItem
+	for _, i := range items {
+		if i.Status == model.PaymentItemStatusInvalid {
+			invalid = append(invalid, i)
+		}
+	}
+	switch {
+	case len(invalid) == len(items):
+		b.Status = model.PaymentBatchStatusRejected
+		b.Error = "no line of the file is valid"
+	case len(invalid) > 0 && in.Mode == model.PaymentBatchModeAllOrNothing:
+		b.Status = model.PaymentBatchStatusRejected
+		b.Error = fmt.Sprintf("%d of %d lines are invalid", len(invalid), len(items))
+	}
+
+	tx, err := s.paymentBatchRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.paymentBatchRepository.CreateTx(ctx, tx, b, items); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	saved, err := s.paymentBatchRepository.GetById(ctx, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	resp := mapper.PaymentBatchToResponse(saved)
+	if len(invalid) > 0 {
+		if resp.Errors, err = s.invalidItems(ctx, b.ID); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// validate resolves the accounts of every payment and checks what can be
+// checked before execution; balances are only checked when the batch runs.
+// Payments that would need approval as a single transfer are refused, as a
+// batch cannot wait for a second person, and so are payments clientID's role
+// on the debtor account does not allow.
+func (s *PaymentBatchService) validate(ctx context.Context, payments []paymentfile.Payment, clientID int) ([]*model.PaymentBatchItem, error) {
+	accounts := map[string]*model.Account{}
+	lookup := func(number string) (*model.Account, error) {
+		if a, ok := accounts[number]; ok {
+			return a, nil
+		}
+		a, err := s.accountRepository.GetByAccountNumber(ctx, number)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				accounts[number] = nil
+				return nil, nil
+			}
+			return nil, err
+		}
+		accounts[number] = a
+		return a, nil
+	}
+	scheme := config.App.AccountNumbers
+
+	seen := map[string]int{}
+	items := make([]*model.PaymentBatchItem, 0, len(payments))
+	for _, p := range payments {
+		item := &model.PaymentBatchItem{
+			LineNo:            p.LineNo,
+			FromAccountNumber: p.FromAccount,
+			ToAccountNumber:   p.ToAccount,
+			Amount:            p.Amount,
+			Currency:          p.Currency,
+			EndToEndID:        p.EndToEndID,
+			Description:       p.Description,
+			Status:            model.PaymentItemStatusPending,
+		}
+		items = append(items, item)
+
+		reason, err := func() (string, error) {
+			if p.Err != "" {
+				return p.Err, nil
+			}
+			if p.EndToEndID != "" {
+				if first, dup := seen[p.EndToEndID]; dup {
+					return fmt.Sprintf("end-to-end id %q is already used on line %d", p.EndToEndID, first), nil
+				}
+				seen[p.EndToEndID] = p.LineNo
+			}
+			fromNumber, err := scheme.Canonical(p.FromAccount)
+			if err != nil {
+				return "debtor account: " + err.Error(), nil
+			}
+			toNumber, err := scheme.Canonical(p.ToAccount)
+			if err != nil {
+				return "creditor account: " + err.Error(), nil
+			}
+			from, err := lookup(fromNumber)
+			if err != nil || from == nil {
+				return fmt.Sprintf("debtor account %q not found", p.FromAccount), err
+			}
+			item.FromAccountID = from.ID
+			to, err := lookup(toNumber)
+			if err != nil || to == nil {
+				return fmt.Sprintf("creditor account %q not found", p.ToAccount), err
+			}
+			item.ToAccountID = to.ID
+			if from.ID == to.ID {
+				return "debtor and creditor accounts must differ", nil
+			}
+			if err := s.transactionService.limitService.checkHolder(ctx, from, clientID, p.Amount); err != nil {
+				if errors.Is(err, ErrNotAuthorized) || errors.Is(err, ErrLimitExceeded) {
+					return err.Error(), nil
+				}
+				return "", err
+			}
+			if item.Currency == "" {
+				item.Currency = from.Currency
+			}
+			if item.Currency != from.Currency {
+				return fmt.Sprintf("currency %s does not match debtor account currency %s", item.Currency, from.Currency), nil
+			}
+			if to.Currency != from.Currency {
+				return fmt.Sprintf("creditor account is in %s; batches only carry same-currency payments", to.Currency), nil
+			}
+			if err := currency.ValidateAmount(from.Currency, p.Amount); err != nil {
+				return err.Error(), nil
+			}
+			if largeTransfer(from.Currency, p.Amount) {
+				return fmt.Sprintf("amount reaches the %d %s approval threshold; send it as a single transfer",
+					config.App.ApprovalTransferThresholds[from.Currency], from.Currency), nil
+			}
+			return "", nil
+		}()
+		if err != nil {
+			return nil, err
+		}
+		if reason != "" {
+			item.Status = model.PaymentItemStatusInvalid
+			item.Error = reason
+		}
+		if len(item.Currency) != 3 {
+			item.Currency = ""
+		}
+	}
+	return items, nil
+}
+
+func (s *PaymentBatchService) invalidItems(ctx context.Context, batchID int) ([]*dto.PaymentBatchItemResponse, error) {
+	items, err := s.paymentBatchRepository.ListItems(ctx, batchID, model.PaymentItemStatusInvalid)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.PaymentBatchItemsToResponseSlice(items), nil
+}
+
+func (s *PaymentBatchService) GetById(ctx context.Context, id int) (*dto.PaymentBatchResponse, error) {
+	b, err := s.paymentBatchRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.PaymentBatchToResponse(b), nil
+}
+
+func (s *PaymentBatchService) ListItems(ctx context.Context, id int, status string) ([]*dto.PaymentBatchItemResponse, error) {
+	switch status {
+	case "", model.PaymentItemStatusPending, model.PaymentItemStatusInvalid, model.PaymentItemStatusSucceeded,
+		model.PaymentItemStatusFailed, model.PaymentItemStatusSkipped:
+	default:
+		return nil, fmt.Errorf("unknown item status %q", status)
+	}
+	if _, err := s.paymentBatchRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	items, err := s.paymentBatchRepository.ListItems(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.PaymentBatchItemsToResponseSlice(items), nil
+}
+
+// RunDue processes pending batches, and batches whose worker stopped
+// renewing its lease. It is called periodically by the payment batch worker.
+func (s *PaymentBatchService) RunDue(ctx context.Context) error {
+	for i := 0; i < maxBatchesPerTick; i++ {
+		b, err := s.paymentBatchRepository.ClaimNext(ctx, config.App.PaymentBatchLease)
+		if err != nil || b == nil {
+			return err
+		}
+		if err := s.process(ctx, b); err != nil {
+			return fmt.Errorf("payment batch %d: %w", b.ID, err)
+		}
+	}
+	return nil
+}
+
+// errClaimLost stops processing once another worker has taken the batch.
+var errClaimLost = errors.New("claim on payment batch lost")
+
+func (s *PaymentBatchService) process(ctx context.Context, b *model.PaymentBatch) error {
+	items, err := s.paymentBatchRepository.ListItems(ctx, b.ID, model.PaymentItemStatusPending)
+	if err != nil {
+		return err
+	}
+	if b.Mode == model.PaymentBatchModeAllOrNothing {
+		err = s.processAllOrNothing(ctx, b, items)
+	} else {
+		err = s.processBestEffort(ctx, b, items)
+	}
+	if errors.Is(err, errClaimLost) {
+		log.Printf("payment batch %d: taken over by another worker", b.ID)
+		return nil
+	}
+	return err
+}
+
+// processAllOrNothing posts every item in one database transaction. The
+// accounts involved are locked up front in ascending id order, the order
+// transferTx itself uses, so concurrent transfers cannot deadlock against
+// the batch. The first failure rolls everything back.
+func (s *PaymentBatchService) processAllOrNothing(ctx context.Context, b *model.PaymentBatch, items []*model.PaymentBatchItem) error {
+	tx, err := s.paymentBatchRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.renewClaimTx(ctx, tx, b); err != nil {
+		return err
+	}
+
+	// Every account the batch touches, fee revenue accounts included, is
+	// locked up front in id order; the transfers below then take no lock of
+	// their own out of order.
+	var ids []int
+	currencies := map[int]string{}
+	for _, i := range items {
+		if _, ok := currencies[i.FromAccountID]; !ok {
+			from, err := s.accountRepository.GetByIdTx(ctx, tx, i.FromAccountID, false)
+			if err != nil {
+				return err
+			}
+			currencies[i.FromAccountID] = from.Currency
+		}
+		ids = append(ids, i.FromAccountID, i.ToAccountID, feeRevenueAccount(currencies[i.FromAccountID]))
+	}
+	if err := lockAccountsTx(ctx, tx, &s.accountRepository, ids...); err != nil {
+		return err
+	}
+
+	var alerts []*screenedTransfer
+	for _, item := range items {
+		txID, screened, transferErr := s.transfer(ctx, tx, b, item)
+		if transferErr != nil {
+			_ = tx.Rollback(ctx)
+			return s.failAllOrNothing(ctx, b, item, transferErr)
+		}
+		item.Status = model.PaymentItemStatusSucceeded
+		item.TransactionID = txID
+		if err := s.paymentBatchRepository.UpdateItemTx(ctx, tx, item); err != nil {
+			return err
+		}
+		alerts = append(alerts, screened)
+	}
+
+	if err := s.paymentBatchRepository.FinishTx(ctx, tx, b.ID, model.PaymentBatchStatusCompleted, ""); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	for i, screened := range alerts {
+		if screened != nil {
+			s.transactionService.alertScreened(ctx, *screened, strconv.Itoa(items[i].TransactionID), 0)
+		}
+	}
+	return nil
+}
+
+// failAllOrNothing records the item that broke the batch; everything else
+// still pending is skipped.
+func (s *PaymentBatchService) failAllOrNothing(ctx context.Context, b *model.PaymentBatch, item *model.PaymentBatchItem, cause error) error {
+	tx, err := s.paymentBatchRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.renewClaimTx(ctx, tx, b); err != nil {
+		return err
+	}
+	item.Status = model.PaymentItemStatusFailed
+	item.Error = cause.Error()
+	item.TransactionID = 0
+	if err := s.paymentBatchRepository.UpdateItemTx(ctx, tx, item); err != nil {
+		return err
+	}
+	if err := s.paymentBatchRepository.MarkItemsTx(ctx, tx, b.ID, model.PaymentItemStatusPending, model.PaymentItemStatusSkipped,
+		fmt.Sprintf("batch rolled back: line %d failed", item.LineNo)); err != nil {
+		return err
+	}
+	if err := s.paymentBatchRepository.FinishTx(ctx, tx, b.ID, model.PaymentBatchStatusFailed,
+		fmt.Sprintf("line %d: %v", item.LineNo, cause)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// processBestEffort posts each item in its own transaction and records its
+// outcome with it, so a restarted worker picks up exactly where this one
+// stopped.
+func (s *PaymentBatchService) processBestEffort(ctx context.Context, b *model.PaymentBatch, items []*model.PaymentBatchItem) error {
+	for _, item := range items {
+		if err := s.runItem(ctx, b, item); err != nil {
+			return err
+		}
+	}
+
+	tx, err := s.paymentBatchRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.renewClaimTx(ctx, tx, b); err != nil {
+		return err
+	}
+	// Counts include items finished by earlier workers on this batch.
+	current, err := s.paymentBatchRepository.GetById(ctx, b.ID)
+	if err != nil {
+		return err
+	}
+	status := model.PaymentBatchStatusFailed
+	switch {
+	case current.SucceededCount > 0 && current.FailedCount+current.InvalidCount == 0:
+		status = model.PaymentBatchStatusCompleted
+	case current.SucceededCount > 0:
+		status = model.PaymentBatchStatusPartiallyCompleted
+	}
+	if err := s.paymentBatchRepository.FinishTx(ctx, tx, b.ID, status, ""); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (s *PaymentBatchService) runItem(ctx context.Context, b *model.PaymentBatch, item *model.PaymentBatchItem) error {
+	tx, err := s.paymentBatchRepository.Pool().Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := s.renewClaimTx(ctx, tx, b); err != nil {
+		return err
+	}
+
+	// The transfer runs in a savepoint so a failed item can be recorded in
+	// the same transaction.
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin savepoint: %w", err)
+	}
+	txID, screened, transferErr := s.transfer(ctx, sp, b, item)
+	if transferErr == nil {
+		if err := sp.Commit(ctx); err != nil {
+			return fmt.Errorf("release savepoint: %w", err)
+		}
+		item.Status = model.PaymentItemStatusSucceeded
+		item.TransactionID = txID
+	} else {
+		_ = sp.Rollback(ctx)
+		item.Status = model.PaymentItemStatusFailed
+		item.Error = transferErr.Error()
+	}
+	if err := s.paymentBatchRepository.UpdateItemTx(ctx, tx, item); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	if screened != nil {
+		s.transactionService.alertScreened(ctx, *screened, strconv.Itoa(txID), 0)
+	}
+	return nil
+}
+
+// transfer screens one item through transaction monitoring and posts it
+// through the regular transfer path, so locking, funds checks and fees are
+// the same as for a single transfer. An item monitoring holds for review
+// fails; it can be sent again as a single transfer, which waits for
+// approval. The returned alert is due once the item is committed; it is nil
+// when the item was already posted by an earlier worker.
+func (s *PaymentBatchService) transfer(ctx context.Context, tx pgx.Tx, b *model.PaymentBatch, item *model.PaymentBatchItem) (int, *screenedTransfer, error) {
+	in := dto.TransactionCreate{
+		FromAccountID:  item.FromAccountID,
+		ToAccountID:    item.ToAccountID,
+		Amount:         item.Amount,
+		IdempotencyKey: fmt.Sprintf("payment-batch:%d:%d", b.ID, item.ID),
+		Description:    item.Description,
+		InitiatedBy:    b.InitiatedBy,
+	}
+	screened, err := s.transactionService.screenTransferTx(ctx, tx, in, item.Currency)
+	if err != nil {
+		return 0, nil, err
+	}
+	if screened.verdict.Action == monitoring.ActionReview {
+		id := s.transactionService.alertScreened(ctx, screened, "", 0)
+		return 0, nil, fmt.Errorf("held for review by transaction monitoring (alert %d); send it as a single transfer", id)
+	}
+	t, replayed, err := s.transactionService.transferTx(ctx, tx, in)
+	if err != nil {
+		return 0, nil, err
+	}
+	id, err := strconv.Atoi(t.ID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unexpected transaction id %q", t.ID)
+	}
+	if replayed {
+		return id, nil, nil
+	}
+	return id, &screened, nil
+}
+
+func (s *PaymentBatchService) renewClaimTx(ctx context.Context, tx pgx.Tx, b *model.PaymentBatch) error {
+	ok, err := s.paymentBatchRepository.RenewClaimTx(ctx, tx, b.ID, b.ClaimToken, config.App.PaymentBatchLease)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errClaimLost
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/sanctions"
+	"basic-gin/internal/textmatch"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrSanctionsHit = errors.New("client has unresolved sanctions screening hits")
+
+type ScreeningService struct {
+	screener            *sanctions.Screener
+	screeningRepository repository.ScreeningRepository
+	clientRepository    repository.ClientRepository
+	auditRepository     repository.AuditRepository
+	// rescreening keeps the worker and an operator's request from screening
+	// all clients at the same time.
+	rescreening *sync.Mutex
+}
+
+func NewScreeningService(
+	screener *sanctions.Screener,
+	screeningRepository *repository.ScreeningRepository,
+	clientRepository *repository.ClientRepository,
+	auditRepository *repository.AuditRepository,
+) *ScreeningService {
+	return &ScreeningService{
+		screener:            screener,
+		screeningRepository: *screeningRepository,
+		clientRepository:    *clientRepository,
+		auditRepository:     *auditRepository,
+		rescreening:         &sync.Mutex{},
+	}
+}
+
+// screenedName is what a hit remembers the client was screened with.
+func screenedName(c *model.Client) string {
+	if c.IsBusiness() {
+		return textmatch.Normalize(c.Name())
+	}
+	return textmatch.Normalize(c.Name()) + " " + c.BirthDate.Format("2006-01-02")
+}
+
+// ScreenClient screens c against the list in force and records new hits.
+// It returns how many hits were new. Without a list nothing is screened.
+func (s *ScreeningService) ScreenClient(ctx context.Context, c *model.Client) (int, error) {
+	list, _, _ := s.screener.List()
+	if list == nil {
+		return 0, nil
+	}
+	return s.screen(ctx, list, c)
+}
+
+func (s *ScreeningService) screen(ctx context.Context, list *sanctions.List, c *model.Client) (int, error) {
+	matches := list.Screen(sanctions.Subject{Name: c.Name(), BirthDate: c.BirthDate}, config.App.SanctionsMatchThreshold)
+	created := 0
+	for _, m := range matches {
+		saved, isNew, err := s.screeningRepository.CreateHit(ctx, &model.ScreeningHit{
+			ClientID:       int(c.ID),
+			ListVersion:    list.Version,
+			EntryID:        m.EntryID,
+			EntryName:      m.EntryName,
+			Program:        m.Program,
+			MatchedName:    m.MatchedName,
+			ScreenedName:   screenedName(c),
+			Score:          m.Score,
+			BirthDateMatch: m.BirthDateMatch,
+		})
+		if err != nil {
+			return created, err
+		}
+		if isNew {
+			created++
+			log.Printf("screening: client %d resembles %s %q (score %.3f), hit %d", c.ID, m.EntryID, m.MatchedName, m.Score, saved.ID)
+		}
+	}
+	return created, nil
+}
+
+// EnsureClear screens the client again and fails with ErrSanctionsHit while
+// any hit is pending review or was confirmed.
+func (s *ScreeningService) EnsureClear(ctx context.Context, clientID int) error {
+	c, err := s.clientRepository.GetById(ctx, int64(clientID))
+	if err != nil {
+		return err
+	}
+	if _, err := s.ScreenClient(ctx, c); err != nil {
+		return fmt.Errorf("screen client %d: %w", clientID, err)
+	}
+	n, err := s.screeningRepository.CountBlocking(ctx, clientID)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return fmt.Errorf("%w: client %d has %d", ErrSanctionsHit, clientID, n)
+	}
+	return nil
+}
+
+func (s *ScreeningService) List(ctx context.Context) (*dto.ScreeningListResponse, error) {
+	list, source, loadedAt := s.screener.List()
+	out := &dto.ScreeningListResponse{Source: source, Threshold: config.App.SanctionsMatchThreshold, LoadedAt: loadedAt}
+	if list != nil {
+		out.Version, out.Entries = list.Version, len(list.Entries)
+	}
+	last, err := s.screeningRepository.LastRun(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out.LastRun = mapper.ScreeningRunToResponse(last)
+	return out, nil
+}
+
+func (s *ScreeningService) ListHits(ctx context.Context, status string, clientID, limit, offset int) ([]*dto.ScreeningHitResponse, error) {
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.screeningRepository.ListHits(ctx, strings.ToLower(strings.TrimSpace(status)), clientID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ScreeningHitsToResponseSlice(items), nil
+}
+
+func (s *ScreeningService) GetHit(ctx context.Context, id int) (*dto.ScreeningHitResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	h, err := s.screeningRepository.GetHit(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ScreeningHitToResponse(h), nil
+}
+
+// ClearHit records a hit as a false positive. Once no hit is pending or
+// confirmed the client may open accounts.
+func (s *ScreeningService) ClearHit(ctx context.Context, id int, in dto.ScreeningHitReview) (*dto.ScreeningHitResponse, error) {
+	return s.review(ctx, id, in, model.ScreeningHitCleared, "screening_hit.clear")
+}
+
+// ConfirmHit records that the client is the listed person; the client stays
+// blocked.
+func (s *ScreeningService) ConfirmHit(ctx context.Context, id int, in dto.ScreeningHitReview) (*dto.ScreeningHitResponse, error) {
+	return s.review(ctx, id, in, model.ScreeningHitConfirmed, "screening_hit.confirm")
+}
+
+func (s *ScreeningService) review(ctx context.Context, id int, in dto.ScreeningHitReview, status, action string) (*dto.ScreeningHitResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	note := strings.TrimSpace(in.Note)
+	if note == "" {
+		return nil, errors.New("note is required")
+	}
+
+	tx, err := s.screeningRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	h, err := s.screeningRepository.ReviewHitTx(ctx, tx, id, status, middleware.ActorFromContext(ctx), note)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, action, "screening_hit", strconv.Itoa(id), map[string]any{
+		"client_id": h.ClientID,
+		"entry_id":  h.EntryID,
+		"note":      note,
+	})); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return mapper.ScreeningHitToResponse(h), nil
+}
+
+// Rescreen reloads the list file and screens every client against it.
+func (s *ScreeningService) Rescreen(ctx context.Context) (*dto.ScreeningRunResponse, error) {
+	if err := s.screener.LoadFile(config.App.SanctionsListFile); err != nil {
+		return nil, err
+	}
+	list, _, _ := s.screener.List()
+	run, err := s.rescreenAll(ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ScreeningRunToResponse(run), nil
+}
+
+// RescreenIfChanged picks up edits to the list file and screens every client
+// against a list version they were not screened against yet. It is run
+// periodically by the sanctions worker; a broken file leaves the list in
+// force.
+func (s *ScreeningService) RescreenIfChanged(ctx context.Context) error {
+	if reloaded, err := s.screener.ReloadIfChanged(config.App.SanctionsListFile); err != nil {
+		return err
+	} else if reloaded {
+		log.Printf("sanctions list reloaded from %s", config.App.SanctionsListFile)
+	}
+	list, _, _ := s.screener.List()
+	if list == nil {
+		return nil
+	}
+	done, err := s.screeningRepository.HasRun(ctx, list.Version)
+	if err != nil || done {
+		return err
+	}
+	_, err = s.rescreenAll(ctx, list)
+	return err
+}
+
+func (s *ScreeningService) rescreenAll(ctx context.Context, list *sanctions.List) (*model.ScreeningRun, error) {
+	const page = 500
+	s.rescreening.Lock()
+	defer s.rescreening.Unlock()
+
+	run := &model.ScreeningRun{ListVersion: list.Version, StartedAt: time.Now()}
+	for after := int64(0); ; {
+		clients, err := s.clientRepository.ListAfter(ctx, after, page)
+		if err != nil {
+			return nil, err
+		}
+		if len(clients) == 0 {
+			break
+		}
+		for _, c := range clients {
+			n, err := s.screen(ctx, list, c)
+			if err != nil {
+				return nil, fmt.Errorf("screen client %d: %w", c.ID, err)
+			}
+			run.Clients++
+			run.Hits += n
+		}
+		after = clients[len(clients)-1].ID
+	}
+
+	saved, err := s.screeningRepository.CreateRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("screening: %d clients screened against list %.12s, %d new hits", saved.Clients, saved.ListVersion, saved.Hits)
+	return saved, nil
+}
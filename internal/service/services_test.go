@@ -0,0 +1,35 @@
+package service
+
+import (
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/repository"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestServices wires the account and transaction services the way the
+// server does, without a cache and with no monitoring rules loaded.
+func newTestServices(pool *pgxpool.Pool) (*AccountService, *TransactionService) {
+	accounts := repository.NewAccountRepository(pool)
+	transactions := repository.NewTransactionRepository(pool)
+	audit := repository.NewAuditRepository(pool)
+	approvals := repository.NewApprovalRepository(pool)
+	holders := repository.NewAccountHolderRepository(pool)
+	fees := NewFeeService(repository.NewFeeRepository(pool), accounts, transactions, audit)
+	limits := NewLimitService(repository.NewLimitRepository(pool), accounts, transactions, audit, approvals, holders)
+	monitor := NewMonitoringService(monitoring.NewEngine(), repository.NewMonitoringRepository(pool),
+		repository.NewCaseRepository(pool), transactions, audit)
+	accountService := &AccountService{
+		accountRepository:     *accounts,
+		transactionRepository: *transactions,
+		auditRepository:       *audit,
+		approvalRepository:    *approvals,
+		holderRepository:      *holders,
+		feeService:            *fees,
+		limitService:          *limits,
+		monitoringService:     *monitor,
+	}
+	transactionService := NewTransactionService(transactions, accounts, repository.NewFXQuoteRepository(pool), audit, approvals,
+		fees, limits, monitor, NewPayeeService(accounts, repository.NewClientRepository(pool), holders), &BeneficiaryService{})
+	return accountService, transactionService
+}
@@ -0,0 +1,396 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/schedule"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// maxRunsPerTick bounds how many due orders one worker tick executes so a
+// backlog cannot monopolise the connection pool.
+const maxRunsPerTick = 100
+
+type StandingOrderService struct {
+	standingOrderRepository repository.StandingOrderRepository
+	accountRepository       repository.AccountRepository
+	transactionService      TransactionService
+}
+
+func NewStandingOrderService(
+	standingOrderRepository *repository.StandingOrderRepository,
+	accountRepository *repository.AccountRepository,
+	transactionService *TransactionService,
+) *StandingOrderService {
+	return &StandingOrderService{
+		standingOrderRepository: *standingOrderRepository,
+		accountRepository:       *accountRepository,
+		transactionService:      *transactionService,
+	}
+}
+
+// Create sets up a standing order between two accounts given by number or
+// IBAN.
+func (s *StandingOrderService) Create(ctx context.Context, in dto.StandingOrderCreate) (*dto.StandingOrderResponse, error) {
+	if in.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	from, err := resolveAccountNumber(ctx, &s.accountRepository, in.FromAccount)
+	if err != nil {
+		return nil, fmt.Errorf("from_account: %w", err)
+	}
+	to, err := resolveAccountNumber(ctx, &s.accountRepository, in.ToAccount)
+	if err != nil {
+		return nil, fmt.Errorf("to_account: %w", err)
+	}
+	if from.ID == to.ID {
+		return nil, errors.New("from and to accounts must differ")
+	}
+	clientID := middleware.ClientIDFromContext(ctx)
+	if err := s.transactionService.limitService.checkHolder(ctx, from, clientID, in.Amount); err != nil {
+		return nil, err
+	}
+	if from.Currency != to.Currency {
+		return nil, fmt.Errorf("standing orders need accounts in the same currency: %s vs %s", from.Currency, to.Currency)
+	}
+	if err := currency.ValidateAmount(from.Currency, in.Amount); err != nil {
+		return nil, err
+	}
+
+	start := time.Now().UTC()
+	if in.StartAt != nil {
+		start = in.StartAt.UTC()
+	}
+	if in.EndAt != nil && !in.EndAt.After(start) {
+		return nil, errors.New("end_at must be after start_at")
+	}
+	sched, err := schedule.Parse(in.Schedule, start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule: %w", err)
+	}
+	first := sched.Next(start.Add(-time.Second))
+	if first.IsZero() || (in.EndAt != nil && first.After(*in.EndAt)) {
+		return nil, errors.New("schedule has no occurrence before end_at")
+	}
+
+	maxRetries := config.App.StandingOrderMaxRetries
+	if in.MaxRetries != nil {
+		maxRetries = *in.MaxRetries
+	}
+	retryInterval := int(config.App.StandingOrderRetryInterval / time.Second)
+	if in.RetryIntervalSeconds != nil {
+		retryInterval = *in.RetryIntervalSeconds
+	}
+
+	saved, err := s.standingOrderRepository.Create(ctx, &model.StandingOrder{
+		FromAccountID:        from.ID,
+		ToAccountID:          to.ID,
+		Amount:               in.Amount,
+		Currency:             from.Currency,
+		Schedule:             in.Schedule,
+		Reference:            in.Reference,
+		StartAt:              start,
+		EndAt:                in.EndAt,
+		Status:               model.StandingOrderStatusActive,
+		MaxRetries:           maxRetries,
+		RetryIntervalSeconds: retryInterval,
+		CurrentOccurrence:    &first,
+		InitiatedBy:          clientID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapper.StandingOrderToResponse(saved), nil
+}
+
+func (s *StandingOrderService) GetById(ctx context.Context, id int) (*dto.StandingOrderResponse, error) {
+	o, err := s.standingOrderRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.StandingOrderToResponse(o), nil
+}
+
+func (s *StandingOrderService) ListByAccountID(ctx context.Context, accountID int) ([]*dto.StandingOrderResponse, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+	items, err := s.standingOrderRepository.ListByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.StandingOrdersToResponseSlice(items), nil
+}
+
+func (s *StandingOrderService) ListExecutions(ctx context.Context, id, limit, offset int) ([]*dto.StandingOrderExecutionResponse, error) {
+	if _, err := s.standingOrderRepository.GetById(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 || limit > 1000 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	items, err := s.standingOrderRepository.ListExecutions(ctx, id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.ExecutionsToResponseSlice(items), nil
+}
+
+func (s *StandingOrderService) Pause(ctx context.Context, id int) (*dto.StandingOrderResponse, error) {
+	return s.mutate(ctx, id, func(tx pgx.Tx, o *model.StandingOrder, sched schedule.Schedule) error {
+		if o.Status != model.StandingOrderStatusActive {
+			return fmt.Errorf("standing order %d is %s", o.ID, o.Status)
+		}
+		o.Status = model.StandingOrderStatusPaused
+		return nil
+	})
+}
+
+// Resume reactivates a paused order. Occurrences that fell due while it was
+// paused are recorded as skipped rather than executed late.
+func (s *StandingOrderService) Resume(ctx context.Context, id int) (*dto.StandingOrderResponse, error) {
+	return s.mutate(ctx, id, func(tx pgx.Tx, o *model.StandingOrder, sched schedule.Schedule) error {
+		if o.Status != model.StandingOrderStatusPaused {
+			return fmt.Errorf("standing order %d is %s", o.ID, o.Status)
+		}
+		o.Status = model.StandingOrderStatusActive
+		now := time.Now().UTC()
+		for i := 0; i < maxRunsPerTick && o.CurrentOccurrence != nil && o.CurrentOccurrence.Before(now); i++ {
+			if err := s.finishOccurrence(ctx, tx, o, sched, model.ExecutionStatusSkipped, nil, "missed while paused"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *StandingOrderService) Cancel(ctx context.Context, id int) (*dto.StandingOrderResponse, error) {
+	return s.mutate(ctx, id, func(tx pgx.Tx, o *model.StandingOrder, sched schedule.Schedule) error {
+		if o.Status == model.StandingOrderStatusCancelled || o.Status == model.StandingOrderStatusCompleted {
+			return fmt.Errorf("standing order %d is already %s", o.ID, o.Status)
+		}
+		o.Status = model.StandingOrderStatusCancelled
+		o.CurrentOccurrence = nil
+		o.NextRunAt = nil
+		o.Attempts = 0
+		return nil
+	})
+}
+
+// Skip drops the next pending occurrence (including one being retried) and
+// moves the order on to the following one.
+func (s *StandingOrderService) Skip(ctx context.Context, id int) (*dto.StandingOrderResponse, error) {
+	return s.mutate(ctx, id, func(tx pgx.Tx, o *model.StandingOrder, sched schedule.Schedule) error {
+		if o.CurrentOccurrence == nil || (o.Status != model.StandingOrderStatusActive && o.Status != model.StandingOrderStatusPaused) {
+			return fmt.Errorf("standing order %d has no pending occurrence", o.ID)
+		}
+		return s.finishOccurrence(ctx, tx, o, sched, model.ExecutionStatusSkipped, nil, "skipped on request")
+	})
+}
+
+func (s *StandingOrderService) mutate(ctx context.Context, id int, fn func(pgx.Tx, *model.StandingOrder, schedule.Schedule) error) (*dto.StandingOrderResponse, error) {
+	tx, err := s.standingOrderRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	o, err := s.standingOrderRepository.GetByIdTx(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	sched, err := schedule.Parse(o.Schedule, o.StartAt)
+	if err != nil {
+		return nil, fmt.Errorf("standing order %d has invalid schedule: %w", o.ID, err)
+	}
+	if err := fn(tx, o, sched); err != nil {
+		return nil, err
+	}
+	saved, err := s.standingOrderRepository.UpdateStateTx(ctx, tx, o)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit: %w", err)
+	}
+	return mapper.StandingOrderToResponse(saved), nil
+}
+
+// RunDue executes every standing order whose next run is due. It is called
+// periodically by the scheduler worker; each order runs in its own
+// transaction so one failure does not affect the others.
+func (s *StandingOrderService) RunDue(ctx context.Context) error {
+	for i := 0; i < maxRunsPerTick; i++ {
+		ran, err := s.runNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !ran {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (s *StandingOrderService) runNext(ctx context.Context) (bool, error) {
+	tx, err := s.standingOrderRepository.Pool().Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	o, err := s.standingOrderRepository.ClaimDueTx(ctx, tx, time.Now().UTC())
+	if err != nil || o == nil {
+		return false, err
+	}
+	sched, err := schedule.Parse(o.Schedule, o.StartAt)
+	if err != nil {
+		return false, fmt.Errorf("standing order %d has invalid schedule: %w", o.ID, err)
+	}
+	if o.CurrentOccurrence == nil {
+		return false, fmt.Errorf("standing order %d is active without a pending occurrence", o.ID)
+	}
+
+	occurrence := *o.CurrentOccurrence
+	attempt := o.Attempts + 1
+
+	in := dto.TransactionCreate{
+		FromAccountID:  o.FromAccountID,
+		ToAccountID:    o.ToAccountID,
+		Amount:         o.Amount,
+		IdempotencyKey: fmt.Sprintf("standing-order:%d:%d", o.ID, occurrence.Unix()),
+		InitiatedBy:    o.InitiatedBy,
+	}
+
+	// The transfer runs in a savepoint so a failed attempt can be recorded
+	// without losing the order's lock. Monitoring screens every attempt; an
+	// occurrence it holds for review, or one from the approval threshold up,
+	// waits for approval and the order moves on. The approval is filed in the
+	// order's transaction, so it exists exactly when the order has moved past
+	// the occurrence.
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin savepoint: %w", err)
+	}
+	var (
+		t        *model.Transaction
+		replayed bool
+	)
+	screened, transferErr := s.transactionService.screenTransferTx(ctx, sp, in, o.Currency)
+	if transferErr == nil && (largeTransfer(o.Currency, o.Amount) || screened.verdict.Action == monitoring.ActionReview) {
+		transferErr = requestApprovalTx(ctx, sp, &s.transactionService.approvalRepository, &s.transactionService.auditRepository,
+			model.ApprovalOperationTransfer, "account", strconv.Itoa(o.FromAccountID), in)
+	}
+	if transferErr == nil {
+		t, replayed, transferErr = s.transactionService.transferTx(ctx, sp, in)
+	}
+
+	switch {
+	case transferErr == nil:
+		if err := sp.Commit(ctx); err != nil {
+			return false, fmt.Errorf("release savepoint: %w", err)
+		}
+		txID, err := strconv.Atoi(t.ID)
+		if err != nil {
+			return false, fmt.Errorf("unexpected transaction id %q", t.ID)
+		}
+		o.Attempts = attempt
+		if err := s.finishOccurrence(ctx, tx, o, sched, model.ExecutionStatusSucceeded, &txID, ""); err != nil {
+			return false, err
+		}
+	case errors.Is(transferErr, ErrInsufficientFunds) && attempt <= o.MaxRetries:
+		_ = sp.Rollback(ctx)
+		if err := s.standingOrderRepository.RecordExecutionTx(ctx, tx, &model.StandingOrderExecution{
+			StandingOrderID: o.ID,
+			ScheduledFor:    occurrence,
+			Attempt:         attempt,
+			Status:          model.ExecutionStatusRetryScheduled,
+			Error:           transferErr.Error(),
+		}); err != nil {
+			return false, err
+		}
+		next := time.Now().UTC().Add(time.Duration(o.RetryIntervalSeconds) * time.Second)
+		o.Attempts = attempt
+		o.NextRunAt = &next
+	case approvalID(transferErr) != 0:
+		if err := sp.Commit(ctx); err != nil {
+			return false, fmt.Errorf("release savepoint: %w", err)
+		}
+		o.Attempts = attempt
+		if err := s.finishOccurrence(ctx, tx, o, sched, model.ExecutionStatusPendingApproval, nil, transferErr.Error()); err != nil {
+			return false, err
+		}
+	default:
+		_ = sp.Rollback(ctx)
+		o.Attempts = attempt
+		if err := s.finishOccurrence(ctx, tx, o, sched, model.ExecutionStatusFailed, nil, transferErr.Error()); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := s.standingOrderRepository.UpdateStateTx(ctx, tx, o); err != nil {
+		return false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+	if id := approvalID(transferErr); id != 0 {
+		s.transactionService.alertScreened(ctx, screened, "", id)
+	}
+	if transferErr != nil {
+		log.Printf("standing order %d occurrence %s attempt %d: %v", o.ID, occurrence.Format(time.RFC3339), attempt, transferErr)
+	} else if !replayed {
+		s.transactionService.alertScreened(ctx, screened, t.ID, 0)
+	}
+	return true, nil
+}
+
+// finishOccurrence records the outcome of the current occurrence and moves o
+// to the next one, completing the order when the schedule or end date runs
+// out.
+func (s *StandingOrderService) finishOccurrence(ctx context.Context, tx pgx.Tx, o *model.StandingOrder, sched schedule.Schedule, status string, txID *int, msg string) error {
+	occurrence := *o.CurrentOccurrence
+	attempt := o.Attempts
+	if status == model.ExecutionStatusSkipped {
+		attempt = 0
+	}
+	if err := s.standingOrderRepository.RecordExecutionTx(ctx, tx, &model.StandingOrderExecution{
+		StandingOrderID: o.ID,
+		ScheduledFor:    occurrence,
+		Attempt:         attempt,
+		Status:          status,
+		TransactionID:   txID,
+		Error:           msg,
+	}); err != nil {
+		return err
+	}
+
+	o.Attempts = 0
+	next := sched.Next(occurrence)
+	if next.IsZero() || (o.EndAt != nil && next.After(*o.EndAt)) {
+		o.Status = model.StandingOrderStatusCompleted
+		o.CurrentOccurrence = nil
+		o.NextRunAt = nil
+		return nil
+	}
+	o.CurrentOccurrence = &next
+	o.NextRunAt = &next
+	return nil
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/middleware"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/testdb"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRunNextFilesApprovalWithTheOrder(t *testing.T) {
+	pool := testdb.Open(t)
+	saved := config.App
+	t.Cleanup(func() { config.App = saved })
+	config.App.ApprovalOperations = map[string]bool{model.ApprovalOperationTransfer: true}
+	config.App.ApprovalTransferThresholds = map[string]int{"RSD": 500}
+	config.App.ApprovalTTL = time.Hour
+
+	ctx := middleware.WithSystem(context.Background())
+	occurrence := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	var orderID int
+	if err := pool.QueryRow(ctx, `
+		INSERT INTO standing_orders (from_account_id, to_account_id, amount, currency, schedule, start_at, current_occurrence, next_run_at, initiated_by)
+		VALUES (1, 2, 600, 'RSD', 'FREQ=MONTHLY', $1, $1, $1, 1)
+		RETURNING id`, occurrence).Scan(&orderID); err != nil {
+		t.Fatal(err)
+	}
+
+	_, transactionService := newTestServices(pool)
+	s := NewStandingOrderService(repository.NewStandingOrderRepository(pool), repository.NewAccountRepository(pool), transactionService)
+	ran, err := s.runNext(ctx)
+	if err != nil || !ran {
+		t.Fatalf("runNext = %v, %v; want a run", ran, err)
+	}
+
+	var approvals int
+	key := fmt.Sprintf("standing-order:%d:%d", orderID, occurrence.Unix())
+	if err := pool.QueryRow(ctx, `
+		SELECT COUNT(*) FROM approvals
+		WHERE operation = 'transfer' AND status = 'pending' AND payload->>'idempotency_key' = $1`, key).Scan(&approvals); err != nil {
+		t.Fatal(err)
+	}
+	if approvals != 1 {
+		t.Errorf("%d pending approvals for the occurrence, want 1", approvals)
+	}
+	var status string
+	var current time.Time
+	if err := pool.QueryRow(ctx, `
+		SELECT e.status, o.current_occurrence
+		FROM standing_orders o JOIN standing_order_executions e ON e.standing_order_id = o.id
+		WHERE o.id = $1`, orderID).Scan(&status, &current); err != nil {
+		t.Fatal(err)
+	}
+	if status != model.ExecutionStatusPendingApproval || !current.After(occurrence) {
+		t.Errorf("occurrence recorded as %s and order moved to %s; want %s and a later occurrence",
+			status, current.Format(time.RFC3339), model.ExecutionStatusPendingApproval)
+	}
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"basic-gin/internal/currency"
+	"basic-gin/internal/dto"
+	"basic-gin/internal/model"
+	"basic-gin/internal/repository"
+	"basic-gin/internal/statement"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type StatementService struct {
+	statementRepository repository.StatementRepository
+	accountRepository   repository.AccountRepository
+}
+
+func NewStatementService(
+	statementRepository *repository.StatementRepository,
+	accountRepository *repository.AccountRepository,
+) *StatementService {
+	return &StatementService{
+		statementRepository: *statementRepository,
+		accountRepository:   *accountRepository,
+	}
+}
+
+// StatementJob is a validated statement request, ready to be written.
+type StatementJob struct {
+	Account *model.Account
+	// From and To are the first and last day covered (UTC), inclusive.
+	From   time.Time
+	To     time.Time
+	Format string
+}
+
+func (j *StatementJob) ContentType() string { return statement.ContentType(j.Format) }
+
+func (j *StatementJob) Filename() string {
+	return fmt.Sprintf("statement-%s-%s-%s.%s", j.Account.AccountNumber, j.From.Format("20060102"), j.To.Format("20060102"), statement.Extension(j.Format))
+}
+
+// Prepare validates a statement request. It is separate from Write so that
+// errors can still be reported before any output has been sent.
+func (s *StatementService) Prepare(ctx context.Context, accountID int, q dto.StatementQuery) (*StatementJob, error) {
+	if accountID <= 0 {
+		return nil, errors.New("invalid account id")
+	}
+	format := strings.ToLower(strings.TrimSpace(q.Format))
+	if format == "" {
+		format = statement.FormatJSON
+	}
+	if !statement.Supported(format) {
+		return nil, fmt.Errorf("unsupported format %q, want one of %s", q.Format, strings.Join(statement.Formats(), ", "))
+	}
+	from, to, err := statementPeriod(q.From, q.To)
+	if err != nil {
+		return nil, err
+	}
+
+	acc, err := s.accountRepository.GetById(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return &StatementJob{Account: acc, From: from, To: to, Format: format}, nil
+}
+
+// Write renders the statement to w. The opening balance and the movements are
+// read from one snapshot and movements are streamed, so the running and
+// closing balances always agree with the ledger however long the period is.
+func (s *StatementService) Write(ctx context.Context, job *StatementJob, w io.Writer) error {
+	r, err := statement.New(job.Format, w)
+	if err != nil {
+		return err
+	}
+	return s.render(ctx, job, r)
+}
+
+func (s *StatementService) render(ctx context.Context, job *StatementJob, r statement.Renderer) error {
+	tx, err := s.statementRepository.BeginSnapshot(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	acc := job.Account
+	start, end := job.From, job.To.AddDate(0, 0, 1)
+	opening, err := s.statementRepository.BalanceAtTx(ctx, tx, acc.ID, start)
+	if err != nil {
+		return err
+	}
+	closing, err := s.statementRepository.BalanceAtTx(ctx, tx, acc.ID, end)
+	if err != nil {
+		return err
+	}
+	creditCount, debitCount, credits, debits, err := s.statementRepository.TotalsTx(ctx, tx, acc.ID, start, end)
+	if err != nil {
+		return err
+	}
+
+	if err := r.Begin(statement.Header{
+		AccountID:      acc.ID,
+		AccountNumber:  acc.AccountNumber,
+		AccountType:    acc.AccountType,
+		ClientID:       acc.ClientId,
+		Currency:       acc.Currency,
+		From:           job.From,
+		To:             job.To,
+		OpeningBalance: opening,
+		Totals: statement.Summary{
+			ClosingBalance: currency.Round(acc.Currency, closing),
+			Count:          creditCount + debitCount,
+			CreditCount:    creditCount,
+			DebitCount:     debitCount,
+			TotalCredits:   currency.Round(acc.Currency, credits),
+			TotalDebits:    currency.Round(acc.Currency, debits),
+		},
+		GeneratedAt: time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+
+	sum := statement.Summary{ClosingBalance: opening}
+	err = s.statementRepository.EachLineTx(ctx, tx, acc.ID, start, end, func(l *model.StatementLine) error {
+		sum.ClosingBalance = currency.Round(acc.Currency, sum.ClosingBalance+l.Amount)
+		sum.Count++
+		if l.Amount < 0 {
+			sum.DebitCount++
+			sum.TotalDebits = currency.Round(acc.Currency, sum.TotalDebits-l.Amount)
+		} else {
+			sum.CreditCount++
+			sum.TotalCredits = currency.Round(acc.Currency, sum.TotalCredits+l.Amount)
+		}
+		return r.Entry(&statement.Entry{StatementLine: l, Balance: sum.ClosingBalance})
+	})
+	if err != nil {
+		return err
+	}
+	return r.End(sum)
+}
+
+// statementPeriod resolves the from/to query, defaulting to last month.
+func statementPeriod(from, to string) (time.Time, time.Time, error) {
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	var start, end time.Time
+	var err error
+	switch {
+	case from == "" && to == "":
+		start, end = thisMonth.AddDate(0, -1, 0), thisMonth.AddDate(0, 0, -1)
+	case from == "":
+		if end, err = parseDate(to, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		start = time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		if start, err = parseDate(from, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		if end, err = parseDate(to, now); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, errors.New("to must not be before from")
+	}
+	return start, end, nil
+}
@@ -1,36 +1,367 @@
 package service
 
 import (
+	"basic-gin/internal/config"
+	"basic-gin/internal/currency"
 	"basic-gin/internal/dto"
 	"basic-gin/internal/mapper"
+	"basic-gin/internal/middleware"
 	"basic-gin/internal/model"
+	"basic-gin/internal/monitoring"
 	"basic-gin/internal/repository"
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
+// ErrAlreadyUsed is returned for an FX quote, idempotency key or verification
+// token that was already spent.
+var ErrAlreadyUsed = repository.ErrAlreadyUsed
+
 type TransactionService struct {
 	transactionRepository repository.TransactionRepository
 	accountRepository     repository.AccountRepository
+	fxQuoteRepository     repository.FXQuoteRepository
+	auditRepository       repository.AuditRepository
+	approvalRepository    repository.ApprovalRepository
+	feeService            FeeService
+	limitService          LimitService
+	monitoringService     MonitoringService
+	payeeService          PayeeService
+	beneficiaryService    BeneficiaryService
 }
 
 func NewTransactionService(
 	transactionRepository *repository.TransactionRepository,
 	accountRepository *repository.AccountRepository,
+	fxQuoteRepository *repository.FXQuoteRepository,
+	auditRepository *repository.AuditRepository,
+	approvalRepository *repository.ApprovalRepository,
+	feeService *FeeService,
+	limitService *LimitService,
+	monitoringService *MonitoringService,
+	payeeService *PayeeService,
+	beneficiaryService *BeneficiaryService,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepository: *transactionRepository,
 		accountRepository:     *accountRepository,
+		fxQuoteRepository:     *fxQuoteRepository,
+		auditRepository:       *auditRepository,
+		approvalRepository:    *approvalRepository,
+		feeService:            *feeService,
+		limitService:          *limitService,
+		monitoringService:     *monitoringService,
+		payeeService:          *payeeService,
+		beneficiaryService:    *beneficiaryService,
 	}
 }
 
+// CreateTransfer posts a transfer requested through the API. Accounts may be
+// given by number or IBAN; a beneficiary name is confirmed against the
+// recipient before anything is posted, and a saved beneficiary may stand in
+// for the recipient account. Transaction monitoring screens the transfer
+// first and may refuse it; transfers it holds for review, and those from the
+// configured approval threshold up, wait for a second person.
 func (s *TransactionService) CreateTransfer(ctx context.Context, in dto.TransactionCreate) (*dto.TransactionResponse, error) {
+	in.InitiatedBy = middleware.ClientIDFromContext(ctx)
+	in.FromAccountID, in.ToAccountID = 0, 0
+	var beneficiary *model.Beneficiary
+	if in.BeneficiaryID != 0 {
+		var err error
+		if beneficiary, err = s.beneficiaryService.destination(ctx, &in); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.payeeService.resolveParties(ctx, &in); err != nil {
+		return nil, err
+	}
+	if beneficiary != nil {
+		if err := s.beneficiaryService.checkTransfer(ctx, beneficiary, in.FromAccountID, in.Amount); err != nil {
+			return nil, err
+		}
+	}
+	var payee string
+	if strings.TrimSpace(in.BeneficiaryName) != "" {
+		var err error
+		if payee, err = s.payeeService.confirm(ctx, in.ToAccountID, in.BeneficiaryName, in.AcceptCloseMatch); err != nil {
+			return nil, err
+		}
+	}
+
+	from, err := s.accountRepository.GetById(ctx, in.FromAccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.limitService.checkHolder(ctx, from, in.InitiatedBy, in.Amount); err != nil {
+		return nil, err
+	}
+	ev := monitoring.Event{
+		Operation:      monitoring.OperationTransfer,
+		AccountID:      in.FromAccountID,
+		CounterpartyID: in.ToAccountID,
+		Amount:         in.Amount,
+		Currency:       from.Currency,
+	}
+	if beneficiary != nil && time.Now().Before(beneficiary.TrustedAt) {
+		ev.NewCounterparty = true
+	} else if ev.NewCounterparty, err = s.monitoringService.newCounterparty(ctx, in.FromAccountID, in.ToAccountID); err != nil {
+		return nil, err
+	}
+	verdict, err := s.monitoringService.screen(ctx, ev)
+	if err != nil {
+		return nil, err
+	}
+	if largeTransfer(from.Currency, in.Amount) || verdict.Action == monitoring.ActionReview {
+		err := requestApproval(ctx, &s.approvalRepository, &s.auditRepository, model.ApprovalOperationTransfer,
+			"account", strconv.Itoa(in.FromAccountID), in)
+		s.monitoringService.alert(ctx, ev, verdict, "", approvalID(err))
+		return nil, err
+	}
+
+	tx, err := s.accountRepository.Pool().Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	t, replayed, err := s.transferTx(ctx, tx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	if !replayed {
+		s.monitoringService.alert(ctx, ev, verdict, t.ID, 0)
+	}
+
+	resp := mapper.TransactionToResponse(t)
+	resp.PayeeMatch = payee
+	return resp, nil
+}
+
+// largeTransfer reports whether a transfer of amount needs a second person
+// by its size alone.
+func largeTransfer(currency string, amount float64) bool {
+	threshold, capped := config.App.ApprovalTransferThresholds[currency]
+	return needsApproval(model.ApprovalOperationTransfer) && capped && amount >= float64(threshold)
+}
+
+// screenedTransfer is a transfer as transaction monitoring judged it; what
+// fired is alerted on once the outcome is known.
+type screenedTransfer struct {
+	ev      monitoring.Event
+	verdict monitoring.Result
+}
+
+// screenTransferTx screens a transfer no one submits through the API, a
+// standing order occurrence or a payment batch item, inside tx. A blocked
+// transfer is refused with ErrMonitoringBlocked; the caller decides what a
+// review verdict means for it.
+func (s *TransactionService) screenTransferTx(ctx context.Context, tx pgx.Tx, in dto.TransactionCreate, currency string) (screenedTransfer, error) {
+	ev := monitoring.Event{
+		Operation:      monitoring.OperationTransfer,
+		AccountID:      in.FromAccountID,
+		CounterpartyID: in.ToAccountID,
+		Amount:         in.Amount,
+		Currency:       currency,
+	}
+	var err error
+	if ev.NewCounterparty, err = s.monitoringService.newCounterparty(ctx, in.FromAccountID, in.ToAccountID); err != nil {
+		return screenedTransfer{}, err
+	}
+	verdict, err := s.monitoringService.screenTx(ctx, tx, ev)
+	return screenedTransfer{ev: ev, verdict: verdict}, err
+}
+
+// alertScreened records what fired on st against the transaction posted for
+// it, or the approval it waits for.
+func (s *TransactionService) alertScreened(ctx context.Context, st screenedTransfer, transactionID string, approvalID int) int {
+	return s.monitoringService.alert(ctx, st.ev, st.verdict, transactionID, approvalID)
+}
+
+// transferTx runs the whole transfer inside the caller's transaction: both
+// accounts and the fee revenue account are locked in ascending id order, funds and currencies are checked
+// and the movement is recorded. When in.IdempotencyKey was already used the
+// earlier transaction is returned with replayed set and nothing is posted.
+func (s *TransactionService) transferTx(ctx context.Context, tx pgx.Tx, in dto.TransactionCreate) (t *model.Transaction, replayed bool, err error) {
 	if in.FromAccountID == in.ToAccountID {
-		return nil, errors.New("from and to accounts must differ")
+		return nil, false, errors.New("from and to accounts must differ")
 	}
 	if in.Amount <= 0 {
-		return nil, errors.New("amount must be positive")
+		return nil, false, errors.New("amount must be positive")
+	}
+
+	if len(in.IdempotencyKey) > 120 {
+		return nil, false, errors.New("idempotency key must be at most 120 characters")
+	}
+	if in.IdempotencyKey != "" {
+		prev, err := s.transactionRepository.GetByIdempotencyKeyTx(ctx, tx, in.IdempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if prev != nil {
+			if prev.FromAccountID != in.FromAccountID || prev.ToAccountID != in.ToAccountID || math.Abs(prev.Amount-in.Amount) > 1e-9 {
+				return nil, false, fmt.Errorf("idempotency key %q was %w for a different transfer", in.IdempotencyKey, ErrAlreadyUsed)
+			}
+			return prev, true, nil
+		}
+	}
+
+	// The fee revenue account for the payer's currency is locked with the
+	// two parties, as a fee credits it once both balances have moved.
+	payer, err := s.accountRepository.GetByIdTx(ctx, tx, in.FromAccountID, false)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := lockAccountsTx(ctx, tx, &s.accountRepository, in.FromAccountID, in.ToAccountID, feeRevenueAccount(payer.Currency)); err != nil {
+		return nil, false, err
+	}
+
+	fromAcc, err := s.accountRepository.GetByIdTx(ctx, tx, in.FromAccountID, true)
+	if err != nil {
+		return nil, false, err
+	}
+	toAcc, err := s.accountRepository.GetByIdTx(ctx, tx, in.ToAccountID, true)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := currency.ValidateAmount(fromAcc.Currency, in.Amount); err != nil {
+		return nil, false, err
+	}
+	if err := s.limitService.checkHolderTx(ctx, tx, fromAcc, in.InitiatedBy, in.Amount); err != nil {
+		return nil, false, err
+	}
+	if err := s.limitService.checkTx(ctx, tx, fromAcc, model.LimitOperationTransfer, in.Amount); err != nil {
+		return nil, false, err
+	}
+
+	t = &model.Transaction{
+		Type:              model.TransactionTypeTransfer,
+		FromAccountID:     in.FromAccountID,
+		ToAccountID:       in.ToAccountID,
+		Amount:            in.Amount,
+		Currency:          fromAcc.Currency,
+		IdempotencyKey:    in.IdempotencyKey,
+		Description:       in.Description,
+		InitiatedBy:       in.InitiatedBy,
+		FromAccountNumber: fromAcc.AccountNumber,
+		ToAccountNumber:   toAcc.AccountNumber,
+	}
+	credit := in.Amount
+
+	if fromAcc.Currency != toAcc.Currency {
+		if in.QuoteID == "" {
+			return nil, false, fmt.Errorf("currency mismatch: account %s is in %s, account %s is in %s; request an fx quote and pass quote_id", fromAcc.AccountNumber, fromAcc.Currency, toAcc.AccountNumber, toAcc.Currency)
+		}
+		quote, err := s.fxQuoteRepository.GetByIdTx(ctx, tx, in.QuoteID, true)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := checkQuote(quote, fromAcc, toAcc, in.Amount); err != nil {
+			return nil, false, err
+		}
+		credit = quote.BuyAmount
+		t.ToAmount = quote.BuyAmount
+		t.ToCurrency = quote.ToCurrency
+		t.FXRate = quote.Rate
+		t.FXMidRate = quote.MidRate
+		t.FXSpreadBps = quote.SpreadBps
+		t.FXQuoteID = quote.ID
+	} else if in.QuoteID != "" {
+		return nil, false, errors.New("quote_id is only allowed for cross-currency transfers")
+	}
+
+	fee, err := s.feeService.quoteTx(ctx, tx, fromAcc, model.FeeOperationTransfer, in.Amount)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := checkFunds(fromAcc, in.Amount+fee.Amount); err != nil {
+		return nil, false, err
+	}
+
+	fromAcc, err = s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, in.FromAccountID, -in.Amount)
+	if err != nil {
+		return nil, false, err
+	}
+	if _, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, in.ToAccountID, +credit); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.transactionRepository.SaveTx(ctx, tx, t); err != nil {
+		return nil, false, err
+	}
+	if t.FXQuoteID != "" {
+		if err := s.fxQuoteRepository.MarkUsedTx(ctx, tx, t.FXQuoteID, t.ID); err != nil {
+			return nil, false, err
+		}
+	}
+	if fee.Amount > 0 {
+		if _, _, err := s.feeService.postTx(ctx, tx, fromAcc, t, fee.Amount); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return t, false, nil
+}
+
+func checkQuote(q *model.FXQuote, from, to *model.Account, amount float64) error {
+	if q.UsedAt != nil {
+		return fmt.Errorf("fx quote %s %w", q.ID, ErrAlreadyUsed)
+	}
+	if time.Now().After(q.ExpiresAt) {
+		return fmt.Errorf("fx quote %s expired at %s", q.ID, q.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	if q.FromCurrency != from.Currency || q.ToCurrency != to.Currency {
+		return fmt.Errorf("fx quote %s is for %s/%s, transfer needs %s/%s", q.ID, q.FromCurrency, q.ToCurrency, from.Currency, to.Currency)
+	}
+	if math.Abs(q.SellAmount-amount) > 1e-9 {
+		return fmt.Errorf("fx quote %s was issued for %v %s, not %v", q.ID, q.SellAmount, q.FromCurrency, amount)
+	}
+	return nil
+}
+
+func (s *TransactionService) GetById(ctx context.Context, id int) (*dto.TransactionResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	t, err := s.transactionRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TransactionToResponse(t), nil
+}
+
+// Reverse refunds all or part of a transfer with a linked reversal moving
+// money from the recipient back to the payer. Accounts are locked in the same
+// ascending order as CreateTransfer and the original row is locked too, so
+// concurrent reversals can never exceed the original amount.
+func (s *TransactionService) Reverse(ctx context.Context, id int, in dto.TransactionReverse) (*dto.TransactionResponse, error) {
+	if id <= 0 {
+		return nil, errors.New("invalid id")
+	}
+	if in.Amount < 0 {
+		return nil, errors.New("amount cannot be negative")
+	}
+	reason := strings.TrimSpace(in.Reason)
+	if reason == "" {
+		return nil, errors.New("reason is required")
+	}
+
+	orig, err := s.transactionRepository.GetById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if orig.Type != model.TransactionTypeTransfer {
+		return nil, fmt.Errorf("only transfers can be reversed, transaction %d is a %s", id, orig.Type)
 	}
 
 	tx, err := s.accountRepository.Pool().Begin(ctx)
@@ -39,50 +370,104 @@ func (s *TransactionService) CreateTransfer(ctx context.Context, in dto.Transact
 	}
 	defer func() { _ = tx.Rollback(ctx) }()
 
-	first, second := in.FromAccountID, in.ToAccountID
+	first, second := orig.FromAccountID, orig.ToAccountID
 	if first > second {
 		first, second = second, first
 	}
-
 	if _, err := s.accountRepository.GetByIdTx(ctx, tx, first, true); err != nil {
 		return nil, err
 	}
 	if _, err := s.accountRepository.GetByIdTx(ctx, tx, second, true); err != nil {
 		return nil, err
 	}
+	recipient, err := s.accountRepository.GetByIdTx(ctx, tx, orig.ToAccountID, true)
+	if err != nil {
+		return nil, err
+	}
 
-	fromAcc, err := s.accountRepository.GetByIdTx(ctx, tx, in.FromAccountID, true)
+	orig, err = s.transactionRepository.GetByIdTx(ctx, tx, id, true)
 	if err != nil {
 		return nil, err
 	}
-	if fromAcc.Balance < in.Amount {
-		return nil, errors.New("insufficient funds")
+	remaining := currency.Round(orig.Currency, orig.Amount-orig.ReversedAmount)
+	if remaining <= 0 {
+		return nil, fmt.Errorf("transaction %d is already fully reversed", id)
+	}
+	amount := in.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+	if amount > remaining+1e-9 {
+		return nil, fmt.Errorf("reversal amount %v exceeds the %v %s not yet reversed", amount, remaining, orig.Currency)
+	}
+	if err := currency.ValidateAmount(orig.Currency, amount); err != nil {
+		return nil, err
+	}
+
+	// For cross-currency transfers the recipient gives back the same share of
+	// what they received, at the original rate.
+	debitCurrency, debit := orig.Currency, amount
+	if orig.ToCurrency != "" {
+		debitCurrency = orig.ToCurrency
+		debit = currency.Round(orig.ToCurrency, amount*orig.ToAmount/orig.Amount)
 	}
 
-	if _, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, in.FromAccountID, -in.Amount); err != nil {
+	if err := checkFunds(recipient, debit); err != nil {
+		if !in.AllowPartial || !errors.Is(err, ErrInsufficientFunds) {
+			return nil, fmt.Errorf("recipient cannot cover the reversal: %w", err)
+		}
+		debit = currency.Floor(debitCurrency, recipient.Spendable())
+		if debit <= 0 {
+			return nil, fmt.Errorf("recipient cannot cover any part of the reversal: %w", err)
+		}
+		amount = debit
+		if orig.ToCurrency != "" {
+			amount = math.Min(remaining, currency.Floor(orig.Currency, debit*orig.Amount/orig.ToAmount))
+		}
+	}
+
+	if _, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, orig.ToAccountID, -debit); err != nil {
 		return nil, err
 	}
-	if _, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, in.ToAccountID, +in.Amount); err != nil {
+	if _, err := s.accountRepository.UpdateBalanceDeltaTx(ctx, tx, orig.FromAccountID, +amount); err != nil {
 		return nil, err
 	}
 
+	origID, _ := strconv.Atoi(orig.ID)
 	t := &model.Transaction{
-		FromAccountID: in.FromAccountID,
-		ToAccountID:   in.ToAccountID,
-		Amount:        in.Amount,
+		Type:              model.TransactionTypeReversal,
+		FromAccountID:     orig.ToAccountID,
+		ToAccountID:       orig.FromAccountID,
+		Amount:            debit,
+		Currency:          debitCurrency,
+		Description:       reason,
+		ReversalOf:        origID,
+		FromAccountNumber: orig.ToAccountNumber,
+		ToAccountNumber:   orig.FromAccountNumber,
+	}
+	if orig.ToCurrency != "" {
+		t.ToAmount = amount
+		t.ToCurrency = orig.Currency
+		t.FXRate = orig.Amount / orig.ToAmount
 	}
 	if err := s.transactionRepository.SaveTx(ctx, tx, t); err != nil {
 		return nil, err
 	}
 
-	if err := tx.Commit(ctx); err != nil {
+	if err := s.auditRepository.RecordTx(ctx, tx, auditEntry(ctx, "transaction.reverse", "transaction", orig.ID, map[string]any{
+		"reversal_id": t.ID,
+		"amount":      amount,
+		"currency":    orig.Currency,
+		"reason":      reason,
+	})); err != nil {
 		return nil, err
 	}
 
-	// 7) KYT simulacija – klijent čeka 10s
-	time.Sleep(10 * time.Second)
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
 
-	return mapper.TransactionToResponse(t), nil
+	return mapper.TransactionToAdminResponse(t), nil
 }
 
 func (s *TransactionService) ListByAccountID(ctx context.Context, accountID, limit, offset int) ([]*dto.TransactionResponse, error) {
@@ -0,0 +1,224 @@
+package statement
+
+import (
+	"basic-gin/internal/accountnumber"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Camt053Namespace is the ISO 20022 schema the export conforms to.
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// camt053Renderer writes a BankToCustomerStatement. The balances and the
+// transaction summary come from Header.Totals, so entries stream after them
+// in schema order.
+type camt053Renderer struct {
+	buf      *bufio.Writer
+	enc      *xml.Encoder
+	currency string
+	open     []xml.StartElement
+}
+
+func newCamt053(w io.Writer) Renderer {
+	buf := bufio.NewWriter(w)
+	enc := xml.NewEncoder(buf)
+	enc.Indent("", "  ")
+	return &camt053Renderer{buf: buf, enc: enc}
+}
+
+type camtAmount struct {
+	Ccy   string `xml:"Ccy,attr"`
+	Value string `xml:",chardata"`
+}
+
+// camtAccount holds either IBAN or Other. Other is a pointer because the
+// encoder writes the Othr wrapper of an empty nested path all the same.
+type camtAccount struct {
+	IBAN  string     `xml:"Id>IBAN,omitempty"`
+	Other *camtOther `xml:"Id>Othr,omitempty"`
+	Ccy   string     `xml:"Ccy,omitempty"`
+}
+
+type camtOther struct {
+	ID string `xml:"Id"`
+}
+
+// newCamtAccount identifies an account by IBAN when its number is one.
+func newCamtAccount(number, ccy string) *camtAccount {
+	if accountnumber.ValidateIBAN(number) == nil {
+		return &camtAccount{IBAN: number, Ccy: ccy}
+	}
+	return &camtAccount{Other: &camtOther{ID: number}, Ccy: ccy}
+}
+
+func (a camtAccount) id() string {
+	if a.IBAN != "" {
+		return a.IBAN
+	}
+	if a.Other != nil {
+		return a.Other.ID
+	}
+	return ""
+}
+
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	Date      string     `xml:"Dt>Dt"`
+}
+
+type camtSummary struct {
+	Count    int    `xml:"TtlNtries>NbOfNtries"`
+	Sum      string `xml:"TtlNtries>Sum"`
+	Net      string `xml:"TtlNtries>TtlNetNtryAmt"`
+	NetInd   string `xml:"TtlNtries>CdtDbtInd"`
+	CdtCount int    `xml:"TtlCdtNtries>NbOfNtries"`
+	CdtSum   string `xml:"TtlCdtNtries>Sum"`
+	DbtCount int    `xml:"TtlDbtNtries>NbOfNtries"`
+	DbtSum   string `xml:"TtlDbtNtries>Sum"`
+}
+
+type camtEntry struct {
+	XMLName   xml.Name   `xml:"Ntry"`
+	Ref       string     `xml:"NtryRef"`
+	Amt       camtAmount `xml:"Amt"`
+	CdtDbtInd string     `xml:"CdtDbtInd"`
+	RvslInd   string     `xml:"RvslInd,omitempty"`
+	Sts       string     `xml:"Sts"`
+	BookgDt   string     `xml:"BookgDt>DtTm"`
+	ValDt     string     `xml:"ValDt>Dt"`
+	SvcrRef   string     `xml:"AcctSvcrRef"`
+	TxCode    string     `xml:"BkTxCd>Prtry>Cd"`
+	TxIssuer  string     `xml:"BkTxCd>Prtry>Issr"`
+	Details   camtTxDtls `xml:"NtryDtls>TxDtls"`
+}
+
+type camtTxDtls struct {
+	SvcrRef    string       `xml:"Refs>AcctSvcrRef"`
+	EndToEndID string       `xml:"Refs>EndToEndId"`
+	DbtrAcct   *camtAccount `xml:"RltdPties>DbtrAcct,omitempty"`
+	CdtrAcct   *camtAccount `xml:"RltdPties>CdtrAcct,omitempty"`
+	Ustrd      string       `xml:"RmtInf>Ustrd,omitempty"`
+}
+
+func (r *camt053Renderer) start(name string, attrs ...xml.Attr) error {
+	el := xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs}
+	r.open = append(r.open, el)
+	return r.enc.EncodeToken(el)
+}
+
+func (r *camt053Renderer) element(name string, v any) error {
+	return r.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+func (r *camt053Renderer) amount(v float64) camtAmount {
+	return camtAmount{Ccy: r.currency, Value: formatAmount(math.Abs(v), r.currency)}
+}
+
+func (r *camt053Renderer) balance(code string, v float64, day time.Time) camtBalance {
+	return camtBalance{Code: code, Amt: r.amount(v), CdtDbtInd: creditDebit(v), Date: day.Format(dateLayout)}
+}
+
+func (r *camt053Renderer) Begin(h Header) error {
+	r.currency = h.Currency
+	id := fmt.Sprintf("STMT-%d-%s-%s", h.AccountID, h.From.Format("20060102"), h.To.Format("20060102"))
+	created := h.GeneratedAt.UTC().Format(time.RFC3339)
+
+	if _, err := r.buf.WriteString(xml.Header); err != nil {
+		return err
+	}
+	if err := r.start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: Camt053Namespace}); err != nil {
+		return err
+	}
+	_ = r.start("BkToCstmrStmt")
+	_ = r.element("GrpHdr", struct {
+		MsgID   string `xml:"MsgId"`
+		CreDtTm string `xml:"CreDtTm"`
+	}{id, created})
+	_ = r.start("Stmt")
+	_ = r.element("Id", id)
+	_ = r.element("CreDtTm", created)
+	_ = r.element("FrToDt", struct {
+		From string `xml:"FrDtTm"`
+		To   string `xml:"ToDtTm"`
+	}{h.From.Format(dateLayout) + "T00:00:00Z", h.To.Format(dateLayout) + "T23:59:59Z"})
+	_ = r.element("Acct", newCamtAccount(h.AccountNumber, h.Currency))
+	_ = r.element("Bal", r.balance("OPBD", h.OpeningBalance, h.From))
+	_ = r.element("Bal", r.balance("CLBD", h.Totals.ClosingBalance, h.To))
+
+	t := h.Totals
+	net := t.TotalCredits - t.TotalDebits
+	return r.element("TxsSummry", camtSummary{
+		Count:    t.Count,
+		Sum:      formatAmount(t.TotalCredits+t.TotalDebits, r.currency),
+		Net:      formatAmount(math.Abs(net), r.currency),
+		NetInd:   creditDebit(net),
+		CdtCount: t.CreditCount,
+		CdtSum:   formatAmount(t.TotalCredits, r.currency),
+		DbtCount: t.DebitCount,
+		DbtSum:   formatAmount(t.TotalDebits, r.currency),
+	})
+}
+
+func (r *camt053Renderer) Entry(e *Entry) error {
+	ref := strconv.Itoa(e.TransactionID)
+	desc := e.Description
+	if desc == "" {
+		desc = e.Type
+	}
+	ntry := camtEntry{
+		Ref:       ref,
+		Amt:       r.amount(e.Amount),
+		CdtDbtInd: creditDebit(e.Amount),
+		Sts:       "BOOK",
+		BookgDt:   e.CreatedAt.UTC().Format(time.RFC3339),
+		ValDt:     e.CreatedAt.UTC().Format(dateLayout),
+		SvcrRef:   ref,
+		TxCode:    e.Type,
+		TxIssuer:  "BASIC-GIN",
+		Details: camtTxDtls{
+			SvcrRef:    ref,
+			EndToEndID: "NOTPROVIDED",
+			Ustrd:      clip(desc, 140),
+		},
+	}
+	if e.Type == "reversal" {
+		ntry.RvslInd = "true"
+	}
+	if e.CounterpartyNumber != "" {
+		acct := newCamtAccount(e.CounterpartyNumber, "")
+		if e.Amount < 0 {
+			ntry.Details.CdtrAcct = acct
+		} else {
+			ntry.Details.DbtrAcct = acct
+		}
+	}
+	return r.enc.Encode(ntry)
+}
+
+func (r *camt053Renderer) End(Summary) error {
+	for i := len(r.open) - 1; i >= 0; i-- {
+		if err := r.enc.EncodeToken(r.open[i].End()); err != nil {
+			return err
+		}
+	}
+	if err := r.enc.Flush(); err != nil {
+		return err
+	}
+	_ = r.buf.WriteByte('\n')
+	return r.buf.Flush()
+}
+
+// creditDebit is the ISO 20022 credit/debit indicator for a signed amount.
+func creditDebit(v float64) string {
+	if v < 0 {
+		return "DBIT"
+	}
+	return "CRDT"
+}
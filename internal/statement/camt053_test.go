@@ -0,0 +1,66 @@
+package statement
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCamt053ValidatesAgainstSchema(t *testing.T) {
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+	schema, err := filepath.Abs("testdata/camt.053.001.02.xsd")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		number string
+	}{
+		{"iban", "RS35260005601001611379"},
+		{"other", "265000000000000777"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, entries := september(tt.number)
+			path := filepath.Join(t.TempDir(), "statement.xml")
+			if err := os.WriteFile(path, render(t, FormatCamt053, h, entries), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			out, err := exec.Command(xmllint, "--noout", "--schema", schema, path).CombinedOutput()
+			if err != nil {
+				t.Fatalf("xmllint: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+func TestCamt053Verifies(t *testing.T) {
+	h, entries := september("RS35260005601001611379")
+	doc := render(t, FormatCamt053, h, entries)
+	if err := Verify(FormatCamt053, bytes.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		`<Cd>OPBD</Cd>`, `<Amt Ccy="RSD">100.00</Amt>`,
+		`<Cd>CLBD</Cd>`, `<Amt Ccy="RSD">84.50</Amt>`,
+		`<IBAN>RS35260005601001611379</IBAN>`,
+		`<ToDtTm>2026-09-30T23:59:59Z</ToDtTm>`,
+	} {
+		if !strings.Contains(string(doc), want) {
+			t.Errorf("statement lacks %s", want)
+		}
+	}
+
+	tampered := strings.Replace(string(doc), `<Amt Ccy="RSD">84.50</Amt>`, `<Amt Ccy="RSD">85.50</Amt>`, 1)
+	if err := Verify(FormatCamt053, strings.NewReader(tampered)); err == nil {
+		t.Error("Verify accepted a closing balance that does not add up")
+	}
+}
@@ -0,0 +1,51 @@
+package statement
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+)
+
+// csvRenderer writes one row per movement. The opening balance is the first
+// data row and the closing balance and totals are the last ones, marked in
+// the type column.
+type csvRenderer struct {
+	w        *csv.Writer
+	currency string
+}
+
+func newCSV(w io.Writer) Renderer { return &csvRenderer{w: csv.NewWriter(w)} }
+
+func (r *csvRenderer) Begin(h Header) error {
+	r.currency = h.Currency
+	_ = r.w.Write([]string{"date", "transaction_id", "type", "description", "counterparty_account_id", "debit", "credit", "balance", "currency"})
+	_ = r.w.Write([]string{h.From.Format(dateLayout), "", "opening_balance", "account " + h.AccountNumber, "", "", "", formatAmount(h.OpeningBalance, h.Currency), h.Currency})
+	return r.w.Error()
+}
+
+func (r *csvRenderer) Entry(e *Entry) error {
+	debit, credit := "", ""
+	if e.Amount < 0 {
+		debit = formatAmount(-e.Amount, r.currency)
+	} else {
+		credit = formatAmount(e.Amount, r.currency)
+	}
+	counterparty := ""
+	if e.CounterpartyID != 0 {
+		counterparty = strconv.Itoa(e.CounterpartyID)
+	}
+	_ = r.w.Write([]string{
+		e.CreatedAt.UTC().Format(time.RFC3339), strconv.Itoa(e.TransactionID), e.Type, e.Description, counterparty,
+		debit, credit, formatAmount(e.Balance, r.currency), r.currency,
+	})
+	return r.w.Error()
+}
+
+func (r *csvRenderer) End(s Summary) error {
+	_ = r.w.Write([]string{"", "", "totals", strconv.Itoa(s.Count) + " movements", "",
+		formatAmount(s.TotalDebits, r.currency), formatAmount(s.TotalCredits, r.currency), "", r.currency})
+	_ = r.w.Write([]string{"", "", "closing_balance", "", "", "", "", formatAmount(s.ClosingBalance, r.currency), r.currency})
+	r.w.Flush()
+	return r.w.Error()
+}
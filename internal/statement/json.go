@@ -0,0 +1,107 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// jsonRenderer streams a single JSON object; movements are written into its
+// array one at a time.
+type jsonRenderer struct {
+	w        *bufio.Writer
+	currency string
+	n        int
+}
+
+func newJSON(w io.Writer) Renderer { return &jsonRenderer{w: bufio.NewWriter(w)} }
+
+type jsonHeader struct {
+	AccountID      int       `json:"account_id"`
+	AccountNumber  string    `json:"account_number"`
+	AccountType    string    `json:"account_type"`
+	ClientID       int       `json:"client_id"`
+	Currency       string    `json:"currency"`
+	From           string    `json:"from"`
+	To             string    `json:"to"`
+	GeneratedAt    time.Time `json:"generated_at"`
+	OpeningBalance string    `json:"opening_balance"`
+}
+
+type jsonEntry struct {
+	Date                  time.Time `json:"date"`
+	TransactionID         int       `json:"transaction_id"`
+	Type                  string    `json:"type"`
+	Description           string    `json:"description,omitempty"`
+	CounterpartyAccountID int       `json:"counterparty_account_id,omitempty"`
+	Amount                string    `json:"amount"`
+	Balance               string    `json:"balance"`
+}
+
+type jsonSummary struct {
+	Count        int    `json:"count"`
+	TotalCredits string `json:"total_credits"`
+	TotalDebits  string `json:"total_debits"`
+}
+
+func (r *jsonRenderer) Begin(h Header) error {
+	r.currency = h.Currency
+	b, err := json.Marshal(jsonHeader{
+		AccountID:      h.AccountID,
+		AccountNumber:  h.AccountNumber,
+		AccountType:    h.AccountType,
+		ClientID:       h.ClientID,
+		Currency:       h.Currency,
+		From:           h.From.Format(dateLayout),
+		To:             h.To.Format(dateLayout),
+		GeneratedAt:    h.GeneratedAt,
+		OpeningBalance: formatAmount(h.OpeningBalance, h.Currency),
+	})
+	if err != nil {
+		return err
+	}
+	// Reopen the header object to append the movements array.
+	_, _ = r.w.Write(b[:len(b)-1])
+	_, err = r.w.WriteString(`,"movements":[`)
+	return err
+}
+
+func (r *jsonRenderer) Entry(e *Entry) error {
+	b, err := json.Marshal(jsonEntry{
+		Date:                  e.CreatedAt.UTC(),
+		TransactionID:         e.TransactionID,
+		Type:                  e.Type,
+		Description:           e.Description,
+		CounterpartyAccountID: e.CounterpartyID,
+		Amount:                formatAmount(e.Amount, r.currency),
+		Balance:               formatAmount(e.Balance, r.currency),
+	})
+	if err != nil {
+		return err
+	}
+	if r.n > 0 {
+		_ = r.w.WriteByte(',')
+	}
+	r.n++
+	_, err = r.w.Write(b)
+	return err
+}
+
+func (r *jsonRenderer) End(s Summary) error {
+	totals, err := json.Marshal(jsonSummary{
+		Count:        s.Count,
+		TotalCredits: formatAmount(s.TotalCredits, r.currency),
+		TotalDebits:  formatAmount(s.TotalDebits, r.currency),
+	})
+	if err != nil {
+		return err
+	}
+	closing, _ := json.Marshal(formatAmount(s.ClosingBalance, r.currency))
+	_, _ = r.w.WriteString(`],"closing_balance":`)
+	_, _ = r.w.Write(closing)
+	_, _ = r.w.WriteString(`,"totals":`)
+	_, _ = r.w.Write(totals)
+	_, _ = r.w.WriteString("}\n")
+	return r.w.Flush()
+}
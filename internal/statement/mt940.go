@@ -0,0 +1,146 @@
+package statement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mt940Renderer writes the text block of a SWIFT MT940 customer statement,
+// with CRLF line endings and the SWIFT x character set.
+type mt940Renderer struct {
+	w        *bufio.Writer
+	header   Header
+	currency string
+}
+
+func newMT940(w io.Writer) Renderer { return &mt940Renderer{w: bufio.NewWriter(w)} }
+
+// mt940Codes maps transaction types to SWIFT transaction type identification
+// codes used after the N in field 61.
+var mt940Codes = map[string]string{
+	"transfer":   "TRF",
+	"deposit":    "MSC",
+	"withdrawal": "MSC",
+	"fee":        "CHG",
+	"interest":   "INT",
+	"reversal":   "TRF",
+}
+
+func (r *mt940Renderer) field(tag, value string) {
+	r.w.WriteString(":" + tag + ":" + value + "\r\n")
+}
+
+func (r *mt940Renderer) Begin(h Header) error {
+	r.header = h
+	r.currency = h.Currency
+
+	// The statement number is the period's start as YYDDD, so consecutive
+	// statements for the same account are numbered in order.
+	number := (h.From.Year()%100)*1000 + h.From.YearDay()
+
+	r.field("20", "STMT"+h.From.Format("060102"))
+	r.field("25", swiftText(h.AccountNumber, 35))
+	r.field("28C", fmt.Sprintf("%05d/1", number))
+	r.field("60F", r.balance(h.OpeningBalance, h.From))
+	return nil
+}
+
+func (r *mt940Renderer) Entry(e *Entry) error {
+	mark := "C"
+	if e.Amount < 0 {
+		mark = "D"
+	}
+	code := mt940Codes[e.Type]
+	if code == "" {
+		code = "MSC"
+	}
+	ref := strconv.Itoa(e.TransactionID)
+	day := e.CreatedAt.UTC()
+
+	r.field("61", day.Format("060102")+day.Format("0102")+mark+swiftAmount(math.Abs(e.Amount), r.currency)+
+		"N"+code+swiftText(ref, 16)+"//"+swiftText(ref, 16))
+	if e.CounterpartyNumber != "" {
+		r.w.WriteString(swiftText(e.CounterpartyNumber, 34) + "\r\n")
+	}
+
+	info := e.Description
+	if info == "" {
+		info = e.Type
+	}
+	lines := wrap(strings.TrimSpace(swiftText(info, 6*65-4)), 65-4)
+	for i, l := range lines {
+		if i == 0 {
+			r.field("86", l)
+		} else {
+			r.w.WriteString(l + "\r\n")
+		}
+	}
+	return nil
+}
+
+func (r *mt940Renderer) End(s Summary) error {
+	r.field("62F", r.balance(s.ClosingBalance, r.header.To))
+	r.w.WriteString("-\r\n")
+	return r.w.Flush()
+}
+
+// balance formats a 60F/62F balance: mark, YYMMDD, currency, amount.
+func (r *mt940Renderer) balance(v float64, day time.Time) string {
+	mark := "C"
+	if v < 0 {
+		mark = "D"
+	}
+	return mark + day.Format("060102") + r.currency + swiftAmount(math.Abs(v), r.currency)
+}
+
+// swiftAmount formats an amount with a decimal comma, which SWIFT requires
+// even when the currency has no minor unit.
+func swiftAmount(v float64, code string) string {
+	s := strings.Replace(formatAmount(v, code), ".", ",", 1)
+	if !strings.Contains(s, ",") {
+		s += ","
+	}
+	return s
+}
+
+// swiftFallback spells letters outside the SWIFT x character set.
+var swiftFallback = map[rune]string{
+	'Č': "C", 'č': "c", 'Ć': "C", 'ć': "c", 'Đ': "Dj", 'đ': "dj",
+	'Š': "S", 'š': "s", 'Ž': "Z", 'ž': "z",
+}
+
+// swiftText reduces s to the SWIFT x character set and at most n characters.
+func swiftText(s string, n int) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("/-?().,'+ ", r):
+			b.WriteRune(r)
+		case r == ':':
+			// a colon would read as a new field tag at the start of a line
+			b.WriteByte('.')
+		default:
+			if f, ok := swiftFallback[r]; ok {
+				b.WriteString(f)
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+	}
+	return clip(b.String(), n)
+}
+
+// wrap splits s into lines of at most n characters.
+func wrap(s string, n int) []string {
+	var out []string
+	for len(s) > n {
+		out = append(out, s[:n])
+		s = s[n:]
+	}
+	return append(out, s)
+}
@@ -0,0 +1,59 @@
+package statement
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestMT940Balances(t *testing.T) {
+	h, entries := september("265000000000000777")
+	doc := render(t, FormatMT940, h, entries)
+	if err := Verify(FormatMT940, bytes.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+
+	fields := map[string]string{}
+	for _, line := range strings.Split(string(doc), "\r\n") {
+		if tag, value, ok := strings.Cut(strings.TrimPrefix(line, ":"), ":"); ok && strings.HasPrefix(line, ":") {
+			if _, seen := fields[tag]; !seen {
+				fields[tag] = value
+			}
+		}
+	}
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"20", "STMT260901"},
+		{"25", "265000000000000777"},
+		{"28C", "26244/1"},
+		{"60F", "C260901RSD100,00"},
+		{"61", "2609020902C2,50NMSC11//11"},
+		{"62F", "C260930RSD84,50"},
+	}
+	for _, tt := range tests {
+		if got := fields[tt.tag]; got != tt.want {
+			t.Errorf(":%s: = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+	if !strings.HasSuffix(string(doc), "\r\n-\r\n") {
+		t.Error("statement is not terminated with '-'")
+	}
+}
+
+func TestMT940DebitBalance(t *testing.T) {
+	h, entries := september("265000000000000777")
+	h.OpeningBalance = -30
+	h.Totals.ClosingBalance = -45.50
+	doc := string(render(t, FormatMT940, h, entries))
+
+	for _, want := range []string{":60F:D260901RSD30,00\r\n", ":62F:D260930RSD45,50\r\n"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("statement lacks %q", strings.TrimSpace(want))
+		}
+	}
+	if err := Verify(FormatMT940, strings.NewReader(doc)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package statement
+
+import (
+	"basic-gin/internal/pdf"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// pdfRenderer lays the statement out as a fixed-width table.
+type pdfRenderer struct {
+	doc      *pdf.Document
+	header   Header
+	currency string
+}
+
+func newPDF(w io.Writer) Renderer {
+	doc := pdf.New(w)
+	doc.FontSize, doc.Leading = 8, 11
+	return &pdfRenderer{doc: doc}
+}
+
+const pdfRow = "%-10s %-10s %8s %-9s %-20s %13s %13s %14s"
+
+func (r *pdfRenderer) Begin(h Header) error {
+	r.header = h
+	r.currency = h.Currency
+	r.doc.Header = func(d *pdf.Document, page int) {
+		d.BoldLine(fmt.Sprintf("Account statement %s (%s)  %s - %s  page %d",
+			h.AccountNumber, h.Currency, h.From.Format(dateLayout), h.To.Format(dateLayout), page))
+		d.Blank()
+		d.BoldLine(fmt.Sprintf(pdfRow, "Date", "Type", "Ref", "Counter", "Description", "Debit", "Credit", "Balance"))
+	}
+
+	r.doc.BoldLine("ACCOUNT STATEMENT")
+	r.doc.Line(fmt.Sprintf("Account:   %s (id %d, %s)", h.AccountNumber, h.AccountID, h.AccountType))
+	r.doc.Line(fmt.Sprintf("Client id: %d", h.ClientID))
+	r.doc.Line(fmt.Sprintf("Period:    %s - %s", h.From.Format(dateLayout), h.To.Format(dateLayout)))
+	r.doc.Line(fmt.Sprintf("Generated: %s", h.GeneratedAt.UTC().Format("2006-01-02 15:04 MST")))
+	r.doc.Blank()
+	r.doc.Line(fmt.Sprintf(pdfRow, h.From.Format(dateLayout), "opening", "", "", "", "", "", formatAmount(h.OpeningBalance, h.Currency)))
+	return nil
+}
+
+func (r *pdfRenderer) Entry(e *Entry) error {
+	debit, credit := "", ""
+	if e.Amount < 0 {
+		debit = formatAmount(-e.Amount, r.currency)
+	} else {
+		credit = formatAmount(e.Amount, r.currency)
+	}
+	counterparty := ""
+	if e.CounterpartyID != 0 {
+		counterparty = strconv.Itoa(e.CounterpartyID)
+	}
+	r.doc.Line(fmt.Sprintf(pdfRow, e.CreatedAt.UTC().Format(dateLayout), clip(e.Type, 10), clip(strconv.Itoa(e.TransactionID), 8),
+		clip(counterparty, 9), clip(e.Description, 20), debit, credit, formatAmount(e.Balance, r.currency)))
+	return nil
+}
+
+func (r *pdfRenderer) End(s Summary) error {
+	r.doc.Line(fmt.Sprintf(pdfRow, r.header.To.Format(dateLayout), "closing", "", "", "", "", "", formatAmount(s.ClosingBalance, r.currency)))
+	r.doc.Blank()
+	r.doc.BoldLine("Totals")
+	r.doc.Line(fmt.Sprintf("Movements:     %d", s.Count))
+	r.doc.Line(fmt.Sprintf("Total debits:  %s %s", formatAmount(s.TotalDebits, r.currency), r.currency))
+	r.doc.Line(fmt.Sprintf("Total credits: %s %s", formatAmount(s.TotalCredits, r.currency), r.currency))
+	r.doc.Line(fmt.Sprintf("Opening:       %s %s", formatAmount(r.header.OpeningBalance, r.currency), r.currency))
+	r.doc.Line(fmt.Sprintf("Closing:       %s %s", formatAmount(s.ClosingBalance, r.currency), r.currency))
+	return r.doc.Close()
+}
+
+// clip shortens s to n characters.
+func clip(s string, n int) string {
+	rs := []rune(s)
+	if len(rs) <= n {
+		return s
+	}
+	return string(rs[:n])
+}
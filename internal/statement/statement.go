@@ -0,0 +1,128 @@
+// Package statement renders account statements. A Renderer receives the
+// header, then every movement with its running balance, then the closing
+// summary, and writes its format as it goes so periods of any length stream.
+package statement
+
+import (
+	"basic-gin/internal/currency"
+	"basic-gin/internal/model"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatPDF     = "pdf"
+	FormatCamt053 = "camt053"
+	FormatMT940   = "mt940"
+)
+
+type Header struct {
+	AccountID     int
+	AccountNumber string
+	AccountType   string
+	ClientID      int
+	Currency      string
+	// From and To are the first and last day covered, inclusive.
+	From           time.Time
+	To             time.Time
+	OpeningBalance float64
+	// Totals is worked out before any movement is rendered, for formats
+	// that print the closing balance and totals ahead of the movements.
+	Totals      Summary
+	GeneratedAt time.Time
+}
+
+// Entry is a movement and the account balance right after it.
+type Entry struct {
+	*model.StatementLine
+	Balance float64
+}
+
+type Summary struct {
+	ClosingBalance float64
+	Count          int
+	CreditCount    int
+	DebitCount     int
+	TotalCredits   float64
+	TotalDebits    float64
+}
+
+type Renderer interface {
+	Begin(h Header) error
+	Entry(e *Entry) error
+	End(s Summary) error
+}
+
+type format struct {
+	contentType string
+	newRenderer func(io.Writer) Renderer
+}
+
+var formats = map[string]format{
+	FormatCSV:  {"text/csv; charset=utf-8", newCSV},
+	FormatJSON: {"application/json; charset=utf-8", newJSON},
+	FormatPDF:  {"application/pdf", newPDF},
+
+	FormatCamt053: {"application/xml; charset=utf-8", newCamt053},
+	FormatMT940:   {"text/plain; charset=us-ascii", newMT940},
+}
+
+// Supported reports whether name is a known format.
+func Supported(name string) bool {
+	_, ok := formats[name]
+	return ok
+}
+
+// ContentType is the MIME type for the format.
+func ContentType(name string) string { return formats[name].contentType }
+
+// Extension is the file extension for the format.
+func Extension(name string) string {
+	switch name {
+	case FormatCamt053:
+		return "xml"
+	case FormatMT940:
+		return "sta"
+	}
+	return name
+}
+
+// New returns a renderer for the format writing to w.
+func New(name string, w io.Writer) (Renderer, error) {
+	f, ok := formats[name]
+	if !ok {
+		return nil, fmt.Errorf("unsupported statement format %q", name)
+	}
+	return f.newRenderer(w), nil
+}
+
+// Formats lists the supported format names.
+func Formats() []string {
+	out := make([]string, 0, len(formats))
+	for _, name := range []string{FormatCSV, FormatJSON, FormatPDF, FormatCamt053, FormatMT940} {
+		if _, ok := formats[name]; ok {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// formatAmount prints v with the currency's minor-unit precision.
+func formatAmount(v float64, code string) string {
+	n, err := currency.MinorUnits(code)
+	if err != nil {
+		n = 2
+	}
+	s := strconv.FormatFloat(currency.Round(code, v), 'f', n, 64)
+	if strings.Trim(s, "-0.") == "" {
+		s = strings.TrimPrefix(s, "-")
+	}
+	return s
+}
+
+const dateLayout = "2006-01-02"
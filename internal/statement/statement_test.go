@@ -0,0 +1,69 @@
+package statement
+
+import (
+	"basic-gin/internal/model"
+	"bytes"
+	"testing"
+	"time"
+)
+
+// september is a known statement: 100.00 RSD opening, four movements,
+// 84.50 RSD closing.
+func september(number string) (Header, []*Entry) {
+	day := func(d, h int) time.Time { return time.Date(2026, time.September, d, h, 0, 0, 0, time.UTC) }
+	lines := []*model.StatementLine{
+		{TransactionID: 11, Type: "deposit", Amount: 2.50, Currency: "RSD", CreatedAt: day(2, 9)},
+		{TransactionID: 12, Type: "transfer", Description: "Rent: Čubrilović & sinovi", CounterpartyNumber: "265000000012345678",
+			Amount: -20.00, Currency: "RSD", CreatedAt: day(5, 10)},
+		{TransactionID: 13, Type: "reversal", Description: "reversal of 12", CounterpartyNumber: "265000000012345678",
+			Amount: 1.00, Currency: "RSD", CreatedAt: day(6, 11)},
+		{TransactionID: 14, Type: "interest", Amount: 1.00, Currency: "RSD", CreatedAt: day(30, 23)},
+	}
+
+	h := Header{
+		AccountID:      7,
+		AccountNumber:  number,
+		Currency:       "RSD",
+		From:           day(1, 0),
+		To:             day(30, 0),
+		OpeningBalance: 100,
+		GeneratedAt:    time.Date(2026, time.October, 1, 8, 0, 0, 0, time.UTC),
+	}
+	balance := h.OpeningBalance
+	entries := make([]*Entry, 0, len(lines))
+	for _, l := range lines {
+		balance += l.Amount
+		entries = append(entries, &Entry{StatementLine: l, Balance: balance})
+		h.Totals.Count++
+		if l.Amount < 0 {
+			h.Totals.DebitCount++
+			h.Totals.TotalDebits -= l.Amount
+		} else {
+			h.Totals.CreditCount++
+			h.Totals.TotalCredits += l.Amount
+		}
+	}
+	h.Totals.ClosingBalance = balance
+	return h, entries
+}
+
+func render(t *testing.T, format string, h Header, entries []*Entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	r, err := New(format, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Begin(h); err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	for _, e := range entries {
+		if err := r.Entry(e); err != nil {
+			t.Fatalf("Entry %d: %v", e.TransactionID, err)
+		}
+	}
+	if err := r.End(h.Totals); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,230 @@
+package statement
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Verify re-reads an exported camt.053 or MT940 file and checks that it is
+// complete and internally consistent: the required blocks are present and the
+// opening balance plus the entries equals the closing balance. Conformance of
+// the camt.053 output to the ISO 20022 schema is covered by the package tests.
+func Verify(format string, r io.Reader) error {
+	switch format {
+	case FormatCamt053:
+		return verifyCamt053(r)
+	case FormatMT940:
+		return verifyMT940(r)
+	}
+	return fmt.Errorf("no verifier for format %q", format)
+}
+
+type camtDoc struct {
+	XMLName xml.Name `xml:"Document"`
+	Stmt    struct {
+		ID      string        `xml:"Id"`
+		Acct    camtAccount   `xml:"Acct"`
+		Bal     []camtBalance `xml:"Bal"`
+		Summary struct {
+			Count int    `xml:"TtlNtries>NbOfNtries"`
+			Sum   string `xml:"TtlNtries>Sum"`
+		} `xml:"TxsSummry"`
+		Ntry []struct {
+			Amt       camtAmount `xml:"Amt"`
+			CdtDbtInd string     `xml:"CdtDbtInd"`
+			Sts       string     `xml:"Sts"`
+			TxCode    string     `xml:"BkTxCd>Prtry>Cd"`
+		} `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+	MsgID string `xml:"BkToCstmrStmt>GrpHdr>MsgId"`
+}
+
+func verifyCamt053(r io.Reader) error {
+	var doc camtDoc
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return fmt.Errorf("camt.053: %w", err)
+	}
+	if doc.XMLName.Space != Camt053Namespace {
+		return fmt.Errorf("camt.053: namespace %q, want %q", doc.XMLName.Space, Camt053Namespace)
+	}
+	st := doc.Stmt
+	switch {
+	case doc.MsgID == "" || len(doc.MsgID) > 35:
+		return errors.New("camt.053: GrpHdr/MsgId missing or longer than 35 characters")
+	case st.ID == "":
+		return errors.New("camt.053: Stmt/Id missing")
+	case st.Acct.id() == "" || st.Acct.Ccy == "":
+		return errors.New("camt.053: Acct/Id and Acct/Ccy are required")
+	}
+
+	balances := map[string]float64{}
+	for _, b := range st.Bal {
+		v, err := signedAmount(b.Amt.Value, b.CdtDbtInd)
+		if err != nil {
+			return fmt.Errorf("camt.053: balance %s: %w", b.Code, err)
+		}
+		if b.Amt.Ccy != st.Acct.Ccy {
+			return fmt.Errorf("camt.053: balance %s in %s, account is %s", b.Code, b.Amt.Ccy, st.Acct.Ccy)
+		}
+		balances[b.Code] = v
+	}
+	opening, okOpen := balances["OPBD"]
+	closing, okClose := balances["CLBD"]
+	if !okOpen || !okClose {
+		return errors.New("camt.053: OPBD and CLBD balances are required")
+	}
+
+	running, sum := opening, 0.0
+	for i, n := range st.Ntry {
+		v, err := signedAmount(n.Amt.Value, n.CdtDbtInd)
+		if err != nil {
+			return fmt.Errorf("camt.053: entry %d: %w", i+1, err)
+		}
+		if n.Sts == "" || n.TxCode == "" {
+			return fmt.Errorf("camt.053: entry %d: Sts and BkTxCd are required", i+1)
+		}
+		running += v
+		sum += math.Abs(v)
+	}
+	if st.Summary.Count != len(st.Ntry) {
+		return fmt.Errorf("camt.053: summary counts %d entries, file has %d", st.Summary.Count, len(st.Ntry))
+	}
+	if total, err := strconv.ParseFloat(st.Summary.Sum, 64); err != nil || !near(total, sum) {
+		return fmt.Errorf("camt.053: summary sum %s does not match entries %.5f", st.Summary.Sum, sum)
+	}
+	if !near(running, closing) {
+		return fmt.Errorf("camt.053: opening %.5f plus entries gives %.5f, closing is %.5f", opening, running, closing)
+	}
+	return nil
+}
+
+func verifyMT940(r io.Reader) error {
+	sc := bufio.NewScanner(r)
+	fields := map[string]bool{}
+	var opening, closing, running float64
+	lineNo, terminated := 0, false
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if len(line) > 65 {
+			return fmt.Errorf("mt940: line %d is longer than 65 characters", lineNo)
+		}
+		if line == "-" {
+			terminated = true
+			continue
+		}
+		if !strings.HasPrefix(line, ":") {
+			if swiftText(line, len(line)) != line {
+				return fmt.Errorf("mt940: line %d has characters outside the SWIFT set", lineNo)
+			}
+			continue
+		}
+		end := strings.Index(line[1:], ":")
+		if end < 0 {
+			return fmt.Errorf("mt940: line %d: malformed tag", lineNo)
+		}
+		tag, value := line[1:end+1], line[end+2:]
+		if swiftText(value, len(value)) != value {
+			return fmt.Errorf("mt940: line %d has characters outside the SWIFT set", lineNo)
+		}
+		fields[tag] = true
+
+		var err error
+		switch tag {
+		case "60F":
+			opening, err = mt940Balance(value)
+			running = opening
+		case "62F":
+			closing, err = mt940Balance(value)
+		case "61":
+			var v float64
+			v, err = mt940Entry(value)
+			running += v
+		}
+		if err != nil {
+			return fmt.Errorf("mt940: line %d: %w", lineNo, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	for _, tag := range []string{"20", "25", "28C", "60F", "62F"} {
+		if !fields[tag] {
+			return fmt.Errorf("mt940: field :%s: missing", tag)
+		}
+	}
+	if !terminated {
+		return errors.New("mt940: statement not terminated with '-'")
+	}
+	if !near(running, closing) {
+		return fmt.Errorf("mt940: opening %.5f plus entries gives %.5f, closing is %.5f", opening, running, closing)
+	}
+	return nil
+}
+
+// mt940Balance parses "C260901RSD100,00".
+func mt940Balance(v string) (float64, error) {
+	if len(v) < 11 || (v[0] != 'C' && v[0] != 'D') {
+		return 0, fmt.Errorf("malformed balance %q", v)
+	}
+	amount, err := parseSwiftAmount(v[10:])
+	if err != nil {
+		return 0, err
+	}
+	if v[0] == 'D' {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// mt940Entry parses the mark and amount of a field 61 value.
+func mt940Entry(v string) (float64, error) {
+	if len(v) < 12 {
+		return 0, fmt.Errorf("malformed entry %q", v)
+	}
+	rest := v[10:]
+	sign := 1.0
+	switch {
+	case strings.HasPrefix(rest, "RC"), strings.HasPrefix(rest, "RD"):
+		return 0, fmt.Errorf("reversal marks are not used: %q", v)
+	case rest[0] == 'D':
+		sign = -1
+	case rest[0] != 'C':
+		return 0, fmt.Errorf("malformed mark in %q", v)
+	}
+	rest = rest[1:]
+	n := strings.IndexByte(rest, 'N')
+	if n < 0 {
+		return 0, fmt.Errorf("missing transaction type in %q", v)
+	}
+	amount, err := parseSwiftAmount(rest[:n])
+	return sign * amount, err
+}
+
+func parseSwiftAmount(s string) (float64, error) {
+	s = strings.TrimSuffix(strings.Replace(s, ",", ".", 1), ".")
+	return strconv.ParseFloat(s, 64)
+}
+
+func signedAmount(value, ind string) (float64, error) {
+	v, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, err
+	}
+	switch ind {
+	case "CRDT":
+		return v, nil
+	case "DBIT":
+		return -v, nil
+	}
+	return 0, fmt.Errorf("credit/debit indicator %q", ind)
+}
+
+// near compares amounts that carry at most three decimals.
+func near(a, b float64) bool { return math.Abs(a-b) < 1e-4 }
@@ -0,0 +1,171 @@
+// Package textmatch compares people's and companies' names the way a clerk
+// would: case, punctuation, diacritics, Serbian Cyrillic and word order do
+// not matter, and small typos score as close.
+package textmatch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+var transliteration = map[rune]string{
+	// Latin letters with diacritics that do not decompose.
+	'đ': "dj", 'ß': "ss", 'ø': "o", 'ł': "l", 'æ': "ae", 'œ': "oe",
+	// Serbian Cyrillic.
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'ђ': "dj", 'е': "e",
+	'ж': "z", 'з': "z", 'и': "i", 'ј': "j", 'к': "k", 'л': "l", 'љ': "lj",
+	'м': "m", 'н': "n", 'њ': "nj", 'о': "o", 'п': "p", 'р': "r", 'с': "s",
+	'т': "t", 'ћ': "c", 'у': "u", 'ф': "f", 'х': "h", 'ц': "c", 'ч': "c",
+	'џ': "dz", 'ш': "s",
+}
+
+// folded maps precomposed Latin letters to their base letter.
+var folded = map[rune]rune{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a', 'ā': 'a', 'ă': 'a', 'ą': 'a',
+	'ç': 'c', 'ć': 'c', 'č': 'c', 'ĉ': 'c', 'ď': 'd',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e', 'ē': 'e', 'ę': 'e', 'ě': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ī': 'i',
+	'ñ': 'n', 'ń': 'n', 'ň': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o', 'ő': 'o', 'ō': 'o',
+	'ř': 'r', 'ś': 's', 'š': 's', 'ş': 's', 'ș': 's', 'ť': 't', 'ț': 't',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ű': 'u', 'ū': 'u', 'ů': 'u',
+	'ý': 'y', 'ÿ': 'y', 'ź': 'z', 'ż': 'z', 'ž': 'z',
+}
+
+// Normalize lower-cases s, folds diacritics and Cyrillic to plain Latin,
+// turns punctuation into spaces and collapses whitespace.
+func Normalize(s string) string {
+	var b strings.Builder
+	space := true
+	for _, r := range strings.ToLower(s) {
+		if t, ok := transliteration[r]; ok {
+			b.WriteString(t)
+			space = false
+			continue
+		}
+		if f, ok := folded[r]; ok {
+			r = f
+		}
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+			space = false
+		case unicode.Is(unicode.Mn, r), r == '\'':
+			// Combining marks and apostrophes (O'Neil) vanish.
+		default:
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Tokens returns the normalized words of s in alphabetical order.
+func Tokens(s string) []string {
+	t := strings.Fields(Normalize(s))
+	sort.Strings(t)
+	return t
+}
+
+// JaroWinkler returns the Jaro-Winkler similarity of a and b, from 0 to 1.
+func JaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	window := max(len(ra), len(rb))/2 - 1
+	if window < 0 {
+		window = 0
+	}
+	ma := make([]bool, len(ra))
+	mb := make([]bool, len(rb))
+	matches := 0
+	for i := range ra {
+		lo, hi := max(0, i-window), min(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !mb[j] && ra[i] == rb[j] {
+				ma[i], mb[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !ma[i] {
+			continue
+		}
+		for !mb[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < 4 && prefix < len(ra) && prefix < len(rb) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// NameSimilarity scores two names from 0 to 1, ignoring word order.
+func NameSimilarity(a, b string) float64 {
+	ta, tb := Tokens(a), Tokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	return max(
+		JaroWinkler(Normalize(a), Normalize(b)),
+		JaroWinkler(strings.Join(ta, " "), strings.Join(tb, " ")),
+	)
+}
+
+// SameTokens reports whether a and b have the same words in any order.
+func SameTokens(a, b string) bool {
+	ta, tb := Tokens(a), Tokens(b)
+	if len(ta) == 0 || len(ta) != len(tb) {
+		return false
+	}
+	for i := range ta {
+		if ta[i] != tb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// InitialsMatch reports whether given abbreviates some words of full to
+// their initials and matches the rest, as "M. Petrovic" does "Marko
+// Petrovic".
+func InitialsMatch(given, full string) bool {
+	g, f := strings.Fields(Normalize(given)), strings.Fields(Normalize(full))
+	if len(g) == 0 || len(g) != len(f) {
+		return false
+	}
+	abbreviated := false
+	for i := range g {
+		switch {
+		case g[i] == f[i]:
+		case len(g[i]) == 1 && strings.HasPrefix(f[i], g[i]):
+			abbreviated = true
+		default:
+			return false
+		}
+	}
+	return abbreviated
+}
@@ -0,0 +1,82 @@
+package textmatch
+
+import (
+	"math"
+	"testing"
+)
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"MARTHA", "MARHTA", 0.961},
+		{"DWAYNE", "DUANE", 0.840},
+		{"DIXON", "DICKSONX", 0.813},
+		{"abc", "abc", 1},
+		{"abc", "xyz", 0},
+		{"", "", 1},
+		{"abc", "", 0},
+	}
+	for _, tt := range tests {
+		if got := JaroWinkler(tt.a, tt.b); math.Abs(got-tt.want) > 0.001 {
+			t.Errorf("JaroWinkler(%q, %q) = %.4f, want %.3f", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"  Marko   PETROVIĆ ", "marko petrovic"},
+		{"Ђорђе Јовановић", "djordje jovanovic"},
+		{"Đorđe Jovanović", "djordje jovanovic"},
+		{"O'Neil, Sean-Paul", "oneil sean paul"},
+		{"Niš", "nis"},
+		{"Müller & Söhne GmbH.", "muller sohne gmbh"},
+	}
+	for _, tt := range tests {
+		if got := Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTokenMatching(t *testing.T) {
+	if !SameTokens("Petrović Marko", "MARKO petrovic") {
+		t.Error("SameTokens ignores word order")
+	}
+	if SameTokens("Marko Petrovic", "Marko Petrovic Jr") {
+		t.Error("SameTokens matched names with different word counts")
+	}
+	if SameTokens("", "") {
+		t.Error("SameTokens matched empty names")
+	}
+
+	if !InitialsMatch("M. Petrović", "Marko Petrovic") {
+		t.Error("InitialsMatch(M. Petrović, Marko Petrovic) = false")
+	}
+	if InitialsMatch("Marko Petrovic", "Marko Petrovic") {
+		t.Error("InitialsMatch matched a name without initials")
+	}
+	if InitialsMatch("J. Petrovic", "Marko Petrovic") {
+		t.Error("InitialsMatch matched the wrong initial")
+	}
+
+	if got := NameSimilarity("Jovanović Đorđe", "Ђорђе Јовановић"); got != 1 {
+		t.Errorf("NameSimilarity of reordered transliterated names = %.3f, want 1", got)
+	}
+	if got := NameSimilarity("Marko Petrovic", "Marko Petrovič"); got != 1 {
+		t.Errorf("NameSimilarity ignoring diacritics = %.3f, want 1", got)
+	}
+	if got := NameSimilarity("Marko Petrovic", "Marko Petrovci"); got < 0.9 || got == 1 {
+		t.Errorf("NameSimilarity of a typo = %.3f, want close but not equal", got)
+	}
+	if got := NameSimilarity("Marko Petrovic", "Ana Ilic"); got > 0.7 {
+		t.Errorf("NameSimilarity of different names = %.3f, want low", got)
+	}
+	if got := NameSimilarity("", "Ana Ilic"); got != 0 {
+		t.Errorf("NameSimilarity with an empty name = %.3f, want 0", got)
+	}
+}
@@ -0,0 +1,32 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Every runs fn once per interval until ctx is cancelled. Errors are logged
+// and do not stop the loop. It blocks, so start it in its own goroutine.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	if interval <= 0 {
+		log.Printf("worker %s disabled: interval %s", name, interval)
+		return
+	}
+
+	log.Printf("worker %s started: every %s", name, interval)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Printf("worker %s stopped", name)
+			return
+		case <-t.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("worker %s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+ALTER TABLE transactions
+    DROP COLUMN currency,
+    ALTER COLUMN amount TYPE NUMERIC(18, 2);
+
+ALTER TABLE accounts
+    DROP COLUMN currency,
+    ALTER COLUMN balance TYPE NUMERIC(18, 2);
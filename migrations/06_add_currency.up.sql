@@ -0,0 +1,15 @@
+ALTER TABLE accounts
+    ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RSD',
+    ALTER COLUMN balance TYPE NUMERIC(18, 3);
+
+ALTER TABLE transactions
+    ADD COLUMN currency CHAR(3),
+    ALTER COLUMN amount TYPE NUMERIC(18, 3);
+
+UPDATE transactions t
+SET currency = a.currency
+FROM accounts a
+WHERE a.id = t.from_account_id;
+
+ALTER TABLE transactions
+    ALTER COLUMN currency SET NOT NULL;
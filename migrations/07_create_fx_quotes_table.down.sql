@@ -0,0 +1,9 @@
+ALTER TABLE transactions
+  DROP COLUMN fx_quote_id,
+  DROP COLUMN fx_spread_bps,
+  DROP COLUMN fx_mid_rate,
+  DROP COLUMN fx_rate,
+  DROP COLUMN to_currency,
+  DROP COLUMN to_amount;
+
+DROP TABLE fx_quotes;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS fx_quotes (
+  id             UUID PRIMARY KEY,
+  from_currency  CHAR(3) NOT NULL,
+  to_currency    CHAR(3) NOT NULL,
+  mid_rate       NUMERIC(20,10) NOT NULL CHECK (mid_rate > 0),
+  rate           NUMERIC(20,10) NOT NULL CHECK (rate > 0),
+  spread_bps     INT NOT NULL DEFAULT 0,
+  sell_amount    NUMERIC(18,3) NOT NULL CHECK (sell_amount > 0),
+  buy_amount     NUMERIC(18,3) NOT NULL CHECK (buy_amount > 0),
+  expires_at     TIMESTAMPTZ NOT NULL,
+  used_at        TIMESTAMPTZ,
+  transaction_id INT REFERENCES transactions(id),
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE transactions
+  ADD COLUMN to_amount     NUMERIC(18,3),
+  ADD COLUMN to_currency   CHAR(3),
+  ADD COLUMN fx_rate       NUMERIC(20,10),
+  ADD COLUMN fx_mid_rate   NUMERIC(20,10),
+  ADD COLUMN fx_spread_bps INT,
+  ADD COLUMN fx_quote_id   UUID REFERENCES fx_quotes(id);
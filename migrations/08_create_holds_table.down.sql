@@ -0,0 +1,9 @@
+DROP TABLE holds;
+
+DELETE FROM transactions WHERE type <> 'transfer';
+
+ALTER TABLE transactions
+  DROP CONSTRAINT transactions_has_account,
+  ALTER COLUMN from_account_id SET NOT NULL,
+  ALTER COLUMN to_account_id SET NOT NULL,
+  DROP COLUMN type;
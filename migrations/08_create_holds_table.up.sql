@@ -0,0 +1,22 @@
+ALTER TABLE transactions
+  ADD COLUMN type VARCHAR(20) NOT NULL DEFAULT 'transfer',
+  ALTER COLUMN from_account_id DROP NOT NULL,
+  ALTER COLUMN to_account_id DROP NOT NULL,
+  ADD CONSTRAINT transactions_has_account CHECK (from_account_id IS NOT NULL OR to_account_id IS NOT NULL);
+
+CREATE TABLE IF NOT EXISTS holds (
+  id              SERIAL PRIMARY KEY,
+  account_id      INT NOT NULL REFERENCES accounts(id),
+  amount          NUMERIC(18,3) NOT NULL CHECK (amount > 0),
+  captured_amount NUMERIC(18,3) NOT NULL DEFAULT 0 CHECK (captured_amount >= 0 AND captured_amount <= amount),
+  currency        CHAR(3) NOT NULL,
+  status          VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'captured', 'released', 'expired')),
+  reference       VARCHAR(100),
+  expires_at      TIMESTAMPTZ NOT NULL,
+  transaction_id  INT REFERENCES transactions(id),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_holds_account_active ON holds(account_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_holds_expires_active ON holds(expires_at) WHERE status = 'active';
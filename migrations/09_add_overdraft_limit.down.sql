@@ -0,0 +1,5 @@
+DROP TABLE audit_log;
+
+ALTER TABLE accounts
+  DROP CONSTRAINT accounts_balance_within_overdraft,
+  DROP COLUMN overdraft_limit;
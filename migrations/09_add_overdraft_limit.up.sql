@@ -0,0 +1,20 @@
+ALTER TABLE accounts
+  ADD COLUMN overdraft_limit NUMERIC(18,3) NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0);
+
+-- NOT VALID: balances that already went negative through unchecked withdrawals
+-- are left alone, every new balance change is checked.
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_balance_within_overdraft CHECK (balance >= -overdraft_limit) NOT VALID;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+  id          BIGSERIAL PRIMARY KEY,
+  actor       VARCHAR(100) NOT NULL,
+  action      VARCHAR(50) NOT NULL,
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id   VARCHAR(50) NOT NULL,
+  details     JSONB NOT NULL DEFAULT '{}'::jsonb,
+  request_id  VARCHAR(100),
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_type, entity_id);
@@ -0,0 +1,5 @@
+DROP TABLE standing_order_executions;
+DROP TABLE standing_orders;
+
+DROP INDEX IF EXISTS idx_transactions_idempotency_key;
+ALTER TABLE transactions DROP COLUMN idempotency_key;
@@ -0,0 +1,42 @@
+ALTER TABLE transactions
+  ADD COLUMN idempotency_key VARCHAR(120);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_idempotency_key
+  ON transactions(idempotency_key) WHERE idempotency_key IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS standing_orders (
+  id                     SERIAL PRIMARY KEY,
+  from_account_id        INT NOT NULL REFERENCES accounts(id),
+  to_account_id          INT NOT NULL REFERENCES accounts(id),
+  amount                 NUMERIC(18,3) NOT NULL CHECK (amount > 0),
+  currency               CHAR(3) NOT NULL,
+  schedule               VARCHAR(200) NOT NULL,
+  reference              VARCHAR(140),
+  start_at               TIMESTAMPTZ NOT NULL,
+  end_at                 TIMESTAMPTZ,
+  status                 VARCHAR(16) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'paused', 'cancelled', 'completed')),
+  max_retries            INT NOT NULL DEFAULT 0 CHECK (max_retries >= 0),
+  retry_interval_seconds INT NOT NULL DEFAULT 0 CHECK (retry_interval_seconds >= 0),
+  current_occurrence     TIMESTAMPTZ,
+  attempts               INT NOT NULL DEFAULT 0,
+  next_run_at            TIMESTAMPTZ,
+  created_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at             TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (from_account_id <> to_account_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_orders_due ON standing_orders(next_run_at) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_standing_orders_from ON standing_orders(from_account_id);
+
+CREATE TABLE IF NOT EXISTS standing_order_executions (
+  id                SERIAL PRIMARY KEY,
+  standing_order_id INT NOT NULL REFERENCES standing_orders(id) ON DELETE CASCADE,
+  scheduled_for     TIMESTAMPTZ NOT NULL,
+  attempt           INT NOT NULL,
+  status            VARCHAR(20) NOT NULL CHECK (status IN ('succeeded', 'failed', 'retry_scheduled', 'skipped')),
+  transaction_id    INT REFERENCES transactions(id),
+  error             TEXT,
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_standing_order_executions_order ON standing_order_executions(standing_order_id, scheduled_for);
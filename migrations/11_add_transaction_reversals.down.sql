@@ -0,0 +1,5 @@
+DELETE FROM transactions WHERE reversal_of IS NOT NULL;
+
+ALTER TABLE transactions
+  DROP COLUMN description,
+  DROP COLUMN reversal_of;
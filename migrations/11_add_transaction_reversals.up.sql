@@ -0,0 +1,5 @@
+ALTER TABLE transactions
+  ADD COLUMN reversal_of INT REFERENCES transactions(id),
+  ADD COLUMN description VARCHAR(255);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_reversal_of ON transactions(reversal_of) WHERE reversal_of IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP TABLE fee_schedules;
+
+DELETE FROM transactions WHERE fee_for IS NOT NULL;
+
+ALTER TABLE transactions DROP COLUMN fee_for;
+
+ALTER TABLE accounts DROP COLUMN account_type;
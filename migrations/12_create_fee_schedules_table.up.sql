@@ -0,0 +1,32 @@
+ALTER TABLE accounts
+  ADD COLUMN account_type VARCHAR(20) NOT NULL DEFAULT 'current';
+
+ALTER TABLE transactions
+  ADD COLUMN fee_for INT REFERENCES transactions(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_fee_for ON transactions(fee_for) WHERE fee_for IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS fee_schedules (
+  id             SERIAL PRIMARY KEY,
+  account_type   VARCHAR(20) NOT NULL,
+  operation      VARCHAR(20) NOT NULL CHECK (operation IN ('transfer', 'withdrawal')),
+  currency       CHAR(3) NOT NULL,
+  kind           VARCHAR(20) NOT NULL CHECK (kind IN ('flat', 'percentage', 'tiered')),
+  flat_amount    NUMERIC(18,3) NOT NULL DEFAULT 0 CHECK (flat_amount >= 0),
+  percent        NUMERIC(9,6) NOT NULL DEFAULT 0 CHECK (percent >= 0),
+  min_fee        NUMERIC(18,3) NOT NULL DEFAULT 0 CHECK (min_fee >= 0),
+  max_fee        NUMERIC(18,3) CHECK (max_fee IS NULL OR max_fee >= min_fee),
+  tiers          JSONB NOT NULL DEFAULT '[]'::jsonb,
+  free_per_month INT NOT NULL DEFAULT 0 CHECK (free_per_month >= 0),
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (account_type, operation, currency)
+);
+
+INSERT INTO fee_schedules (account_type, operation, currency, kind, flat_amount, percent, min_fee, max_fee, tiers, free_per_month)
+VALUES
+  ('current', 'transfer',   'RSD', 'percentage', 0,   0.1, 20,  500, '[]', 3),
+  ('current', 'withdrawal', 'RSD', 'flat',       100, 0,   0,   NULL, '[]', 2),
+  ('current', 'transfer',   'EUR', 'tiered',     0,   0,   0,   NULL,
+     '[{"up_to": 1000, "flat": 0.5, "percent": 0}, {"up_to": 10000, "flat": 1, "percent": 0.05}, {"flat": 5, "percent": 0.02}]', 0)
+ON CONFLICT (account_type, operation, currency) DO NOTHING;
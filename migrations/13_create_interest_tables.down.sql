@@ -0,0 +1,9 @@
+DROP TABLE interest_accruals;
+
+DROP TABLE interest_capitalizations;
+
+DELETE FROM transactions WHERE type = 'interest';
+
+ALTER TABLE accounts DROP CONSTRAINT accounts_account_type_fkey;
+
+DROP TABLE account_products;
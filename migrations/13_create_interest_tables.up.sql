@@ -0,0 +1,48 @@
+CREATE TABLE IF NOT EXISTS account_products (
+  code        VARCHAR(20) PRIMARY KEY,
+  name        VARCHAR(100) NOT NULL,
+  -- annual_rate is a percentage, accrued daily on an ACT/365 basis
+  annual_rate NUMERIC(9,6) NOT NULL DEFAULT 0 CHECK (annual_rate >= 0),
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO account_products (code, name, annual_rate)
+VALUES
+  ('current', 'Current account', 0),
+  ('savings', 'Savings account', 2.5)
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO account_products (code, name)
+SELECT DISTINCT account_type, account_type FROM accounts
+ON CONFLICT (code) DO NOTHING;
+
+ALTER TABLE accounts
+  ADD CONSTRAINT accounts_account_type_fkey FOREIGN KEY (account_type) REFERENCES account_products(code);
+
+CREATE TABLE IF NOT EXISTS interest_capitalizations (
+  id             SERIAL PRIMARY KEY,
+  account_id     INT NOT NULL REFERENCES accounts(id),
+  period         DATE NOT NULL,
+  accrued        NUMERIC(20,8) NOT NULL,
+  carried_in     NUMERIC(20,8) NOT NULL DEFAULT 0,
+  posted         NUMERIC(18,3) NOT NULL CHECK (posted >= 0),
+  carry_out      NUMERIC(20,8) NOT NULL DEFAULT 0,
+  transaction_id INT REFERENCES transactions(id),
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (account_id, period)
+);
+
+CREATE TABLE IF NOT EXISTS interest_accruals (
+  id                SERIAL PRIMARY KEY,
+  account_id        INT NOT NULL REFERENCES accounts(id),
+  accrual_date      DATE NOT NULL,
+  balance           NUMERIC(18,3) NOT NULL,
+  annual_rate       NUMERIC(9,6) NOT NULL,
+  amount            NUMERIC(20,8) NOT NULL CHECK (amount >= 0),
+  capitalization_id INT REFERENCES interest_capitalizations(id),
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (account_id, accrual_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_interest_accruals_open ON interest_accruals(account_id, accrual_date) WHERE capitalization_id IS NULL;
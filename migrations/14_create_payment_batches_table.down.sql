@@ -0,0 +1,3 @@
+DROP TABLE payment_batch_items;
+
+DROP TABLE payment_batches;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS payment_batches (
+  id           SERIAL PRIMARY KEY,
+  format       VARCHAR(10) NOT NULL CHECK (format IN ('pain001', 'csv')),
+  mode         VARCHAR(20) NOT NULL CHECK (mode IN ('all_or_nothing', 'best_effort')),
+  status       VARCHAR(20) NOT NULL DEFAULT 'pending'
+                 CHECK (status IN ('pending', 'processing', 'completed', 'partially_completed', 'failed', 'rejected')),
+  message_id   VARCHAR(35),
+  control_sum  NUMERIC(18,3) NOT NULL DEFAULT 0,
+  created_by   VARCHAR(100),
+  error        TEXT,
+  -- claim_token and lease_until identify the worker processing the batch;
+  -- an expired lease lets another worker take over.
+  claim_token  UUID,
+  lease_until  TIMESTAMPTZ,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  completed_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_batches_message_id ON payment_batches(message_id) WHERE message_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_payment_batches_open ON payment_batches(id) WHERE status IN ('pending', 'processing');
+
+CREATE TABLE IF NOT EXISTS payment_batch_items (
+  id                  SERIAL PRIMARY KEY,
+  batch_id            INT NOT NULL REFERENCES payment_batches(id) ON DELETE CASCADE,
+  line_no             INT NOT NULL,
+  from_account_number VARCHAR(34) NOT NULL,
+  to_account_number   VARCHAR(34) NOT NULL,
+  from_account_id     INT REFERENCES accounts(id),
+  to_account_id       INT REFERENCES accounts(id),
+  amount              NUMERIC(18,3) NOT NULL,
+  currency            CHAR(3),
+  end_to_end_id       VARCHAR(35),
+  description         VARCHAR(140),
+  status              VARCHAR(16) NOT NULL DEFAULT 'pending'
+                        CHECK (status IN ('pending', 'invalid', 'succeeded', 'failed', 'skipped')),
+  error               TEXT,
+  transaction_id      INT REFERENCES transactions(id),
+  updated_at          TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (batch_id, line_no)
+);
@@ -0,0 +1,2 @@
+ALTER TABLE accounts
+  DROP COLUMN legacy_account_number;
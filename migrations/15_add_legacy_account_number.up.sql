@@ -0,0 +1,4 @@
+-- Accounts renumbered into the configured scheme (see the renumber-accounts
+-- command) keep their old number here so it still resolves.
+ALTER TABLE accounts
+  ADD COLUMN legacy_account_number VARCHAR(50) UNIQUE;
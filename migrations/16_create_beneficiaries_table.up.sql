@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS beneficiaries (
+  id             SERIAL PRIMARY KEY,
+  client_id      INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  nickname       VARCHAR(70) NOT NULL,
+  account_id     INT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  account_number VARCHAR(50) NOT NULL,
+  name           VARCHAR(140) NOT NULL,
+  currency       CHAR(3) NOT NULL,
+  -- payee_match is the confirmation-of-payee outcome when the beneficiary
+  -- was saved.
+  payee_match    VARCHAR(16) NOT NULL CHECK (payee_match IN ('match', 'close_match')),
+  -- Large transfers to the beneficiary are refused until trusted_at.
+  trusted_at     TIMESTAMPTZ NOT NULL,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (client_id, account_id),
+  UNIQUE (client_id, nickname)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_transactions_outgoing;
+
+DROP TABLE transfer_limits;
@@ -0,0 +1,40 @@
+-- A limit row caps outgoing transfers or withdrawals. Product rows are the
+-- defaults for an account type; client and account rows override them field
+-- by field. A NULL cap means the level does not set that cap.
+CREATE TABLE IF NOT EXISTS transfer_limits (
+  id              SERIAL PRIMARY KEY,
+  scope           VARCHAR(10) NOT NULL CHECK (scope IN ('product', 'client', 'account')),
+  account_type    VARCHAR(20) REFERENCES account_products(code) ON DELETE CASCADE,
+  client_id       INT REFERENCES clients(id) ON DELETE CASCADE,
+  account_id      INT REFERENCES accounts(id) ON DELETE CASCADE,
+  operation       VARCHAR(20) NOT NULL CHECK (operation IN ('transfer', 'withdrawal')),
+  currency        CHAR(3) NOT NULL,
+  per_transaction NUMERIC(18,3) CHECK (per_transaction > 0),
+  daily           NUMERIC(18,3) CHECK (daily > 0),
+  monthly         NUMERIC(18,3) CHECK (monthly > 0),
+  created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (
+    (scope = 'product' AND account_type IS NOT NULL AND client_id IS NULL AND account_id IS NULL) OR
+    (scope = 'client' AND client_id IS NOT NULL AND account_type IS NULL AND account_id IS NULL) OR
+    (scope = 'account' AND account_id IS NOT NULL AND account_type IS NULL AND client_id IS NULL)
+  )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_limits_product ON transfer_limits(account_type, operation, currency) WHERE scope = 'product';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_limits_client ON transfer_limits(client_id, operation, currency) WHERE scope = 'client';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transfer_limits_account ON transfer_limits(account_id, operation) WHERE scope = 'account';
+
+CREATE INDEX IF NOT EXISTS idx_transactions_outgoing ON transactions(from_account_id, type, created_at);
+
+INSERT INTO transfer_limits (scope, account_type, operation, currency, per_transaction, daily, monthly)
+VALUES
+  ('product', 'current', 'transfer',   'RSD', 1000000, 2000000, 10000000),
+  ('product', 'current', 'withdrawal', 'RSD',  200000,  400000,  2000000),
+  ('product', 'current', 'transfer',   'EUR',   10000,   20000,   100000),
+  ('product', 'current', 'withdrawal', 'EUR',    2000,    4000,    20000),
+  ('product', 'savings', 'transfer',   'RSD',  500000,  500000,  2000000),
+  ('product', 'savings', 'withdrawal', 'RSD',  100000,  100000,   500000),
+  ('product', 'savings', 'transfer',   'EUR',    5000,    5000,    20000),
+  ('product', 'savings', 'withdrawal', 'EUR',    1000,    1000,     5000)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,23 @@
+-- An approval holds an operation a maker asked for until a second person
+-- (the checker) approves or rejects it. payload is what the operation is
+-- executed with on approval; result is what it returned.
+CREATE TABLE IF NOT EXISTS approvals (
+  id          SERIAL PRIMARY KEY,
+  operation   VARCHAR(30) NOT NULL CHECK (operation IN ('withdrawal', 'transfer', 'overdraft_limit', 'transfer_limit')),
+  entity_type VARCHAR(50) NOT NULL,
+  entity_id   VARCHAR(50) NOT NULL,
+  payload     JSONB NOT NULL,
+  status      VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected', 'expired')),
+  maker       VARCHAR(100) NOT NULL,
+  checker     VARCHAR(100),
+  reason      TEXT,
+  result      JSONB,
+  request_id  VARCHAR(100),
+  expires_at  TIMESTAMPTZ NOT NULL,
+  decided_at  TIMESTAMPTZ,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (status = 'pending' OR decided_at IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_approvals_pending ON approvals(expires_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_approvals_entity ON approvals(entity_type, entity_id);
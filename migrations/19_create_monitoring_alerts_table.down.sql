@@ -0,0 +1,6 @@
+ALTER TABLE approvals DROP CONSTRAINT IF EXISTS approvals_operation_check;
+ALTER TABLE approvals ADD CONSTRAINT approvals_operation_check
+  CHECK (operation IN ('withdrawal', 'transfer', 'overdraft_limit', 'transfer_limit'));
+
+DROP INDEX IF EXISTS idx_transactions_to_account_created;
+DROP TABLE monitoring_alerts;
@@ -0,0 +1,30 @@
+-- An alert records an operation on which monitoring rules fired. Blocked
+-- operations have no transaction; held ones point at the approval they wait
+-- for.
+CREATE TABLE IF NOT EXISTS monitoring_alerts (
+  id                      SERIAL PRIMARY KEY,
+  operation               VARCHAR(20) NOT NULL CHECK (operation IN ('transfer', 'deposit', 'withdrawal')),
+  account_id              INT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  counterparty_account_id INT REFERENCES accounts(id) ON DELETE SET NULL,
+  amount                  NUMERIC(18,3) NOT NULL,
+  currency                CHAR(3) NOT NULL,
+  score                   INT NOT NULL,
+  action                  VARCHAR(10) NOT NULL CHECK (action IN ('allow', 'review', 'block')),
+  hits                    JSONB NOT NULL,
+  transaction_id          INT REFERENCES transactions(id) ON DELETE SET NULL,
+  approval_id             INT REFERENCES approvals(id) ON DELETE SET NULL,
+  status                  VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+  closed_by               VARCHAR(100),
+  note                    TEXT,
+  closed_at               TIMESTAMPTZ,
+  created_at              TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_monitoring_alerts_open ON monitoring_alerts(created_at) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_monitoring_alerts_account ON monitoring_alerts(account_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_transactions_to_account_created ON transactions(to_account_id, created_at);
+
+-- Deposits held by monitoring wait for approval like any other operation.
+ALTER TABLE approvals DROP CONSTRAINT IF EXISTS approvals_operation_check;
+ALTER TABLE approvals ADD CONSTRAINT approvals_operation_check
+  CHECK (operation IN ('withdrawal', 'deposit', 'transfer', 'overdraft_limit', 'transfer_limit'));
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_monitoring_alerts_case;
+ALTER TABLE monitoring_alerts DROP COLUMN IF EXISTS case_id;
+
+DROP TABLE case_attachments;
+DROP TABLE case_notes;
+DROP TABLE cases;
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS status;
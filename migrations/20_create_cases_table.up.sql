@@ -0,0 +1,51 @@
+-- A frozen account may not be debited; credits still post.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS status VARCHAR(10) NOT NULL DEFAULT 'active'
+  CHECK (status IN ('active', 'frozen'));
+
+-- A case groups a client's monitoring alerts for an analyst to work through.
+-- A client has at most one case that is not closed; new alerts join it.
+CREATE TABLE IF NOT EXISTS cases (
+  id         SERIAL PRIMARY KEY,
+  client_id  INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  status     VARCHAR(30) NOT NULL DEFAULT 'open'
+    CHECK (status IN ('open', 'investigating', 'escalated', 'closed-false-positive', 'closed-reported')),
+  assignee   VARCHAR(100),
+  opened_by  VARCHAR(100) NOT NULL,
+  closed_by  VARCHAR(100),
+  closed_at  TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (status NOT LIKE 'closed-%' OR closed_at IS NOT NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cases_client_active ON cases(client_id)
+  WHERE status IN ('open', 'investigating', 'escalated');
+CREATE INDEX IF NOT EXISTS idx_cases_status ON cases(status, updated_at);
+
+CREATE TABLE IF NOT EXISTS case_notes (
+  id         SERIAL PRIMARY KEY,
+  case_id    INT NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+  author     VARCHAR(100) NOT NULL,
+  body       TEXT NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_notes_case ON case_notes(case_id, id);
+
+-- Attachments are stored elsewhere; only what identifies them is kept here.
+CREATE TABLE IF NOT EXISTS case_attachments (
+  id           SERIAL PRIMARY KEY,
+  case_id      INT NOT NULL REFERENCES cases(id) ON DELETE CASCADE,
+  file_name    VARCHAR(255) NOT NULL,
+  content_type VARCHAR(100) NOT NULL,
+  size_bytes   BIGINT NOT NULL CHECK (size_bytes >= 0),
+  sha256       CHAR(64),
+  storage_url  TEXT NOT NULL,
+  uploaded_by  VARCHAR(100) NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_case_attachments_case ON case_attachments(case_id, id);
+
+ALTER TABLE monitoring_alerts ADD COLUMN IF NOT EXISTS case_id INT REFERENCES cases(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_monitoring_alerts_case ON monitoring_alerts(case_id);
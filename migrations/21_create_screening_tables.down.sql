@@ -0,0 +1,2 @@
+DROP TABLE screening_runs;
+DROP TABLE screening_hits;
@@ -0,0 +1,38 @@
+-- A screening hit is a client resembling a sanctions list entry. Pending and
+-- confirmed hits keep the client from opening accounts; cleared ones were
+-- false positives. screened_name is the normalized name and birth date the
+-- client was screened with, so a cleared hit is not raised again unless
+-- those change.
+CREATE TABLE IF NOT EXISTS screening_hits (
+  id               SERIAL PRIMARY KEY,
+  client_id        INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  list_version     VARCHAR(64) NOT NULL,
+  entry_id         VARCHAR(100) NOT NULL,
+  entry_name       VARCHAR(255) NOT NULL,
+  program          VARCHAR(100),
+  matched_name     VARCHAR(255) NOT NULL,
+  screened_name    VARCHAR(255) NOT NULL,
+  score            NUMERIC(4,3) NOT NULL,
+  birth_date_match BOOLEAN NOT NULL,
+  status           VARCHAR(10) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'cleared', 'confirmed')),
+  reviewed_by      VARCHAR(100),
+  note             TEXT,
+  reviewed_at      TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (client_id, entry_id, screened_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_screening_hits_pending ON screening_hits(created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_screening_hits_client ON screening_hits(client_id) WHERE status <> 'cleared';
+
+-- A screening run is a rescreening of every client against one list version.
+CREATE TABLE IF NOT EXISTS screening_runs (
+  id           SERIAL PRIMARY KEY,
+  list_version VARCHAR(64) NOT NULL,
+  clients      INT NOT NULL,
+  hits         INT NOT NULL,
+  started_at   TIMESTAMPTZ NOT NULL,
+  finished_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_screening_runs_version ON screening_runs(list_version);
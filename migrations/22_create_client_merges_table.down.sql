@@ -0,0 +1,4 @@
+DROP TABLE client_merges;
+
+DROP INDEX IF EXISTS idx_clients_birth_date;
+ALTER TABLE clients DROP COLUMN IF EXISTS merged_into;
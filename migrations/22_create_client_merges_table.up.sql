@@ -0,0 +1,20 @@
+-- A merged client keeps its row, pointing at the client it was merged into;
+-- everything it owned moved there.
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS merged_into INT REFERENCES clients(id);
+
+CREATE INDEX IF NOT EXISTS idx_clients_birth_date ON clients(birth_date) WHERE merged_into IS NULL;
+
+-- moved records what the merge reassigned to the survivor and what it
+-- dropped as already present there.
+CREATE TABLE IF NOT EXISTS client_merges (
+  id           SERIAL PRIMARY KEY,
+  survivor_id  INT NOT NULL REFERENCES clients(id),
+  duplicate_id INT NOT NULL UNIQUE REFERENCES clients(id),
+  reason       TEXT NOT NULL,
+  merged_by    VARCHAR(100) NOT NULL,
+  moved        JSONB NOT NULL,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK (survivor_id <> duplicate_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_client_merges_survivor ON client_merges(survivor_id);
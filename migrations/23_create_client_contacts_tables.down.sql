@@ -0,0 +1,3 @@
+DROP TABLE client_emails;
+DROP TABLE client_phones;
+DROP TABLE client_addresses;
@@ -0,0 +1,91 @@
+-- A client has at most one current address per type; replacing it ends the
+-- old one (valid_to) so the history is kept. raw holds the free text an
+-- address was parsed from, for rows carried over from residence_address.
+CREATE TABLE IF NOT EXISTS client_addresses (
+  id           SERIAL PRIMARY KEY,
+  client_id    INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  type         VARCHAR(10) NOT NULL CHECK (type IN ('residence', 'mailing')),
+  street       VARCHAR(200),
+  house_number VARCHAR(20),
+  postal_code  VARCHAR(20),
+  city         VARCHAR(100),
+  country      CHAR(2) CHECK (country ~ '^[A-Z]{2}$'),
+  raw          TEXT,
+  valid_from   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  valid_to     TIMESTAMPTZ,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_addresses_current ON client_addresses(client_id, type) WHERE valid_to IS NULL;
+
+-- Phone numbers are stored in E.164.
+CREATE TABLE IF NOT EXISTS client_phones (
+  id         SERIAL PRIMARY KEY,
+  client_id  INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  number     VARCHAR(16) NOT NULL CHECK (number ~ '^\+[1-9][0-9]{6,14}$'),
+  type       VARCHAR(10) NOT NULL CHECK (type IN ('mobile', 'home', 'work')),
+  is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (client_id, number)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_phones_primary ON client_phones(client_id) WHERE is_primary;
+
+-- The primary email stays in clients.email; these are the client's other
+-- addresses. An address belongs to one client across both tables.
+CREATE TABLE IF NOT EXISTS client_emails (
+  id         SERIAL PRIMARY KEY,
+  client_id  INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  email      VARCHAR(150) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_client_emails_email ON client_emails(LOWER(email));
+
+-- Carry residence_address over best-effort. The text is split on commas:
+-- the first part is the street, with the house number when it ends in one
+-- ("Knez Mihailova 12a"); a part starting with a postal code gives the
+-- postal code and city ("11000 Beograd"), otherwise the second part is the
+-- city; a last part naming a known country gives the country. A single part
+-- without a number is taken as the city. Unparsed parts stay NULL and
+-- residence_address itself is left as it was.
+INSERT INTO client_addresses (client_id, type, street, house_number, postal_code, city, country, raw, valid_from)
+SELECT
+  c.id,
+  'residence',
+  CASE WHEN p.n = 1 AND m.street IS NULL THEN NULL ELSE COALESCE(m.street[1], p.parts[1]) END,
+  m.street[2],
+  m.postal[1],
+  CASE
+    WHEN p.n = 1 AND m.street IS NULL THEN p.parts[1]
+    WHEN m.postal IS NOT NULL THEN m.postal[2]
+    WHEN p.n >= 3 OR (p.n = 2 AND m.country IS NULL) THEN p.parts[2]
+  END,
+  m.country,
+  c.residence_address,
+  COALESCE(c.created_at, NOW())
+FROM clients c
+CROSS JOIN LATERAL (
+  SELECT parts, array_length(parts, 1) AS n
+  FROM (SELECT regexp_split_to_array(btrim(c.residence_address), '\s*,\s*') AS parts) s
+) p
+CROSS JOIN LATERAL (
+  SELECT
+    regexp_match(p.parts[1], '^(.*\S)\s+([0-9]+[[:alpha:]]?(?:/[0-9]+)?)$') AS street,
+    regexp_match(p.parts[2], '^([0-9]{4,6})\s+(.+)$') AS postal,
+    CASE WHEN p.n >= 2 THEN
+      CASE lower(p.parts[p.n])
+        WHEN 'serbia' THEN 'RS' WHEN 'srbija' THEN 'RS' WHEN 'србија' THEN 'RS' WHEN 'rs' THEN 'RS'
+        WHEN 'montenegro' THEN 'ME' WHEN 'crna gora' THEN 'ME' WHEN 'me' THEN 'ME'
+        WHEN 'bosnia and herzegovina' THEN 'BA' WHEN 'bosna i hercegovina' THEN 'BA' WHEN 'ba' THEN 'BA'
+        WHEN 'croatia' THEN 'HR' WHEN 'hrvatska' THEN 'HR' WHEN 'hr' THEN 'HR'
+        WHEN 'north macedonia' THEN 'MK' WHEN 'severna makedonija' THEN 'MK' WHEN 'mk' THEN 'MK'
+        WHEN 'slovenia' THEN 'SI' WHEN 'slovenija' THEN 'SI' WHEN 'si' THEN 'SI'
+        WHEN 'hungary' THEN 'HU' WHEN 'mađarska' THEN 'HU' WHEN 'hu' THEN 'HU'
+        WHEN 'germany' THEN 'DE' WHEN 'nemačka' THEN 'DE' WHEN 'deutschland' THEN 'DE' WHEN 'de' THEN 'DE'
+        WHEN 'austria' THEN 'AT' WHEN 'austrija' THEN 'AT' WHEN 'österreich' THEN 'AT' WHEN 'at' THEN 'AT'
+      END
+    END AS country
+) m
+WHERE btrim(COALESCE(c.residence_address, '')) <> ''
+  AND NOT EXISTS (SELECT 1 FROM client_addresses a WHERE a.client_id = c.id);
@@ -0,0 +1,3 @@
+DROP TABLE email_verifications;
+
+ALTER TABLE clients DROP COLUMN IF EXISTS email_verified_at;
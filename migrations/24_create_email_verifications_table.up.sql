@@ -0,0 +1,19 @@
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- A verification proves the client reads email: 'verify' confirms the
+-- current address, 'change' moves the client to a new one once confirmed.
+-- Only the SHA-256 of the token is kept. Requesting a new token supersedes
+-- the client's pending one for the same purpose.
+CREATE TABLE IF NOT EXISTS email_verifications (
+  id           SERIAL PRIMARY KEY,
+  client_id    INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  purpose      VARCHAR(10) NOT NULL CHECK (purpose IN ('verify', 'change')),
+  email        VARCHAR(150) NOT NULL,
+  token_hash   CHAR(64) NOT NULL UNIQUE,
+  status       VARCHAR(12) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'superseded')),
+  expires_at   TIMESTAMPTZ NOT NULL,
+  confirmed_at TIMESTAMPTZ,
+  created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_verifications_pending ON email_verifications(client_id, purpose) WHERE status = 'pending';
@@ -0,0 +1,16 @@
+DROP TABLE business_parties;
+
+DROP INDEX IF EXISTS idx_clients_registration;
+DELETE FROM clients WHERE type = 'business';
+ALTER TABLE clients DROP CONSTRAINT IF EXISTS clients_type_fields;
+ALTER TABLE clients
+  ALTER COLUMN first_name SET NOT NULL,
+  ALTER COLUMN last_name SET NOT NULL,
+  ALTER COLUMN birth_date SET NOT NULL;
+ALTER TABLE clients
+  DROP COLUMN IF EXISTS incorporation_country,
+  DROP COLUMN IF EXISTS incorporation_date,
+  DROP COLUMN IF EXISTS tax_id,
+  DROP COLUMN IF EXISTS registration_number,
+  DROP COLUMN IF EXISTS registered_name,
+  DROP COLUMN IF EXISTS type;
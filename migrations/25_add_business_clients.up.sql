@@ -0,0 +1,42 @@
+-- A client is an individual or a business (legal entity). Individuals keep
+-- their names and birth date; businesses have registration details instead.
+ALTER TABLE clients
+  ADD COLUMN IF NOT EXISTS type VARCHAR(10) NOT NULL DEFAULT 'individual' CHECK (type IN ('individual', 'business')),
+  ADD COLUMN IF NOT EXISTS registered_name VARCHAR(200),
+  ADD COLUMN IF NOT EXISTS registration_number VARCHAR(50),
+  ADD COLUMN IF NOT EXISTS tax_id VARCHAR(50),
+  ADD COLUMN IF NOT EXISTS incorporation_date DATE,
+  ADD COLUMN IF NOT EXISTS incorporation_country CHAR(2) CHECK (incorporation_country ~ '^[A-Z]{2}$');
+
+ALTER TABLE clients
+  ALTER COLUMN first_name DROP NOT NULL,
+  ALTER COLUMN last_name DROP NOT NULL,
+  ALTER COLUMN birth_date DROP NOT NULL;
+
+ALTER TABLE clients ADD CONSTRAINT clients_type_fields CHECK (
+  (type = 'individual' AND first_name IS NOT NULL AND last_name IS NOT NULL AND birth_date IS NOT NULL)
+  OR (type = 'business' AND registered_name IS NOT NULL AND registration_number IS NOT NULL
+      AND incorporation_date IS NOT NULL AND incorporation_country IS NOT NULL)
+);
+
+-- A company is registered once per country.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clients_registration
+  ON clients(incorporation_country, UPPER(registration_number))
+  WHERE type = 'business' AND merged_into IS NULL;
+
+-- The individuals behind a business client: its beneficial owners, with
+-- their share, and the signatories authorized to act for it.
+CREATE TABLE IF NOT EXISTS business_parties (
+  id                SERIAL PRIMARY KEY,
+  business_id       INT NOT NULL REFERENCES clients(id) ON DELETE CASCADE,
+  person_id         INT NOT NULL REFERENCES clients(id),
+  role              VARCHAR(20) NOT NULL CHECK (role IN ('beneficial_owner', 'signatory')),
+  ownership_percent NUMERIC(5,2) CHECK (ownership_percent > 0 AND ownership_percent <= 100),
+  title             VARCHAR(100),
+  created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  UNIQUE (business_id, person_id, role),
+  CHECK (business_id <> person_id),
+  CHECK (role <> 'beneficial_owner' OR ownership_percent IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_business_parties_person ON business_parties(person_id);
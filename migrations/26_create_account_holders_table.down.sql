@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_transactions_initiated_by;
+ALTER TABLE transactions DROP COLUMN IF EXISTS initiated_by;
+
+DROP TABLE account_holder_consents;
+DROP TABLE account_holders;
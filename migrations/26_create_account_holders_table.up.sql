@@ -0,0 +1,43 @@
+-- Who may use an account and how. accounts.client_id stays the account's
+-- owner, mirrored here with the owner role; joint owners have the same
+-- rights, authorized users may spend up to spend_limit a day and view-only
+-- holders cannot move money. A holder added by someone else stays pending
+-- until everyone whose consent it needs gave it.
+CREATE TABLE IF NOT EXISTS account_holders (
+  id          SERIAL PRIMARY KEY,
+  account_id  INT NOT NULL REFERENCES accounts(id) ON DELETE CASCADE,
+  client_id   INT NOT NULL REFERENCES clients(id),
+  role        VARCHAR(20) NOT NULL CHECK (role IN ('owner', 'joint_owner', 'authorized_user', 'view_only')),
+  spend_limit NUMERIC(18,3) CHECK (spend_limit > 0),
+  status      VARCHAR(10) NOT NULL DEFAULT 'active' CHECK (status IN ('pending', 'active', 'declined', 'removed')),
+  added_by    INT REFERENCES clients(id),
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CHECK ((role = 'authorized_user') = (spend_limit IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_holders_current
+  ON account_holders(account_id, client_id) WHERE status IN ('pending', 'active');
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_holders_owner
+  ON account_holders(account_id) WHERE role = 'owner' AND status = 'active';
+CREATE INDEX IF NOT EXISTS idx_account_holders_client ON account_holders(client_id) WHERE status = 'active';
+
+INSERT INTO account_holders (account_id, client_id, role)
+SELECT id, client_id, 'owner' FROM accounts;
+
+-- One row per consent a pending holder needs: from the client being added
+-- and from each owner or joint owner other than the one adding it.
+CREATE TABLE IF NOT EXISTS account_holder_consents (
+  id           SERIAL PRIMARY KEY,
+  holder_id    INT NOT NULL REFERENCES account_holders(id) ON DELETE CASCADE,
+  client_id    INT NOT NULL REFERENCES clients(id),
+  consented_at TIMESTAMPTZ,
+  UNIQUE (holder_id, client_id)
+);
+
+-- The holder who made a transfer or withdrawal, when a client made it
+-- itself; authorized users' spend limits are counted from it.
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS initiated_by INT REFERENCES clients(id);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_initiated_by
+  ON transactions(from_account_id, initiated_by, created_at) WHERE initiated_by IS NOT NULL;
@@ -0,0 +1,12 @@
+DROP TABLE client_erasures;
+
+-- Erased clients cannot get their personal data back, so the original
+-- check is not validated against them.
+ALTER TABLE clients DROP CONSTRAINT IF EXISTS clients_type_fields;
+ALTER TABLE clients ADD CONSTRAINT clients_type_fields CHECK (
+  (type = 'individual' AND first_name IS NOT NULL AND last_name IS NOT NULL AND birth_date IS NOT NULL)
+  OR (type = 'business' AND registered_name IS NOT NULL AND registration_number IS NOT NULL
+      AND incorporation_date IS NOT NULL AND incorporation_country IS NOT NULL)
+) NOT VALID;
+
+ALTER TABLE clients DROP COLUMN IF EXISTS erased_at;
@@ -0,0 +1,22 @@
+-- An erased client keeps its row so the financial records that must be
+-- retained stay linked to it, but its personal data is pseudonymized:
+-- names, birth date and address are cleared and the email replaced.
+ALTER TABLE clients ADD COLUMN IF NOT EXISTS erased_at TIMESTAMPTZ;
+
+ALTER TABLE clients DROP CONSTRAINT IF EXISTS clients_type_fields;
+ALTER TABLE clients ADD CONSTRAINT clients_type_fields CHECK (
+  erased_at IS NOT NULL
+  OR (type = 'individual' AND first_name IS NOT NULL AND last_name IS NOT NULL AND birth_date IS NOT NULL)
+  OR (type = 'business' AND registered_name IS NOT NULL AND registration_number IS NOT NULL
+      AND incorporation_date IS NOT NULL AND incorporation_country IS NOT NULL)
+);
+
+-- erased records what the erasure removed, pseudonymized or froze.
+CREATE TABLE IF NOT EXISTS client_erasures (
+  id         SERIAL PRIMARY KEY,
+  client_id  INT NOT NULL UNIQUE REFERENCES clients(id),
+  reason     TEXT NOT NULL,
+  erased_by  VARCHAR(100) NOT NULL,
+  erased     JSONB NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+UPDATE standing_order_executions SET status = 'failed' WHERE status = 'pending_approval';
+
+ALTER TABLE standing_order_executions DROP CONSTRAINT IF EXISTS standing_order_executions_status_check;
+ALTER TABLE standing_order_executions ADD CONSTRAINT standing_order_executions_status_check
+  CHECK (status IN ('succeeded', 'failed', 'retry_scheduled', 'skipped'));
@@ -0,0 +1,5 @@
+-- A standing order occurrence held for review by transaction monitoring
+-- waits for approval instead of being posted.
+ALTER TABLE standing_order_executions DROP CONSTRAINT IF EXISTS standing_order_executions_status_check;
+ALTER TABLE standing_order_executions ADD CONSTRAINT standing_order_executions_status_check
+  CHECK (status IN ('succeeded', 'failed', 'retry_scheduled', 'skipped', 'pending_approval'));
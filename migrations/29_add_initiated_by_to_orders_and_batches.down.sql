@@ -0,0 +1,2 @@
+ALTER TABLE payment_batches DROP COLUMN IF EXISTS initiated_by;
+ALTER TABLE standing_orders DROP COLUMN IF EXISTS initiated_by;
@@ -0,0 +1,5 @@
+-- The holder who set up a standing order or submitted a payment batch, when
+-- a client did it itself. Its role on the debited account is checked again
+-- whenever a payment is posted, and authorized users' spend limits count it.
+ALTER TABLE standing_orders ADD COLUMN IF NOT EXISTS initiated_by INT REFERENCES clients(id);
+ALTER TABLE payment_batches ADD COLUMN IF NOT EXISTS initiated_by INT REFERENCES clients(id);
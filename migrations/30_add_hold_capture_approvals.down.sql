@@ -0,0 +1,4 @@
+DELETE FROM approvals WHERE operation = 'hold_capture';
+ALTER TABLE approvals DROP CONSTRAINT IF EXISTS approvals_operation_check;
+ALTER TABLE approvals ADD CONSTRAINT approvals_operation_check
+  CHECK (operation IN ('withdrawal', 'deposit', 'transfer', 'overdraft_limit', 'transfer_limit'));
@@ -0,0 +1,5 @@
+-- A hold capture that needs a second person waits for approval like the
+-- withdrawal or transfer it posts.
+ALTER TABLE approvals DROP CONSTRAINT IF EXISTS approvals_operation_check;
+ALTER TABLE approvals ADD CONSTRAINT approvals_operation_check
+  CHECK (operation IN ('withdrawal', 'deposit', 'transfer', 'overdraft_limit', 'transfer_limit', 'hold_capture'));